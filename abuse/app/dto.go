@@ -0,0 +1,91 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/abuse/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type ReportCmd struct {
+	Item     domain.Item
+	Reporter types.Account
+	Reason   domain.ReportReason
+}
+
+type ReviewCmd struct {
+	Item     domain.Item
+	Action   domain.ReviewAction
+	Operator string
+	Comment  domain.ReviewComment
+}
+
+func (cmd *ReviewCmd) Validate() error {
+	if cmd.Operator == "" {
+		return errors.New("missing operator")
+	}
+
+	return nil
+}
+
+// ModerationCmd is the item which is checked by the moderation,
+// the reason tells where the content is.
+type ModerationCmd struct {
+	Item   domain.Item
+	Reason domain.ReportReason
+}
+
+type PendingItemListCmd = repository.PendingItemListOption
+
+type AuditListCmd = repository.AuditListOption
+
+type ItemDTO struct {
+	Type  string `json:"type"`
+	Owner string `json:"owner"`
+	Id    string `json:"id"`
+}
+
+func toItemDTO(i *domain.Item) ItemDTO {
+	return ItemDTO{
+		Type:  i.Type.ItemType(),
+		Owner: i.Owner.Account(),
+		Id:    i.Id,
+	}
+}
+
+type PendingItemDTO struct {
+	ItemDTO
+
+	Reasons         []string `json:"reasons"`
+	Count           int      `json:"count"`
+	FirstReportedAt int64    `json:"first_reported_at"`
+	LastReportedAt  int64    `json:"last_reported_at"`
+}
+
+type PendingItemsDTO struct {
+	Total int              `json:"total"`
+	Items []PendingItemDTO `json:"items"`
+}
+
+type ReportDTO struct {
+	Id        string `json:"id"`
+	Reporter  string `json:"reporter"`
+	Reason    string `json:"reason"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type AuditDTO struct {
+	ItemDTO
+
+	Action    string `json:"action"`
+	Operator  string `json:"operator"`
+	Comment   string `json:"comment"`
+	Reports   int    `json:"reports"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type AuditsDTO struct {
+	Total  int        `json:"total"`
+	Audits []AuditDTO `json:"audits"`
+}
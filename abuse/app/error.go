@@ -0,0 +1,9 @@
+package app
+
+const (
+	errorItemNotFound      = "abuse_item_not_found"
+	errorReportOwnItem     = "abuse_report_own_item"
+	errorDuplicateReport   = "abuse_duplicate_report"
+	errorNoPendingReport   = "abuse_no_pending_report"
+	errorUnsupportedAction = "abuse_unsupported_action"
+)
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/abuse/domain/item"
+	"github.com/opensourceways/xihe-server/abuse/domain/repository"
+	repoerr "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// ModerationService hides the items whose content is found sensitive by the moderation
+// and puts them into the review queue of admin. The item is restored if the content
+// is clean again and no one else has reported it.
+type ModerationService interface {
+	Hide(*ModerationCmd) error
+	Restore(*ModerationCmd) error
+}
+
+func NewModerationService(
+	items item.Item,
+	reports repository.Report,
+	audits repository.Audit,
+) ModerationService {
+	return &moderationService{
+		items:   items,
+		reports: reports,
+		audits:  audits,
+	}
+}
+
+type moderationService struct {
+	items   item.Item
+	reports repository.Report
+	audits  repository.Audit
+}
+
+func (s *moderationService) Hide(cmd *ModerationCmd) error {
+	if err := s.items.Hide(&cmd.Item); err != nil {
+		return err
+	}
+
+	_, err := s.reports.AddReport(&domain.Report{
+		Item:      cmd.Item,
+		Reporter:  domain.ModerationReporter(),
+		Reason:    cmd.Reason,
+		Status:    domain.ReportStatusPending,
+		CreatedAt: utils.Now(),
+	})
+	if err != nil && repoerr.IsErrorDuplicateCreating(err) {
+		err = nil
+	}
+
+	return err
+}
+
+// Restore does nothing if the item is not hidden by the moderation for the same reason,
+// such as it is hidden by the admin or reported by the users.
+func (s *moderationService) Restore(cmd *ModerationCmd) error {
+	v, err := s.reports.ListReports(&cmd.Item, domain.ReportStatusPending)
+	if err != nil || len(v) == 0 {
+		return err
+	}
+
+	for i := range v {
+		if !v[i].IsByModeration() || v[i].Reason.ReportReason() != cmd.Reason.ReportReason() {
+			return nil
+		}
+	}
+
+	if err := s.items.Restore(&cmd.Item); err != nil {
+		return err
+	}
+
+	n, err := s.reports.ResolveReports(&cmd.Item, domain.ReviewActionRestore.ReportStatus())
+	if err != nil {
+		return err
+	}
+
+	comment, err := domain.NewReviewComment("the content is clean again")
+	if err != nil {
+		return err
+	}
+
+	return s.audits.AddAudit(&domain.Audit{
+		Item:      cmd.Item,
+		Action:    domain.ReviewActionRestore,
+		Operator:  domain.ModerationReporter().Account(),
+		Comment:   comment,
+		Reports:   n,
+		CreatedAt: utils.Now(),
+	})
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/abuse/domain/item"
+	"github.com/opensourceways/xihe-server/abuse/domain/repository"
+	repoerr "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// ReportService lets the users report the public items which are abusive
+type ReportService interface {
+	Report(*ReportCmd) (string, error)
+}
+
+func NewReportService(items item.Item, reports repository.Report) ReportService {
+	return &reportService{
+		items:   items,
+		reports: reports,
+	}
+}
+
+type reportService struct {
+	items   item.Item
+	reports repository.Report
+}
+
+func (s *reportService) Report(cmd *ReportCmd) (code string, err error) {
+	if cmd.Item.IsOwner(cmd.Reporter) {
+		code = errorReportOwnItem
+		err = errors.New("can't report own item")
+
+		return
+	}
+
+	b, err := s.items.IsPublic(&cmd.Item)
+	if err != nil {
+		return
+	}
+
+	if !b {
+		code = errorItemNotFound
+		err = errors.New("the item does not exist")
+
+		return
+	}
+
+	_, err = s.reports.AddReport(&domain.Report{
+		Item:      cmd.Item,
+		Reporter:  cmd.Reporter,
+		Reason:    cmd.Reason,
+		Status:    domain.ReportStatusPending,
+		CreatedAt: utils.Now(),
+	})
+	if err != nil && repoerr.IsErrorDuplicateCreating(err) {
+		code = errorDuplicateReport
+	}
+
+	return
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/abuse/domain/item"
+	"github.com/opensourceways/xihe-server/abuse/domain/message"
+	"github.com/opensourceways/xihe-server/abuse/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// ReviewService is used by the admins to review the reported items
+type ReviewService interface {
+	ListPendingItems(*PendingItemListCmd) (PendingItemsDTO, error)
+	ListReports(*domain.Item) ([]ReportDTO, error)
+
+	// Review hides, deletes, restores the item or dismisses the reports of it.
+	// All the pending reports are resolved and the owner is notified.
+	// The hidden item can be restored even if it has no pending reports.
+	Review(*ReviewCmd) (string, error)
+	ListAudits(*AuditListCmd) (AuditsDTO, error)
+}
+
+func NewReviewService(
+	items item.Item,
+	reports repository.Report,
+	audits repository.Audit,
+	producer message.MessageProducer,
+) ReviewService {
+	return &reviewService{
+		items:    items,
+		reports:  reports,
+		audits:   audits,
+		producer: producer,
+	}
+}
+
+type reviewService struct {
+	items    item.Item
+	reports  repository.Report
+	audits   repository.Audit
+	producer message.MessageProducer
+}
+
+func (s *reviewService) ListPendingItems(cmd *PendingItemListCmd) (dto PendingItemsDTO, err error) {
+	v, err := s.reports.ListPendingItems(cmd)
+	if err != nil {
+		return
+	}
+
+	dto.Total = v.Total
+	dto.Items = make([]PendingItemDTO, len(v.Items))
+
+	for i := range v.Items {
+		p := &v.Items[i]
+
+		dto.Items[i] = PendingItemDTO{
+			ItemDTO:         toItemDTO(&p.Item),
+			Reasons:         p.Reasons,
+			Count:           p.Count,
+			FirstReportedAt: p.FirstReportedAt,
+			LastReportedAt:  p.LastReportedAt,
+		}
+	}
+
+	return
+}
+
+func (s *reviewService) ListReports(i *domain.Item) ([]ReportDTO, error) {
+	v, err := s.reports.ListReports(i, domain.ReportStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]ReportDTO, len(v))
+	for i := range v {
+		r[i] = ReportDTO{
+			Id:        v[i].Id,
+			Reporter:  v[i].Reporter.Account(),
+			Reason:    v[i].Reason.ReportReason(),
+			CreatedAt: v[i].CreatedAt,
+		}
+	}
+
+	return r, nil
+}
+
+func (s *reviewService) Review(cmd *ReviewCmd) (code string, err error) {
+	if cmd.Action.IsDelete() && !cmd.Item.Type.CanBeDeleted() {
+		code = errorUnsupportedAction
+		err = errors.New("the item can't be deleted")
+
+		return
+	}
+
+	v, err := s.reports.ListReports(&cmd.Item, domain.ReportStatusPending)
+	if err != nil {
+		return
+	}
+
+	if len(v) == 0 && !cmd.Action.IsRestore() {
+		code = errorNoPendingReport
+		err = errors.New("no pending report of the item")
+
+		return
+	}
+
+	switch {
+	case cmd.Action.IsHide():
+		err = s.items.Hide(&cmd.Item)
+
+	case cmd.Action.IsDelete():
+		err = s.items.Delete(&cmd.Item)
+
+	case cmd.Action.IsRestore():
+		err = s.items.Restore(&cmd.Item)
+	}
+
+	if err != nil {
+		return
+	}
+
+	n, err := s.reports.ResolveReports(&cmd.Item, cmd.Action.ReportStatus())
+	if err != nil {
+		return
+	}
+
+	err = s.audits.AddAudit(&domain.Audit{
+		Item:      cmd.Item,
+		Action:    cmd.Action,
+		Operator:  cmd.Operator,
+		Comment:   cmd.Comment,
+		Reports:   n,
+		CreatedAt: utils.Now(),
+	})
+	if err != nil {
+		return
+	}
+
+	if cmd.Action.IsDismiss() {
+		return
+	}
+
+	err1 := s.producer.SendItemReviewedEvent(&domain.ItemReviewedEvent{
+		Item:    cmd.Item,
+		Action:  cmd.Action,
+		Comment: cmd.Comment,
+	})
+	if err1 != nil {
+		logrus.Errorf("notify the owner of %s failed, err:%s", cmd.Item.String(), err1.Error())
+	}
+
+	return
+}
+
+func (s *reviewService) ListAudits(cmd *AuditListCmd) (dto AuditsDTO, err error) {
+	v, err := s.audits.ListAudits(cmd)
+	if err != nil {
+		return
+	}
+
+	dto.Total = v.Total
+	dto.Audits = make([]AuditDTO, len(v.Audits))
+
+	for i := range v.Audits {
+		a := &v.Audits[i]
+
+		dto.Audits[i] = AuditDTO{
+			ItemDTO:   toItemDTO(&a.Item),
+			Action:    a.Action.ReviewAction(),
+			Operator:  a.Operator,
+			Comment:   a.Comment.ReviewComment(),
+			Reports:   a.Reports,
+			CreatedAt: a.CreatedAt,
+		}
+	}
+
+	return
+}
@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+const (
+	itemTypeWuKongPicture  = "wukong_picture"
+	itemTypeGalleryPicture = "gallery_picture"
+	itemTypeProject        = "project"
+	itemTypeModel          = "model"
+	itemTypeDataset        = "dataset"
+
+	actionHide    = "hide"
+	actionDelete  = "delete"
+	actionDismiss = "dismiss"
+	actionRestore = "restore"
+
+	reportStatusPending   = "pending"
+	reportStatusHidden    = "hidden"
+	reportStatusDeleted   = "deleted"
+	reportStatusDismissed = "dismissed"
+	reportStatusRestored  = "restored"
+)
+
+var (
+	ItemTypeWuKongPicture  = itemType(itemTypeWuKongPicture)
+	ItemTypeGalleryPicture = itemType(itemTypeGalleryPicture)
+	ItemTypeProject        = itemType(itemTypeProject)
+	ItemTypeModel          = itemType(itemTypeModel)
+	ItemTypeDataset        = itemType(itemTypeDataset)
+
+	ReportStatusPending = reportStatus(reportStatusPending)
+
+	ReviewActionRestore = reviewAction(actionRestore)
+)
+
+// ItemType is the type of public item which can be reported
+type ItemType interface {
+	ItemType() string
+
+	// CanBeDeleted checks whether the item can be deleted by the admin,
+	// the resource can only be hidden because its repo is managed by the owner.
+	CanBeDeleted() bool
+}
+
+func NewItemType(v string) (ItemType, error) {
+	b := v == itemTypeWuKongPicture ||
+		v == itemTypeGalleryPicture ||
+		v == itemTypeProject ||
+		v == itemTypeModel ||
+		v == itemTypeDataset
+	if !b {
+		return nil, errors.New("invalid item type")
+	}
+
+	return itemType(v), nil
+}
+
+type itemType string
+
+func (r itemType) ItemType() string {
+	return string(r)
+}
+
+func (r itemType) CanBeDeleted() bool {
+	return string(r) == itemTypeWuKongPicture || string(r) == itemTypeGalleryPicture
+}
+
+// ReportReason
+type ReportReason interface {
+	ReportReason() string
+}
+
+func NewReportReason(v string) (ReportReason, error) {
+	v = utils.XSSFilter(strings.TrimSpace(v))
+
+	if v == "" {
+		return nil, errors.New("empty reason")
+	}
+
+	if max := 200; utils.StrLen(v) > max {
+		return nil, fmt.Errorf("the length of reason should be less than %d", max)
+	}
+
+	return reportReason(v), nil
+}
+
+type reportReason string
+
+func (r reportReason) ReportReason() string {
+	return string(r)
+}
+
+// ReviewComment is the comment of admin which is sent to the owner of item
+type ReviewComment interface {
+	ReviewComment() string
+}
+
+func NewReviewComment(v string) (ReviewComment, error) {
+	v = utils.XSSFilter(strings.TrimSpace(v))
+
+	if max := 500; utils.StrLen(v) > max {
+		return nil, fmt.Errorf("the length of comment should be less than %d", max)
+	}
+
+	return reviewComment(v), nil
+}
+
+type reviewComment string
+
+func (r reviewComment) ReviewComment() string {
+	return string(r)
+}
+
+// ReviewAction is what the admin does to the reported item
+type ReviewAction interface {
+	ReviewAction() string
+	IsHide() bool
+	IsDelete() bool
+	IsDismiss() bool
+	IsRestore() bool
+
+	// ReportStatus is the status of reports after the action
+	ReportStatus() ReportStatus
+}
+
+func NewReviewAction(v string) (ReviewAction, error) {
+	b := v == actionHide ||
+		v == actionDelete ||
+		v == actionDismiss ||
+		v == actionRestore
+	if !b {
+		return nil, errors.New("invalid review action")
+	}
+
+	return reviewAction(v), nil
+}
+
+type reviewAction string
+
+func (r reviewAction) ReviewAction() string {
+	return string(r)
+}
+
+func (r reviewAction) IsHide() bool {
+	return string(r) == actionHide
+}
+
+func (r reviewAction) IsDelete() bool {
+	return string(r) == actionDelete
+}
+
+func (r reviewAction) IsDismiss() bool {
+	return string(r) == actionDismiss
+}
+
+func (r reviewAction) IsRestore() bool {
+	return string(r) == actionRestore
+}
+
+func (r reviewAction) ReportStatus() ReportStatus {
+	switch string(r) {
+	case actionHide:
+		return reportStatus(reportStatusHidden)
+
+	case actionDelete:
+		return reportStatus(reportStatusDeleted)
+
+	case actionRestore:
+		return reportStatus(reportStatusRestored)
+	}
+
+	return reportStatus(reportStatusDismissed)
+}
+
+// ReportStatus
+type ReportStatus interface {
+	ReportStatus() string
+	IsPending() bool
+}
+
+func NewReportStatus(v string) (ReportStatus, error) {
+	b := v == reportStatusPending ||
+		v == reportStatusHidden ||
+		v == reportStatusDeleted ||
+		v == reportStatusDismissed ||
+		v == reportStatusRestored
+	if !b {
+		return nil, errors.New("invalid report status")
+	}
+
+	return reportStatus(v), nil
+}
+
+type reportStatus string
+
+func (r reportStatus) ReportStatus() string {
+	return string(r)
+}
+
+func (r reportStatus) IsPending() bool {
+	return string(r) == reportStatusPending
+}
@@ -0,0 +1,8 @@
+package domain
+
+// ItemReviewedEvent notifies the owner that the item is hidden, deleted or restored by the admin
+type ItemReviewedEvent struct {
+	Item    Item
+	Action  ReviewAction
+	Comment ReviewComment
+}
@@ -0,0 +1,12 @@
+package item
+
+import "github.com/opensourceways/xihe-server/abuse/domain"
+
+// Item operates the reported items which are owned by the other domains
+type Item interface {
+	// IsPublic checks whether the item exists and can be seen by others
+	IsPublic(*domain.Item) (bool, error)
+	Hide(*domain.Item) error
+	Restore(*domain.Item) error
+	Delete(*domain.Item) error
+}
@@ -0,0 +1,7 @@
+package message
+
+import "github.com/opensourceways/xihe-server/abuse/domain"
+
+type MessageProducer interface {
+	SendItemReviewedEvent(*domain.ItemReviewedEvent) error
+}
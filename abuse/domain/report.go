@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+// Item is the public item which is reported, such as a public wukong picture
+// or a public project. The id is the id of picture or resource of owner.
+type Item struct {
+	Type  ItemType
+	Owner types.Account
+	Id    string
+}
+
+func (i *Item) String() string {
+	return fmt.Sprintf("%s_%s_%s", i.Type.ItemType(), i.Owner.Account(), i.Id)
+}
+
+func (i *Item) IsOwner(u types.Account) bool {
+	return u != nil && i.Owner.Account() == u.Account()
+}
+
+// moderationReporter is the reporter of the items which are hidden
+// by the moderation automatically.
+const moderationReporter = "xihe_moderation"
+
+func ModerationReporter() types.Account {
+	v, _ := types.NewAccount(moderationReporter)
+
+	return v
+}
+
+// Report is the report of user about an abusive item.
+// It is pending until the admin reviews the item.
+type Report struct {
+	Id        string
+	Item      Item
+	Reporter  types.Account
+	Reason    ReportReason
+	Status    ReportStatus
+	CreatedAt int64
+}
+
+func (r *Report) IsByModeration() bool {
+	return r.Reporter.Account() == moderationReporter
+}
+
+// PendingItem is an item in the review queue with its pending reports
+type PendingItem struct {
+	Item Item
+
+	// Reasons are the reasons of the latest reports
+	Reasons []string
+	Count   int
+
+	FirstReportedAt int64
+	LastReportedAt  int64
+}
+
+// Audit is the record of what the admin does to a reported item
+type Audit struct {
+	Id        string
+	Item      Item
+	Action    ReviewAction
+	Operator  string
+	Comment   ReviewComment
+	Reports   int // the number of reports which are resolved
+	CreatedAt int64
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/abuse/domain"
+)
+
+type PendingItemListOption struct {
+	Type         domain.ItemType // all the types if nil
+	CountPerPage int
+	PageNum      int
+}
+
+type PendingItemList struct {
+	Total int
+	Items []domain.PendingItem
+}
+
+type Report interface {
+	// AddReport returns ErrorDuplicateCreating if the user has reported
+	// the item and it is still pending.
+	AddReport(*domain.Report) (string, error)
+
+	// ListPendingItems lists the items which have pending reports,
+	// the item reported by most users is in the front.
+	ListPendingItems(*PendingItemListOption) (PendingItemList, error)
+	ListReports(*domain.Item, domain.ReportStatus) ([]domain.Report, error)
+
+	// ResolveReports changes the status of all the pending reports of item
+	// and returns the number of them.
+	ResolveReports(*domain.Item, domain.ReportStatus) (int, error)
+}
+
+type AuditListOption struct {
+	CountPerPage int
+	PageNum      int
+}
+
+type AuditList struct {
+	Total  int
+	Audits []domain.Audit
+}
+
+type Audit interface {
+	AddAudit(*domain.Audit) error
+	ListAudits(*AuditListOption) (AuditList, error)
+}
@@ -0,0 +1,164 @@
+package itemimpl
+
+import (
+	"errors"
+
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/abuse/domain/item"
+	bigmodelrepo "github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	commonrepo "github.com/opensourceways/xihe-server/common/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+)
+
+func NewItemImpl(
+	wukong bigmodelrepo.WuKongPicture,
+	gallery bigmodelrepo.GalleryPicture,
+	project repository.Project,
+	model repository.Model,
+	dataset repository.Dataset,
+) item.Item {
+	return &itemImpl{
+		wukong:  wukong,
+		gallery: gallery,
+		project: project,
+		model:   model,
+		dataset: dataset,
+	}
+}
+
+type itemImpl struct {
+	wukong  bigmodelrepo.WuKongPicture
+	gallery bigmodelrepo.GalleryPicture
+	project repository.Project
+	model   repository.Model
+	dataset repository.Dataset
+}
+
+func (impl *itemImpl) IsPublic(i *domain.Item) (b bool, err error) {
+	switch i.Type.ItemType() {
+	case domain.ItemTypeWuKongPicture.ItemType():
+		p, err := impl.wukong.GetPublicByUserName(i.Owner, i.Id)
+		if err != nil {
+			return false, ignoreNotExists(err)
+		}
+
+		return !p.Hidden, nil
+
+	case domain.ItemTypeGalleryPicture.ItemType():
+		p, err := impl.gallery.GetPicture(i.Id)
+		if err != nil {
+			return false, ignoreNotExists(err)
+		}
+
+		return p.IsOwner(i.Owner) && p.IsPublished(), nil
+
+	case domain.ItemTypeProject.ItemType():
+		p, err := impl.project.Get(i.Owner, i.Id)
+		if err != nil {
+			return false, ignoreNotExists(err)
+		}
+
+		return !p.IsPrivate() && !p.IsHidden(), nil
+
+	case domain.ItemTypeModel.ItemType():
+		m, err := impl.model.Get(i.Owner, i.Id)
+		if err != nil {
+			return false, ignoreNotExists(err)
+		}
+
+		return !m.IsPrivate() && !m.IsHidden(), nil
+
+	case domain.ItemTypeDataset.ItemType():
+		d, err := impl.dataset.Get(i.Owner, i.Id)
+		if err != nil {
+			return false, ignoreNotExists(err)
+		}
+
+		return !d.IsPrivate() && !d.IsHidden(), nil
+	}
+
+	return
+}
+
+func (impl *itemImpl) Hide(i *domain.Item) error {
+	index := &types.ResourceIndex{Owner: i.Owner, Id: i.Id}
+
+	switch i.Type.ItemType() {
+	case domain.ItemTypeWuKongPicture.ItemType():
+		p, err := impl.wukong.GetPublicByUserName(i.Owner, i.Id)
+		if err != nil {
+			return err
+		}
+
+		p.Hidden = true
+
+		return impl.wukong.UpdatePublicPicture(i.Owner, i.Id, p.Version, &p)
+
+	case domain.ItemTypeGalleryPicture.ItemType():
+		return impl.gallery.UpdateHidden(i.Id, true)
+
+	case domain.ItemTypeProject.ItemType():
+		return impl.project.SetReviewStatus(index, types.ReviewStatusHidden)
+
+	case domain.ItemTypeModel.ItemType():
+		return impl.model.SetReviewStatus(index, types.ReviewStatusHidden)
+
+	case domain.ItemTypeDataset.ItemType():
+		return impl.dataset.SetReviewStatus(index, types.ReviewStatusHidden)
+	}
+
+	return errors.New("unsupported item type")
+}
+
+func (impl *itemImpl) Restore(i *domain.Item) error {
+	index := &types.ResourceIndex{Owner: i.Owner, Id: i.Id}
+
+	switch i.Type.ItemType() {
+	case domain.ItemTypeWuKongPicture.ItemType():
+		p, err := impl.wukong.GetPublicByUserName(i.Owner, i.Id)
+		if err != nil {
+			return err
+		}
+
+		p.Hidden = false
+
+		return impl.wukong.UpdatePublicPicture(i.Owner, i.Id, p.Version, &p)
+
+	case domain.ItemTypeGalleryPicture.ItemType():
+		return impl.gallery.UpdateHidden(i.Id, false)
+
+	case domain.ItemTypeProject.ItemType():
+		return impl.project.SetReviewStatus(index, "")
+
+	case domain.ItemTypeModel.ItemType():
+		return impl.model.SetReviewStatus(index, "")
+
+	case domain.ItemTypeDataset.ItemType():
+		return impl.dataset.SetReviewStatus(index, "")
+	}
+
+	return errors.New("unsupported item type")
+}
+
+// Delete removes the public picture from the gallery.
+// The file of picture is kept on obs as the evidence of the report.
+func (impl *itemImpl) Delete(i *domain.Item) error {
+	switch i.Type.ItemType() {
+	case domain.ItemTypeWuKongPicture.ItemType():
+		return impl.wukong.DeletePublic(i.Owner, i.Id)
+
+	case domain.ItemTypeGalleryPicture.ItemType():
+		return impl.gallery.DeletePicture(i.Owner, i.Id)
+	}
+
+	return errors.New("unsupported item type")
+}
+
+func ignoreNotExists(err error) error {
+	if commonrepo.IsErrorResourceNotExists(err) || repository.IsErrorResourceNotExists(err) {
+		return nil
+	}
+
+	return err
+}
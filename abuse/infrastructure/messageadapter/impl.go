@@ -0,0 +1,45 @@
+package messageadapter
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	common "github.com/opensourceways/xihe-server/common/domain/message"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+func MessageAdapter(cfg *Config, p common.Publisher) *messageAdapter {
+	return &messageAdapter{cfg: *cfg, publisher: p}
+}
+
+type messageAdapter struct {
+	cfg       Config
+	publisher common.Publisher
+}
+
+func (impl *messageAdapter) SendItemReviewedEvent(v *domain.ItemReviewedEvent) error {
+	cfg := &impl.cfg.ItemReviewed
+
+	msg := common.MsgNormal{
+		Type: cfg.Name,
+		User: v.Item.Owner.Account(),
+		Desc: fmt.Sprintf(
+			"the %s of %s is taken down by action: %s",
+			v.Item.Type.ItemType(), v.Item.Id, v.Action.ReviewAction(),
+		),
+		Details: map[string]string{
+			"item_type": v.Item.Type.ItemType(),
+			"item_id":   v.Item.Id,
+			"action":    v.Action.ReviewAction(),
+			"comment":   v.Comment.ReviewComment(),
+		},
+		CreatedAt: utils.Now(),
+	}
+
+	return impl.publisher.Publish(cfg.Topic, &msg, nil)
+}
+
+// Config
+type Config struct {
+	ItemReviewed common.TopicConfig `json:"item_reviewed"  required:"true"`
+}
@@ -0,0 +1,77 @@
+package repositoryimpl
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/abuse/domain/repository"
+)
+
+func NewAuditRepo(m mongodbClient) repository.Audit {
+	return &auditRepoImpl{m}
+}
+
+type auditRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *auditRepoImpl) AddAudit(a *domain.Audit) error {
+	doc, err := genDoc(toAuditDoc(a))
+	if err != nil {
+		return err
+	}
+
+	doc[fieldId] = newId()
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.Collection().InsertOne(ctx, doc)
+
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (impl *auditRepoImpl) ListAudits(opt *repository.AuditListOption) (
+	r repository.AuditList, err error,
+) {
+	var v []dAudit
+
+	f := func(ctx context.Context) error {
+		n, err := impl.cli.Collection().CountDocuments(ctx, bson.M{})
+		if err != nil {
+			return err
+		}
+
+		r.Total = int(n)
+
+		cursor, err := impl.cli.Collection().Find(
+			ctx, bson.M{},
+			options.Find().
+				SetSort(bson.M{fieldCreatedAt: -1}).
+				SetSkip(int64(opt.CountPerPage*(opt.PageNum-1))).
+				SetLimit(int64(opt.CountPerPage)),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		return
+	}
+
+	r.Audits = make([]domain.Audit, len(v))
+	for i := range v {
+		if err = v[i].toAudit(&r.Audits[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
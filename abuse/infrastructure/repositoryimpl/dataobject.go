@@ -0,0 +1,106 @@
+package repositoryimpl
+
+import (
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+func toItemDoc(i *domain.Item) dItem {
+	return dItem{
+		Type:  i.Type.ItemType(),
+		Owner: i.Owner.Account(),
+		Id:    i.Id,
+	}
+}
+
+func (d *dItem) toItem(i *domain.Item) (err error) {
+	if i.Type, err = domain.NewItemType(d.Type); err != nil {
+		return
+	}
+
+	if i.Owner, err = types.NewAccount(d.Owner); err != nil {
+		return
+	}
+
+	i.Id = d.Id
+
+	return
+}
+
+func toReportDoc(r *domain.Report) dReport {
+	return dReport{
+		dItem:     toItemDoc(&r.Item),
+		Reporter:  r.Reporter.Account(),
+		Reason:    r.Reason.ReportReason(),
+		Status:    r.Status.ReportStatus(),
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (d *dReport) toReport(r *domain.Report) (err error) {
+	if err = d.dItem.toItem(&r.Item); err != nil {
+		return
+	}
+
+	if r.Reporter, err = types.NewAccount(d.Reporter); err != nil {
+		return
+	}
+
+	if r.Reason, err = domain.NewReportReason(d.Reason); err != nil {
+		return
+	}
+
+	if r.Status, err = domain.NewReportStatus(d.Status); err != nil {
+		return
+	}
+
+	r.Id = d.Id
+	r.CreatedAt = d.CreatedAt
+
+	return
+}
+
+func (d *dPendingItem) toPendingItem(p *domain.PendingItem) (err error) {
+	if err = d.Item.toItem(&p.Item); err != nil {
+		return
+	}
+
+	p.Reasons = d.Reasons
+	p.Count = d.Count
+	p.FirstReportedAt = d.First
+	p.LastReportedAt = d.Last
+
+	return
+}
+
+func toAuditDoc(a *domain.Audit) dAudit {
+	return dAudit{
+		dItem:     toItemDoc(&a.Item),
+		Action:    a.Action.ReviewAction(),
+		Operator:  a.Operator,
+		Comment:   a.Comment.ReviewComment(),
+		Reports:   a.Reports,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func (d *dAudit) toAudit(a *domain.Audit) (err error) {
+	if err = d.dItem.toItem(&a.Item); err != nil {
+		return
+	}
+
+	if a.Action, err = domain.NewReviewAction(d.Action); err != nil {
+		return
+	}
+
+	if a.Comment, err = domain.NewReviewComment(d.Comment); err != nil {
+		return
+	}
+
+	a.Id = d.Id
+	a.Operator = d.Operator
+	a.Reports = d.Reports
+	a.CreatedAt = d.CreatedAt
+
+	return
+}
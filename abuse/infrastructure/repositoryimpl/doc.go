@@ -0,0 +1,47 @@
+package repositoryimpl
+
+const (
+	fieldId        = "id"
+	fieldItemType  = "item_type"
+	fieldOwner     = "owner"
+	fieldItemId    = "item_id"
+	fieldReporter  = "reporter"
+	fieldReason    = "reason"
+	fieldStatus    = "status"
+	fieldCreatedAt = "created_at"
+)
+
+type dItem struct {
+	Type  string `bson:"item_type"  json:"item_type"`
+	Owner string `bson:"owner"      json:"owner"`
+	Id    string `bson:"item_id"    json:"item_id"`
+}
+
+type dReport struct {
+	dItem `bson:",inline"`
+
+	Id        string `bson:"id"          json:"id"`
+	Reporter  string `bson:"reporter"    json:"reporter"`
+	Reason    string `bson:"reason"      json:"reason"`
+	Status    string `bson:"status"      json:"status"`
+	CreatedAt int64  `bson:"created_at"  json:"created_at"`
+}
+
+type dPendingItem struct {
+	Item    dItem    `bson:"_id"`
+	Reasons []string `bson:"reasons"`
+	Count   int      `bson:"count"`
+	First   int64    `bson:"first"`
+	Last    int64    `bson:"last"`
+}
+
+type dAudit struct {
+	dItem `bson:",inline"`
+
+	Id        string `bson:"id"          json:"id"`
+	Action    string `bson:"action"      json:"action"`
+	Operator  string `bson:"operator"    json:"operator"`
+	Comment   string `bson:"comment"     json:"comment"`
+	Reports   int    `bson:"reports"     json:"reports"`
+	CreatedAt int64  `bson:"created_at"  json:"created_at"`
+}
@@ -0,0 +1,186 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/abuse/domain/repository"
+	repoerr "github.com/opensourceways/xihe-server/domain/repository"
+)
+
+// maxReasonsOfPendingItem is the max number of the latest reasons of a pending item
+const maxReasonsOfPendingItem = 5
+
+func NewReportRepo(m mongodbClient) repository.Report {
+	return &reportRepoImpl{m}
+}
+
+type reportRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *reportRepoImpl) AddReport(r *domain.Report) (string, error) {
+	doc, err := genDoc(toReportDoc(r))
+	if err != nil {
+		return "", err
+	}
+
+	doc[fieldId] = newId()
+
+	filter := itemFilter(&r.Item)
+	filter[fieldReporter] = r.Reporter.Account()
+	filter[fieldStatus] = domain.ReportStatusPending.ReportStatus()
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.NewDocIfNotExist(ctx, filter, doc)
+
+		return err
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocExists(err) {
+			err = repoerr.NewErrorDuplicateCreating(
+				errors.New("the item has been reported"),
+			)
+		}
+
+		return "", err
+	}
+
+	return doc[fieldId].(string), nil
+}
+
+func (impl *reportRepoImpl) ListPendingItems(opt *repository.PendingItemListOption) (
+	r repository.PendingItemList, err error,
+) {
+	match := bson.M{fieldStatus: domain.ReportStatusPending.ReportStatus()}
+	if opt.Type != nil {
+		match[fieldItemType] = opt.Type.ItemType()
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$sort": bson.M{fieldCreatedAt: -1}},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{
+				fieldItemType: "$" + fieldItemType,
+				fieldOwner:    "$" + fieldOwner,
+				fieldItemId:   "$" + fieldItemId,
+			},
+			"reasons": bson.M{"$push": "$" + fieldReason},
+			"count":   bson.M{"$sum": 1},
+			"first":   bson.M{"$min": "$" + fieldCreatedAt},
+			"last":    bson.M{"$max": "$" + fieldCreatedAt},
+		}},
+		bson.M{"$project": bson.M{
+			"reasons": bson.M{"$slice": bson.A{"$reasons", maxReasonsOfPendingItem}},
+			"count":   1,
+			"first":   1,
+			"last":    1,
+		}},
+		bson.M{"$facet": bson.M{
+			"total": bson.A{bson.M{"$count": "n"}},
+			"items": bson.A{
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "last", Value: -1}}},
+				bson.M{"$skip": opt.CountPerPage * (opt.PageNum - 1)},
+				bson.M{"$limit": opt.CountPerPage},
+			},
+		}},
+	}
+
+	var v []struct {
+		Total []struct {
+			N int `bson:"n"`
+		} `bson:"total"`
+		Items []dPendingItem `bson:"items"`
+	}
+
+	f := func(ctx context.Context) error {
+		cursor, err := impl.cli.Collection().Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err = withContext(f); err != nil || len(v) == 0 {
+		return
+	}
+
+	if len(v[0].Total) > 0 {
+		r.Total = v[0].Total[0].N
+	}
+
+	r.Items = make([]domain.PendingItem, 0, len(v[0].Items))
+	for i := range v[0].Items {
+		var item domain.PendingItem
+		if err = v[0].Items[i].toPendingItem(&item); err != nil {
+			return
+		}
+
+		r.Items = append(r.Items, item)
+	}
+
+	return
+}
+
+func (impl *reportRepoImpl) ListReports(item *domain.Item, status domain.ReportStatus) (
+	[]domain.Report, error,
+) {
+	filter := itemFilter(item)
+	filter[fieldStatus] = status.ReportStatus()
+
+	var v []dReport
+
+	f := func(ctx context.Context) error {
+		return impl.cli.GetDocs(ctx, filter, nil, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	r := make([]domain.Report, len(v))
+	for i := range v {
+		if err := v[i].toReport(&r[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (impl *reportRepoImpl) ResolveReports(item *domain.Item, status domain.ReportStatus) (
+	n int, err error,
+) {
+	filter := itemFilter(item)
+	filter[fieldStatus] = domain.ReportStatusPending.ReportStatus()
+
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().UpdateMany(
+			ctx, filter,
+			bson.M{mongoCmdSet: bson.M{fieldStatus: status.ReportStatus()}},
+		)
+		if err == nil {
+			n = int(r.ModifiedCount)
+		}
+
+		return err
+	}
+
+	err = withContext(f)
+
+	return
+}
+
+func itemFilter(i *domain.Item) bson.M {
+	return bson.M{
+		fieldItemType: i.Type.ItemType(),
+		fieldOwner:    i.Owner.Account(),
+		fieldItemId:   i.Id,
+	}
+}
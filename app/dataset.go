@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
 	"github.com/opensourceways/xihe-server/domain/platform"
@@ -102,6 +103,7 @@ type DatasetDTO struct {
 	UpdatedAt     string   `json:"updated_at"`
 	LikeCount     int      `json:"like_count"`
 	DownloadCount int      `json:"download_count"`
+	ReviewStatus  string   `json:"review_status,omitempty"`
 }
 
 type DatasetDetailDTO struct {
@@ -131,11 +133,13 @@ func NewDatasetService(
 	activity repository.Activity,
 	pr platform.Repository,
 	sender message.ResourceProducer,
+	moderation moderation.Moderation,
 ) DatasetService {
 	return datasetService{
-		repo:     repo,
-		activity: activity,
-		sender:   sender,
+		repo:       repo,
+		activity:   activity,
+		sender:     sender,
+		moderation: moderation,
 		rs: resourceService{
 			user:    user,
 			model:   model,
@@ -151,6 +155,8 @@ type datasetService struct {
 	activity repository.Activity
 	sender   message.ResourceProducer
 	rs       resourceService
+
+	moderation moderation.Moderation
 }
 
 func (s datasetService) CanApplyResourceName(owner domain.Account, name domain.ResourceName) bool {
@@ -158,6 +164,12 @@ func (s datasetService) CanApplyResourceName(owner domain.Account, name domain.R
 }
 
 func (s datasetService) Create(cmd *DatasetCreateCmd, pr platform.Repository) (dto DatasetDTO, err error) {
+	if err = checkResourceContent(
+		s.moderation, moderation.SceneDataset, cmd.Name, cmd.Title, cmd.Desc,
+	); err != nil {
+		return
+	}
+
 	pid, err := pr.New(&platform.RepoOption{
 		Name:     cmd.Name,
 		RepoType: cmd.RepoType,
@@ -240,6 +252,12 @@ func (s datasetService) GetByName(
 		return
 	}
 
+	if !allowPrivacy && v.IsHidden() {
+		err = ErrorPrivateRepo{errors.New("hidden pending review")}
+
+		return
+	}
+
 	d, err := s.rs.listModels(v.RelatedModels)
 	if err != nil {
 		return
@@ -309,6 +327,7 @@ func (s datasetService) toDatasetDTO(d *domain.Dataset, dto *DatasetDTO) {
 		UpdatedAt:     utils.ToDate(d.UpdatedAt),
 		LikeCount:     d.LikeCount,
 		DownloadCount: d.DownloadCount,
+		ReviewStatus:  d.ReviewStatus,
 	}
 
 	if d.Desc != nil {
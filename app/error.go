@@ -12,7 +12,7 @@ type ErrorExccedMaxTrainingRecord struct {
 	error
 }
 
-type ErrorOnlyOneRunningTraining struct {
+type ErrorTrainingQueueFull struct {
 	error
 }
 
@@ -57,6 +57,18 @@ const (
 	ErrorTrainNoOutput     = "train_no_output"
 	ErrorTrainNotFound     = "train_not_found"
 	ErrorTrainExccedMaxNum = "train_excced_max_num" // excced max training num for a user
+	ErrorTrainNotQueued    = "train_not_queued"
+	ErrorTrainQueueFull    = "train_queue_full"
+
+	ErrorTrainInvalidQueuePosition = "train_invalid_queue_position"
+
+	ErrorTrainInvalidSweep  = "train_invalid_sweep"
+	ErrorTrainSweepNotFound = "train_sweep_not_found"
+
+	ErrorTrainOutputTooLarge = "train_output_too_large"
+
+	ErrorTrainInvalidSchedule  = "train_invalid_schedule"
+	ErrorTrainScheduleNotFound = "train_schedule_not_found"
 
 	ErrorWuKongInvalidId        = "wukong_invalid_id"
 	ErrorWuKongInvalidOwner     = "wukong_invalid_owner"
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/platform"
 )
@@ -43,6 +44,12 @@ func (cmd *ProjectForkCmd) toProject(r *domain.Project) {
 }
 
 func (s projectService) Fork(cmd *ProjectForkCmd, pr platform.Repository) (dto ProjectDTO, err error) {
+	if err = checkResourceContent(
+		s.moderation, moderation.SceneProject, cmd.Name, nil, cmd.Desc,
+	); err != nil {
+		return
+	}
+
 	pid, err := pr.Fork(cmd.From.RepoId, cmd.Name)
 	if err != nil {
 		return
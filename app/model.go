@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
 	"github.com/opensourceways/xihe-server/domain/platform"
@@ -103,6 +104,7 @@ type ModelDTO struct {
 	UpdatedAt     string   `json:"updated_at"`
 	LikeCount     int      `json:"like_count"`
 	DownloadCount int      `json:"download_count"`
+	ReviewStatus  string   `json:"review_status,omitempty"`
 }
 
 type ModelDetailDTO struct {
@@ -135,11 +137,13 @@ func NewModelService(
 	activity repository.Activity,
 	pr platform.Repository,
 	sender message.ResourceProducer,
+	moderation moderation.Moderation,
 ) ModelService {
 	return modelService{
-		repo:     repo,
-		activity: activity,
-		sender:   sender,
+		repo:       repo,
+		activity:   activity,
+		sender:     sender,
+		moderation: moderation,
 		rs: resourceService{
 			user:    user,
 			model:   repo,
@@ -155,6 +159,8 @@ type modelService struct {
 	activity repository.Activity
 	rs       resourceService
 	sender   message.ResourceProducer
+
+	moderation moderation.Moderation
 }
 
 func (s modelService) CanApplyResourceName(owner domain.Account, name domain.ResourceName) bool {
@@ -162,6 +168,12 @@ func (s modelService) CanApplyResourceName(owner domain.Account, name domain.Res
 }
 
 func (s modelService) Create(cmd *ModelCreateCmd, pr platform.Repository) (dto ModelDTO, err error) {
+	if err = checkResourceContent(
+		s.moderation, moderation.SceneModel, cmd.Name, cmd.Title, cmd.Desc,
+	); err != nil {
+		return
+	}
+
 	pid, err := pr.New(&platform.RepoOption{
 		Name:     cmd.Name,
 		RepoType: cmd.RepoType,
@@ -244,6 +256,12 @@ func (s modelService) GetByName(
 		return
 	}
 
+	if !allowPrivacy && v.IsHidden() {
+		err = ErrorPrivateRepo{errors.New("hidden pending review")}
+
+		return
+	}
+
 	d, err := s.rs.listDatasets(v.RelatedDatasets)
 	if err != nil {
 		return
@@ -313,6 +331,7 @@ func (s modelService) toModelDTO(m *domain.Model, dto *ModelDTO) {
 		UpdatedAt:     utils.ToDate(m.UpdatedAt),
 		LikeCount:     m.LikeCount,
 		DownloadCount: m.DownloadCount,
+		ReviewStatus:  m.ReviewStatus,
 	}
 
 	if m.Desc != nil {
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+
+	abuseapp "github.com/opensourceways/xihe-server/abuse/app"
+	abusedomain "github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/message"
+)
+
+// maxModerationPathLen keeps the reason of report in the limit of length
+const maxModerationPathLen = 150
+
+// ResourceModerationMessageService re-checks the files committed to the repo.
+// The resource is hidden and put into the review queue if the content is sensitive,
+// and it is restored if the same file is clean again.
+type ResourceModerationMessageService interface {
+	CheckRepoFile(*message.RepoFileCommitted) error
+}
+
+func NewResourceModerationMessageService(
+	moderation moderation.Moderation,
+	abuse abuseapp.ModerationService,
+) ResourceModerationMessageService {
+	return resourceModerationMessageService{
+		moderation: moderation,
+		abuse:      abuse,
+	}
+}
+
+type resourceModerationMessageService struct {
+	moderation moderation.Moderation
+	abuse      abuseapp.ModerationService
+}
+
+func (s resourceModerationMessageService) CheckRepoFile(e *message.RepoFileCommitted) error {
+	err := s.moderation.CheckText(moderation.SceneRepo, e.Content)
+	if err != nil && !moderation.IsErrorSensitiveContent(err) {
+		return err
+	}
+
+	cmd, err1 := toModerationCmd(e)
+	if err1 != nil {
+		return err1
+	}
+
+	if err == nil {
+		return s.abuse.Restore(&cmd)
+	}
+
+	return s.abuse.Hide(&cmd)
+}
+
+func toModerationCmd(e *message.RepoFileCommitted) (cmd abuseapp.ModerationCmd, err error) {
+	switch e.Resource.Type.ResourceType() {
+	case domain.ResourceTypeProject.ResourceType():
+		cmd.Item.Type = abusedomain.ItemTypeProject
+
+	case domain.ResourceTypeModel.ResourceType():
+		cmd.Item.Type = abusedomain.ItemTypeModel
+
+	case domain.ResourceTypeDataset.ResourceType():
+		cmd.Item.Type = abusedomain.ItemTypeDataset
+
+	default:
+		err = fmt.Errorf("unsupported resource type %s", e.Resource.Type.ResourceType())
+
+		return
+	}
+
+	cmd.Item.Owner = e.Resource.Owner
+	cmd.Item.Id = e.Resource.Id
+
+	path := e.Path
+	if v := []rune(path); len(v) > maxModerationPathLen {
+		path = "..." + string(v[len(v)-maxModerationPathLen:])
+	}
+
+	// the reason is used to match the file when it is clean again
+	cmd.Reason, err = abusedomain.NewReportReason("sensitive content in file " + path)
+
+	return
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
 	"github.com/opensourceways/xihe-server/domain/platform"
@@ -121,6 +122,7 @@ type ProjectDTO struct {
 	LikeCount     int      `json:"like_count"`
 	ForkCount     int      `json:"fork_count"`
 	DownloadCount int      `json:"download_count"`
+	ReviewStatus  string   `json:"review_status,omitempty"`
 }
 
 type ProjectDetailDTO struct {
@@ -157,11 +159,13 @@ func NewProjectService(
 	activity repository.Activity,
 	pr platform.Repository,
 	sender message.ResourceProducer,
+	moderation moderation.Moderation,
 ) ProjectService {
 	return projectService{
-		repo:     repo,
-		activity: activity,
-		sender:   sender,
+		repo:       repo,
+		activity:   activity,
+		sender:     sender,
+		moderation: moderation,
 		rs: resourceService{
 			user:    user,
 			model:   model,
@@ -177,6 +181,8 @@ type projectService struct {
 	activity repository.Activity
 	sender   message.ResourceProducer
 	rs       resourceService
+
+	moderation moderation.Moderation
 }
 
 func (s projectService) CanApplyResourceName(owner domain.Account, name domain.ResourceName) bool {
@@ -184,6 +190,12 @@ func (s projectService) CanApplyResourceName(owner domain.Account, name domain.R
 }
 
 func (s projectService) Create(cmd *ProjectCreateCmd, pr platform.Repository) (dto ProjectDTO, err error) {
+	if err = checkResourceContent(
+		s.moderation, moderation.SceneProject, cmd.Name, cmd.Title, cmd.Desc,
+	); err != nil {
+		return
+	}
+
 	// step1: create repo on gitlab
 	pid, err := pr.New(&platform.RepoOption{
 		Name:     cmd.Name,
@@ -268,6 +280,12 @@ func (s projectService) GetByName(
 		return
 	}
 
+	if !allowPrivacy && v.IsHidden() {
+		err = ErrorPrivateRepo{errors.New("hidden pending review")}
+
+		return
+	}
+
 	m, err := s.rs.listModels(v.RelatedModels)
 	if err != nil {
 		return
@@ -351,6 +369,7 @@ func (s projectService) toProjectDTO(p *domain.Project, dto *ProjectDTO) {
 		LikeCount:     p.LikeCount,
 		ForkCount:     p.ForkCount,
 		DownloadCount: p.DownloadCount,
+		ReviewStatus:  p.ReviewStatus,
 	}
 
 	if p.Desc != nil {
@@ -4,7 +4,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"io"
+	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
@@ -29,16 +31,22 @@ type RepoFileService interface {
 	DownloadRepo(u *UserInfo, obj *domain.RepoDownloadedEvent, handle func(io.Reader, int64)) error
 }
 
-func NewRepoFileService(rf platform.RepoFile, sender message.RepoMessageProducer) RepoFileService {
+func NewRepoFileService(
+	rf platform.RepoFile,
+	sender message.RepoMessageProducer,
+	producer message.RepoFileProducer,
+) RepoFileService {
 	return &repoFileService{
-		rf:     rf,
-		sender: sender,
+		rf:       rf,
+		sender:   sender,
+		producer: producer,
 	}
 }
 
 type repoFileService struct {
-	rf     platform.RepoFile
-	sender message.RepoMessageProducer
+	rf       platform.RepoFile
+	sender   message.RepoMessageProducer
+	producer message.RepoFileProducer
 }
 
 type RepoFileListCmd = RepoDir
@@ -58,6 +66,9 @@ type RepoFileCreateCmd struct {
 	RepoFileInfo
 
 	RepoFileContent
+
+	// Resource is the resource which the file belongs to
+	Resource domain.ResourceObject
 }
 
 type RepoFileUpdateCmd = RepoFileCreateCmd
@@ -74,7 +85,13 @@ func (cmd *RepoFileCreateCmd) Validate() error {
 }
 
 func (s *repoFileService) Create(u *platform.UserInfo, cmd *RepoFileCreateCmd) error {
-	return s.rf.Create(u, &cmd.RepoFileInfo, &cmd.RepoFileContent)
+	if err := s.rf.Create(u, &cmd.RepoFileInfo, &cmd.RepoFileContent); err != nil {
+		return err
+	}
+
+	s.sendRepoFileCommitted(cmd)
+
+	return nil
 }
 
 func (s *repoFileService) Update(u *platform.UserInfo, cmd *RepoFileUpdateCmd) error {
@@ -89,7 +106,51 @@ func (s *repoFileService) Update(u *platform.UserInfo, cmd *RepoFileUpdateCmd) e
 		}
 	}
 
-	return s.rf.Update(u, &cmd.RepoFileInfo, &cmd.RepoFileContent)
+	if err = s.rf.Update(u, &cmd.RepoFileInfo, &cmd.RepoFileContent); err != nil {
+		return err
+	}
+
+	s.sendRepoFileCommitted(cmd)
+
+	return nil
+}
+
+// sendRepoFileCommitted sends the content of text file to be re-checked asynchronously
+func (s *repoFileService) sendRepoFileCommitted(cmd *RepoFileCreateCmd) {
+	if s.producer == nil || cmd.Content == nil || cmd.Resource.Type == nil {
+		return
+	}
+
+	if !isTextFileToCheck(cmd.Path.FilePath()) {
+		return
+	}
+
+	content := *cmd.Content
+	if cmd.IsEncoded {
+		v, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return
+		}
+
+		content = string(v)
+	}
+
+	_ = s.producer.SendRepoFileCommitted(&message.RepoFileCommitted{
+		Resource: cmd.Resource,
+		Path:     cmd.Path.FilePath(),
+		Content:  content,
+	})
+}
+
+func isTextFileToCheck(p string) bool {
+	name := strings.ToLower(filepath.Base(p))
+	if strings.HasPrefix(name, "readme") {
+		return true
+	}
+
+	ext := filepath.Ext(name)
+
+	return ext == ".md" || ext == ".txt"
 }
 
 func (s *repoFileService) Delete(u *platform.UserInfo, cmd *RepoFileDeleteCmd) error {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/repository"
 	userdomain "github.com/opensourceways/xihe-server/user/domain"
@@ -450,3 +451,31 @@ func (s resourceService) IsPrivate(owner domain.Account, resourceType domain.Res
 
 	return false, false
 }
+
+// checkResourceContent checks the name, title and description of a resource
+func checkResourceContent(
+	m moderation.Moderation, scene string,
+	name domain.ResourceName, title domain.ResourceTitle, desc domain.ResourceDesc,
+) error {
+	if m == nil {
+		return nil
+	}
+
+	if name != nil {
+		if err := m.CheckText(scene, name.ResourceName()); err != nil {
+			return err
+		}
+	}
+
+	if title != nil {
+		if err := m.CheckText(scene, title.ResourceTitle()); err != nil {
+			return err
+		}
+	}
+
+	if desc != nil {
+		return m.CheckText(scene, desc.ResourceDesc())
+	}
+
+	return nil
+}
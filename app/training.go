@@ -2,10 +2,13 @@ package app
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"strconv"
 
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
+	"github.com/opensourceways/xihe-server/domain/platform"
 	"github.com/opensourceways/xihe-server/domain/repository"
 	"github.com/opensourceways/xihe-server/domain/training"
 	"github.com/opensourceways/xihe-server/utils"
@@ -13,8 +16,12 @@ import (
 )
 
 const (
+	trainingStatusQueued         = "queued"
+	trainingStatusCancelled      = "cancelled"
 	trainingStatusScheduling     = "scheduling"
 	trainingStatusScheduleFailed = "schedule_failed"
+
+	retryNumOfUpdatingTrainingQueue = 3
 )
 
 type JobDetail = domain.JobDetail
@@ -23,7 +30,7 @@ type TrainingConfig = domain.TrainingConfig
 
 type TrainingService interface {
 	Create(*TrainingCreateCmd) (string, error)
-	Recreate(*TrainingIndex) (string, error)
+	Recreate(info *TrainingIndex, token string) (string, error)
 	UpdateJobDetail(*TrainingIndex, *JobDetail) error
 	List(user domain.Account, projectId string) ([]TrainingSummaryDTO, error)
 	Get(*TrainingIndex) (TrainingDTO, string, error)
@@ -32,41 +39,70 @@ type TrainingService interface {
 	GetLogDownloadURL(*TrainingIndex) (string, string, error)
 	GetOutputDownloadURL(*TrainingIndex) (string, string, error)
 	CreateTrainingJob(*TrainingIndex, string, bool) (bool, error)
+
+	// CheckCapacity checks whether n trainings can be created for the project
+	CheckCapacity(user domain.Account, projectId string, n int) error
+
+	// queue
+	ListQueue(domain.Account) (TrainingQueueDTO, error)
+	MoveQueued(info *TrainingIndex, position int) (string, error)
+	CancelQueued(*TrainingIndex) (string, error)
+
+	PublishOutput(*TrainingPublishCmd, platform.Repository) (TrainingPublishDTO, string, error)
 }
 
 func NewTrainingService(
 	train training.Training,
 	repo repository.Training,
+	queue repository.TrainingQueue,
 	sender message.MessageProducer,
+	model ModelService,
+	rf platform.RepoFile,
+	schedule repository.TrainingSchedule,
 	maxTrainingRecordNum int,
 ) TrainingService {
 	return trainingService{
-		train:  train,
-		repo:   repo,
-		sender: sender,
+		log:      logrus.NewEntry(logrus.StandardLogger()),
+		train:    train,
+		repo:     repo,
+		queue:    queue,
+		sender:   sender,
+		model:    model,
+		rf:       rf,
+		schedule: schedule,
 
 		maxTrainingRecordNum: maxTrainingRecordNum,
 	}
 }
 
 type trainingService struct {
-	log    *logrus.Entry
-	train  training.Training
-	repo   repository.Training
-	sender message.MessageProducer
+	log      *logrus.Entry
+	train    training.Training
+	repo     repository.Training
+	queue    repository.TrainingQueue
+	sender   message.MessageProducer
+	model    ModelService
+	rf       platform.RepoFile
+	schedule repository.TrainingSchedule
 
 	maxTrainingRecordNum int
 }
 
 func (s trainingService) isJobDone(status string) bool {
-	return status != "" && (s.train.IsJobDone(status) || status == trainingStatusScheduleFailed)
+	return isTrainingJobDone(s.train, status)
+}
+
+func isTrainingJobDone(train training.Training, status string) bool {
+	return status != "" && (train.IsJobDone(status) ||
+		status == trainingStatusScheduleFailed ||
+		status == trainingStatusCancelled)
 }
 
 func (s trainingService) Create(cmd *TrainingCreateCmd) (string, error) {
-	return s.create(cmd.User, cmd.ProjectId, cmd.toTrainingConfig())
+	return s.create(cmd.User, cmd.ProjectId, cmd.Token, cmd.toTrainingConfig())
 }
 
-func (s trainingService) Recreate(info *TrainingIndex) (string, error) {
+func (s trainingService) Recreate(info *TrainingIndex, token string) (string, error) {
 	v, err := s.repo.GetTrainingConfig(info)
 	if err != nil {
 		return "", err
@@ -79,29 +115,79 @@ func (s trainingService) Recreate(info *TrainingIndex) (string, error) {
 		return "", err
 	}
 
-	return s.create(info.Project.Owner, info.Project.Id, &v)
+	return s.create(info.Project.Owner, info.Project.Id, token, &v)
+}
+
+func (s trainingService) CheckCapacity(user domain.Account, projectId string, n int) error {
+	v, _, err := s.repo.List(user, projectId)
+	if err != nil {
+		return err
+	}
+
+	removable, err := s.removableTrainings(user, projectId, v)
+	if err != nil {
+		return err
+	}
+
+	if kept := len(v) - len(removable); kept+n > s.maxTrainingRecordNum {
+		return ErrorExccedMaxTrainingRecord{
+			fmt.Errorf(
+				"exceed max training num, only %d trainings can be created",
+				s.maxTrainingRecordNum-kept,
+			),
+		}
+	}
+
+	q, err := s.queue.Get(user)
+	if err != nil {
+		return err
+	}
+
+	if len(q.Queued)+n > domain.DomainConfig.MaxQueuedTrainings {
+		return ErrorTrainingQueueFull{
+			fmt.Errorf(
+				"the training queue is full, only %d trainings can be queued",
+				domain.DomainConfig.MaxQueuedTrainings-len(q.Queued),
+			),
+		}
+	}
+
+	return nil
 }
 
 func (s trainingService) create(
-	user domain.Account, projectId string, config *TrainingConfig,
+	user domain.Account, projectId, token string, config *TrainingConfig,
 ) (string, error) {
 	v, version, err := s.repo.List(user, projectId)
 	if err != nil {
 		return "", err
 	}
 
-	if len(v) >= s.maxTrainingRecordNum {
-		return "", ErrorExccedMaxTrainingRecord{
-			errors.New("exceed max training num"),
+	// the oldest finished trainings are removed to make room for the new one,
+	// so the trainings are only limited by the ones unfinished.
+	if n := len(v) - s.maxTrainingRecordNum + 1; n > 0 {
+		if err = s.removeOldest(user, projectId, v, n); err != nil {
+			return "", err
+		}
+
+		if _, version, err = s.repo.List(user, projectId); err != nil {
+			return "", err
 		}
 	}
 
-	for i := range v {
-		if !s.isJobDone(v[i].Status) {
-			return "", ErrorOnlyOneRunningTraining{
-				errors.New("a training is running"),
+	// the queue is saved if it is new, so that the training
+	// saved below will not be adopted as a running one.
+	err = s.updateQueue(user, func(q *domain.TrainingQueue) (bool, error) {
+		if q.IsFull() {
+			return false, ErrorTrainingQueueFull{
+				errors.New("the training queue is full"),
 			}
 		}
+
+		return q.Version == 0, nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	t := domain.UserTraining{
@@ -109,6 +195,7 @@ func (s trainingService) create(
 		ProjectId:      projectId,
 		CreatedAt:      utils.Now(),
 		TrainingConfig: *config,
+		Commit:         s.projectCommit(user, token, config),
 	}
 
 	r, err := s.repo.Save(&t, version)
@@ -116,7 +203,6 @@ func (s trainingService) create(
 		return "", err
 	}
 
-	// send message
 	index := TrainingIndex{
 		Project: domain.ResourceIndex{
 			Owner: user,
@@ -125,27 +211,258 @@ func (s trainingService) create(
 		TrainingId: r,
 	}
 
-	err = s.sender.SendTrainingCreated(&domain.TrainingCreatedEvent{
-		Account:        user,
-		TrainingIndex:  index,
-		TrainingInputs: config.Inputs,
+	// the training waits in the queue until there is a free slot
+	err = s.updateQueue(user, func(q *domain.TrainingQueue) (bool, error) {
+		return true, q.Enqueue(&domain.QueuedTraining{
+			TrainingIndex: index,
+			CreatedAt:     t.CreatedAt,
+		})
 	})
 	if err != nil {
-		s.log.Errorf("send message of creating training failed, err:%s", err.Error())
+		if err1 := s.repo.Delete(&index); err1 != nil {
+			s.log.Errorf("delete the training(%s) which can't be queued, err:%s", r, err1.Error())
+		}
+
+		if !repository.IsErrorConcurrentUpdating(err) {
+			err = ErrorTrainingQueueFull{err}
+		}
+
+		return "", err
 	}
 
+	s.dispatch(user)
+
 	return r, nil
 }
 
+// removableTrainings returns the finished trainings of the project from the oldest,
+// except the ones which the schedules are attached to.
+func (s trainingService) removableTrainings(
+	user domain.Account, projectId string, v []domain.TrainingSummary,
+) ([]domain.TrainingSummary, error) {
+	attached := map[string]bool{}
+
+	if s.schedule != nil {
+		items, err := s.schedule.List(user, projectId)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range items {
+			attached[items[i].TrainingId] = true
+		}
+	}
+
+	r := make([]domain.TrainingSummary, 0, len(v))
+	for i := range v {
+		if s.isJobDone(v[i].Status) && !attached[v[i].Id] {
+			r = append(r, v[i])
+		}
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].CreatedAt < r[j].CreatedAt
+	})
+
+	return r, nil
+}
+
+// removeOldest removes n oldest finished trainings of the project.
+func (s trainingService) removeOldest(
+	user domain.Account, projectId string, v []domain.TrainingSummary, n int,
+) error {
+	removable, err := s.removableTrainings(user, projectId, v)
+	if err != nil {
+		return err
+	}
+
+	if len(removable) < n {
+		return ErrorExccedMaxTrainingRecord{
+			errors.New("exceed max training num, and the unfinished trainings can't be removed"),
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		index := TrainingIndex{
+			Project: domain.ResourceIndex{
+				Owner: user,
+				Id:    projectId,
+			},
+			TrainingId: removable[i].Id,
+		}
+
+		if err := s.Delete(&index); err != nil {
+			return err
+		}
+
+		s.log.Infof("remove the old training(%s) of project %s", index.TrainingId, projectId)
+	}
+
+	return nil
+}
+
+// projectCommit returns the last commit of the code dir which the training runs.
+// It is empty if the repo file is not available, such as in the message server.
+func (s trainingService) projectCommit(
+	user domain.Account, token string, config *TrainingConfig,
+) string {
+	if s.rf == nil {
+		return ""
+	}
+
+	commit, _, err := s.rf.GetDirFileInfo(
+		&UserInfo{User: user, Token: token},
+		&platform.RepoDirFile{
+			RepoName: config.ProjectName,
+			Dir:      config.CodeDir,
+			File:     config.BootFile,
+		},
+	)
+	if err != nil {
+		s.log.Errorf("get the commit of project %s failed, err:%s", config.ProjectName.ResourceName(), err.Error())
+	}
+
+	return commit
+}
+
+// updateQueue retries when the queue is updated concurrently
+func (s trainingService) updateQueue(
+	user domain.Account, f func(*domain.TrainingQueue) (bool, error),
+) (err error) {
+	var q domain.TrainingQueue
+	var changed bool
+
+	for i := 0; i < retryNumOfUpdatingTrainingQueue; i++ {
+		if q, err = s.queue.Get(user); err != nil {
+			return
+		}
+
+		// the queue is created right now
+		adopted := false
+		if q.Version == 0 {
+			if adopted, err = s.adoptRunning(&q); err != nil {
+				return
+			}
+		}
+
+		if changed, err = f(&q); err != nil || !(changed || adopted) {
+			return
+		}
+
+		if err = s.queue.Save(&q); err == nil || !repository.IsErrorConcurrentUpdating(err) {
+			return
+		}
+	}
+
+	return
+}
+
+// adoptRunning counts the trainings which were started before the queue
+// was created as the running ones, and returns whether there is any.
+func (s trainingService) adoptRunning(q *domain.TrainingQueue) (bool, error) {
+	v, err := s.repo.ListStatus(q.Owner)
+	if err != nil {
+		return false, err
+	}
+
+	n := len(q.Running)
+	for i := range v {
+		if !s.isJobDone(v[i].Status) && q.Position(v[i].TrainingId) == 0 {
+			q.Running = append(q.Running, v[i].TrainingIndex)
+		}
+	}
+
+	return len(q.Running) > n, nil
+}
+
+// dispatch starts the queued trainings of user if there are free slots
+func (s trainingService) dispatch(user domain.Account) {
+	var started []domain.TrainingIndex
+
+	err := s.updateQueue(user, func(q *domain.TrainingQueue) (bool, error) {
+		started = q.Dispatch()
+
+		return len(started) > 0, nil
+	})
+	if err != nil {
+		s.log.Errorf("dispatch trainings of %s failed, err:%s", user.Account(), err.Error())
+
+		return
+	}
+
+	for i := range started {
+		index := &started[i]
+
+		if err := s.start(index); err != nil {
+			s.log.Errorf(
+				"start training(%s) failed, err:%s", index.TrainingId, err.Error(),
+			)
+
+			// fail the training, otherwise it holds the slot forever
+			err = s.repo.UpdateJobDetail(index, &JobDetail{
+				Status: trainingStatusScheduleFailed,
+				Error:  err.Error(),
+			})
+			if err != nil && !repository.IsErrorResourceNotExists(err) {
+				s.log.Errorf(
+					"update status of training(%s) failed, err:%s", index.TrainingId, err.Error(),
+				)
+			}
+
+			s.release(index)
+		}
+	}
+}
+
+func (s trainingService) start(index *TrainingIndex) error {
+	config, err := s.repo.GetTrainingConfig(index)
+	if err != nil {
+		if repository.IsErrorResourceNotExists(err) {
+			// the training is deleted after it is dispatched
+			s.release(index)
+
+			return nil
+		}
+
+		return err
+	}
+
+	return s.sender.SendTrainingCreated(&domain.TrainingCreatedEvent{
+		Account:        index.Project.Owner,
+		TrainingIndex:  *index,
+		TrainingInputs: config.Inputs,
+	})
+}
+
+// release frees the slot or the place in the queue of training,
+// and starts the next queued training.
+func (s trainingService) release(index *TrainingIndex) {
+	err := s.updateQueue(index.Project.Owner, func(q *domain.TrainingQueue) (bool, error) {
+		return q.Release(index.TrainingId) || q.Remove(index.TrainingId), nil
+	})
+	if err != nil {
+		s.log.Errorf("release training(%s) failed, err:%s", index.TrainingId, err.Error())
+
+		return
+	}
+
+	s.dispatch(index.Project.Owner)
+}
+
 func (s trainingService) List(user domain.Account, projectId string) ([]TrainingSummaryDTO, error) {
 	v, _, err := s.repo.List(user, projectId)
 	if err != nil || len(v) == 0 {
 		return nil, err
 	}
 
+	q, err := s.queue.Get(user)
+	if err != nil {
+		return nil, err
+	}
+
 	r := make([]TrainingSummaryDTO, len(v))
 	for i := range v {
-		s.toTrainingSummaryDTO(&v[i], &r[i])
+		s.toTrainingSummaryDTO(&v[i], &r[i], q.Position(v[i].Id))
 	}
 
 	return r, nil
@@ -169,13 +486,26 @@ func (s trainingService) Get(info *TrainingIndex) (dto TrainingDTO, code string,
 		}
 	}
 
-	s.toTrainingDTO(&dto, &data, link)
+	q, err := s.queue.Get(info.Project.Owner)
+	if err != nil {
+		return
+	}
+
+	s.toTrainingDTO(&dto, &data, link, q.Position(info.TrainingId))
 
 	return
 }
 
 func (s trainingService) UpdateJobDetail(info *TrainingIndex, v *JobDetail) error {
-	return s.repo.UpdateJobDetail(info, v)
+	if err := s.repo.UpdateJobDetail(info, v); err != nil {
+		return err
+	}
+
+	if s.isJobDone(v.Status) {
+		s.release(info)
+	}
+
+	return nil
 }
 
 func (s trainingService) Delete(info *TrainingIndex) error {
@@ -195,10 +525,21 @@ func (s trainingService) Delete(info *TrainingIndex) error {
 		}
 	}
 
-	return s.repo.Delete(info)
+	if err = s.repo.Delete(info); err != nil {
+		return err
+	}
+
+	s.release(info)
+
+	return nil
 }
 
+// Terminate cancels the training if it is waiting in the queue
 func (s trainingService) Terminate(info *TrainingIndex) error {
+	if code, err := s.CancelQueued(info); code != ErrorTrainNotQueued {
+		return err
+	}
+
 	job, err := s.repo.GetJob(info)
 	if err != nil || job.JobId == "" {
 		return err
@@ -256,6 +597,8 @@ func (s trainingService) CreateTrainingJob(
 			Status: trainingStatusScheduleFailed,
 			Error:  err.Error(),
 		})
+
+		s.release(info)
 	}
 
 	return
@@ -297,3 +640,71 @@ func (s trainingService) createTrainingJob(info *TrainingIndex, endpoint string)
 
 	return
 }
+
+func (s trainingService) ListQueue(user domain.Account) (dto TrainingQueueDTO, err error) {
+	q, err := s.queue.Get(user)
+	if err != nil {
+		return
+	}
+
+	dto.Running = make([]TrainingQueueItemDTO, len(q.Running))
+	for i := range q.Running {
+		dto.Running[i] = TrainingQueueItemDTO{
+			ProjectId:  q.Running[i].Project.Id,
+			TrainingId: q.Running[i].TrainingId,
+		}
+	}
+
+	dto.Queued = make([]TrainingQueueItemDTO, len(q.Queued))
+	for i := range q.Queued {
+		item := &q.Queued[i]
+
+		dto.Queued[i] = TrainingQueueItemDTO{
+			ProjectId:  item.Project.Id,
+			TrainingId: item.TrainingId,
+			Position:   i + 1,
+			CreatedAt:  utils.ToDate(item.CreatedAt),
+		}
+	}
+
+	return
+}
+
+func (s trainingService) MoveQueued(info *TrainingIndex, position int) (code string, err error) {
+	err = s.updateQueue(info.Project.Owner, func(q *domain.TrainingQueue) (bool, error) {
+		if q.Position(info.TrainingId) == 0 {
+			code = ErrorTrainNotQueued
+
+			return false, errors.New("the training is not in the queue")
+		}
+
+		if err := q.Move(info.TrainingId, position); err != nil {
+			code = ErrorTrainInvalidQueuePosition
+
+			return false, err
+		}
+
+		return true, nil
+	})
+
+	return
+}
+
+func (s trainingService) CancelQueued(info *TrainingIndex) (code string, err error) {
+	err = s.updateQueue(info.Project.Owner, func(q *domain.TrainingQueue) (bool, error) {
+		if !q.Remove(info.TrainingId) {
+			code = ErrorTrainNotQueued
+
+			return false, errors.New("the training is not in the queue")
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return
+	}
+
+	err = s.repo.UpdateJobDetail(info, &JobDetail{Status: trainingStatusCancelled})
+
+	return
+}
@@ -11,6 +11,9 @@ type TrainingCreateCmd struct {
 	User      domain.Account
 	ProjectId string
 
+	// Token is the platform token of user to get the commit of project
+	Token string
+
 	domain.TrainingConfig
 }
 
@@ -76,24 +79,36 @@ type TrainingSummaryDTO struct {
 	CreatedAt string `json:"created_at"`
 	IsDone    bool   `json:"is_done"`
 	Duration  int    `json:"duration"`
+
+	// QueuePosition is the position in the queue of user starting from 1,
+	// and 0 means the training is not waiting in the queue.
+	QueuePosition int `json:"queue_position"`
 }
 
-func (s trainingService) toTrainingSummaryDTO(
-	t *domain.TrainingSummary, dto *TrainingSummaryDTO,
-) {
-	status := t.Status
-	if status == "" {
-		status = trainingStatusScheduling
+func (s trainingService) trainingStatus(status string, position int) string {
+	if status != "" {
+		return status
+	}
+
+	if position > 0 {
+		return trainingStatusQueued
 	}
 
+	return trainingStatusScheduling
+}
+
+func (s trainingService) toTrainingSummaryDTO(
+	t *domain.TrainingSummary, dto *TrainingSummaryDTO, position int,
+) {
 	*dto = TrainingSummaryDTO{
-		Id:        t.Id,
-		Name:      t.Name.TrainingName(),
-		Error:     t.Error,
-		Status:    status,
-		IsDone:    s.isJobDone(t.Status),
-		Duration:  t.Duration,
-		CreatedAt: utils.ToDate(t.CreatedAt),
+		Id:            t.Id,
+		Name:          t.Name.TrainingName(),
+		Error:         t.Error,
+		Status:        s.trainingStatus(t.Status, position),
+		IsDone:        s.isJobDone(t.Status),
+		Duration:      t.Duration,
+		CreatedAt:     utils.ToDate(t.CreatedAt),
+		QueuePosition: position,
 	}
 
 	if t.Desc != nil {
@@ -117,6 +132,8 @@ type TrainingDTO struct {
 	AimPath   string     `json:"aim_path"`
 	EnableAim bool       `json:"enable_aim"`
 
+	QueuePosition int `json:"queue_position"`
+
 	LogPreviewURL string `json:"-"`
 }
 
@@ -126,16 +143,13 @@ type ComputeDTO struct {
 	Flavor  string `json:"flavor"`
 }
 
-func (s trainingService) toTrainingDTO(dto *TrainingDTO, ut *domain.UserTraining, link string) {
+func (s trainingService) toTrainingDTO(
+	dto *TrainingDTO, ut *domain.UserTraining, link string, position int,
+) {
 	t := &ut.TrainingConfig
 	detail := &ut.JobDetail
 	c := &t.Compute
 
-	status := detail.Status
-	if status == "" {
-		status = trainingStatusScheduling
-	}
-
 	*dto = TrainingDTO{
 		Id:        ut.Id,
 		ProjectId: ut.ProjectId,
@@ -143,7 +157,7 @@ func (s trainingService) toTrainingDTO(dto *TrainingDTO, ut *domain.UserTraining
 		Name:      t.Name.TrainingName(),
 		IsDone:    s.isJobDone(detail.Status),
 		Error:     detail.Error,
-		Status:    status,
+		Status:    s.trainingStatus(detail.Status, position),
 		Duration:  detail.Duration,
 		CreatedAt: utils.ToDate(ut.CreatedAt),
 		Compute: ComputeDTO{
@@ -154,6 +168,7 @@ func (s trainingService) toTrainingDTO(dto *TrainingDTO, ut *domain.UserTraining
 		EnableAim: t.EnableAim,
 		AimPath:   ut.JobDetail.AimPath,
 
+		QueuePosition: position,
 		LogPreviewURL: link,
 	}
 
@@ -161,3 +176,15 @@ func (s trainingService) toTrainingDTO(dto *TrainingDTO, ut *domain.UserTraining
 		dto.Desc = t.Desc.TrainingDesc()
 	}
 }
+
+type TrainingQueueItemDTO struct {
+	ProjectId  string `json:"project_id"`
+	TrainingId string `json:"training_id"`
+	Position   int    `json:"position,omitempty"`
+	CreatedAt  string `json:"created_at,omitempty"`
+}
+
+type TrainingQueueDTO struct {
+	Running []TrainingQueueItemDTO `json:"running"`
+	Queued  []TrainingQueueItemDTO `json:"queued"`
+}
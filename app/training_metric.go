@@ -0,0 +1,286 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/domain/training"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+const maxComparedTrainings = 10
+
+// trainingMetricLogMarker marks the line of training log which is a point of metric,
+// such as: [xihe-metric] {"name": "loss", "step": 10, "value": 0.25}
+// The training prints the metrics to its log, and they are ingested when the callback
+// of training reports the path of log.
+const trainingMetricLogMarker = "[xihe-metric]"
+
+type trainingMetricLogLine struct {
+	Name  string  `json:"name"`
+	Step  int     `json:"step"`
+	Value float64 `json:"value"`
+}
+
+// parseTrainingMetricLog returns the points of metrics in the log keyed by the name.
+// The lines which are not valid are skipped, and only the last points of each metric
+// are kept if there are too many of them.
+func parseTrainingMetricLog(r io.Reader) (map[string][]domain.MetricPoint, error) {
+	metrics := map[string][]domain.MetricPoint{}
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if i := strings.Index(line, trainingMetricLogMarker); i >= 0 {
+			item := trainingMetricLogLine{}
+			data := line[i+len(trainingMetricLogMarker):]
+
+			if json.Unmarshal([]byte(data), &item) == nil && item.Name != "" && item.Step >= 0 {
+				metrics[item.Name] = append(metrics[item.Name], domain.MetricPoint{
+					Step:  item.Step,
+					Value: item.Value,
+				})
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	max := domain.DomainConfig.MaxTrainingMetricPoints
+	for k, v := range metrics {
+		if len(v) > max {
+			metrics[k] = v[len(v)-max:]
+		}
+	}
+
+	return metrics, nil
+}
+
+type TrainingMetricCompareCmd struct {
+	Project     domain.ResourceIndex
+	TrainingIds []string
+
+	// Names is the metrics to compare, all the metrics if it is empty
+	Names []string
+}
+
+func (cmd *TrainingMetricCompareCmd) Validate() error {
+	if n := len(cmd.TrainingIds); n == 0 || n > maxComparedTrainings {
+		return errors.New("invalid number of trainings to compare")
+	}
+
+	return nil
+}
+
+type MetricPointDTO struct {
+	Step  int     `json:"step"`
+	Value float64 `json:"value"`
+}
+
+type TrainingMetricSeriesDTO struct {
+	TrainingId   string           `json:"training_id"`
+	TrainingName string           `json:"training_name"`
+	Points       []MetricPointDTO `json:"points"`
+}
+
+// TrainingMetricDTO overlays the series of the same metric of trainings
+type TrainingMetricDTO struct {
+	Name   string                    `json:"name"`
+	Series []TrainingMetricSeriesDTO `json:"series"`
+}
+
+type TrainingMetricService interface {
+	// IngestLog records the step-wise metrics printed in the log of training
+	IngestLog(info *TrainingIndex, logPath string) error
+	Get(*TrainingIndex) ([]TrainingMetricDTO, error)
+	Compare(*TrainingMetricCompareCmd) ([]TrainingMetricDTO, error)
+}
+
+func NewTrainingMetricService(
+	train training.Training,
+	repo repository.Training,
+	metric repository.TrainingMetric,
+) TrainingMetricService {
+	return trainingMetricService{
+		log:    logrus.NewEntry(logrus.StandardLogger()),
+		train:  train,
+		repo:   repo,
+		metric: metric,
+	}
+}
+
+type trainingMetricService struct {
+	log    *logrus.Entry
+	train  training.Training
+	repo   repository.Training
+	metric repository.TrainingMetric
+}
+
+// IngestLog parses the whole log every time the path of log is reported,
+// so only the points after the last step saved are appended.
+func (s trainingMetricService) IngestLog(info *TrainingIndex, logPath string) error {
+	_, endpoint, err := s.repo.GetJobDetail(info)
+	if err != nil {
+		return err
+	}
+
+	var metrics map[string][]domain.MetricPoint
+
+	err = s.train.DownloadFile(endpoint, logPath, func(r io.Reader, _ int64) (err error) {
+		metrics, err = parseTrainingMetricLog(r)
+
+		return
+	})
+	if err != nil || len(metrics) == 0 {
+		return err
+	}
+
+	saved, err := s.metric.List(&info.Project, []string{info.TrainingId}, nil)
+	if err != nil {
+		return err
+	}
+
+	last := make(map[string]int, len(saved))
+	for i := range saved {
+		if p, ok := saved[i].Latest(); ok {
+			last[saved[i].Name.CustomizedKey()] = p.Step
+		}
+	}
+
+	now := utils.Now()
+
+	for name, points := range metrics {
+		m, err := s.newTrainingMetric(info, name, points, last, now)
+		if err != nil {
+			s.log.Errorf(
+				"invalid metric %s of training(%s), err:%s",
+				name, info.TrainingId, err.Error(),
+			)
+
+			continue
+		}
+
+		if len(m.Points) == 0 {
+			continue
+		}
+
+		if err := s.metric.Append(&m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s trainingMetricService) newTrainingMetric(
+	info *TrainingIndex, name string, points []domain.MetricPoint,
+	last map[string]int, now int64,
+) (m domain.TrainingMetric, err error) {
+	k, err := domain.NewCustomizedKey(name)
+	if err != nil {
+		return
+	}
+
+	m = domain.TrainingMetric{
+		TrainingIndex: *info,
+		Name:          k,
+	}
+
+	step, saved := last[name]
+	for i := range points {
+		if !saved || points[i].Step > step {
+			points[i].ReportedAt = now
+			m.Points = append(m.Points, points[i])
+		}
+	}
+
+	if len(m.Points) > 0 {
+		err = m.Validate()
+	}
+
+	return
+}
+
+func (s trainingMetricService) Get(info *TrainingIndex) ([]TrainingMetricDTO, error) {
+	return s.Compare(&TrainingMetricCompareCmd{
+		Project:     info.Project,
+		TrainingIds: []string{info.TrainingId},
+	})
+}
+
+func (s trainingMetricService) Compare(cmd *TrainingMetricCompareCmd) ([]TrainingMetricDTO, error) {
+	v, err := s.metric.List(&cmd.Project, cmd.TrainingIds, cmd.Names)
+	if err != nil || len(v) == 0 {
+		return nil, err
+	}
+
+	trainings, _, err := s.repo.List(cmd.Project.Owner, cmd.Project.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(trainings))
+	for i := range trainings {
+		names[trainings[i].Id] = trainings[i].Name.TrainingName()
+	}
+
+	// keep the order of trainings in the cmd
+	order := make(map[string]int, len(cmd.TrainingIds))
+	for i, id := range cmd.TrainingIds {
+		order[id] = i
+	}
+
+	sort.SliceStable(v, func(i, j int) bool {
+		return order[v[i].TrainingId] < order[v[j].TrainingId]
+	})
+
+	r := []TrainingMetricDTO{}
+	index := map[string]int{}
+
+	for i := range v {
+		item := &v[i]
+
+		name := item.Name.CustomizedKey()
+
+		n, ok := index[name]
+		if !ok {
+			n = len(r)
+			index[name] = n
+			r = append(r, TrainingMetricDTO{Name: name})
+		}
+
+		points := make([]MetricPointDTO, len(item.Points))
+		for j := range item.Points {
+			points[j] = MetricPointDTO{
+				Step:  item.Points[j].Step,
+				Value: item.Points[j].Value,
+			}
+		}
+
+		r[n].Series = append(r[n].Series, TrainingMetricSeriesDTO{
+			TrainingId:   item.TrainingId,
+			TrainingName: names[item.TrainingId],
+			Points:       points,
+		})
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Name < r[j].Name
+	})
+
+	return r, nil
+}
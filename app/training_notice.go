@@ -0,0 +1,46 @@
+package app
+
+import (
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+)
+
+// TrainingNoticeService keeps the notices of the failed runs of training schedules,
+// which are sent by the scheduler, so that the owner can check them.
+type TrainingNoticeService interface {
+	Add(*domain.TrainingScheduleRunFailedEvent, int64) error
+	List(domain.Account) ([]TrainingScheduleNoticeDTO, error)
+}
+
+func NewTrainingNoticeService(repo repository.TrainingScheduleNotice) TrainingNoticeService {
+	return trainingNoticeService{repo}
+}
+
+type trainingNoticeService struct {
+	repo repository.TrainingScheduleNotice
+}
+
+func (s trainingNoticeService) Add(e *domain.TrainingScheduleRunFailedEvent, createdAt int64) error {
+	return s.repo.Add(e.Account, &domain.TrainingScheduleNotice{
+		ScheduleId: e.ScheduleId,
+		ProjectId:  e.ProjectId,
+		TrainingId: e.TrainingId,
+		Reason:     e.Reason,
+		CreatedAt:  createdAt,
+	})
+}
+
+// List returns the notices from the latest to the earliest
+func (s trainingNoticeService) List(user domain.Account) ([]TrainingScheduleNoticeDTO, error) {
+	v, err := s.repo.List(user)
+	if err != nil || len(v) == 0 {
+		return nil, err
+	}
+
+	r := make([]TrainingScheduleNoticeDTO, len(v))
+	for i := range v {
+		r[len(v)-1-i] = toTrainingScheduleNoticeDTO(&v[i])
+	}
+
+	return r, nil
+}
@@ -0,0 +1,353 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/platform"
+	"github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+const (
+	trainingPublishDir         = "training"
+	trainingPublishOutputFile  = "output.zip"
+	trainingPublishLineageFile = "lineage.json"
+
+	// trainingPublishLFSRule tracks the output as the LFS file,
+	// otherwise the pointer file is treated as the output itself.
+	trainingPublishLFSRule = trainingPublishDir + "/**/" + trainingPublishOutputFile +
+		" filter=lfs diff=lfs merge=lfs -text"
+	gitAttributesFile = ".gitattributes"
+)
+
+// TrainingPublishCmd publishes the output of training to the model.
+// The model will be created by Model if it doesn't exist.
+type TrainingPublishCmd struct {
+	TrainingIndex
+
+	User  UserInfo
+	Model ModelCreateCmd
+}
+
+func (cmd *TrainingPublishCmd) Validate() error {
+	if cmd.Model.Owner == nil || cmd.Model.Name == nil {
+		return errors.New("invalid cmd of publishing training")
+	}
+
+	if cmd.Model.Owner.Account() != cmd.Project.Owner.Account() {
+		return errors.New("can't publish to the model of other user")
+	}
+
+	return nil
+}
+
+type TrainingPublishDTO struct {
+	ModelId     string `json:"model_id"`
+	ModelName   string `json:"model_name"`
+	OutputPath  string `json:"output_path"`
+	LineagePath string `json:"lineage_path"`
+	Commit      string `json:"commit"`
+}
+
+// trainingLineage is the metadata recorded in the model to trace
+// from the datasets and the code of project to the model.
+type trainingLineage struct {
+	Owner       string `json:"owner"`
+	ProjectId   string `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	ProjectRepo string `json:"project_repo_id"`
+	TrainingId  string `json:"training_id"`
+	Training    string `json:"training_name"`
+
+	CodeDir  string `json:"code_dir"`
+	BootFile string `json:"boot_file"`
+	Commit   string `json:"commit"`
+
+	Hyperparameters map[string]string `json:"hyperparameters"`
+	Inputs          []trainingInput   `json:"inputs"`
+
+	Compute struct {
+		Type    string `json:"type"`
+		Flavor  string `json:"flavor"`
+		Version string `json:"version"`
+	} `json:"compute"`
+
+	Duration    int    `json:"duration"`
+	PublishedAt string `json:"published_at"`
+}
+
+type trainingInput struct {
+	Key    string `json:"key"`
+	Type   string `json:"type"`
+	Owner  string `json:"owner"`
+	RepoId string `json:"repo_id"`
+	File   string `json:"file"`
+}
+
+func newTrainingLineage(t *domain.UserTraining, commit string) trainingLineage {
+	c := &t.TrainingConfig
+
+	v := trainingLineage{
+		Owner:           t.Owner.Account(),
+		ProjectId:       t.ProjectId,
+		ProjectName:     c.ProjectName.ResourceName(),
+		ProjectRepo:     c.ProjectRepoId,
+		TrainingId:      t.Id,
+		Training:        c.Name.TrainingName(),
+		CodeDir:         c.CodeDir.Directory(),
+		BootFile:        c.BootFile.FilePath(),
+		Commit:          commit,
+		Hyperparameters: make(map[string]string, len(c.Hyperparameters)),
+		Inputs:          make([]trainingInput, len(c.Inputs)),
+		Duration:        t.JobDetail.Duration,
+		PublishedAt:     utils.Date(),
+	}
+
+	// the env is not recorded, because it may contain the secrets.
+	for _, kv := range c.Hyperparameters {
+		v.Hyperparameters[kv.Key.CustomizedKey()] = kv.Value.CustomizedValue()
+	}
+
+	for i := range c.Inputs {
+		item := &c.Inputs[i]
+
+		v.Inputs[i] = trainingInput{
+			Key:    item.Key.CustomizedKey(),
+			Type:   item.Type.ResourceType(),
+			Owner:  item.User.Account(),
+			RepoId: item.RepoId,
+		}
+
+		if item.File != nil {
+			v.Inputs[i].File = item.File.InputeFilePath()
+		}
+	}
+
+	v.Compute.Type = c.Compute.Type.ComputeType()
+	v.Compute.Flavor = c.Compute.Flavor.ComputeFlavor()
+	v.Compute.Version = c.Compute.Version.ComputeVersion()
+
+	return v
+}
+
+// PublishOutput commits the output and the lineage of the finished training
+// into the model repository, every publishing is a new version of the model.
+func (s trainingService) PublishOutput(cmd *TrainingPublishCmd, pr platform.Repository) (
+	dto TrainingPublishDTO, code string, err error,
+) {
+	t, err := s.repo.Get(&cmd.TrainingIndex)
+	if err != nil {
+		return
+	}
+
+	if t.JobDetail.OutputPath == "" || !s.isJobDone(t.JobDetail.Status) {
+		code = ErrorTrainNoOutput
+		err = errors.New("the training has no output")
+
+		return
+	}
+
+	u := &cmd.User
+	commit := t.Commit
+	if commit == "" {
+		// the training is created before the commit is recorded
+		commit = s.projectCommit(u.User, u.Token, &t.TrainingConfig)
+	}
+
+	lineage, err := json.MarshalIndent(newTrainingLineage(&t, commit), "", "  ")
+	if err != nil {
+		return
+	}
+
+	dir, err := domain.NewDirectory(trainingPublishDir + "/" + t.Id)
+	if err != nil {
+		return
+	}
+
+	err = s.train.DownloadFile(
+		t.Job.Endpoint, t.JobDetail.OutputPath,
+		func(r io.Reader, size int64) (err error) {
+			// check the size before the output is read
+			if size < 0 || size > domain.DomainConfig.MaxTrainingOutputSize {
+				code = ErrorTrainOutputTooLarge
+
+				return fmt.Errorf("the size(%d) of output is unknown or too large to publish", size)
+			}
+
+			dto, err = s.publishOutput(cmd, pr, dir, string(lineage), r, size)
+
+			return
+		},
+	)
+	if err == nil {
+		dto.Commit = commit
+	}
+
+	return
+}
+
+// publishOutput commits the output inline if it is small, otherwise
+// uploads it as the LFS file and commits the pointer file.
+func (s trainingService) publishOutput(
+	cmd *TrainingPublishCmd, pr platform.Repository, dir domain.Directory,
+	lineage string, r io.Reader, size int64,
+) (dto TrainingPublishDTO, err error) {
+	model, err := s.getOrCreateModel(&cmd.Model, pr)
+	if err != nil {
+		return
+	}
+
+	u := &cmd.User
+	outputContent := platform.RepoFileContent{}
+
+	if size <= platform.MaxRepoFileSize {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return dto, err
+		}
+
+		output := base64.StdEncoding.EncodeToString(data)
+		outputContent = platform.RepoFileContent{Content: &output, IsEncoded: true}
+	} else {
+		pointer, err := s.rf.UploadLFSFile(u, cmd.Model.Name, r)
+		if err != nil {
+			return dto, err
+		}
+
+		if err := s.trackLFSOutput(u, &model); err != nil {
+			return dto, err
+		}
+
+		outputContent = platform.RepoFileContent{Content: &pointer}
+	}
+
+	files := []struct {
+		name    string
+		content *platform.RepoFileContent
+	}{
+		{trainingPublishOutputFile, &outputContent},
+		{trainingPublishLineageFile, &platform.RepoFileContent{Content: &lineage}},
+	}
+
+	paths := make([]string, len(files))
+	for i := range files {
+		if paths[i], err = s.commitFile(u, &model, dir, files[i].name, files[i].content); err != nil {
+			return
+		}
+	}
+
+	dto = TrainingPublishDTO{
+		ModelId:     model.Id,
+		ModelName:   model.Name,
+		OutputPath:  paths[0],
+		LineagePath: paths[1],
+	}
+
+	return
+}
+
+// trackLFSOutput adds the rule of LFS for the output to the .gitattributes
+// of model repository if it is not there.
+func (s trainingService) trackLFSOutput(u *UserInfo, model *ModelDTO) error {
+	path, err := domain.NewFilePath(gitAttributesFile)
+	if err != nil {
+		return err
+	}
+
+	info := platform.RepoFileInfo{
+		RepoId: model.RepoId,
+		Path:   path,
+	}
+
+	data, notFound, err := s.rf.Download(u.Token, &info)
+	if err != nil && !notFound {
+		return err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == trainingPublishLFSRule {
+			return nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += trainingPublishLFSRule + "\n"
+
+	if notFound {
+		return s.rf.Create(u, &info, &platform.RepoFileContent{Content: &content})
+	}
+
+	return s.rf.Update(u, &info, &platform.RepoFileContent{Content: &content})
+}
+
+func (s trainingService) getOrCreateModel(cmd *ModelCreateCmd, pr platform.Repository) (
+	ModelDTO, error,
+) {
+	v, err := s.model.GetByName(cmd.Owner, cmd.Name, true)
+	if err == nil {
+		return v.ModelDTO, nil
+	}
+
+	if !repository.IsErrorResourceNotExists(err) {
+		return ModelDTO{}, err
+	}
+
+	if err := cmd.Validate(); err != nil {
+		return ModelDTO{}, err
+	}
+
+	return s.model.Create(cmd, pr)
+}
+
+// commitFile creates the file in the model repository or updates it
+// if the training has been published to the model before.
+func (s trainingService) commitFile(
+	u *UserInfo, model *ModelDTO, dir domain.Directory,
+	name string, content *platform.RepoFileContent,
+) (string, error) {
+	file, err := domain.NewFilePath(name)
+	if err != nil {
+		return "", err
+	}
+
+	repoName, err := domain.NewResourceName(model.Name)
+	if err != nil {
+		return "", err
+	}
+
+	_, exist, err := s.rf.GetDirFileInfo(u, &platform.RepoDirFile{
+		RepoName: repoName,
+		Dir:      dir,
+		File:     file,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	path, err := domain.NewFilePath(dir.Directory() + "/" + name)
+	if err != nil {
+		return "", err
+	}
+
+	info := platform.RepoFileInfo{
+		RepoId: model.RepoId,
+		Path:   path,
+	}
+
+	if exist {
+		err = s.rf.Update(u, &info, content)
+	} else {
+		err = s.rf.Create(u, &info, content)
+	}
+
+	return path.FilePath(), err
+}
@@ -0,0 +1,412 @@
+package app
+
+import (
+	"encoding/hex"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/message"
+	"github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/domain/training"
+	userrepo "github.com/opensourceways/xihe-server/user/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+const retryNumOfUpdatingTrainingSchedule = 3
+
+type TrainingScheduleService interface {
+	Create(*TrainingScheduleCreateCmd) (string, string, error)
+	List(user domain.Account, projectId string) ([]TrainingScheduleSummaryDTO, error)
+	Get(user domain.Account, scheduleId string) (TrainingScheduleDTO, string, error)
+	SetPaused(user domain.Account, scheduleId string, paused bool) (string, error)
+	Delete(user domain.Account, scheduleId string) error
+}
+
+func NewTrainingScheduleService(
+	ts TrainingService,
+	repo repository.Training,
+	schedule repository.TrainingSchedule,
+) TrainingScheduleService {
+	return trainingScheduleService{
+		ts:       ts,
+		repo:     repo,
+		schedule: schedule,
+	}
+}
+
+type trainingScheduleService struct {
+	ts       TrainingService
+	repo     repository.Training
+	schedule repository.TrainingSchedule
+}
+
+func (s trainingScheduleService) Create(cmd *TrainingScheduleCreateCmd) (
+	id string, code string, err error,
+) {
+	if _, err = s.repo.GetTrainingConfig(&cmd.TrainingIndex); err != nil {
+		if repository.IsErrorResourceNotExists(err) {
+			code = ErrorTrainNotFound
+		}
+
+		return
+	}
+
+	now := utils.Now()
+	v := cmd.toTrainingSchedule(now)
+
+	if err = v.CheckInterval(now); err != nil {
+		code = ErrorTrainInvalidSchedule
+
+		return
+	}
+
+	v.ScheduleNext(now)
+
+	id, err = s.schedule.Save(&v)
+
+	return
+}
+
+func (s trainingScheduleService) List(user domain.Account, projectId string) (
+	[]TrainingScheduleSummaryDTO, error,
+) {
+	v, err := s.schedule.List(user, projectId)
+	if err != nil || len(v) == 0 {
+		return nil, err
+	}
+
+	r := make([]TrainingScheduleSummaryDTO, len(v))
+	for i := range v {
+		r[i] = toTrainingScheduleSummaryDTO(&v[i])
+	}
+
+	return r, nil
+}
+
+func (s trainingScheduleService) Get(user domain.Account, scheduleId string) (
+	dto TrainingScheduleDTO, code string, err error,
+) {
+	v, err := s.schedule.Get(user, scheduleId)
+	if err != nil {
+		if repository.IsErrorResourceNotExists(err) {
+			code = ErrorTrainScheduleNotFound
+		}
+
+		return
+	}
+
+	var trainings []TrainingSummaryDTO
+	if len(v.PendingRuns()) > 0 {
+		if trainings, err = s.ts.List(user, v.ProjectId); err != nil {
+			return
+		}
+	}
+
+	dto = toTrainingScheduleDTO(&v, trainings)
+
+	return
+}
+
+// SetPaused pauses or resumes the schedule. The runs missed
+// during the pause will not be spawned after resuming.
+func (s trainingScheduleService) SetPaused(user domain.Account, scheduleId string, paused bool) (
+	code string, err error,
+) {
+	err = updateTrainingSchedule(s.schedule, user, scheduleId, func(v *domain.TrainingSchedule) bool {
+		if paused {
+			return v.Pause()
+		}
+
+		return v.Resume(utils.Now())
+	})
+
+	if err != nil && repository.IsErrorResourceNotExists(err) {
+		code = ErrorTrainScheduleNotFound
+	}
+
+	return
+}
+
+func (s trainingScheduleService) Delete(user domain.Account, scheduleId string) error {
+	err := s.schedule.Delete(user, scheduleId)
+	if err != nil && repository.IsErrorResourceNotExists(err) {
+		return nil
+	}
+
+	return err
+}
+
+// updateTrainingSchedule retries when the schedule is updated concurrently
+func updateTrainingSchedule(
+	repo repository.TrainingSchedule, owner domain.Account, scheduleId string,
+	f func(*domain.TrainingSchedule) bool,
+) (err error) {
+	var v domain.TrainingSchedule
+
+	for i := 0; i < retryNumOfUpdatingTrainingSchedule; i++ {
+		if v, err = repo.Get(owner, scheduleId); err != nil {
+			return
+		}
+
+		if !f(&v) {
+			return
+		}
+
+		if err = repo.Update(&v); err == nil || !repository.IsErrorConcurrentUpdating(err) {
+			return
+		}
+	}
+
+	return
+}
+
+// TrainingScheduler spawns the runs of the due schedules
+// and notifies the owner when the run fails.
+type TrainingScheduler interface {
+	Schedule(now int64) error
+}
+
+func NewTrainingScheduler(
+	ts TrainingService,
+	train training.Training,
+	repo repository.Training,
+	schedule repository.TrainingSchedule,
+	sender message.MessageProducer,
+	user userrepo.User,
+	encryption utils.SymmetricEncryption,
+) TrainingScheduler {
+	return trainingScheduler{
+		log:        logrus.NewEntry(logrus.StandardLogger()),
+		ts:         ts,
+		train:      train,
+		repo:       repo,
+		schedule:   schedule,
+		sender:     sender,
+		user:       user,
+		encryption: encryption,
+	}
+}
+
+type trainingScheduler struct {
+	log        *logrus.Entry
+	ts         TrainingService
+	train      training.Training
+	repo       repository.Training
+	schedule   repository.TrainingSchedule
+	sender     message.MessageProducer
+	user       userrepo.User
+	encryption utils.SymmetricEncryption
+}
+
+type finishedScheduleRun struct {
+	trainingId string
+	status     string
+	failed     bool
+}
+
+func (s trainingScheduler) Schedule(now int64) error {
+	v, err := s.schedule.FindToHandle(now)
+	if err != nil {
+		return err
+	}
+
+	for i := range v {
+		item := &v[i]
+
+		if runs := item.PendingRuns(); len(runs) > 0 {
+			s.checkRuns(item, runs)
+		}
+
+		if item.IsDue(now) {
+			s.run(item, now)
+		}
+	}
+
+	return nil
+}
+
+// checkRuns records the final status of the runs whose trainings are done.
+func (s trainingScheduler) checkRuns(v *domain.TrainingSchedule, runs []string) {
+	var finished []finishedScheduleRun
+
+	index := v.TrainingIndex()
+
+	for _, tid := range runs {
+		index.TrainingId = tid
+
+		detail, _, err := s.repo.GetJobDetail(&index)
+		if err != nil {
+			if repository.IsErrorResourceNotExists(err) {
+				finished = append(finished, finishedScheduleRun{
+					trainingId: tid,
+					status:     trainingStatusDeleted,
+				})
+			} else {
+				s.log.Errorf("get the training(%s) of schedule failed, err:%s", tid, err.Error())
+			}
+
+			continue
+		}
+
+		if status := detail.Status; isTrainingJobDone(s.train, status) {
+			finished = append(finished, finishedScheduleRun{
+				trainingId: tid,
+				status:     status,
+				failed:     s.train.IsJobFailed(status) || status == trainingStatusScheduleFailed,
+			})
+		}
+	}
+
+	if len(finished) == 0 {
+		return
+	}
+
+	var failed []finishedScheduleRun
+
+	err := updateTrainingSchedule(s.schedule, v.Owner, v.Id, func(item *domain.TrainingSchedule) bool {
+		changed := false
+		failed = failed[:0]
+
+		for i := range finished {
+			r := &finished[i]
+
+			if item.FinishRun(r.trainingId, r.status, r.failed) {
+				changed = true
+
+				if r.failed {
+					failed = append(failed, *r)
+				}
+			}
+		}
+
+		return changed
+	})
+	if err != nil {
+		s.log.Errorf("update the runs of training schedule(%s) failed, err:%s", v.Id, err.Error())
+
+		return
+	}
+
+	for i := range failed {
+		s.notify(v, failed[i].trainingId, "the training is "+failed[i].status)
+	}
+}
+
+// run recreates the training of the schedule like the owner clicks the recreate.
+func (s trainingScheduler) run(v *domain.TrainingSchedule, now int64) {
+	// claim the run by moving to the next run time first,
+	// so that the run will not be spawned again.
+	claimed := false
+
+	err := updateTrainingSchedule(s.schedule, v.Owner, v.Id, func(item *domain.TrainingSchedule) bool {
+		if claimed = item.IsDue(now); claimed {
+			item.ScheduleNext(now)
+		}
+
+		return claimed
+	})
+	if err != nil || !claimed {
+		if err != nil {
+			s.log.Errorf("claim the run of training schedule(%s) failed, err:%s", v.Id, err.Error())
+		}
+
+		return
+	}
+
+	index := v.TrainingIndex()
+	run := domain.ScheduleRun{RunAt: now}
+	pause := false
+
+	if run.TrainingId, err = s.ts.Recreate(&index, s.ownerToken(v)); err != nil {
+		s.log.Errorf("training schedule(%s) recreates training failed, err:%s", v.Id, err.Error())
+
+		run.Done = true
+		run.Failed = true
+		run.Error = scheduleRunError(err)
+
+		// the schedule can't run any more if the training it attaches to is deleted.
+		// It keeps running if the project has too many trainings, because the
+		// finished trainings are removed then and the next run may succeed.
+		pause = repository.IsErrorResourceNotExists(err)
+	}
+
+	err = updateTrainingSchedule(s.schedule, v.Owner, v.Id, func(item *domain.TrainingSchedule) bool {
+		item.AddRun(&run)
+
+		if pause {
+			item.Pause()
+		}
+
+		return true
+	})
+	if err != nil {
+		s.log.Errorf("add the run of training schedule(%s) failed, err:%s", v.Id, err.Error())
+	}
+
+	if run.Failed {
+		reason := "failed to create the training, " + run.Error
+		if pause {
+			reason += ", and the schedule is paused"
+		}
+
+		s.notify(v, "", reason)
+	}
+}
+
+// ownerToken returns the platform token of owner like the owner recreates the
+// training, so the commit of private project is recorded too. The commit is not
+// recorded if the token is not available.
+func (s trainingScheduler) ownerToken(v *domain.TrainingSchedule) string {
+	u, err := s.user.GetByAccount(v.Owner)
+	if err != nil || u.PlatformToken == "" {
+		if err != nil {
+			s.log.Errorf("get the owner of training schedule(%s) failed, err:%s", v.Id, err.Error())
+		}
+
+		return ""
+	}
+
+	b, err := hex.DecodeString(u.PlatformToken)
+	if err == nil {
+		b, err = s.encryption.Decrypt(b)
+	}
+
+	if err != nil {
+		s.log.Errorf("decrypt the token of training schedule(%s) failed, err:%s", v.Id, err.Error())
+
+		return ""
+	}
+
+	return string(b)
+}
+
+func (s trainingScheduler) notify(v *domain.TrainingSchedule, trainingId, reason string) {
+	err := s.sender.SendTrainingScheduleRunFailed(&domain.TrainingScheduleRunFailedEvent{
+		Account:    v.Owner,
+		ScheduleId: v.Id,
+		ProjectId:  v.ProjectId,
+		TrainingId: trainingId,
+		Reason:     reason,
+	})
+	if err != nil {
+		s.log.Errorf("notify the owner of training schedule(%s) failed, err:%s", v.Id, err.Error())
+	}
+}
+
+// scheduleRunError returns the reason which can be shown to the owner.
+func scheduleRunError(err error) string {
+	switch err.(type) {
+	case ErrorExccedMaxTrainingRecord:
+		return "exceed max training num"
+
+	case ErrorTrainingQueueFull:
+		return "the training queue is full"
+	}
+
+	if repository.IsErrorResourceNotExists(err) {
+		return "the training of schedule is deleted"
+	}
+
+	return "system error"
+}
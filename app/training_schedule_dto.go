@@ -0,0 +1,150 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/opensourceways/xihe-server/domain"
+)
+
+const scheduleTimeLayout = "2006-01-02 15:04:05"
+
+type TrainingScheduleCreateCmd struct {
+	TrainingIndex
+
+	Spec     domain.CronSpec
+	Timezone domain.Timezone
+}
+
+func (cmd *TrainingScheduleCreateCmd) Validate() error {
+	b := cmd.Project.Owner != nil &&
+		cmd.Project.Id != "" &&
+		cmd.TrainingId != "" &&
+		cmd.Spec != nil &&
+		cmd.Timezone != nil
+
+	if !b {
+		return errors.New("invalid cmd of creating training schedule")
+	}
+
+	return nil
+}
+
+func (cmd *TrainingScheduleCreateCmd) toTrainingSchedule(now int64) domain.TrainingSchedule {
+	return domain.TrainingSchedule{
+		Owner:      cmd.Project.Owner,
+		ProjectId:  cmd.Project.Id,
+		TrainingId: cmd.TrainingId,
+		Spec:       cmd.Spec,
+		Timezone:   cmd.Timezone,
+		CreatedAt:  now,
+	}
+}
+
+type TrainingScheduleSummaryDTO struct {
+	Id         string `json:"id"`
+	TrainingId string `json:"training_id"`
+	Spec       string `json:"spec"`
+	Timezone   string `json:"timezone"`
+	Paused     bool   `json:"paused"`
+	CreatedAt  string `json:"created_at"`
+
+	// NextRunAt is the time in the timezone of schedule,
+	// and it is empty if the schedule is paused.
+	NextRunAt string `json:"next_run_at"`
+}
+
+type TrainingScheduleDTO struct {
+	TrainingScheduleSummaryDTO
+
+	// Runs is the history of runs from the earliest to the latest
+	Runs []ScheduleRunDTO `json:"runs"`
+}
+
+type ScheduleRunDTO struct {
+	TrainingId string `json:"training_id"`
+	RunAt      string `json:"run_at"`
+	Status     string `json:"status"`
+	Error      string `json:"error"`
+	IsDone     bool   `json:"is_done"`
+	IsFailed   bool   `json:"is_failed"`
+}
+
+func toTrainingScheduleSummaryDTO(s *domain.TrainingSchedule) TrainingScheduleSummaryDTO {
+	loc := s.Timezone.Location()
+
+	dto := TrainingScheduleSummaryDTO{
+		Id:         s.Id,
+		TrainingId: s.TrainingId,
+		Spec:       s.Spec.CronSpec(),
+		Timezone:   s.Timezone.Timezone(),
+		Paused:     s.Paused,
+		CreatedAt:  scheduleTime(s.CreatedAt, loc),
+	}
+
+	if !s.Paused {
+		dto.NextRunAt = scheduleTime(s.NextRunAt, loc)
+	}
+
+	return dto
+}
+
+// toTrainingScheduleDTO takes the current status of the runs
+// which are not done from the trainings.
+func toTrainingScheduleDTO(s *domain.TrainingSchedule, trainings []TrainingSummaryDTO) TrainingScheduleDTO {
+	m := make(map[string]*TrainingSummaryDTO, len(trainings))
+	for i := range trainings {
+		m[trainings[i].Id] = &trainings[i]
+	}
+
+	loc := s.Timezone.Location()
+
+	runs := make([]ScheduleRunDTO, len(s.Runs))
+	for i := range s.Runs {
+		r := &s.Runs[i]
+
+		runs[i] = ScheduleRunDTO{
+			TrainingId: r.TrainingId,
+			RunAt:      scheduleTime(r.RunAt, loc),
+			Status:     r.Status,
+			Error:      r.Error,
+			IsDone:     r.Done,
+			IsFailed:   r.Failed,
+		}
+
+		if t, ok := m[r.TrainingId]; ok && !r.Done {
+			runs[i].Status = t.Status
+		}
+	}
+
+	return TrainingScheduleDTO{
+		TrainingScheduleSummaryDTO: toTrainingScheduleSummaryDTO(s),
+		Runs:                       runs,
+	}
+}
+
+func scheduleTime(n int64, loc *time.Location) string {
+	if n <= 0 {
+		return ""
+	}
+
+	return time.Unix(n, 0).In(loc).Format(scheduleTimeLayout)
+}
+
+type TrainingScheduleNoticeDTO struct {
+	ScheduleId string `json:"schedule_id"`
+	ProjectId  string `json:"project_id"`
+	TrainingId string `json:"training_id"`
+	Reason     string `json:"reason"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func toTrainingScheduleNoticeDTO(v *domain.TrainingScheduleNotice) TrainingScheduleNoticeDTO {
+	return TrainingScheduleNoticeDTO{
+		ScheduleId: v.ScheduleId,
+		ProjectId:  v.ProjectId,
+		TrainingId: v.TrainingId,
+		Reason:     v.Reason,
+		CreatedAt:  scheduleTime(v.CreatedAt, time.Local),
+	}
+}
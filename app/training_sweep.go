@@ -0,0 +1,215 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+const (
+	trainingStatusDeleted = "deleted"
+
+	retryNumOfUpdatingTrainingSweep = 3
+)
+
+type TrainingSweepService interface {
+	Create(*TrainingSweepCreateCmd) (string, string, error)
+	List(user domain.Account, projectId string) ([]TrainingSweepSummaryDTO, error)
+	Get(user domain.Account, projectId, sweepId string) (TrainingSweepDTO, string, error)
+}
+
+func NewTrainingSweepService(
+	ts TrainingService,
+	repo repository.TrainingSweep,
+	metric repository.TrainingMetric,
+) TrainingSweepService {
+	return trainingSweepService{
+		log:    logrus.NewEntry(logrus.StandardLogger()),
+		ts:     ts,
+		repo:   repo,
+		metric: metric,
+	}
+}
+
+type trainingSweepService struct {
+	log    *logrus.Entry
+	ts     TrainingService
+	repo   repository.TrainingSweep
+	metric repository.TrainingMetric
+}
+
+// Create expands the sweep into trainings which wait in the training queue of user.
+func (s trainingSweepService) Create(cmd *TrainingSweepCreateCmd) (
+	id string, code string, err error,
+) {
+	sweep := cmd.toTrainingSweep()
+
+	trials, err := sweep.GenTrials()
+	if err != nil {
+		code = ErrorTrainInvalidSweep
+
+		return
+	}
+
+	// reject the sweep before any trial is created
+	if err = s.ts.CheckCapacity(sweep.Owner, sweep.ProjectId, len(trials)); err != nil {
+		code = trainingCapacityCode(err)
+
+		return
+	}
+
+	sweep.Trials = make([]domain.SweepTrial, 0, len(trials))
+	defer func() {
+		if err != nil {
+			s.rollback(&sweep)
+		}
+	}()
+
+	for i := range trials {
+		c, err1 := cmd.trialCmd(i+1, trials[i])
+		if err1 != nil {
+			code, err = ErrorTrainInvalidSweep, err1
+
+			return
+		}
+
+		tid, err1 := s.ts.Create(&c)
+		if err1 != nil {
+			code, err = trainingCapacityCode(err1), err1
+
+			return
+		}
+
+		sweep.Trials = append(sweep.Trials, domain.SweepTrial{
+			TrainingId:      tid,
+			Hyperparameters: trials[i],
+		})
+	}
+
+	sweep.CreatedAt = utils.Now()
+
+	id, err = s.repo.Save(&sweep)
+
+	return
+}
+
+// trainingCapacityCode returns the code of error that there is no room for the trainings
+func trainingCapacityCode(err error) string {
+	switch err.(type) {
+	case ErrorExccedMaxTrainingRecord:
+		return ErrorTrainExccedMaxNum
+
+	case ErrorTrainingQueueFull:
+		return ErrorTrainQueueFull
+	}
+
+	return ""
+}
+
+// rollback deletes the trainings which are created for the sweep
+func (s trainingSweepService) rollback(sweep *domain.TrainingSweep) {
+	for i := range sweep.Trials {
+		index := TrainingIndex{
+			Project: domain.ResourceIndex{
+				Owner: sweep.Owner,
+				Id:    sweep.ProjectId,
+			},
+			TrainingId: sweep.Trials[i].TrainingId,
+		}
+
+		if err := s.ts.Delete(&index); err != nil {
+			s.log.Errorf(
+				"delete the trial(%s) of sweep failed, err:%s",
+				index.TrainingId, err.Error(),
+			)
+		}
+	}
+}
+
+func (s trainingSweepService) List(user domain.Account, projectId string) (
+	[]TrainingSweepSummaryDTO, error,
+) {
+	v, err := s.repo.List(user, projectId)
+	if err != nil || len(v) == 0 {
+		return nil, err
+	}
+
+	r := make([]TrainingSweepSummaryDTO, len(v))
+	for i := range v {
+		r[i] = toTrainingSweepSummaryDTO(&v[i])
+	}
+
+	return r, nil
+}
+
+func (s trainingSweepService) Get(user domain.Account, projectId, sweepId string) (
+	dto TrainingSweepDTO, code string, err error,
+) {
+	sweep, err := s.repo.Get(user, sweepId)
+	if err != nil {
+		if repository.IsErrorResourceNotExists(err) {
+			code = ErrorTrainSweepNotFound
+		}
+
+		return
+	}
+
+	if sweep.ProjectId != projectId {
+		code = ErrorTrainSweepNotFound
+		err = errors.New("the sweep is not of the project")
+
+		return
+	}
+
+	trainings, err := s.ts.List(user, sweep.ProjectId)
+	if err != nil {
+		return
+	}
+
+	metrics, err := s.trialMetrics(&sweep)
+	if err != nil {
+		return
+	}
+
+	dto = toTrainingSweepDTO(&sweep, trainings, metrics)
+
+	return
+}
+
+// trialMetrics returns the metric of sweep reported by each trial, which is
+// the value of the last step in the series sent by the training.
+func (s trainingSweepService) trialMetrics(sweep *domain.TrainingSweep) (
+	map[string]float64, error,
+) {
+	if len(sweep.Trials) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(sweep.Trials))
+	for i := range sweep.Trials {
+		ids[i] = sweep.Trials[i].TrainingId
+	}
+
+	project := domain.ResourceIndex{
+		Owner: sweep.Owner,
+		Id:    sweep.ProjectId,
+	}
+
+	v, err := s.metric.List(&project, ids, []string{sweep.Metric.Name.CustomizedKey()})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(map[string]float64, len(v))
+	for i := range v {
+		if p, ok := v[i].Latest(); ok {
+			r[v[i].TrainingId] = p.Value
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+type TrainingSweepCreateCmd struct {
+	TrainingCreateCmd
+
+	Strategy   domain.SweepStrategy
+	Metric     domain.SweepMetric
+	MaxTrials  int
+	Parameters []domain.SweepParameter
+}
+
+func (cmd *TrainingSweepCreateCmd) Validate() error {
+	if err := cmd.TrainingCreateCmd.Validate(); err != nil {
+		return err
+	}
+
+	b := cmd.Strategy != nil &&
+		cmd.Metric.Name != nil &&
+		cmd.Metric.Goal != nil &&
+		len(cmd.Parameters) > 0
+
+	if !b {
+		return errors.New("invalid cmd of creating sweep")
+	}
+
+	for i := range cmd.Parameters {
+		if cmd.Parameters[i].Key == nil {
+			return errors.New("invalid sweep parameter")
+		}
+	}
+
+	return nil
+}
+
+func (cmd *TrainingSweepCreateCmd) toTrainingSweep() domain.TrainingSweep {
+	return domain.TrainingSweep{
+		Owner:      cmd.User,
+		ProjectId:  cmd.ProjectId,
+		Name:       cmd.Name,
+		Strategy:   cmd.Strategy,
+		Metric:     cmd.Metric,
+		MaxTrials:  cmd.MaxTrials,
+		Parameters: cmd.Parameters,
+	}
+}
+
+// trialCmd returns the cmd of creating the training of the No.n trial.
+// The swept hyperparameters override the ones of the sweep.
+func (cmd *TrainingSweepCreateCmd) trialCmd(n int, kv []domain.KeyValue) (
+	TrainingCreateCmd, error,
+) {
+	r := cmd.TrainingCreateCmd
+
+	name, err := domain.NewTrainingName(cmd.Name.TrainingName() + "-" + strconv.Itoa(n))
+	if err != nil {
+		return r, err
+	}
+	r.Name = name
+
+	swept := make(map[string]bool, len(kv))
+	for i := range kv {
+		swept[kv[i].Key.CustomizedKey()] = true
+	}
+
+	v := make([]domain.KeyValue, 0, len(cmd.Hyperparameters)+len(kv))
+	for _, item := range cmd.Hyperparameters {
+		if !swept[item.Key.CustomizedKey()] {
+			v = append(v, item)
+		}
+	}
+	r.Hyperparameters = append(v, kv...)
+
+	return r, nil
+}
+
+type TrainingSweepSummaryDTO struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	Strategy  string `json:"strategy"`
+	Metric    string `json:"metric"`
+	Goal      string `json:"goal"`
+	MaxTrials int    `json:"max_trials"`
+	TrialNum  int    `json:"trial_num"`
+	CreatedAt string `json:"created_at"`
+}
+
+type TrainingSweepDTO struct {
+	TrainingSweepSummaryDTO
+
+	Parameters []SweepParameterDTO `json:"parameters"`
+
+	// Trials is the comparison table of the trials
+	Trials []SweepTrialDTO `json:"trials"`
+
+	// BestTrial is the training id of the trial which has the best metric
+	BestTrial string `json:"best_trial"`
+}
+
+type SweepParameterDTO struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values,omitempty"`
+	Min    float64  `json:"min"`
+	Max    float64  `json:"max"`
+	Step   float64  `json:"step"`
+}
+
+type SweepTrialDTO struct {
+	TrainingId      string            `json:"training_id"`
+	Name            string            `json:"name"`
+	Status          string            `json:"status"`
+	IsDone          bool              `json:"is_done"`
+	Duration        int               `json:"duration"`
+	Hyperparameters map[string]string `json:"hyperparameters"`
+	Metric          *float64          `json:"metric,omitempty"`
+	IsBest          bool              `json:"is_best"`
+}
+
+func toTrainingSweepSummaryDTO(s *domain.TrainingSweep) TrainingSweepSummaryDTO {
+	return TrainingSweepSummaryDTO{
+		Id:        s.Id,
+		Name:      s.Name.TrainingName(),
+		Strategy:  s.Strategy.SweepStrategy(),
+		Metric:    s.Metric.Name.CustomizedKey(),
+		Goal:      s.Metric.Goal.SweepGoal(),
+		MaxTrials: s.MaxTrials,
+		TrialNum:  len(s.Trials),
+		CreatedAt: utils.ToDate(s.CreatedAt),
+	}
+}
+
+func toTrainingSweepDTO(
+	s *domain.TrainingSweep, trainings []TrainingSummaryDTO, metrics map[string]float64,
+) TrainingSweepDTO {
+	dto := TrainingSweepDTO{
+		TrainingSweepSummaryDTO: toTrainingSweepSummaryDTO(s),
+		Parameters:              make([]SweepParameterDTO, len(s.Parameters)),
+		Trials:                  make([]SweepTrialDTO, len(s.Trials)),
+	}
+
+	for i := range s.Parameters {
+		p := &s.Parameters[i]
+
+		values := make([]string, len(p.Values))
+		for j := range p.Values {
+			values[j] = p.Values[j].CustomizedValue()
+		}
+
+		dto.Parameters[i] = SweepParameterDTO{
+			Key:    p.Key.CustomizedKey(),
+			Values: values,
+			Min:    p.Range.Min,
+			Max:    p.Range.Max,
+			Step:   p.Range.Step,
+		}
+	}
+
+	m := make(map[string]*TrainingSummaryDTO, len(trainings))
+	for i := range trainings {
+		m[trainings[i].Id] = &trainings[i]
+	}
+
+	best := s.BestTrial(metrics)
+
+	for i := range s.Trials {
+		t := &s.Trials[i]
+
+		item := SweepTrialDTO{
+			TrainingId:      t.TrainingId,
+			Hyperparameters: make(map[string]string, len(t.Hyperparameters)),
+			IsBest:          i == best,
+		}
+
+		for _, kv := range t.Hyperparameters {
+			item.Hyperparameters[kv.Key.CustomizedKey()] = kv.Value.CustomizedValue()
+		}
+
+		if v, ok := metrics[t.TrainingId]; ok {
+			item.Metric = &v
+		}
+
+		if v, ok := m[t.TrainingId]; ok {
+			item.Name = v.Name
+			item.Status = v.Status
+			item.IsDone = v.IsDone
+			item.Duration = v.Duration
+		} else {
+			item.Status = trainingStatusDeleted
+			item.IsDone = true
+		}
+
+		if item.IsBest {
+			dto.BestTrial = t.TrainingId
+		}
+
+		dto.Trials[i] = item
+	}
+
+	return dto
+}
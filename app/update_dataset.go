@@ -1,6 +1,7 @@
 package app
 
 import (
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/platform"
 	"github.com/opensourceways/xihe-server/domain/repository"
@@ -51,6 +52,12 @@ func (cmd *DatasetUpdateCmd) toDataset(
 func (s datasetService) Update(
 	d *domain.Dataset, cmd *DatasetUpdateCmd, pr platform.Repository,
 ) (dto DatasetDTO, err error) {
+	if err = checkResourceContent(
+		s.moderation, moderation.SceneDataset, cmd.Name, cmd.Title, cmd.Desc,
+	); err != nil {
+		return
+	}
+
 	opt := new(platform.RepoOption)
 	if !cmd.toDataset(&d.DatasetModifiableProperty, opt) {
 		s.toDatasetDTO(d, &dto)
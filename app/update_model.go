@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
 	"github.com/opensourceways/xihe-server/domain/platform"
@@ -54,6 +55,12 @@ func (cmd *ModelUpdateCmd) toModel(
 func (s modelService) Update(
 	m *domain.Model, cmd *ModelUpdateCmd, pr platform.Repository,
 ) (dto ModelDTO, err error) {
+	if err = checkResourceContent(
+		s.moderation, moderation.SceneModel, cmd.Name, cmd.Title, cmd.Desc,
+	); err != nil {
+		return
+	}
+
 	opt := new(platform.RepoOption)
 	if !cmd.toModel(&m.ModelModifiableProperty, opt) {
 		s.toModelDTO(m, &dto)
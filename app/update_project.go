@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
 	"github.com/opensourceways/xihe-server/domain/platform"
@@ -64,6 +65,12 @@ func (cmd *ProjectUpdateCmd) toProject(
 func (s projectService) Update(
 	p *domain.Project, cmd *ProjectUpdateCmd, pr platform.Repository,
 ) (dto ProjectDTO, err error) {
+	if err = checkResourceContent(
+		s.moderation, moderation.SceneProject, cmd.Name, cmd.Title, cmd.Desc,
+	); err != nil {
+		return
+	}
+
 	opt := new(platform.RepoOption)
 	if !cmd.toProject(&p.ProjectModifiableProperty, opt) {
 		s.toProjectDTO(p, &dto)
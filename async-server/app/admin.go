@@ -0,0 +1,41 @@
+package app
+
+import (
+	"github.com/opensourceways/xihe-server/async-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+type TaskAdminService interface {
+	ListDeadTasks(*DeadTaskListCmd) (DeadTasksDTO, error)
+	RequeueDeadTask(id uint64) error
+}
+
+func NewTaskAdminService(repo repository.AsyncTask) TaskAdminService {
+	return &taskAdminService{
+		repo: repo,
+	}
+}
+
+type taskAdminService struct {
+	repo repository.AsyncTask
+}
+
+func (s *taskAdminService) ListDeadTasks(cmd *DeadTaskListCmd) (dto DeadTasksDTO, err error) {
+	v, total, err := s.repo.ListDeadTasks(cmd)
+	if err != nil {
+		return
+	}
+
+	dto.Total = total
+	dto.Tasks = make([]DeadTaskDTO, len(v))
+
+	for i := range v {
+		dto.Tasks[i].toDeadTaskDTO(&v[i])
+	}
+
+	return
+}
+
+func (s *taskAdminService) RequeueDeadTask(id uint64) error {
+	return s.repo.RequeueDeadTask(id, utils.Now())
+}
@@ -1,35 +1,202 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/async-server/domain"
 	"github.com/opensourceways/xihe-server/async-server/domain/bigmodel"
 	"github.com/opensourceways/xihe-server/async-server/domain/pool"
 	"github.com/opensourceways/xihe-server/async-server/domain/repository"
+	commonrepo "github.com/opensourceways/xihe-server/common/domain/repository"
 )
 
 type AsyncService interface {
 	AsyncWuKong(taskType string, time int64) error
 	AsyncWuKong4Img(taskType string, time int64) error
+	AsyncTask(taskType string, time int64) error
+
+	// CheckFailedTasks retries the failed or timeout tasks,
+	// or moves them to the dead letter when the attempts are exhausted.
+	CheckFailedTasks(now int64) error
+
+	// CheckCancelledTasks signals the workers to abandon the cancelled tasks.
+	CheckCancelledTasks(now int64) error
+
+	// AsyncBatch runs the items of batches and finishes the batches which are done.
+	AsyncBatch(now int64) error
 }
 
 func NewAsyncService(
 	bigmodel bigmodel.BigModel,
 	pool pool.Pool,
 	repo repository.AsyncTask,
+	policy domain.RetryPolicy,
+	batches repository.AsyncBatch,
+	batchPolicy domain.BatchPolicy,
 ) AsyncService {
 	return &asyncService{
-		bigmodel: bigmodel,
-		pool:     pool,
-		repo:     repo,
+		bigmodel:    bigmodel,
+		pool:        pool,
+		repo:        repo,
+		policy:      policy,
+		batches:     batches,
+		batchPolicy: batchPolicy,
 	}
 }
 
 type asyncService struct {
-	bigmodel bigmodel.BigModel
-	pool     pool.Pool
-	repo     repository.AsyncTask
+	bigmodel    bigmodel.BigModel
+	pool        pool.Pool
+	repo        repository.AsyncTask
+	policy      domain.RetryPolicy
+	batches     repository.AsyncBatch
+	batchPolicy domain.BatchPolicy
+}
+
+// maxFailedTasksPerCheck limits the failed tasks handled by each check
+const maxFailedTasksPerCheck = 100
+
+var (
+	errTaskTimeout   = errors.New("task timeout")
+	errTaskCancelled = errors.New("task cancelled")
+)
+
+// claim marks the task as running, it returns false if the task has been taken.
+func (s *asyncService) claim(id uint64) bool {
+	ok, err := s.repo.ClaimTask(id, s.policy.ClaimDeadline(time.Now().Unix()))
+	if err != nil {
+		logrus.Errorf("claim task %d failed, err:%s", id, err.Error())
+	}
+
+	return ok
+}
+
+// start sets the deadline of the task when it starts to run. It returns false if
+// the task is not running any more, which means it has waited for a worker too long
+// and has been retried or killed.
+func (s *asyncService) start(id uint64) bool {
+	err := s.repo.RenewTask(id, s.policy.Deadline(time.Now().Unix()))
+	if err == nil {
+		return true
+	}
+
+	if commonrepo.IsErrorConcurrentUpdating(err) {
+		logrus.Warnf("task %d is not running any more, skip it", id)
+
+		return false
+	}
+
+	// the deadline set when it was claimed is still valid
+	logrus.Errorf("set deadline of task %d failed, err:%s", id, err.Error())
+
+	return true
+}
+
+// execute runs the task within the deadline and records the failure of it.
+// The task is interrupted when it is timeout or cancelled, and the worker
+// is held until it returns, so that the task will not be run twice at the same time.
+func (s *asyncService) execute(ctx context.Context, id uint64, f func(context.Context) error) error {
+	if !s.start(id) {
+		return nil
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, time.Duration(s.policy.Timeout)*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- f(tctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-tctx.Done():
+		if ctx.Err() == nil {
+			logrus.Warnf("task %d is timeout, wait for it to return", id)
+
+			if err = s.hold(id, done); err != nil {
+				err = errTaskTimeout
+			}
+		} else {
+			<-done
+		}
+	}
+
+	if ctx.Err() != nil {
+		logrus.Infof("task %d is cancelled", id)
+
+		return errTaskCancelled
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	logrus.Errorf("do task %d failed, err:%s", id, err.Error())
+
+	// the task which will fail again is not retried
+	var e error
+	if s.bigmodel.IsRetryable(err) {
+		e = s.repo.FailTask(id, err.Error())
+	} else {
+		e = s.repo.KillTask(id, err.Error())
+	}
+
+	if e != nil {
+		logrus.Errorf("mark task %d as failed, err:%s", id, e.Error())
+	}
+
+	return err
 }
 
-func (s *asyncService) AsyncWuKong(taskType string, time int64) (err error) {
+// hold renews the deadline of the running task until it returns
+func (s *asyncService) hold(id uint64, done <-chan error) error {
+	renew := func() {
+		if err := s.repo.RenewTask(id, s.policy.Deadline(time.Now().Unix())); err != nil {
+			logrus.Errorf("renew task %d failed, err:%s", id, err.Error())
+		}
+	}
+
+	renew()
+
+	ticker := time.NewTicker(time.Duration(s.policy.Timeout) * time.Second / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+			renew()
+		}
+	}
+}
+
+// capacity returns how many tasks can be run now
+func (s *asyncService) capacity(taskType string) (int, error) {
+	ep, err := s.bigmodel.GetIdleEndpoint(taskType)
+	if err != nil {
+		return 0, err
+	}
+
+	if w := s.pool.GetIdleWorker(); w < ep {
+		return w, nil
+	}
+
+	return ep, nil
+}
+
+func (s *asyncService) asyncWuKong(
+	taskType string, time int64, f func(context.Context, *repository.WuKongTask) error,
+) (err error) {
 	// 1. get waiting tasks before oder time
 	var reqs []repository.WuKongTask
 	if reqs, err = s.repo.GetNewTask(taskType, time); err != nil || len(reqs) == 0 { // TODO config
@@ -37,62 +204,126 @@ func (s *asyncService) AsyncWuKong(taskType string, time int64) (err error) {
 	}
 
 	// 2. get endpoint idle & idle worker
-	ep, w := 0, 0
-	if ep, err = s.bigmodel.GetIdleEndpoint(taskType); err != nil {
+	n, err := s.capacity(taskType)
+	if err != nil {
 		return
 	}
 
-	w = s.pool.GetIdleWorker()
+	reqs = s.claimWuKongTasks(reqs, n)
+
+	// 3. do task in the goroutine pool
+	var tasks pool.TaskList
+	tasks.InitTaskList(reqs, func(ctx context.Context, t *repository.WuKongTask) error {
+		return s.execute(ctx, t.Id, func(ctx context.Context) error { return f(ctx, t) })
+	})
+
+	return s.pool.DoTasks(tasks)
+}
+
+func (s *asyncService) claimWuKongTasks(reqs []repository.WuKongTask, n int) []repository.WuKongTask {
+	claimed := make([]repository.WuKongTask, 0, n)
 
-	// 3. check above
-	f := func(a, b int) int {
-		if a < b {
-			return a
+	for i := range reqs {
+		if len(claimed) >= n {
+			break
 		}
-		return b
-	}
 
-	if len(reqs) > f(ep, w) {
-		reqs = reqs[:f(ep, w)]
+		if s.claim(reqs[i].Id) {
+			claimed = append(claimed, reqs[i])
+		}
 	}
 
-	// 4. do task in the goroutine pool
-	var tasks pool.TaskList
-	tasks.InitTaskList(reqs, s.bigmodel.WuKong)
+	return claimed
+}
 
-	return s.pool.DoTasks(tasks)
+func (s *asyncService) AsyncWuKong(taskType string, time int64) error {
+	return s.asyncWuKong(taskType, time, s.bigmodel.WuKong)
+}
+
+func (s *asyncService) AsyncWuKong4Img(taskType string, time int64) error {
+	return s.asyncWuKong(taskType, time, s.bigmodel.WuKong4Img)
 }
 
-func (s *asyncService) AsyncWuKong4Img(taskType string, time int64) (err error) {
+func (s *asyncService) AsyncTask(taskType string, time int64) (err error) {
+	if !s.bigmodel.HasTaskHandler(taskType) {
+		return fmt.Errorf("no handler for task type: %s", taskType)
+	}
+
 	// 1. get waiting tasks before oder time
-	var reqs []repository.WuKongTask
-	if reqs, err = s.repo.GetNewTask(taskType, time); err != nil || len(reqs) == 0 { // TODO config
+	var reqs []repository.Task
+	if reqs, err = s.repo.GetNewTasks(taskType, time); err != nil || len(reqs) == 0 {
 		return
 	}
 
 	// 2. get endpoint idle & idle worker
-	ep, w := 0, 0
-	if ep, err = s.bigmodel.GetIdleEndpoint(taskType); err != nil {
+	n, err := s.capacity(taskType)
+	if err != nil {
 		return
 	}
 
-	w = s.pool.GetIdleWorker()
-
-	// 3. check above
-	f := func(a, b int) int {
-		if a < b {
-			return a
+	claimed := make([]repository.Task, 0, n)
+	for i := range reqs {
+		if len(claimed) >= n {
+			break
 		}
-		return b
-	}
 
-	if len(reqs) > f(ep, w) {
-		reqs = reqs[:f(ep, w)]
+		if s.claim(reqs[i].Id) {
+			claimed = append(claimed, reqs[i])
+		}
 	}
 
-	// 4. do task in the goroutine pool
+	// 3. do task in the goroutine pool
 	var tasks pool.TaskList
-	tasks.InitTaskListForWuKong4Img(reqs, s.bigmodel.WuKong4Img)
+	tasks.InitTaskListForTasks(claimed, func(ctx context.Context, t *repository.Task) error {
+		return s.execute(ctx, t.Id, func(ctx context.Context) error { return s.bigmodel.HandleTask(ctx, t) })
+	})
 
 	return s.pool.DoTasks(tasks)
 }
+
+func (s *asyncService) CheckFailedTasks(now int64) error {
+	tasks, err := s.repo.GetRetryableTasks(now, s.policy.MaxAttempts, maxFailedTasksPerCheck)
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		t := &tasks[i]
+
+		if err := s.repo.RetryTask(t.Id, s.policy.NextRetryAt(t.Attempts, now)); err != nil {
+			logrus.Errorf("retry failed task %d, err:%s", t.Id, err.Error())
+		}
+	}
+
+	if tasks, err = s.repo.GetExhaustedTasks(now, s.policy.MaxAttempts, maxFailedTasksPerCheck); err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		t := &tasks[i]
+
+		msg := t.ErrMsg
+		if msg == "" {
+			msg = errTaskTimeout.Error()
+		}
+
+		if err := s.repo.KillTask(t.Id, msg); err != nil {
+			logrus.Errorf("kill exhausted task %d, err:%s", t.Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (s *asyncService) CheckCancelledTasks(now int64) error {
+	ids, err := s.repo.FindCancelledTasks(s.pool.RunningTasks())
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		s.pool.CancelTask(id)
+	}
+
+	return nil
+}
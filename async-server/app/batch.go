@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/async-server/domain"
+	"github.com/opensourceways/xihe-server/async-server/domain/pool"
+	"github.com/opensourceways/xihe-server/async-server/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type BatchService interface {
+	CreateBatch(*domain.Batch, []domain.BatchItem) (uint64, error)
+	GetBatch(types.Account, uint64) (repository.BatchResp, error)
+	ListBatches(types.Account, int) ([]repository.BatchResp, error)
+	ListBatchItems(types.Account, uint64) ([]repository.BatchItemResp, error)
+	CancelBatch(types.Account, uint64) error
+	CountPendingItems(types.Account, string) (int, error)
+}
+
+func NewBatchService(repo repository.AsyncBatch) BatchService {
+	return &batchService{
+		repo: repo,
+	}
+}
+
+type batchService struct {
+	repo repository.AsyncBatch
+}
+
+func (s *batchService) CreateBatch(b *domain.Batch, items []domain.BatchItem) (uint64, error) {
+	return s.repo.CreateBatch(b, items)
+}
+
+func (s *batchService) GetBatch(user types.Account, id uint64) (repository.BatchResp, error) {
+	return s.repo.GetBatch(user, id)
+}
+
+func (s *batchService) ListBatches(user types.Account, limit int) ([]repository.BatchResp, error) {
+	return s.repo.ListBatches(user, limit)
+}
+
+func (s *batchService) ListBatchItems(user types.Account, id uint64) (
+	[]repository.BatchItemResp, error,
+) {
+	// check the owner
+	if _, err := s.repo.GetBatch(user, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListBatchItems(id)
+}
+
+func (s *batchService) CancelBatch(user types.Account, id uint64) error {
+	return s.repo.CancelBatch(user, id)
+}
+
+func (s *batchService) CountPendingItems(user types.Account, taskType string) (int, error) {
+	return s.repo.CountPendingItems(user, taskType)
+}
+
+// AsyncBatch runs the items of the active batches within the capacity.
+// The items are run as the async tasks, so they are retried,
+// moved to the dead letter and cancelled in the same way.
+func (s *asyncService) AsyncBatch(now int64) error {
+	batches, err := s.batches.GetActiveBatches()
+	if err != nil {
+		return err
+	}
+
+	for i := range batches {
+		if err := s.runBatch(&batches[i], now); err != nil {
+			logrus.Errorf("run batch %d failed, err:%s", batches[i].Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (s *asyncService) runBatch(b *repository.BatchResp, now int64) error {
+	if b.Progress.IsDone() {
+		return s.batches.UpdateBatchStatus(b.Id, b.Status, domain.TaskStatusFinished)
+	}
+
+	if b.Status.IsWaiting() {
+		err := s.batches.UpdateBatchStatus(b.Id, b.Status, domain.TaskStatusRunning)
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := s.capacity(b.TaskType.TaskType())
+	if err != nil {
+		return err
+	}
+
+	if m := s.batchPolicy.MaxConcurrency - b.Progress.Running; m < n {
+		n = m
+	}
+
+	if n <= 0 {
+		return nil
+	}
+
+	reqs, err := s.batches.GetBatchTasks(b.Id, now, n)
+	if err != nil {
+		return err
+	}
+
+	claimed := make([]repository.Task, 0, len(reqs))
+	for i := range reqs {
+		if s.claim(reqs[i].Id) {
+			claimed = append(claimed, reqs[i])
+		}
+	}
+
+	var tasks pool.TaskList
+	tasks.InitTaskListForTasks(claimed, func(ctx context.Context, t *repository.Task) error {
+		return s.execute(ctx, t.Id, func(ctx context.Context) error { return s.runBatchItem(ctx, t) })
+	})
+
+	return s.pool.DoTasks(tasks)
+}
+
+// runBatchItem runs the item and records it as a call of the api of model
+func (s *asyncService) runBatchItem(ctx context.Context, t *repository.Task) error {
+	start := time.Now()
+
+	err := s.bigmodel.HandleTask(ctx, t)
+
+	if e := s.bigmodel.RecordApiCall(t, time.Since(start), err); e != nil {
+		logrus.Errorf("record api call of task %d failed, err:%s", t.Id, e.Error())
+	}
+
+	return err
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"github.com/opensourceways/xihe-server/async-server/domain/repository"
 	bigmodeldomain "github.com/opensourceways/xihe-server/bigmodel/domain"
 	types "github.com/opensourceways/xihe-server/domain"
 )
@@ -10,3 +11,28 @@ type WuKongCmd struct {
 	Desc  bigmodeldomain.WuKongPictureDesc
 	Style string
 }
+
+type DeadTaskListCmd = repository.DeadTaskListOption
+
+type DeadTaskDTO struct {
+	Id        uint64 `json:"id"`
+	User      string `json:"user"`
+	TaskType  string `json:"task_type"`
+	Attempts  int    `json:"attempts"`
+	Error     string `json:"error"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func (dto *DeadTaskDTO) toDeadTaskDTO(v *repository.DeadTask) {
+	dto.Id = v.Id
+	dto.User = v.User.Account()
+	dto.TaskType = v.TaskType
+	dto.Attempts = v.Attempts
+	dto.Error = v.ErrMsg
+	dto.CreatedAt = v.CreatedAt
+}
+
+type DeadTasksDTO struct {
+	Total int           `json:"total"`
+	Tasks []DeadTaskDTO `json:"tasks"`
+}
@@ -8,6 +8,9 @@ import (
 type AsyncMessageService interface {
 	UpdateWuKongTask(*repository.WuKongResp) error
 	CreateWuKongTask(*domain.WuKongRequest) error
+
+	CreateTask(*domain.TaskRequest) error
+	UpdateTask(*repository.TaskResp) error
 }
 
 func NewAsyncMessageService(
@@ -29,3 +32,11 @@ func (s *asyncMessageService) CreateWuKongTask(d *domain.WuKongRequest) error {
 func (s *asyncMessageService) UpdateWuKongTask(resp *repository.WuKongResp) error {
 	return s.repo.UpdateTask(resp)
 }
+
+func (s *asyncMessageService) CreateTask(d *domain.TaskRequest) error {
+	return s.repo.CreateTask(d)
+}
+
+func (s *asyncMessageService) UpdateTask(resp *repository.TaskResp) error {
+	return s.repo.UpdateTaskResp(resp)
+}
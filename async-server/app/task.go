@@ -8,7 +8,10 @@ import (
 
 type TaskService interface {
 	GetWaitingTaskRank(types.Account, commondomain.Time, []string) (int, error)
+	GetWaitingTaskRanks(commondomain.Time, []string) (map[string]int, error)
 	GetLastFinishedTask(types.Account, []string) (repository.WuKongResp, error)
+	GetLastTask(types.Account, []string) (repository.TaskResp, error)
+	CancelTask(types.Account, uint64, []string) error
 }
 
 func NewTaskService(
@@ -27,6 +30,18 @@ func (s *taskService) GetWaitingTaskRank(user types.Account, time commondomain.T
 	return s.repo.GetWaitingTaskRank(user, time, taskType)
 }
 
+func (s *taskService) GetWaitingTaskRanks(time commondomain.Time, taskType []string) (map[string]int, error) {
+	return s.repo.GetWaitingTaskRanks(time, taskType)
+}
+
 func (s *taskService) GetLastFinishedTask(user types.Account, taskType []string) (resp repository.WuKongResp, err error) {
 	return s.repo.GetLastFinishedTask(user, taskType)
 }
+
+func (s *taskService) GetLastTask(user types.Account, taskType []string) (resp repository.TaskResp, err error) {
+	return s.repo.GetLastTask(user, taskType)
+}
+
+func (s *taskService) CancelTask(user types.Account, id uint64, taskType []string) error {
+	return s.repo.CancelTask(user, id, taskType)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/opensourceways/xihe-server/async-server/domain"
 	"github.com/opensourceways/xihe-server/async-server/infrastructure/poolimpl"
 	"github.com/opensourceways/xihe-server/async-server/infrastructure/repositoryimpl"
 	"github.com/opensourceways/xihe-server/async-server/infrastructure/watchimpl"
@@ -24,11 +25,13 @@ func LoadConfig(path string, cfg *Config) error {
 type Config struct {
 	MaxRetry int `json:"max_retry"`
 
-	BigModel   bigmodel.Config  `json:"bigmodel"     required:"true"`
-	Postgresql PostgresqlConfig `json:"postgresql"   required:"true"`
-	MQ         kafka.Config     `json:"mq"           required:"true"`
-	Pool       poolimpl.Config  `json:"pool"         required:"true"`
-	Watcher    watchimpl.Config `json:"watcher"      required:"true"`
+	BigModel   bigmodel.Config    `json:"bigmodel"     required:"true"`
+	Postgresql PostgresqlConfig   `json:"postgresql"   required:"true"`
+	MQ         kafka.Config       `json:"mq"           required:"true"`
+	Pool       poolimpl.Config    `json:"pool"         required:"true"`
+	Watcher    watchimpl.Config   `json:"watcher"      required:"true"`
+	Retry      domain.RetryPolicy `json:"retry"`
+	Batch      domain.BatchPolicy `json:"batch"`
 }
 
 func (cfg *Config) ConfigItems() []interface{} {
@@ -38,6 +41,8 @@ func (cfg *Config) ConfigItems() []interface{} {
 		&cfg.Postgresql.Config,
 		&cfg.MQ,
 		&cfg.Pool,
+		&cfg.Retry,
+		&cfg.Batch,
 	}
 }
 
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+const (
+	metaBatchIndex = "batch_index"
+	metaCustomId   = "custom_id"
+
+	maxBatchLineSize = 1 << 20
+	maxCustomIdLen   = 64
+)
+
+// NewBatchTaskType returns the task type which can be run in batch
+func NewBatchTaskType(v string) (TaskType, error) {
+	t, err := NewTaskType(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsWuKong() && !t.IsTextGeneration() {
+		return nil, errors.New("unsupported task type of batch")
+	}
+
+	return t, nil
+}
+
+// BatchItem is one line of the input of a batch
+type BatchItem struct {
+	Index    int
+	CustomId string
+	Payload  TaskPayload
+}
+
+func (item *BatchItem) MetaData() map[string]string {
+	m := map[string]string{
+		metaBatchIndex: strconv.Itoa(item.Index),
+	}
+
+	if item.CustomId != "" {
+		m[metaCustomId] = item.CustomId
+	}
+
+	for k, v := range item.Payload.MetaData() {
+		m[k] = v
+	}
+
+	return m
+}
+
+// BatchItemOfTask returns the index and custom id saved in the metadata of a task
+func BatchItemOfTask(m map[string]string) (index int, customId string) {
+	index, _ = strconv.Atoi(m[metaBatchIndex])
+
+	return index, m[metaCustomId]
+}
+
+// ParseBatchInput reads the items from the JSONL input whose line is a JSON object
+// of strings, such as {"custom_id": "q1", "text": "hello"}.
+// The fields except custom_id are decoded by the payload schema of the task type.
+func ParseBatchInput(t TaskType, input []byte, maxItems int) ([]BatchItem, error) {
+	schema, err := GetTaskSchema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(input))
+	scanner.Buffer(nil, maxBatchLineSize)
+
+	items := []BatchItem{}
+
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if len(items) >= maxItems {
+			return nil, fmt.Errorf("too many items, the max is %d", maxItems)
+		}
+
+		m := map[string]string{}
+		if err := json.Unmarshal(line, &m); err != nil {
+			return nil, fmt.Errorf("line %d is not a json object of strings", n)
+		}
+
+		item := BatchItem{
+			Index:    len(items),
+			CustomId: m[metaCustomId],
+		}
+
+		if len(item.CustomId) > maxCustomIdLen {
+			return nil, fmt.Errorf("custom_id of line %d is too long", n)
+		}
+
+		delete(m, metaCustomId)
+
+		if item.Payload, err = schema.Payload(m); err != nil {
+			return nil, fmt.Errorf("line %d is invalid, %s", n, err.Error())
+		}
+
+		items = append(items, item)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("no item in the input")
+	}
+
+	return items, nil
+}
+
+// Batch is a group of async tasks which are created from one input.
+// Its status is waiting, running, finished or cancelled.
+type Batch struct {
+	Id        uint64
+	User      types.Account
+	TaskType  TaskType
+	Status    TaskStatus
+	Total     int
+	CreatedAt int64
+}
+
+func (b *Batch) IsActive() bool {
+	return b.Status.IsWaiting() || b.Status.IsRunning()
+}
+
+// BatchProgress is the count of the items in each status
+type BatchProgress struct {
+	Waiting   int
+	Running   int
+	Finished  int
+	Failed    int
+	Cancelled int
+}
+
+// Count adds n items of the status.
+// The item in error is counted as running, because it will be retried.
+func (p *BatchProgress) Count(status string, n int) {
+	switch status {
+	case taskStatusWaiting:
+		p.Waiting += n
+
+	case taskStatusRunning, taskStatusError:
+		p.Running += n
+
+	case taskStatusFinished:
+		p.Finished += n
+
+	case taskStatusDead:
+		p.Failed += n
+
+	case taskStatusCancelled:
+		p.Cancelled += n
+	}
+}
+
+func (p *BatchProgress) IsDone() bool {
+	return p.Waiting+p.Running == 0
+}
+
+func (p *BatchProgress) Done() int {
+	return p.Finished + p.Failed + p.Cancelled
+}
+
+// BatchPolicy limits the resource used by the batches
+type BatchPolicy struct {
+	// MaxConcurrency is the max number of items of a batch which are run at once,
+	// so that the batches don't starve the interactive tasks.
+	MaxConcurrency int `json:"max_concurrency"`
+}
+
+func (p *BatchPolicy) SetDefault() {
+	if p.MaxConcurrency <= 0 {
+		p.MaxConcurrency = 2
+	}
+}
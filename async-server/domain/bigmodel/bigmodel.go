@@ -1,11 +1,27 @@
 package bigmodel
 
 import (
+	"context"
+	"time"
+
 	"github.com/opensourceways/xihe-server/async-server/domain/repository"
 )
 
 type BigModel interface {
 	GetIdleEndpoint(bid string) (int, error)
-	WuKong(*repository.WuKongTask) error
-	WuKong4Img(*repository.WuKongTask) error
+	WuKong(context.Context, *repository.WuKongTask) error
+	WuKong4Img(context.Context, *repository.WuKongTask) error
+
+	// HandleTask runs the task by the handler registered for its task type,
+	// the task is interrupted when the context is done.
+	HandleTask(context.Context, *repository.Task) error
+	HasTaskHandler(taskType string) bool
+
+	// IsRetryable returns false if the task will fail again,
+	// such as the input is invalid or contains sensitive info.
+	IsRetryable(error) bool
+
+	// RecordApiCall records the task as a call of the api of its model,
+	// err is the result of the task.
+	RecordApiCall(t *repository.Task, latency time.Duration, err error) error
 }
@@ -6,13 +6,34 @@ import (
 )
 
 const (
-	taskStatusWaiting  = "waiting"
-	taskStatusRunning  = "running"
-	taskStatusFinished = "finished"
-	taskStatusError    = "error"
+	taskStatusWaiting   = "waiting"
+	taskStatusRunning   = "running"
+	taskStatusFinished  = "finished"
+	taskStatusError     = "error"
+	taskStatusDead      = "dead"
+	taskStatusCancelled = "cancelled"
 
 	taskTypeWuKong     = "wukong"
 	taskTypeWuKong4Img = "wukong_4img"
+	taskTypeGLM2       = "glm2"
+	taskTypeLLAMA2     = "llama2"
+	taskTypeBaiChuan   = "baichuan"
+	taskTypePanGu      = "pangu"
+	taskTypeCodeGeex   = "codegeex"
+
+	maxTaskPriority = 9
+)
+
+var (
+	TaskStatusWaiting   = dptaskstatus(taskStatusWaiting)
+	TaskStatusRunning   = dptaskstatus(taskStatusRunning)
+	TaskStatusFinished  = dptaskstatus(taskStatusFinished)
+	TaskStatusError     = dptaskstatus(taskStatusError)
+	TaskStatusDead      = dptaskstatus(taskStatusDead)
+	TaskStatusCancelled = dptaskstatus(taskStatusCancelled)
+
+	TaskPriorityNormal  = dptaskpriority(0)
+	TaskPriorityApiUser = dptaskpriority(5)
 )
 
 // taskStatus
@@ -22,13 +43,17 @@ type TaskStatus interface {
 	IsRunning() bool
 	IsFinished() bool
 	IsError() bool
+	IsDead() bool
+	IsCancelled() bool
 }
 
 func NewTaskStatus(v string) (TaskStatus, error) {
 	b := v == taskStatusWaiting ||
 		v == taskStatusRunning ||
 		v == taskStatusFinished ||
-		v == taskStatusError
+		v == taskStatusError ||
+		v == taskStatusDead ||
+		v == taskStatusCancelled
 
 	if !b {
 		return nil, errors.New("invalid value")
@@ -59,18 +84,32 @@ func (r dptaskstatus) IsError() bool {
 	return r.TaskStatus() == taskStatusError
 }
 
+func (r dptaskstatus) IsDead() bool {
+	return r.TaskStatus() == taskStatusDead
+}
+
+func (r dptaskstatus) IsCancelled() bool {
+	return r.TaskStatus() == taskStatusCancelled
+}
+
 // Task Type
 type TaskType interface {
 	TaskType() string
 	IsWuKong() bool
 	IsWuKong4Img() bool
+	IsTextGeneration() bool
 }
 
 type dptasktype string
 
 func NewTaskType(v string) (TaskType, error) {
 	b := v == taskTypeWuKong ||
-		v == taskTypeWuKong4Img
+		v == taskTypeWuKong4Img ||
+		v == taskTypeGLM2 ||
+		v == taskTypeLLAMA2 ||
+		v == taskTypeBaiChuan ||
+		v == taskTypePanGu ||
+		v == taskTypeCodeGeex
 
 	if !b {
 		return nil, errors.New("invalid value")
@@ -91,6 +130,16 @@ func (r dptasktype) IsWuKong4Img() bool {
 	return r.TaskType() == taskTypeWuKong4Img
 }
 
+func (r dptasktype) IsTextGeneration() bool {
+	v := r.TaskType()
+
+	return v == taskTypeGLM2 ||
+		v == taskTypeLLAMA2 ||
+		v == taskTypeBaiChuan ||
+		v == taskTypePanGu ||
+		v == taskTypeCodeGeex
+}
+
 // Links
 type Links interface {
 	Links() []string
@@ -131,3 +180,22 @@ func (r dplinks) StringLinks() string {
 
 	return strings.TrimRight(s, ",")
 }
+
+// Task Priority
+type TaskPriority interface {
+	TaskPriority() int
+}
+
+type dptaskpriority int
+
+func NewTaskPriority(v int) (TaskPriority, error) {
+	if v < 0 || v > maxTaskPriority {
+		return nil, errors.New("invalid task priority")
+	}
+
+	return dptaskpriority(v), nil
+}
+
+func (r dptaskpriority) TaskPriority() int {
+	return int(r)
+}
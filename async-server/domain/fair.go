@@ -0,0 +1,99 @@
+package domain
+
+import "sort"
+
+// weightUnit is divisible by every weight of the priorities,
+// so that the virtual time can be calculated in integer.
+const weightUnit = 2520
+
+// QueuedTask is a task which is waiting or being run
+type QueuedTask struct {
+	Id        uint64
+	User      string
+	Priority  int
+	CreatedAt int64
+	Running   bool
+}
+
+func (t *QueuedTask) cost() int {
+	return weightUnit / (t.Priority + 1)
+}
+
+// FairQueue sorts the tasks by the weighted fair queuing across users.
+// Each user owns a virtual clock which starts with the cost of its running tasks,
+// and every waiting task of the user advances the clock by the cost of it.
+// The waiting tasks are served by the order of the virtual time at which they finish,
+// so a user who submits a burst of tasks can't starve the others,
+// and the task with a higher priority costs less.
+// The running tasks are put at the head of the result.
+func FairQueue(tasks []QueuedTask) []QueuedTask {
+	r := make([]QueuedTask, 0, len(tasks))
+	waiting := make([]QueuedTask, 0, len(tasks))
+	clocks := map[string]int{}
+
+	for i := range tasks {
+		if t := &tasks[i]; t.Running {
+			r = append(r, *t)
+			clocks[t.User] += t.cost()
+		} else {
+			waiting = append(waiting, *t)
+		}
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].CreatedAt < r[j].CreatedAt
+	})
+
+	// the tasks of a user are served by priority first, then by the time created.
+	sort.SliceStable(waiting, func(i, j int) bool {
+		a, b := &waiting[i], &waiting[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+
+		if a.CreatedAt != b.CreatedAt {
+			return a.CreatedAt < b.CreatedAt
+		}
+
+		return a.Id < b.Id
+	})
+
+	finish := make(map[uint64]int, len(waiting))
+	for i := range waiting {
+		t := &waiting[i]
+
+		clocks[t.User] += t.cost()
+		finish[t.Id] = clocks[t.User]
+	}
+
+	sort.SliceStable(waiting, func(i, j int) bool {
+		return finish[waiting[i].Id] < finish[waiting[j].Id]
+	})
+
+	return append(r, waiting...)
+}
+
+// RankInQueue returns the position of the first task of user in the fair queue,
+// it is 0 if the user has no task in the queue.
+func RankInQueue(user string, tasks []QueuedTask) int {
+	for i := range tasks {
+		if tasks[i].User == user {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// RanksInQueue returns the rank of every user who has tasks in the fair queue
+func RanksInQueue(tasks []QueuedTask) map[string]int {
+	r := make(map[string]int, len(tasks))
+
+	for i := range tasks {
+		if _, ok := r[tasks[i].User]; !ok {
+			r[tasks[i].User] = i + 1
+		}
+	}
+
+	return r
+}
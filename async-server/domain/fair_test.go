@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFairQueue(t *testing.T) {
+	cases := []struct {
+		name  string
+		tasks []QueuedTask
+		want  []uint64
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "burst of a user",
+			tasks: []QueuedTask{
+				{Id: 1, User: "a", CreatedAt: 1},
+				{Id: 2, User: "a", CreatedAt: 2},
+				{Id: 3, User: "a", CreatedAt: 3},
+				{Id: 4, User: "b", CreatedAt: 4},
+			},
+			want: []uint64{1, 4, 2, 3},
+		},
+		{
+			name: "higher priority costs less",
+			tasks: []QueuedTask{
+				{Id: 1, User: "a", CreatedAt: 1},
+				{Id: 2, User: "b", CreatedAt: 2, Priority: 2},
+			},
+			want: []uint64{2, 1},
+		},
+		{
+			name: "priority first in the tasks of a user",
+			tasks: []QueuedTask{
+				{Id: 1, User: "a", CreatedAt: 1},
+				{Id: 2, User: "a", CreatedAt: 2, Priority: 1},
+			},
+			want: []uint64{2, 1},
+		},
+		{
+			name: "running tasks at head and advance the clock",
+			tasks: []QueuedTask{
+				{Id: 1, User: "a", CreatedAt: 1},
+				{Id: 2, User: "b", CreatedAt: 2},
+				{Id: 3, User: "a", CreatedAt: 5, Running: true},
+			},
+			want: []uint64{3, 2, 1},
+		},
+		{
+			name: "running tasks by the time created",
+			tasks: []QueuedTask{
+				{Id: 1, User: "a", CreatedAt: 2, Running: true},
+				{Id: 2, User: "b", CreatedAt: 1, Running: true},
+			},
+			want: []uint64{2, 1},
+		},
+	}
+
+	for _, c := range cases {
+		r := FairQueue(c.tasks)
+
+		got := make([]uint64, len(r))
+		for i := range r {
+			got[i] = r[i].Id
+		}
+
+		if len(got) == 0 && len(c.want) == 0 {
+			continue
+		}
+
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expect %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestRanksInQueue(t *testing.T) {
+	tasks := []QueuedTask{
+		{Id: 1, User: "a"},
+		{Id: 2, User: "b"},
+		{Id: 3, User: "a"},
+	}
+
+	want := map[string]int{"a": 1, "b": 2}
+	if got := RanksInQueue(tasks); !reflect.DeepEqual(got, want) {
+		t.Errorf("expect %v, got %v", want, got)
+	}
+
+	cases := []struct {
+		user string
+		want int
+	}{
+		{"a", 1},
+		{"b", 2},
+		{"c", 0},
+	}
+
+	for _, c := range cases {
+		if got := RankInQueue(c.user, tasks); got != c.want {
+			t.Errorf("rank of %s: expect %d, got %d", c.user, c.want, got)
+		}
+	}
+}
@@ -1,42 +1,55 @@
 package pool
 
 import (
+	"context"
+
 	"github.com/opensourceways/xihe-server/async-server/domain/repository"
 )
 
-type TaskList []func()
+// Task is run by the worker of pool, it should abandon the work when the ctx is done.
+type Task struct {
+	Id  uint64
+	Run func(context.Context)
+}
+
+type TaskList []Task
 
 type Pool interface {
 	GetIdleWorker() int
 	DoTasks(TaskList) error
+
+	// RunningTasks returns the ids of the tasks being run
+	RunningTasks() []uint64
+	// CancelTask signals the worker which is running the task to abandon it
+	CancelTask(id uint64) bool
 }
 
-func (r *TaskList) InitTaskList(reqs []repository.WuKongTask, f func(*repository.WuKongTask) error) {
+func (r *TaskList) InitTaskList(reqs []repository.WuKongTask, f func(context.Context, *repository.WuKongTask) error) {
 	*r = make(TaskList, len(reqs))
 
 	// build new function with new address
-	funcBuild := func(i int) func() {
-		return func() {
-			f(&reqs[i])
+	funcBuild := func(i int) func(context.Context) {
+		return func(ctx context.Context) {
+			f(ctx, &reqs[i])
 		}
 	}
 
 	for i := range reqs {
-		([]func())(*r)[i] = funcBuild(i)
+		(*r)[i] = Task{Id: reqs[i].Id, Run: funcBuild(i)}
 	}
 }
 
-func (r *TaskList) InitTaskListForWuKong4Img(reqs []repository.WuKongTask, f func(*repository.WuKongTask) error) {
+func (r *TaskList) InitTaskListForTasks(reqs []repository.Task, f func(context.Context, *repository.Task) error) {
 	*r = make(TaskList, len(reqs))
 
 	// build new function with new address
-	funcBuild := func(i int) func() {
-		return func() {
-			f(&reqs[i])
+	funcBuild := func(i int) func(context.Context) {
+		return func(ctx context.Context) {
+			f(ctx, &reqs[i])
 		}
 	}
 
 	for i := range reqs {
-		([]func())(*r)[i] = funcBuild(i)
+		(*r)[i] = Task{Id: reqs[i].Id, Run: funcBuild(i)}
 	}
 }
@@ -19,14 +19,80 @@ type WuKongResp struct {
 	Links domain.Links
 }
 
+// Task is the generic async task whose payload is decoded by the schema of its task type
+type Task struct {
+	domain.TaskRequest
+
+	Id     uint64
+	Status domain.TaskStatus
+}
+
+type TaskResp struct {
+	Task
+
+	Result domain.TaskResult
+	ErrMsg string
+}
+
+// TaskAttempt is the execution state of a task
+type TaskAttempt struct {
+	Id       uint64
+	TaskType string
+	Attempts int
+	Deadline int64
+	ErrMsg   string
+}
+
+type DeadTask struct {
+	TaskAttempt
+
+	User      types.Account
+	CreatedAt int64
+}
+
+type DeadTaskListOption struct {
+	TaskType     []string
+	PageNum      int
+	CountPerPage int
+}
+
 type AsyncTask interface {
-	GetNewTask(taskType string, time int64) ([]WuKongTask, error) // TODO: async task not wukong task
+	// wukong
+	GetNewTask(taskType string, time int64) ([]WuKongTask, error)
 
 	UpdateTask(*WuKongResp) error
 	InsertTask(*domain.WuKongRequest) error
 
 	GetWaitingTaskRank(types.Account, commondomain.Time, []string) (int, error)
+	// GetWaitingTaskRanks returns the ranks of all the users in the queue
+	GetWaitingTaskRanks(commondomain.Time, []string) (map[string]int, error)
 	GetLastFinishedTask(types.Account, []string) (WuKongResp, error)
+
+	// generic
+	GetNewTasks(taskType string, time int64) ([]Task, error)
+	CreateTask(*domain.TaskRequest) error
+	UpdateTaskResp(*TaskResp) error
+	GetLastTask(types.Account, []string) (TaskResp, error)
+
+	// CancelTask cancels the waiting or running task of user.
+	CancelTask(user types.Account, id uint64, taskType []string) error
+	FindCancelledTasks(ids []uint64) ([]uint64, error)
+
+	// retry & dead letter
+	ClaimTask(id uint64, deadline int64) (bool, error)
+	FailTask(id uint64, errMsg string) error
+	// RenewTask extends the deadline of the running task
+	RenewTask(id uint64, deadline int64) error
+	// GetRetryableTasks returns at most limit failed or timeout tasks
+	// whose attempts are less than maxAttempts.
+	GetRetryableTasks(now int64, maxAttempts, limit int) ([]TaskAttempt, error)
+	// GetExhaustedTasks returns at most limit failed or timeout tasks
+	// whose attempts are not less than maxAttempts.
+	GetExhaustedTasks(now int64, maxAttempts, limit int) ([]TaskAttempt, error)
+	RetryTask(id uint64, retryAt int64) error
+	KillTask(id uint64, errMsg string) error
+	ListDeadTasks(*DeadTaskListOption) ([]DeadTask, int, error)
+	RequeueDeadTask(id uint64, now int64) error
 }
 
 func (r *WuKongTask) SetDefaultStatusWuKongTask(req *domain.WuKongRequest) {
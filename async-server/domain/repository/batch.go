@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/async-server/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type BatchResp struct {
+	domain.Batch
+
+	Progress domain.BatchProgress
+}
+
+// BatchItemResp is the result of an item, the ErrMsg is set if it is failed
+type BatchItemResp struct {
+	TaskResp
+
+	Index    int
+	CustomId string
+}
+
+// AsyncBatch saves the batches, the items of a batch are saved as the async tasks.
+type AsyncBatch interface {
+	// CreateBatch saves the batch and its items at once
+	CreateBatch(*domain.Batch, []domain.BatchItem) (uint64, error)
+	GetBatch(user types.Account, id uint64) (BatchResp, error)
+	ListBatches(user types.Account, limit int) ([]BatchResp, error)
+	ListBatchItems(id uint64) ([]BatchItemResp, error)
+	CancelBatch(user types.Account, id uint64) error
+
+	// CountPendingItems returns the number of items which are not done
+	// in all the batches of user for the task type.
+	CountPendingItems(user types.Account, taskType string) (int, error)
+
+	// worker
+	GetActiveBatches() ([]BatchResp, error)
+	GetBatchTasks(id uint64, now int64, n int) ([]Task, error)
+	UpdateBatchStatus(id uint64, from, to domain.TaskStatus) error
+}
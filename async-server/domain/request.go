@@ -11,5 +11,7 @@ type WuKongRequest struct {
 	TaskType  TaskType
 	Style     string
 	Desc      bigmodeldomain.WuKongPictureDesc
+	Input     bigmodeldomain.WuKongPictureInput
+	Priority  TaskPriority
 	CreatedAt commondomain.Time
 }
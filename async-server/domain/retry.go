@@ -0,0 +1,58 @@
+package domain
+
+// RetryPolicy decides how a failed or timeout task is retried.
+// The unit of time is second.
+type RetryPolicy struct {
+	MaxAttempts int   `json:"max_attempts"`
+	Timeout     int64 `json:"timeout"`
+	BackoffBase int64 `json:"backoff_base"`
+	BackoffMax  int64 `json:"backoff_max"`
+}
+
+func (p *RetryPolicy) SetDefault() {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+
+	if p.Timeout <= 0 {
+		p.Timeout = 300
+	}
+
+	if p.BackoffBase <= 0 {
+		p.BackoffBase = 10
+	}
+
+	if p.BackoffMax < p.BackoffBase {
+		p.BackoffMax = 30 * p.BackoffBase
+	}
+}
+
+// IsExhausted returns true if the task should be moved to the dead letter
+func (p *RetryPolicy) IsExhausted(attempts int) bool {
+	return attempts >= p.MaxAttempts
+}
+
+// Deadline returns the time before which a task starting at now must be done
+func (p *RetryPolicy) Deadline(now int64) int64 {
+	return now + p.Timeout
+}
+
+// ClaimDeadline returns the deadline of a task claimed at now. The task may wait
+// for a worker before it starts, so the time of Timeout is left for the waiting.
+func (p *RetryPolicy) ClaimDeadline(now int64) int64 {
+	return p.Deadline(now) + p.Timeout
+}
+
+// NextRetryAt returns the time of next attempt with exponential backoff
+func (p *RetryPolicy) NextRetryAt(attempts int, now int64) int64 {
+	d := p.BackoffBase
+	for i := 1; i < attempts && d < p.BackoffMax; i++ {
+		d *= 2
+	}
+
+	if d > p.BackoffMax {
+		d = p.BackoffMax
+	}
+
+	return now + d
+}
@@ -0,0 +1,86 @@
+package domain
+
+import "testing"
+
+func TestRetryPolicySetDefault(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy RetryPolicy
+		want   RetryPolicy
+	}{
+		{
+			name:   "all default",
+			policy: RetryPolicy{},
+			want:   RetryPolicy{MaxAttempts: 3, Timeout: 300, BackoffBase: 10, BackoffMax: 300},
+		},
+		{
+			name:   "max less than base",
+			policy: RetryPolicy{MaxAttempts: 5, Timeout: 60, BackoffBase: 20, BackoffMax: 5},
+			want:   RetryPolicy{MaxAttempts: 5, Timeout: 60, BackoffBase: 20, BackoffMax: 600},
+		},
+		{
+			name:   "kept",
+			policy: RetryPolicy{MaxAttempts: 1, Timeout: 10, BackoffBase: 1, BackoffMax: 1},
+			want:   RetryPolicy{MaxAttempts: 1, Timeout: 10, BackoffBase: 1, BackoffMax: 1},
+		},
+	}
+
+	for _, c := range cases {
+		c.policy.SetDefault()
+
+		if c.policy != c.want {
+			t.Errorf("%s: expect %+v, got %+v", c.name, c.want, c.policy)
+		}
+	}
+}
+
+func TestRetryPolicyNextRetryAt(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3, Timeout: 300, BackoffBase: 10, BackoffMax: 60}
+
+	cases := []struct {
+		attempts int
+		want     int64
+	}{
+		{0, 1010},
+		{1, 1010},
+		{2, 1020},
+		{3, 1040},
+		{4, 1060},
+		{10, 1060},
+	}
+
+	for _, c := range cases {
+		if got := p.NextRetryAt(c.attempts, 1000); got != c.want {
+			t.Errorf("attempts %d: expect %d, got %d", c.attempts, c.want, got)
+		}
+	}
+}
+
+func TestRetryPolicyDeadLetter(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3, Timeout: 300, BackoffBase: 10, BackoffMax: 60}
+
+	cases := []struct {
+		attempts int
+		want     bool
+	}{
+		{0, false},
+		{2, false},
+		{3, true},
+		{4, true},
+	}
+
+	for _, c := range cases {
+		if got := p.IsExhausted(c.attempts); got != c.want {
+			t.Errorf("attempts %d: expect exhausted=%v, got %v", c.attempts, c.want, got)
+		}
+	}
+
+	if v := p.Deadline(1000); v != 1300 {
+		t.Errorf("expect deadline 1300, got %d", v)
+	}
+
+	// the time of waiting for a worker is left in the claim
+	if v := p.ClaimDeadline(1000); v != 1600 {
+		t.Errorf("expect claim deadline 1600, got %d", v)
+	}
+}
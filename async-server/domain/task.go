@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	bigmodeldomain "github.com/opensourceways/xihe-server/bigmodel/domain"
+	commondomain "github.com/opensourceways/xihe-server/common/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+const (
+	metaStyle   = "style"
+	metaDesc    = "desc"
+	metaMode    = "mode"
+	metaImage   = "image"
+	metaMask    = "mask"
+	metaLinks   = "links"
+	metaText    = "text"
+	metaLang    = "lang"
+	metaContent = "content"
+	metaError   = "error"
+)
+
+// TaskPayload is the typed input of an async task
+type TaskPayload interface {
+	MetaData() map[string]string
+}
+
+// TaskResult is the typed output of an async task
+type TaskResult interface {
+	MetaData() map[string]string
+}
+
+// TaskSchema decodes the payload and the result of one kind of task
+type TaskSchema struct {
+	Payload func(map[string]string) (TaskPayload, error)
+	Result  func(map[string]string) (TaskResult, error)
+}
+
+var taskSchemas = map[string]TaskSchema{}
+
+func init() {
+	wukong := TaskSchema{
+		Payload: newWuKongPayload,
+		Result:  newLinksResult,
+	}
+
+	RegisterTaskSchema(taskTypeWuKong, wukong)
+	RegisterTaskSchema(taskTypeWuKong4Img, wukong)
+
+	text := TaskSchema{
+		Payload: newTextPayload,
+		Result:  newTextResult,
+	}
+
+	RegisterTaskSchema(taskTypeGLM2, text)
+	RegisterTaskSchema(taskTypeLLAMA2, text)
+	RegisterTaskSchema(taskTypeBaiChuan, text)
+	RegisterTaskSchema(taskTypePanGu, text)
+
+	RegisterTaskSchema(taskTypeCodeGeex, TaskSchema{
+		Payload: newCodeGeexPayload,
+		Result:  newTextResult,
+	})
+}
+
+func RegisterTaskSchema(t string, s TaskSchema) {
+	taskSchemas[t] = s
+}
+
+func GetTaskSchema(t TaskType) (TaskSchema, error) {
+	if t == nil {
+		return TaskSchema{}, errors.New("missing task type")
+	}
+
+	s, ok := taskSchemas[t.TaskType()]
+	if !ok {
+		return s, fmt.Errorf("no schema for task type: %s", t.TaskType())
+	}
+
+	return s, nil
+}
+
+// TaskRequest
+type TaskRequest struct {
+	User      types.Account
+	TaskType  TaskType
+	Payload   TaskPayload
+	Priority  TaskPriority
+	CreatedAt commondomain.Time
+}
+
+func (r *TaskRequest) MetaData() map[string]string {
+	if r.Payload == nil {
+		return map[string]string{}
+	}
+
+	return r.Payload.MetaData()
+}
+
+// WuKongPayload
+type WuKongPayload struct {
+	Style string
+	Desc  bigmodeldomain.WuKongPictureDesc
+	Input bigmodeldomain.WuKongPictureInput
+}
+
+func newWuKongPayload(m map[string]string) (TaskPayload, error) {
+	desc, err := bigmodeldomain.NewWuKongPictureDesc(m[metaDesc])
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := NewWuKongPictureInput(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return WuKongPayload{
+		Style: m[metaStyle],
+		Desc:  desc,
+		Input: input,
+	}, nil
+}
+
+func (p WuKongPayload) MetaData() map[string]string {
+	m := map[string]string{metaStyle: p.Style}
+
+	if p.Desc != nil {
+		m[metaDesc] = p.Desc.WuKongPictureDesc()
+	}
+
+	for k, v := range WuKongPictureInputMetaData(&p.Input) {
+		m[k] = v
+	}
+
+	return m
+}
+
+// NewWuKongPictureInput decodes the input of img2img and inpainting from the metadata,
+// it is text-to-image when the mode is missing.
+func NewWuKongPictureInput(m map[string]string) (bigmodeldomain.WuKongPictureInput, error) {
+	return bigmodeldomain.NewWuKongPictureInput(m[metaMode], m[metaImage], m[metaMask])
+}
+
+func WuKongPictureInputMetaData(input *bigmodeldomain.WuKongPictureInput) map[string]string {
+	m := map[string]string{metaMode: input.WuKongMode().WuKongMode()}
+
+	if input.Image != nil {
+		m[metaImage] = input.Image.OBSPath()
+	}
+
+	if input.Mask != nil {
+		m[metaMask] = input.Mask.OBSPath()
+	}
+
+	return m
+}
+
+// TextPayload
+type TextPayload struct {
+	Text string
+}
+
+func NewTextPayload(v string) (TextPayload, error) {
+	if v == "" {
+		return TextPayload{}, errors.New("empty text")
+	}
+
+	return TextPayload{Text: v}, nil
+}
+
+func newTextPayload(m map[string]string) (TaskPayload, error) {
+	return NewTextPayload(m[metaText])
+}
+
+func (p TextPayload) MetaData() map[string]string {
+	return map[string]string{metaText: p.Text}
+}
+
+// CodeGeexPayload
+type CodeGeexPayload struct {
+	Lang    string
+	Content string
+}
+
+func newCodeGeexPayload(m map[string]string) (TaskPayload, error) {
+	if m[metaLang] == "" || m[metaText] == "" {
+		return nil, errors.New("invalid codegeex payload")
+	}
+
+	return CodeGeexPayload{
+		Lang:    m[metaLang],
+		Content: m[metaText],
+	}, nil
+}
+
+func (p CodeGeexPayload) MetaData() map[string]string {
+	return map[string]string{
+		metaLang: p.Lang,
+		metaText: p.Content,
+	}
+}
+
+// LinksResult
+type LinksResult struct {
+	Links Links
+}
+
+func newLinksResult(m map[string]string) (TaskResult, error) {
+	v, err := NewLinks(m[metaLinks])
+	if err != nil {
+		return nil, err
+	}
+
+	return LinksResult{Links: v}, nil
+}
+
+func (r LinksResult) MetaData() map[string]string {
+	if r.Links == nil {
+		return map[string]string{}
+	}
+
+	return map[string]string{metaLinks: r.Links.StringLinks()}
+}
+
+// TextResult
+type TextResult struct {
+	Content string
+}
+
+func newTextResult(m map[string]string) (TaskResult, error) {
+	return TextResult{Content: m[metaContent]}, nil
+}
+
+func (r TextResult) MetaData() map[string]string {
+	return map[string]string{metaContent: r.Content}
+}
+
+// RawTaskResult is the result which has not been decoded by the schema of task
+type RawTaskResult map[string]string
+
+func (r RawTaskResult) MetaData() map[string]string {
+	return r
+}
+
+// ErrorOfTask returns the error message saved in the metadata of a failed task
+func ErrorOfTask(m map[string]string) string {
+	return m[metaError]
+}
+
+func ErrorMetaData(msg string) map[string]string {
+	return map[string]string{metaError: msg}
+}
@@ -1,20 +1,44 @@
 package bigmodelimpl
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
 	"github.com/opensourceways/xihe-server/async-server/domain/bigmodel"
 	"github.com/opensourceways/xihe-server/async-server/domain/repository"
 	bigmodelapp "github.com/opensourceways/xihe-server/bigmodel/app"
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	modeldomain "github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
 )
 
+type taskHandler func(context.Context, *repository.Task) error
+
 func NewBigModelImpl(s bigmodelapp.AsyncBigModelService) bigmodel.BigModel {
-	return &bigmodelImpl{
+	impl := &bigmodelImpl{
 		srv: s,
 	}
+
+	impl.handlers = map[string]taskHandler{
+		string(domain.BigmodelGLM2):     impl.glm2,
+		string(domain.BigmodelLLAMA2):   impl.llama2,
+		string(domain.BigmodelBaiChuan): impl.baichuan,
+		string(domain.BigmodelPanGu):    impl.pangu,
+		string(domain.BigmodelCodeGeex): impl.codegeex,
+
+		// the wukong task is handled by the handler only when it is the item of batch
+		string(domain.BigmodelWuKong): impl.wukong,
+	}
+
+	return impl
 }
 
 type bigmodelImpl struct {
 	srv bigmodelapp.AsyncBigModelService
+
+	handlers map[string]taskHandler
 }
 
 func (impl *bigmodelImpl) GetIdleEndpoint(bid string) (
@@ -23,28 +47,154 @@ func (impl *bigmodelImpl) GetIdleEndpoint(bid string) (
 	return impl.srv.GetIdleEndpoint(bid)
 }
 
-func (impl *bigmodelImpl) WuKong(d *repository.WuKongTask) (err error) {
+func (impl *bigmodelImpl) WuKong(ctx context.Context, d *repository.WuKongTask) (err error) {
 	cmd := bigmodelapp.WuKongCmd{
 		WuKongPictureMeta: domain.WuKongPictureMeta{
 			Style: d.Style,
 			Desc:  d.Desc,
+
+			WuKongPictureInput: d.Input,
 		},
 
 		EsType: d.TaskType.TaskType(),
 	}
 
-	return impl.srv.WuKong(d.Id, d.User, &cmd)
+	return impl.srv.WuKong(ctx, d.Id, d.User, &cmd)
 }
 
-func (impl *bigmodelImpl) WuKong4Img(d *repository.WuKongTask) (err error) {
+func (impl *bigmodelImpl) WuKong4Img(ctx context.Context, d *repository.WuKongTask) (err error) {
 	cmd := bigmodelapp.WuKongCmd{
 		WuKongPictureMeta: domain.WuKongPictureMeta{
 			Style: d.Style,
 			Desc:  d.Desc,
+
+			WuKongPictureInput: d.Input,
 		},
 
 		EsType: d.TaskType.TaskType(),
 	}
 
-	return impl.srv.WuKong(d.Id, d.User, &cmd)
+	return impl.srv.WuKong(ctx, d.Id, d.User, &cmd)
+}
+
+func (impl *bigmodelImpl) wukong(ctx context.Context, t *repository.Task) error {
+	p, ok := t.Payload.(asyncdomain.WuKongPayload)
+	if !ok {
+		return modeldomain.NewErrorInvalidInput(errors.New("unexpected payload of wukong task"))
+	}
+
+	cmd := bigmodelapp.WuKongCmd{
+		WuKongPictureMeta: domain.WuKongPictureMeta{
+			Style: p.Style,
+			Desc:  p.Desc,
+
+			WuKongPictureInput: p.Input,
+		},
+
+		EsType: t.TaskType.TaskType(),
+	}
+
+	return impl.srv.WuKong(ctx, t.Id, t.User, &cmd)
+}
+
+func (impl *bigmodelImpl) RecordApiCall(t *repository.Task, latency time.Duration, err error) error {
+	// the task type of batch is the name of model
+	return impl.srv.RecordApiCall(t.User, t.TaskType.TaskType(), latency, err)
+}
+
+func (impl *bigmodelImpl) HasTaskHandler(taskType string) bool {
+	_, ok := impl.handlers[taskType]
+
+	return ok
+}
+
+func (impl *bigmodelImpl) IsRetryable(err error) bool {
+	return !modeldomain.IsErrorSensitiveInfo(err) && !modeldomain.IsErrorInvalidInput(err)
+}
+
+func (impl *bigmodelImpl) HandleTask(ctx context.Context, t *repository.Task) error {
+	h, ok := impl.handlers[t.TaskType.TaskType()]
+	if !ok {
+		return fmt.Errorf("no handler for task type: %s", t.TaskType.TaskType())
+	}
+
+	return h(ctx, t)
+}
+
+func (impl *bigmodelImpl) text(t *repository.Task) (string, error) {
+	p, ok := t.Payload.(asyncdomain.TextPayload)
+	if !ok {
+		return "", modeldomain.NewErrorInvalidInput(errors.New("unexpected payload of text task"))
+	}
+
+	return p.Text, nil
+}
+
+func (impl *bigmodelImpl) glm2(ctx context.Context, t *repository.Task) error {
+	v, err := impl.text(t)
+	if err != nil {
+		return err
+	}
+
+	cmd := bigmodelapp.GLM2Cmd{User: t.User}
+	cmd.SetDefault()
+
+	if cmd.Text, err = domain.NewGLM2Text(v); err != nil {
+		return modeldomain.NewErrorInvalidInput(err)
+	}
+
+	return impl.srv.GLM2(ctx, t.Id, &cmd)
+}
+
+func (impl *bigmodelImpl) llama2(ctx context.Context, t *repository.Task) error {
+	v, err := impl.text(t)
+	if err != nil {
+		return err
+	}
+
+	cmd := bigmodelapp.LLAMA2Cmd{User: t.User}
+	cmd.SetDefault()
+
+	if cmd.Text, err = domain.NewLLAMA2Text(v); err != nil {
+		return modeldomain.NewErrorInvalidInput(err)
+	}
+
+	return impl.srv.LLAMA2(ctx, t.Id, &cmd)
+}
+
+func (impl *bigmodelImpl) baichuan(ctx context.Context, t *repository.Task) error {
+	v, err := impl.text(t)
+	if err != nil {
+		return err
+	}
+
+	cmd := bigmodelapp.BaiChuanCmd{User: t.User}
+	cmd.SetDefault()
+
+	if cmd.Text, err = domain.NewBaiChuanText(v); err != nil {
+		return modeldomain.NewErrorInvalidInput(err)
+	}
+
+	return impl.srv.BaiChuan(ctx, t.Id, &cmd)
+}
+
+func (impl *bigmodelImpl) pangu(ctx context.Context, t *repository.Task) error {
+	v, err := impl.text(t)
+	if err != nil {
+		return err
+	}
+
+	return impl.srv.PanGu(ctx, t.Id, t.User, v)
+}
+
+func (impl *bigmodelImpl) codegeex(ctx context.Context, t *repository.Task) error {
+	p, ok := t.Payload.(asyncdomain.CodeGeexPayload)
+	if !ok {
+		return modeldomain.NewErrorInvalidInput(errors.New("unexpected payload of codegeex task"))
+	}
+
+	return impl.srv.CodeGeex(ctx, t.Id, t.User, &bigmodelapp.CodeGeexCmd{
+		Lang:    p.Lang,
+		Content: p.Content,
+	})
 }
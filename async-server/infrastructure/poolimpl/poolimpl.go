@@ -1,6 +1,9 @@
 package poolimpl
 
 import (
+	"context"
+	"sync"
+
 	"github.com/panjf2000/ants/v2"
 
 	"github.com/opensourceways/xihe-server/async-server/domain/pool"
@@ -8,12 +11,16 @@ import (
 
 func NewPoolImpl() pool.Pool {
 	return &poolImpl{
-		p: gpool,
+		p:       gpool,
+		running: map[uint64]context.CancelFunc{},
 	}
 }
 
 type poolImpl struct {
 	p *ants.Pool
+
+	lock    sync.Mutex
+	running map[uint64]context.CancelFunc
 }
 
 func (impl *poolImpl) GetIdleWorker() int {
@@ -22,7 +29,7 @@ func (impl *poolImpl) GetIdleWorker() int {
 
 func (impl *poolImpl) DoTasks(tasks pool.TaskList) error {
 	for i := range tasks {
-		if err := impl.p.Submit(tasks[i]); err != nil {
+		if err := impl.p.Submit(impl.wrap(tasks[i])); err != nil {
 			return err
 		}
 
@@ -30,3 +37,48 @@ func (impl *poolImpl) DoTasks(tasks pool.TaskList) error {
 
 	return nil
 }
+
+// wrap registers the task so that it can be cancelled while running.
+func (impl *poolImpl) wrap(t pool.Task) func() {
+	return func() {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		impl.lock.Lock()
+		impl.running[t.Id] = cancel
+		impl.lock.Unlock()
+
+		defer func() {
+			impl.lock.Lock()
+			delete(impl.running, t.Id)
+			impl.lock.Unlock()
+
+			cancel()
+		}()
+
+		t.Run(ctx)
+	}
+}
+
+func (impl *poolImpl) RunningTasks() []uint64 {
+	impl.lock.Lock()
+	defer impl.lock.Unlock()
+
+	r := make([]uint64, 0, len(impl.running))
+	for id := range impl.running {
+		r = append(r, id)
+	}
+
+	return r
+}
+
+func (impl *poolImpl) CancelTask(id uint64) bool {
+	impl.lock.Lock()
+	defer impl.lock.Unlock()
+
+	cancel, ok := impl.running[id]
+	if ok {
+		cancel()
+	}
+
+	return ok
+}
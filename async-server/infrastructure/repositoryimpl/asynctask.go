@@ -1,14 +1,25 @@
 package repositoryimpl
 
 import (
+	"fmt"
+
+	"gorm.io/gorm"
+
 	"github.com/opensourceways/xihe-server/async-server/domain"
 	"github.com/opensourceways/xihe-server/async-server/domain/repository"
 	commondomain "github.com/opensourceways/xihe-server/common/domain"
 	commonrepo "github.com/opensourceways/xihe-server/common/domain/repository"
 	"github.com/opensourceways/xihe-server/common/infrastructure/pgsql"
 	types "github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/utils"
 )
 
+// queuedTaskQuery selects the running tasks and the waiting tasks whose time to retry is reached,
+// which are created after the time or being retried.
+// The items of batches are run by the worker of batch, so they are excluded.
+const queuedTaskQuery = "task_type = ? AND batch_id = 0 AND (created_at > ? OR attempts > 0) AND " +
+	"(status = ? OR (status = ? AND retry_at <= ?))"
+
 func NewAsyncTaskRepo(cfg *Config) repository.AsyncTask {
 	return &asyncTaskRepoImpl{
 		cli: pgsql.NewDBTable(cfg.Table.AsyncTask),
@@ -22,11 +33,10 @@ type asyncTaskRepoImpl struct {
 func (impl *asyncTaskRepoImpl) GetNewTask(taskType string, time int64) (
 	d []repository.WuKongTask, err error,
 ) {
-	var twukong []TAsyncTask
-
-	impl.cli.DB().
-		Where("task_type = ? AND created_at > ? AND status = ?", taskType, time, "waiting").
-		Find(&twukong)
+	twukong, err := impl.waitingTasks(taskType, time)
+	if err != nil {
+		return
+	}
 
 	d = make([]repository.WuKongTask, len(twukong))
 	for i := range twukong {
@@ -36,16 +46,54 @@ func (impl *asyncTaskRepoImpl) GetNewTask(taskType string, time int64) (
 	return
 }
 
-func (impl *asyncTaskRepoImpl) UpdateTask(resp *repository.WuKongResp) (err error) {
+// queue returns the tasks in the order of fair queuing.
+func (impl *asyncTaskRepoImpl) queue(query string, args ...interface{}) ([]TAsyncTask, error) {
+	var t []TAsyncTask
 
-	v := NewTAsyncTask()
-	v.toTAsyncTask(resp)
+	if err := impl.cli.DB().Where(query, args...).Find(&t).Error; err != nil {
+		return nil, err
+	}
 
-	filter := map[string]interface{}{
-		fieldId: resp.WuKongTask.Id,
+	m := make(map[uint64]*TAsyncTask, len(t))
+	q := make([]domain.QueuedTask, len(t))
+	for i := range t {
+		m[t[i].Id] = &t[i]
+		q[i] = t[i].toQueuedTask()
+	}
+
+	q = domain.FairQueue(q)
+
+	r := make([]TAsyncTask, len(q))
+	for i := range q {
+		r[i] = *m[q[i].Id]
+	}
+
+	return r, nil
+}
+
+// waitingTasks returns the waiting tasks in the order of fair queuing,
+// the running tasks are counted in to share the capacity fairly.
+func (impl *asyncTaskRepoImpl) waitingTasks(taskType string, time int64) ([]TAsyncTask, error) {
+	t, err := impl.queue(queuedTaskQuery, taskType, time, "running", "waiting", utils.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]TAsyncTask, 0, len(t))
+	for i := range t {
+		if t[i].Status == "waiting" {
+			r = append(r, t[i])
+		}
 	}
 
-	return impl.cli.UpdateRecord(filter, v) // TODO: don't cover old metadata
+	return r, nil
+}
+
+func (impl *asyncTaskRepoImpl) UpdateTask(resp *repository.WuKongResp) error {
+	v := NewTAsyncTask()
+	v.toTAsyncTask(resp)
+
+	return impl.updateResult(resp.WuKongTask.Id, v.Status, v.MetaData)
 }
 
 func (impl *asyncTaskRepoImpl) InsertTask(req *domain.WuKongRequest) error {
@@ -57,13 +105,54 @@ func (impl *asyncTaskRepoImpl) InsertTask(req *domain.WuKongRequest) error {
 }
 
 func (impl *asyncTaskRepoImpl) GetWaitingTaskRank(user types.Account, t commondomain.Time, taskType []string) (r int, err error) {
-	var twukong []TAsyncTask
+	q, err := impl.waitingQueue(t, taskType)
+	if err != nil {
+		return
+	}
 
-	// 1. get all task before t
-	err = impl.cli.DB().
-		Where("created_at > ? and status IN ? and task_type IN ?", t.Time(), []string{"waiting", "running"}, taskType).
-		Find(&twukong).Error
+	return domain.RankInQueue(user.Account(), q), nil
+}
+
+func (impl *asyncTaskRepoImpl) GetWaitingTaskRanks(t commondomain.Time, taskType []string) (map[string]int, error) {
+	q, err := impl.waitingQueue(t, taskType)
 	if err != nil {
+		return nil, err
+	}
+
+	return domain.RanksInQueue(q), nil
+}
+
+// waitingQueue returns all tasks after t in the order of dispatching
+func (impl *asyncTaskRepoImpl) waitingQueue(t commondomain.Time, taskType []string) ([]domain.QueuedTask, error) {
+	tasks, err := impl.queue(
+		"task_type IN ? AND batch_id = 0 AND (created_at > ? OR attempts > 0) AND status IN ?",
+		taskType, t.Time(), []string{"waiting", "running"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	q := make([]domain.QueuedTask, len(tasks))
+	for i := range tasks {
+		q[i] = tasks[i].toQueuedTask()
+	}
+
+	return q, nil
+}
+
+func (impl *asyncTaskRepoImpl) GetLastFinishedTask(user types.Account, taskType []string) (resp repository.WuKongResp, err error) {
+	var twukong TAsyncTask
+
+	filter := map[string]interface{}{
+		fieldUserName: user.Account(),
+		fieldTaskType: taskType,
+		fieldStatus:   []string{"finished", "cancelled"},
+		fieldBatchId:  0,
+	}
+
+	order := "created_at DESC"
+
+	if err = impl.cli.GetOrderOneRecord(filter, order, &twukong); err != nil {
 		if impl.cli.IsRowNotFound(err) {
 			err = commonrepo.NewErrorResourceNotExists(err)
 
@@ -73,59 +162,324 @@ func (impl *asyncTaskRepoImpl) GetWaitingTaskRank(user types.Account, t commondo
 		return
 	}
 
-	// 2. is user in task
-	f1 := func(v []TAsyncTask) bool {
-		for i := range twukong {
-			if twukong[i].User == user.Account() {
-				return true
-			}
-		}
+	err = twukong.toWuKongTaskResp(&resp)
+
+	return
+}
 
-		return false
+func (impl *asyncTaskRepoImpl) GetNewTasks(taskType string, time int64) (
+	d []repository.Task, err error,
+) {
+	t, err := impl.waitingTasks(taskType, time)
+	if err != nil {
+		return
 	}
 
-	if !f1(twukong) {
-		return 0, nil
+	d = make([]repository.Task, 0, len(t))
+	for i := range t {
+		var v repository.Task
+		if err := t[i].toTask(&v); err != nil {
+			continue
+		}
+
+		d = append(d, v)
 	}
 
-	// 2. caculate rank
-	f2 := func(v []TAsyncTask) int {
-		i := 0
+	return
+}
+
+func (impl *asyncTaskRepoImpl) CreateTask(req *domain.TaskRequest) error {
+	v := NewTAsyncTask()
+	v.toTAsyncTaskFromTaskRequest(req)
+
+	return impl.cli.Create(v)
+}
+
+func (impl *asyncTaskRepoImpl) UpdateTaskResp(resp *repository.TaskResp) error {
+	v := NewTAsyncTask()
+	v.mergeTaskResp(resp)
+
+	return impl.updateResult(resp.Id, v.Status, v.MetaData)
+}
+
+// updateResult saves the status and merges the metadata into the old one,
+// so that the payload is kept and the task can be retried with it.
+// The cancelled task is abandoned, its result is dropped.
+func (impl *asyncTaskRepoImpl) updateResult(id uint64, status string, m JSONMap) error {
+	update := map[string]interface{}{}
 
-		for j := range v {
-			if v[j].CreatedAt >= t.Time() {
-				i++
-			}
+	if status != "" {
+		update[fieldStatus] = status
+	}
+
+	if len(m) > 0 {
+		expr, err := mergeMetaData(m)
+		if err != nil {
+			return err
 		}
 
-		return i
+		update[fieldMetaData] = expr
 	}
 
-	return f2(twukong), nil
+	if len(update) == 0 {
+		return nil
+	}
+
+	return impl.cli.DB().Model(&TAsyncTask{}).
+		Where(fieldId+" = ?", id).
+		Where(fieldStatus+" <> ?", "cancelled").
+		Updates(update).Error
 }
 
-func (impl *asyncTaskRepoImpl) GetLastFinishedTask(user types.Account, taskType []string) (resp repository.WuKongResp, err error) {
-	var twukong TAsyncTask
+func (impl *asyncTaskRepoImpl) GetLastTask(user types.Account, taskType []string) (
+	resp repository.TaskResp, err error,
+) {
+	var t TAsyncTask
 
 	filter := map[string]interface{}{
 		fieldUserName: user.Account(),
 		fieldTaskType: taskType,
-		fieldStatus:   "finished",
+		fieldBatchId:  0,
 	}
 
-	order := "created_at DESC"
-
-	if err = impl.cli.GetOrderOneRecord(filter, order, &twukong); err != nil {
+	if err = impl.cli.GetOrderOneRecord(filter, "created_at DESC", &t); err != nil {
 		if impl.cli.IsRowNotFound(err) {
 			err = commonrepo.NewErrorResourceNotExists(err)
+		}
 
-			return
+		return
+	}
+
+	err = t.toTaskResp(&resp)
+
+	return
+}
+
+func (impl *asyncTaskRepoImpl) ClaimTask(id uint64, deadline int64) (bool, error) {
+	r := impl.cli.DB().Model(&TAsyncTask{}).
+		Where(map[string]interface{}{fieldId: id, fieldStatus: "waiting"}).
+		Updates(map[string]interface{}{
+			fieldStatus:   "running",
+			fieldAttempts: gorm.Expr(fieldAttempts + " + 1"),
+			fieldDeadline: deadline,
+		})
+
+	if r.Error != nil {
+		return false, r.Error
+	}
+
+	return r.RowsAffected == 1, nil
+}
+
+func (impl *asyncTaskRepoImpl) setStatus(
+	id uint64, from []string, update map[string]interface{}, errMsg string,
+) error {
+	filter := map[string]interface{}{
+		fieldId: id,
+	}
+
+	if errMsg != "" {
+		expr, err := mergeMetaData(domain.ErrorMetaData(errMsg))
+		if err != nil {
+			return err
 		}
 
+		update[fieldMetaData] = expr
+	}
+
+	r := impl.cli.DB().Model(&TAsyncTask{}).
+		Where(filter).
+		Where(fieldStatus+" IN ?", from).
+		Updates(update)
+
+	if r.Error != nil {
+		return r.Error
+	}
+
+	if r.RowsAffected == 0 {
+		return commonrepo.NewErrorConcurrentUpdating(
+			fmt.Errorf("task %d is not in status %v", id, from),
+		)
+	}
+
+	return nil
+}
+
+func (impl *asyncTaskRepoImpl) FailTask(id uint64, errMsg string) error {
+	// the status may has been set to error by the message of bigmodel
+	return impl.setStatus(
+		id, []string{"running", "error"},
+		map[string]interface{}{fieldStatus: "error"},
+		errMsg,
+	)
+}
+
+func (impl *asyncTaskRepoImpl) GetRetryableTasks(now int64, maxAttempts, limit int) (
+	[]repository.TaskAttempt, error,
+) {
+	return impl.failedTasks(now, fieldAttempts+" < ?", maxAttempts, limit)
+}
+
+func (impl *asyncTaskRepoImpl) GetExhaustedTasks(now int64, maxAttempts, limit int) (
+	[]repository.TaskAttempt, error,
+) {
+	return impl.failedTasks(now, fieldAttempts+" >= ?", maxAttempts, limit)
+}
+
+// failedTasks selects the failed or timeout tasks which have been claimed at least once,
+// the tasks which failed before the retry is supported have no attempts and are left.
+func (impl *asyncTaskRepoImpl) failedTasks(
+	now int64, attempts string, maxAttempts, limit int,
+) (r []repository.TaskAttempt, err error) {
+	var t []TAsyncTask
+
+	err = impl.cli.DB().
+		Where(
+			"(status = ? OR (status = ? AND deadline > 0 AND deadline < ?))",
+			"error", "running", now,
+		).
+		Where(fieldAttempts+" > 0").
+		Where(attempts, maxAttempts).
+		Order(fieldId).
+		Limit(limit).
+		Find(&t).Error
+	if err != nil {
 		return
 	}
 
-	err = twukong.toWuKongTaskResp(&resp)
+	r = make([]repository.TaskAttempt, len(t))
+	for i := range t {
+		t[i].toTaskAttempt(&r[i])
+	}
+
+	return
+}
+
+func (impl *asyncTaskRepoImpl) RenewTask(id uint64, deadline int64) error {
+	return impl.setStatus(
+		id, []string{"running"},
+		map[string]interface{}{fieldDeadline: deadline},
+		"",
+	)
+}
+
+func (impl *asyncTaskRepoImpl) RetryTask(id uint64, retryAt int64) error {
+	return impl.setStatus(
+		id, []string{"error", "running"},
+		map[string]interface{}{
+			fieldStatus:   "waiting",
+			fieldRetryAt:  retryAt,
+			fieldDeadline: 0,
+		},
+		"",
+	)
+}
+
+func (impl *asyncTaskRepoImpl) KillTask(id uint64, errMsg string) error {
+	return impl.setStatus(
+		id, []string{"error", "running"},
+		map[string]interface{}{
+			fieldStatus:   "dead",
+			fieldDeadline: 0,
+		},
+		errMsg,
+	)
+}
+
+func (impl *asyncTaskRepoImpl) ListDeadTasks(opt *repository.DeadTaskListOption) (
+	r []repository.DeadTask, total int, err error,
+) {
+	filter := map[string]interface{}{
+		fieldStatus: "dead",
+	}
+
+	if len(opt.TaskType) > 0 {
+		filter[fieldTaskType] = opt.TaskType
+	}
+
+	if total, err = impl.cli.Count(filter); err != nil || total == 0 {
+		return
+	}
+
+	var t []TAsyncTask
+
+	err = impl.cli.GetRecords(
+		filter, &t,
+		pgsql.Pagination{
+			PageNum:      opt.PageNum,
+			CountPerPage: opt.CountPerPage,
+		},
+		[]pgsql.SortByColumn{{Column: fieldCreateAt}},
+	)
+	if err != nil {
+		return
+	}
+
+	r = make([]repository.DeadTask, 0, len(t))
+	for i := range t {
+		var v repository.DeadTask
+		if err := t[i].toDeadTask(&v); err != nil {
+			continue
+		}
+
+		r = append(r, v)
+	}
+
+	return
+}
+
+func (impl *asyncTaskRepoImpl) RequeueDeadTask(id uint64, now int64) error {
+	// reset created_at, otherwise the task is too old to be scanned
+	return impl.setStatus(
+		id, []string{"dead"},
+		map[string]interface{}{
+			fieldStatus:   "waiting",
+			fieldAttempts: 0,
+			fieldRetryAt:  0,
+			fieldDeadline: 0,
+			fieldCreateAt: now,
+		},
+		"",
+	)
+}
+
+func (impl *asyncTaskRepoImpl) CancelTask(user types.Account, id uint64, taskType []string) error {
+	filter := map[string]interface{}{
+		fieldId:       id,
+		fieldUserName: user.Account(),
+		fieldTaskType: taskType,
+		fieldBatchId:  0,
+	}
+
+	r := impl.cli.DB().Model(&TAsyncTask{}).
+		Where(filter).
+		Where(fieldStatus+" IN ?", []string{"waiting", "running"}).
+		Updates(map[string]interface{}{
+			fieldStatus:   "cancelled",
+			fieldDeadline: 0,
+		})
+
+	if r.Error != nil {
+		return r.Error
+	}
+
+	if r.RowsAffected == 0 {
+		return commonrepo.NewErrorResourceNotExists(
+			fmt.Errorf("no waiting or running task %d", id),
+		)
+	}
+
+	return nil
+}
+
+func (impl *asyncTaskRepoImpl) FindCancelledTasks(ids []uint64) (r []uint64, err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	err = impl.cli.DB().Model(&TAsyncTask{}).
+		Where(map[string]interface{}{fieldId: ids, fieldStatus: "cancelled"}).
+		Pluck(fieldId, &r).Error
 
 	return
 }
@@ -0,0 +1,259 @@
+package repositoryimpl
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/opensourceways/xihe-server/async-server/domain"
+	"github.com/opensourceways/xihe-server/async-server/domain/repository"
+	commonrepo "github.com/opensourceways/xihe-server/common/domain/repository"
+	"github.com/opensourceways/xihe-server/common/infrastructure/pgsql"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+// batchInsertSize is the number of items inserted by one statement
+const batchInsertSize = 200
+
+var (
+	activeBatchStatus = []string{"waiting", "running"}
+
+	// the item in error will be retried
+	pendingItemStatus = []string{"waiting", "running", "error"}
+)
+
+func NewAsyncBatchRepo(cfg *Config) repository.AsyncBatch {
+	return &asyncBatchRepoImpl{
+		cli:  pgsql.NewDBTable(cfg.Table.AsyncBatch),
+		task: pgsql.NewDBTable(cfg.Table.AsyncTask),
+	}
+}
+
+type asyncBatchRepoImpl struct {
+	cli  pgsqlClient
+	task pgsqlClient
+}
+
+func (impl *asyncBatchRepoImpl) CreateBatch(b *domain.Batch, items []domain.BatchItem) (
+	uint64, error,
+) {
+	t := TAsyncBatch{}
+	t.toTAsyncBatch(b)
+
+	err := impl.cli.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&t).Error; err != nil {
+			return err
+		}
+
+		tasks := make([]TAsyncTask, len(items))
+		for i := range items {
+			v := NewTAsyncTask()
+			v.toTAsyncTaskFromBatchItem(&t, &items[i])
+
+			tasks[i] = *v
+		}
+
+		return tx.CreateInBatches(tasks, batchInsertSize).Error
+	})
+
+	return t.Id, err
+}
+
+func (impl *asyncBatchRepoImpl) toBatchResp(t *TAsyncBatch) (r repository.BatchResp, err error) {
+	if err = t.toBatch(&r.Batch); err != nil {
+		return
+	}
+
+	r.Progress, err = impl.progress(t.Id)
+
+	return
+}
+
+func (impl *asyncBatchRepoImpl) progress(id uint64) (p domain.BatchProgress, err error) {
+	var counts []struct {
+		Status string
+		Count  int
+	}
+
+	err = impl.task.DB().Model(&TAsyncTask{}).
+		Select("status, count(*) AS count").
+		Where(fieldBatchId+" = ?", id).
+		Group(fieldStatus).
+		Scan(&counts).Error
+	if err != nil {
+		return
+	}
+
+	for i := range counts {
+		p.Count(counts[i].Status, counts[i].Count)
+	}
+
+	return
+}
+
+func (impl *asyncBatchRepoImpl) GetBatch(user types.Account, id uint64) (
+	r repository.BatchResp, err error,
+) {
+	filter := map[string]interface{}{
+		fieldId:       id,
+		fieldUserName: user.Account(),
+	}
+
+	var t TAsyncBatch
+	if err = impl.cli.GetRecord(filter, &t); err != nil {
+		if impl.cli.IsRowNotFound(err) {
+			err = commonrepo.NewErrorResourceNotExists(err)
+		}
+
+		return
+	}
+
+	return impl.toBatchResp(&t)
+}
+
+func (impl *asyncBatchRepoImpl) batches(query *gorm.DB) ([]repository.BatchResp, error) {
+	var t []TAsyncBatch
+	if err := query.Find(&t).Error; err != nil {
+		return nil, err
+	}
+
+	r := make([]repository.BatchResp, 0, len(t))
+	for i := range t {
+		v, err := impl.toBatchResp(&t[i])
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, v)
+	}
+
+	return r, nil
+}
+
+func (impl *asyncBatchRepoImpl) ListBatches(user types.Account, limit int) (
+	[]repository.BatchResp, error,
+) {
+	return impl.batches(
+		impl.cli.DB().Model(&TAsyncBatch{}).
+			Where(map[string]interface{}{fieldUserName: user.Account()}).
+			Order(fieldCreateAt + " DESC").
+			Limit(limit),
+	)
+}
+
+func (impl *asyncBatchRepoImpl) ListBatchItems(id uint64) (r []repository.BatchItemResp, err error) {
+	var t []TAsyncTask
+
+	err = impl.task.DB().
+		Where(map[string]interface{}{fieldBatchId: id}).
+		Order(fieldId).
+		Find(&t).Error
+	if err != nil {
+		return
+	}
+
+	r = make([]repository.BatchItemResp, len(t))
+	for i := range t {
+		if err = t[i].toBatchItemResp(&r[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (impl *asyncBatchRepoImpl) CancelBatch(user types.Account, id uint64) error {
+	return impl.cli.DB().Transaction(func(tx *gorm.DB) error {
+		r := tx.Model(&TAsyncBatch{}).
+			Where(map[string]interface{}{fieldId: id, fieldUserName: user.Account()}).
+			Where(fieldStatus+" IN ?", activeBatchStatus).
+			Update(fieldStatus, "cancelled")
+
+		if r.Error != nil {
+			return r.Error
+		}
+
+		if r.RowsAffected == 0 {
+			return commonrepo.NewErrorResourceNotExists(
+				fmt.Errorf("no waiting or running batch %d", id),
+			)
+		}
+
+		// the running items will be abandoned by the checker of cancelled tasks
+		return tx.Model(&TAsyncTask{}).
+			Where(map[string]interface{}{fieldBatchId: id}).
+			Where(fieldStatus+" IN ?", pendingItemStatus).
+			Updates(map[string]interface{}{
+				fieldStatus:   "cancelled",
+				fieldDeadline: 0,
+			}).Error
+	})
+}
+
+func (impl *asyncBatchRepoImpl) CountPendingItems(user types.Account, taskType string) (int, error) {
+	var total int64
+
+	err := impl.task.DB().Model(&TAsyncTask{}).
+		Where(map[string]interface{}{
+			fieldUserName: user.Account(),
+			fieldTaskType: taskType,
+		}).
+		Where(fieldBatchId+" > 0 AND "+fieldStatus+" IN ?", pendingItemStatus).
+		Count(&total).Error
+
+	return int(total), err
+}
+
+func (impl *asyncBatchRepoImpl) GetActiveBatches() ([]repository.BatchResp, error) {
+	return impl.batches(
+		impl.cli.DB().Model(&TAsyncBatch{}).
+			Where(fieldStatus+" IN ?", activeBatchStatus).
+			Order(fieldCreateAt),
+	)
+}
+
+func (impl *asyncBatchRepoImpl) GetBatchTasks(id uint64, now int64, n int) (
+	r []repository.Task, err error,
+) {
+	var t []TAsyncTask
+
+	err = impl.task.DB().
+		Where(map[string]interface{}{fieldBatchId: id, fieldStatus: "waiting"}).
+		Where(fieldRetryAt+" <= ?", now).
+		Order(fieldId).
+		Limit(n).
+		Find(&t).Error
+	if err != nil {
+		return
+	}
+
+	r = make([]repository.Task, 0, len(t))
+	for i := range t {
+		var v repository.Task
+		if err := t[i].toTask(&v); err != nil {
+			continue
+		}
+
+		r = append(r, v)
+	}
+
+	return
+}
+
+func (impl *asyncBatchRepoImpl) UpdateBatchStatus(id uint64, from, to domain.TaskStatus) error {
+	r := impl.cli.DB().Model(&TAsyncBatch{}).
+		Where(map[string]interface{}{fieldId: id, fieldStatus: from.TaskStatus()}).
+		Update(fieldStatus, to.TaskStatus())
+
+	if r.Error != nil {
+		return r.Error
+	}
+
+	if r.RowsAffected == 0 {
+		return commonrepo.NewErrorConcurrentUpdating(
+			fmt.Errorf("batch %d is not in status %s", id, from.TaskStatus()),
+		)
+	}
+
+	return nil
+}
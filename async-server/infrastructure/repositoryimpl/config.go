@@ -5,5 +5,6 @@ type Config struct {
 }
 
 type Table struct {
-	AsyncTask string `json:"async_task" required:"true"`
+	AsyncTask  string `json:"async_task"  required:"true"`
+	AsyncBatch string `json:"async_batch" required:"true"`
 }
@@ -11,10 +11,18 @@ import (
 )
 
 const (
+	metaLinks = "links"
+
 	fieldId       = "id"
 	fieldUserName = "username"
 	fieldTaskType = "task_type"
 	fieldStatus   = "status"
+	fieldAttempts = "attempts"
+	fieldDeadline = "deadline"
+	fieldRetryAt  = "retry_at"
+	fieldMetaData = "metadata"
+	fieldCreateAt = "created_at"
+	fieldBatchId  = "batch_id"
 )
 
 func (table *TAsyncTask) toWuKongTask(p *repository.WuKongTask) (err error) {
@@ -43,6 +51,10 @@ func (table *TAsyncTask) toWuKongTask(p *repository.WuKongTask) (err error) {
 		}
 	}
 
+	if p.Priority, err = domain.NewTaskPriority(table.Priority); err != nil {
+		return
+	}
+
 	if table.MetaData["style"] != nil {
 		var ok bool
 		if p.Style, ok = table.MetaData["style"].(string); !ok {
@@ -50,6 +62,10 @@ func (table *TAsyncTask) toWuKongTask(p *repository.WuKongTask) (err error) {
 		}
 	}
 
+	if p.Input, err = domain.NewWuKongPictureInput(table.metaData()); err != nil {
+		return
+	}
+
 	p.Id = table.Id
 
 	return
@@ -95,10 +111,18 @@ func (table *TAsyncTask) toTAsyncTaskFromWuKongTask(task *repository.WuKongTask)
 		table.MetaData["desc"] = task.Desc.WuKongPictureDesc()
 	}
 
+	for k, v := range domain.WuKongPictureInputMetaData(&task.Input) {
+		table.MetaData[k] = v
+	}
+
 	if task.Status != nil {
 		table.Status = task.Status.TaskStatus()
 	}
 
+	if task.Priority != nil {
+		table.Priority = task.Priority.TaskPriority()
+	}
+
 	if task.CreatedAt != nil {
 		table.CreatedAt = task.CreatedAt.Time()
 	}
@@ -116,3 +140,194 @@ func (table *TAsyncTask) toTAsyncTask(resp *repository.WuKongResp) {
 	}
 
 }
+
+func (table *TAsyncTask) metaData() map[string]string {
+	m := make(map[string]string, len(table.MetaData))
+
+	for k, v := range table.MetaData {
+		if s, ok := v.(string); ok {
+			m[k] = s
+		}
+	}
+
+	return m
+}
+
+func (table *TAsyncTask) toTask(p *repository.Task) (err error) {
+	if p.User, err = types.NewAccount(table.User); err != nil {
+		return
+	}
+
+	if p.TaskType, err = domain.NewTaskType(table.TaskType); err != nil {
+		return
+	}
+
+	if p.Status, err = domain.NewTaskStatus(table.Status); err != nil {
+		return
+	}
+
+	if p.CreatedAt, err = commondomain.NewTime(table.CreatedAt); err != nil {
+		return
+	}
+
+	if p.Priority, err = domain.NewTaskPriority(table.Priority); err != nil {
+		return
+	}
+
+	schema, err := domain.GetTaskSchema(p.TaskType)
+	if err != nil {
+		return
+	}
+
+	if p.Payload, err = schema.Payload(table.metaData()); err != nil {
+		return
+	}
+
+	p.Id = table.Id
+
+	return
+}
+
+func (table *TAsyncTask) toTaskResp(p *repository.TaskResp) (err error) {
+	if err = table.toTask(&p.Task); err != nil {
+		return
+	}
+
+	m := table.metaData()
+
+	if p.Status.IsError() {
+		p.ErrMsg = domain.ErrorOfTask(m)
+
+		return
+	}
+
+	if !p.Status.IsFinished() {
+		return
+	}
+
+	schema, err := domain.GetTaskSchema(p.TaskType)
+	if err != nil {
+		return
+	}
+
+	p.Result, err = schema.Result(m)
+
+	return
+}
+
+func (table *TAsyncTask) toTAsyncTaskFromTaskRequest(req *domain.TaskRequest) {
+	table.User = req.User.Account()
+	table.TaskType = req.TaskType.TaskType()
+	table.Status = domain.TaskStatusWaiting.TaskStatus()
+
+	for k, v := range req.MetaData() {
+		table.MetaData[k] = v
+	}
+
+	if req.Priority != nil {
+		table.Priority = req.Priority.TaskPriority()
+	}
+
+	if req.CreatedAt != nil {
+		table.CreatedAt = req.CreatedAt.Time()
+	}
+}
+
+func (table *TAsyncTask) mergeTaskResp(resp *repository.TaskResp) {
+	if resp.Status != nil {
+		table.Status = resp.Status.TaskStatus()
+	}
+
+	if resp.Result != nil {
+		for k, v := range resp.Result.MetaData() {
+			table.MetaData[k] = v
+		}
+	}
+
+	if resp.ErrMsg != "" {
+		for k, v := range domain.ErrorMetaData(resp.ErrMsg) {
+			table.MetaData[k] = v
+		}
+	}
+}
+
+func (table *TAsyncTask) toTaskAttempt(p *repository.TaskAttempt) {
+	p.Id = table.Id
+	p.TaskType = table.TaskType
+	p.Attempts = table.Attempts
+	p.Deadline = table.Deadline
+
+	m := table.metaData()
+	if p.ErrMsg = domain.ErrorOfTask(m); p.ErrMsg == "" && table.TaskType != "" {
+		// the error of wukong task is saved in links
+		if t, err := domain.NewTaskType(table.TaskType); err == nil && (t.IsWuKong() || t.IsWuKong4Img()) {
+			p.ErrMsg = m[metaLinks]
+		}
+	}
+}
+
+func (table *TAsyncTask) toDeadTask(p *repository.DeadTask) (err error) {
+	table.toTaskAttempt(&p.TaskAttempt)
+
+	p.CreatedAt = table.CreatedAt
+	p.User, err = types.NewAccount(table.User)
+
+	return
+}
+
+func (table *TAsyncTask) toTAsyncTaskFromBatchItem(b *TAsyncBatch, item *domain.BatchItem) {
+	table.User = b.User
+	table.TaskType = b.TaskType
+	table.Status = domain.TaskStatusWaiting.TaskStatus()
+	table.CreatedAt = b.CreatedAt
+	table.BatchId = b.Id
+
+	for k, v := range item.MetaData() {
+		table.MetaData[k] = v
+	}
+}
+
+func (table *TAsyncTask) toBatchItemResp(p *repository.BatchItemResp) (err error) {
+	if err = table.toTaskResp(&p.TaskResp); err != nil {
+		return
+	}
+
+	m := table.metaData()
+
+	// the error of the dead item is reported as well
+	if p.Status.IsDead() {
+		p.ErrMsg = domain.ErrorOfTask(m)
+	}
+
+	p.Index, p.CustomId = domain.BatchItemOfTask(m)
+
+	return
+}
+
+func (table *TAsyncBatch) toTAsyncBatch(b *domain.Batch) {
+	table.User = b.User.Account()
+	table.TaskType = b.TaskType.TaskType()
+	table.Status = domain.TaskStatusWaiting.TaskStatus()
+	table.Total = b.Total
+	table.CreatedAt = b.CreatedAt
+}
+
+func (table *TAsyncBatch) toBatch(b *domain.Batch) (err error) {
+	if b.User, err = types.NewAccount(table.User); err != nil {
+		return
+	}
+
+	if b.TaskType, err = domain.NewTaskType(table.TaskType); err != nil {
+		return
+	}
+
+	if b.Status, err = domain.NewTaskStatus(table.Status); err != nil {
+		return
+	}
+
+	b.Id = table.Id
+	b.Total = table.Total
+	b.CreatedAt = table.CreatedAt
+
+	return
+}
@@ -4,6 +4,11 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/opensourceways/xihe-server/async-server/domain"
 )
 
 type TAsyncTask struct {
@@ -13,6 +18,11 @@ type TAsyncTask struct {
 	Status    string  `gorm:"column:status"`
 	CreatedAt int64   `gorm:"column:created_at;default:extract(epoch from now())"`
 	MetaData  JSONMap `gorm:"column:metadata;type:json;default: '{}'::json"`
+	Attempts  int     `gorm:"column:attempts;default:0"`
+	Deadline  int64   `gorm:"column:deadline;default:0"`
+	RetryAt   int64   `gorm:"column:retry_at;default:0"`
+	Priority  int     `gorm:"column:priority;default:0"`
+	BatchId   uint64  `gorm:"column:batch_id;default:0"`
 }
 
 func NewTAsyncTask() *TAsyncTask {
@@ -33,6 +43,19 @@ func (j JSONMap) Value() (driver.Value, error) {
 	return string(b), err
 }
 
+// mergeMetaData returns the expression which merges m into the metadata in one statement,
+// the value of m overrides the old one of the same key.
+func mergeMetaData(m interface{}) (clause.Expr, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return clause.Expr{}, err
+	}
+
+	return gorm.Expr(
+		"(COALESCE("+fieldMetaData+", '{}')::jsonb || ?::jsonb)::json", string(b),
+	), nil
+}
+
 // Scan implement sql.Scanner interfacne
 func (j *JSONMap) Scan(src interface{}) error {
 	if src == nil {
@@ -60,6 +83,30 @@ func (j *JSONMap) Scan(src interface{}) error {
 	return nil
 }
 
+func (w *TAsyncTask) toQueuedTask() domain.QueuedTask {
+	return domain.QueuedTask{
+		Id:        w.Id,
+		User:      w.User,
+		Priority:  w.Priority,
+		CreatedAt: w.CreatedAt,
+		Running:   w.Status == domain.TaskStatusRunning.TaskStatus(),
+	}
+}
+
 func (w TAsyncTask) TableName() string {
 	return "async_task"
 }
+
+// TAsyncBatch
+type TAsyncBatch struct {
+	Id        uint64 `gorm:"primaryKey;column:id"`
+	User      string `gorm:"column:username"`
+	TaskType  string `gorm:"column:task_type"`
+	Status    string `gorm:"column:status"`
+	Total     int    `gorm:"column:total"`
+	CreatedAt int64  `gorm:"column:created_at"`
+}
+
+func (b TAsyncBatch) TableName() string {
+	return "async_batch"
+}
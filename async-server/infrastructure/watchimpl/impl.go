@@ -12,23 +12,26 @@ import (
 type Watcher struct {
 	repo repository.AsyncTask
 
-	handles map[string]func(string, int64) error
-	cfg     Config
-	timer   *time.Ticker
-	wg      sync.WaitGroup
+	handles  map[string]func(string, int64) error
+	checkers []func(int64) error
+	cfg      Config
+	timer    *time.Ticker
+	wg       sync.WaitGroup
 }
 
 func NewWather(
 	cfg Config,
 	repo repository.AsyncTask,
 	handles map[string]func(string, int64) error,
+	checkers ...func(int64) error,
 ) *Watcher {
 
 	return &Watcher{
-		repo:    repo,
-		timer:   time.NewTicker(time.Duration(cfg.Time.TriggerTime) * time.Second),
-		handles: handles,
-		cfg:     cfg,
+		repo:     repo,
+		timer:    time.NewTicker(time.Duration(cfg.Time.TriggerTime) * time.Second),
+		handles:  handles,
+		checkers: checkers,
+		cfg:      cfg,
 	}
 }
 
@@ -42,6 +45,11 @@ func (w *Watcher) watchRequset() {
 			go w.work(bname, now.Add(-time.Duration(w.cfg.Time.ScanTime)*time.Second).Unix())
 		}
 
+		for i := range w.checkers {
+			w.wg.Add(1)
+			go w.check(w.checkers[i], now.Unix())
+		}
+
 	}
 }
 
@@ -60,6 +68,14 @@ func (w *Watcher) work(bname string, time int64) (err error) {
 	return nil
 }
 
+func (w *Watcher) check(checker func(int64) error, now int64) {
+	defer w.wg.Done()
+
+	if err := checker(now); err != nil {
+		logrus.Errorf("check tasks, err:%s", err.Error())
+	}
+}
+
 func (w *Watcher) Run() {
 
 	w.watchRequset()
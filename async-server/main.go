@@ -15,6 +15,7 @@ import (
 	"github.com/opensourceways/xihe-server/async-server/infrastructure/repositoryimpl"
 	"github.com/opensourceways/xihe-server/async-server/infrastructure/watchimpl"
 	bigmodelapp "github.com/opensourceways/xihe-server/bigmodel/app"
+	bigmodeldomain "github.com/opensourceways/xihe-server/bigmodel/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/bigmodels"
 	bmmsgadapter "github.com/opensourceways/xihe-server/bigmodel/infrastructure/messageadapter"
 	"github.com/opensourceways/xihe-server/common/infrastructure/kafka"
@@ -76,6 +77,8 @@ func main() {
 	}
 
 	// bigmodel
+	bigmodeldomain.Init(&cfg.BigModel.Domain)
+
 	if err := bigmodels.Init(&cfg.BigModel.Config); err != nil {
 		logrus.Fatalf("initialize big model failed, err:%s", err.Error())
 	}
@@ -101,6 +104,8 @@ func main() {
 	bm := bigmodels.NewBigModelService()
 	publisher := kafka.PublisherAdapter()
 
+	bigmodels.SetModerationAuditor(bmmsgadapter.NewModerationAuditor(&cfg.BigModel.Message, publisher))
+
 	// aysnc.bigmodel.bigmodel
 	bigmodel := bigmodelimpl.NewBigModelImpl(
 		bigmodelapp.NewAsyncBigModelService(bm, bmmsgadapter.NewMessageAdapter(
@@ -110,22 +115,45 @@ func main() {
 
 	// repo
 	asyncWuKongRepo := repositoryimpl.NewAsyncTaskRepo(&cfg.Postgresql.Config)
+	asyncBatchRepo := repositoryimpl.NewAsyncBatchRepo(&cfg.Postgresql.Config)
 
 	// async app
 	asyncAppService := app.NewAsyncService(
 		bigmodel,
 		poolimpl.NewPoolImpl(),
 		asyncWuKongRepo,
+		cfg.Retry,
+		asyncBatchRepo,
+		cfg.Batch,
 	)
 
 	// watch
+	handles := map[string]func(string, int64) error{
+		string(bigmodeldomain.BigmodelWuKong):     asyncAppService.AsyncWuKong,
+		string(bigmodeldomain.BigmodelWuKong4Img): asyncAppService.AsyncWuKong4Img,
+	}
+
+	textModels := []bigmodeldomain.BigmodelType{
+		bigmodeldomain.BigmodelGLM2,
+		bigmodeldomain.BigmodelLLAMA2,
+		bigmodeldomain.BigmodelBaiChuan,
+		bigmodeldomain.BigmodelPanGu,
+		bigmodeldomain.BigmodelCodeGeex,
+	}
+
+	for _, t := range textModels {
+		if bigmodel.HasTaskHandler(string(t)) {
+			handles[string(t)] = asyncAppService.AsyncTask
+		}
+	}
+
 	w := watchimpl.NewWather(
 		cfg.Watcher,
 		asyncWuKongRepo,
-		map[string]func(string, int64) error{
-			"wukong":      asyncAppService.AsyncWuKong,
-			"wukong_4img": asyncAppService.AsyncWuKong4Img,
-		},
+		handles,
+		asyncAppService.CheckFailedTasks,
+		asyncAppService.CheckCancelledTasks,
+		asyncAppService.AsyncBatch,
 	)
 
 	w.Run()
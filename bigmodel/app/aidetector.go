@@ -13,7 +13,7 @@ func (s bigModelService) AIDetector(cmd *AIDetectorCmd) (code string, ismachine
 	})
 
 	// audit
-	if err = s.fm.CheckText(cmd.Text.AIDetectorText()); err != nil {
+	if err = s.fm.CheckText(string(domain.BigmodelAIDetector), cmd.Text.AIDetectorText()); err != nil {
 		code = ErrorBigModelSensitiveInfo
 
 		return
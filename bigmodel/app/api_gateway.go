@@ -0,0 +1,173 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/quota"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	crepository "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+type ApiGatewayService interface {
+	// Access checks whether the user can call the api of model and consumes the quota
+	Access(types.Account, domain.ModelName) (ApiRateLimitDTO, string, error)
+	// Record records a call of the api whatever it succeeded or not
+	Record(*ApiCallRecordCmd) error
+	GetUsage(types.Account, domain.ModelName) (ApiQuotaUsageDTO, error)
+	ListUsageStats(*ApiUsageStatsCmd) (ApiUsageStatsDTO, error)
+}
+
+func NewApiGatewayService(
+	apiService repository.ApiService,
+	usage repository.ApiUsage,
+	limiter quota.Limiter,
+) ApiGatewayService {
+	return &apiGatewayService{
+		apiService: apiService,
+		usage:      usage,
+		limiter:    limiter,
+	}
+}
+
+type apiGatewayService struct {
+	apiService repository.ApiService
+	usage      repository.ApiUsage
+	limiter    quota.Limiter
+}
+
+func (s *apiGatewayService) Access(user types.Account, model domain.ModelName) (
+	dto ApiRateLimitDTO, code string, err error,
+) {
+	v, err := s.apiService.GetApiByUserModel(user, model)
+	if err != nil {
+		if crepository.IsErrorResourceNotExists(err) {
+			code = ErrorApiNotApplied
+			err = errors.New("the api has not been applied")
+		}
+
+		return
+	}
+
+	if !v.Enabled {
+		code = ErrorApiDisabled
+		err = errors.New("the api is disabled")
+
+		return
+	}
+
+	q := v.EffectiveQuota()
+
+	r, err := s.limiter.Take(
+		user.Account()+":"+model.ModelName(),
+		[]quota.Limit{
+			{Window: quota.WindowMinute, Max: q.RPM},
+			{Window: quota.WindowDay, Max: q.Daily},
+			{Window: quota.WindowMonth, Max: q.Monthly},
+		},
+		time.Now(),
+	)
+	if err != nil {
+		return
+	}
+
+	dto.toApiRateLimitDTO(&r)
+
+	if !r.Allowed {
+		if r.Window == quota.WindowMinute {
+			code = ErrorApiRateLimited
+			err = errors.New("too many requests, please try again later")
+		} else {
+			code = ErrorApiQuotaExceeded
+			err = errors.New("the quota of api is exhausted")
+		}
+	}
+
+	return
+}
+
+func (s *apiGatewayService) Record(cmd *ApiCallRecordCmd) error {
+	u := domain.ApiUsage{
+		User:    cmd.User,
+		Model:   cmd.Model,
+		Date:    utils.ToDate(cmd.At.Unix()),
+		Hour:    cmd.At.Hour(),
+		Status:  cmd.Status,
+		Count:   1,
+		Latency: cmd.Latency.Milliseconds(),
+	}
+
+	if err := s.usage.IncUsage(&u); err != nil {
+		return err
+	}
+
+	if !u.IsSucceeded() {
+		return nil
+	}
+
+	v, err := s.apiService.GetApiByUserModel(cmd.User, cmd.Model)
+	if err != nil {
+		return err
+	}
+
+	return s.apiService.AddApiCallCount(cmd.User, cmd.Model, v.Version)
+}
+
+func (s *apiGatewayService) GetUsage(user types.Account, model domain.ModelName) (
+	dto ApiQuotaUsageDTO, err error,
+) {
+	v, err := s.apiService.GetApiByUserModel(user, model)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	today := utils.Date()
+	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	items, err := s.usage.ListUsage(user, &repository.ApiUsageListOption{
+		Model: model,
+		Start: utils.ToDate(firstDay.Unix()),
+		End:   today,
+	})
+	if err != nil {
+		return
+	}
+
+	q := v.EffectiveQuota()
+
+	dto = ApiQuotaUsageDTO{
+		Model:        model.ModelName(),
+		DailyQuota:   q.Daily,
+		MonthlyQuota: q.Monthly,
+		RPM:          q.RPM,
+	}
+
+	// items are sorted by date
+	for i := range items {
+		item := &items[i]
+		if !item.IsSucceeded() {
+			continue
+		}
+
+		dto.MonthlyUsed += item.Count
+
+		if item.Date == today {
+			dto.DailyUsed += item.Count
+		}
+
+		if n := len(dto.History); n > 0 && dto.History[n-1].Date == item.Date {
+			dto.History[n-1].Count += item.Count
+		} else {
+			dto.History = append(dto.History, ApiDailyUsageDTO{
+				Date:  item.Date,
+				Count: item.Count,
+			})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	crepository "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+const secondsOfDay = 24 * 3600
+
+type ApiKeyService interface {
+	Create(*ApiKeyCreateCmd) (ApiKeyCreatedDTO, string, error)
+	List(types.Account, domain.ModelName) ([]ApiKeyDTO, error)
+	Revoke(user types.Account, model domain.ModelName, id string) (string, error)
+
+	// Authenticate checks the key of model and returns the owner of it
+	Authenticate(key string, model domain.ModelName) (types.Account, string, error)
+}
+
+func NewApiKeyService(apiService repository.ApiService, keys repository.ApiKey) ApiKeyService {
+	return &apiKeyService{
+		apiService: apiService,
+		keys:       keys,
+	}
+}
+
+type apiKeyService struct {
+	apiService repository.ApiService
+	keys       repository.ApiKey
+}
+
+func (s *apiKeyService) Create(cmd *ApiKeyCreateCmd) (dto ApiKeyCreatedDTO, code string, err error) {
+	v, err := s.apiService.GetApiByUserModel(cmd.User, cmd.Model)
+	if err != nil {
+		if crepository.IsErrorResourceNotExists(err) {
+			code = ErrorApiNotApplied
+			err = errors.New("the api has not been applied")
+		}
+
+		return
+	}
+
+	if !v.Enabled {
+		code = ErrorApiDisabled
+		err = errors.New("the api is disabled")
+
+		return
+	}
+
+	now := utils.Now()
+
+	keys, err := s.keys.ListApiKeys(cmd.User, cmd.Model)
+	if err != nil {
+		return
+	}
+
+	n := 0
+	for i := range keys {
+		if keys[i].IsAvailable(now) {
+			n++
+		}
+	}
+
+	if n >= domain.MaxApiKeys() {
+		code = ErrorApiKeyExceeded
+		err = errors.New("exceed the max number of api keys")
+
+		return
+	}
+
+	var expireAt int64
+	if cmd.Expiry > 0 {
+		expireAt = now + int64(cmd.Expiry)*secondsOfDay
+	}
+
+	key, k, err := domain.NewApiKey(cmd.User, cmd.Model, cmd.Name, now, expireAt)
+	if err != nil {
+		return
+	}
+
+	if k.Id, err = s.keys.AddApiKey(&k); err != nil {
+		return
+	}
+
+	dto = ApiKeyCreatedDTO{
+		ApiKeyDTO: toApiKeyDTO(&k, now),
+		Key:       key,
+	}
+
+	return
+}
+
+func (s *apiKeyService) List(user types.Account, model domain.ModelName) ([]ApiKeyDTO, error) {
+	keys, err := s.keys.ListApiKeys(user, model)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.Now()
+
+	r := make([]ApiKeyDTO, len(keys))
+	for i := range keys {
+		r[i] = toApiKeyDTO(&keys[i], now)
+	}
+
+	return r, nil
+}
+
+func (s *apiKeyService) Revoke(user types.Account, model domain.ModelName, id string) (code string, err error) {
+	if err = s.keys.RevokeApiKey(user, model, id, utils.Now()); err != nil {
+		if crepository.IsErrorResourceNotExists(err) {
+			code = ErrorApiKeyNotFound
+		}
+	}
+
+	return
+}
+
+func (s *apiKeyService) Authenticate(key string, model domain.ModelName) (
+	user types.Account, code string, err error,
+) {
+	k, err := s.keys.GetApiKeyByHash(domain.HashApiKey(key))
+	if err != nil {
+		if crepository.IsErrorResourceNotExists(err) {
+			code = ErrorApiKeyInvalid
+			err = errors.New("invalid api key")
+		}
+
+		return
+	}
+
+	if k.Model.ModelName() != model.ModelName() {
+		code = ErrorApiKeyInvalid
+		err = errors.New("the api key does not belong to the model")
+
+		return
+	}
+
+	now := utils.Now()
+
+	if k.IsRevoked() {
+		code = ErrorApiKeyRevoked
+		err = errors.New("the api key has been revoked")
+
+		return
+	}
+
+	if k.IsExpired(now) {
+		code = ErrorApiKeyExpired
+		err = errors.New("the api key has expired")
+
+		return
+	}
+
+	if err := s.keys.UpdateLastUsed(k.Id, now); err != nil {
+		logrus.Errorf("update last used time of api key %s failed, err:%s", k.Id, err.Error())
+	}
+
+	user = k.User
+
+	return
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	maxDaysOfUsageStats = 366
+)
+
+// ApiCallRecordCmd
+type ApiCallRecordCmd struct {
+	User    types.Account
+	Model   domain.ModelName
+	Status  int
+	Latency time.Duration
+	At      time.Time
+}
+
+// ApiUsageStatsCmd
+type ApiUsageStatsCmd struct {
+	User   types.Account
+	Model  domain.ModelName // all the models if it is nil
+	Start  string
+	End    string
+	Hourly bool // aggregate by hour instead of day
+}
+
+func (cmd *ApiUsageStatsCmd) Validate() error {
+	start, err := time.Parse(dateLayout, cmd.Start)
+	if err != nil {
+		return errors.New("invalid start date")
+	}
+
+	end, err := time.Parse(dateLayout, cmd.End)
+	if err != nil {
+		return errors.New("invalid end date")
+	}
+
+	if end.Before(start) {
+		return errors.New("the end date is before the start date")
+	}
+
+	if end.Sub(start) >= maxDaysOfUsageStats*24*time.Hour {
+		return fmt.Errorf("the date range can't exceed %d days", maxDaysOfUsageStats)
+	}
+
+	return nil
+}
+
+func (s *apiGatewayService) ListUsageStats(cmd *ApiUsageStatsCmd) (dto ApiUsageStatsDTO, err error) {
+	items, err := s.usage.ListUsage(cmd.User, &repository.ApiUsageListOption{
+		Model: cmd.Model,
+		Start: cmd.Start,
+		End:   cmd.End,
+	})
+	if err != nil {
+		return
+	}
+
+	type statKey struct {
+		period string
+		model  string
+		status int
+	}
+
+	type stat struct {
+		count   int
+		latency int64
+	}
+
+	stats := map[statKey]*stat{}
+
+	for i := range items {
+		item := &items[i]
+
+		k := statKey{
+			period: item.Date,
+			model:  item.Model.ModelName(),
+			status: item.Status,
+		}
+		if cmd.Hourly {
+			k.period = fmt.Sprintf("%s %02d:00", item.Date, item.Hour)
+		}
+
+		v, ok := stats[k]
+		if !ok {
+			v = new(stat)
+			stats[k] = v
+		}
+
+		v.count += item.Count
+		v.latency += item.Latency
+
+		dto.Total += item.Count
+		if !item.IsSucceeded() {
+			dto.Failed += item.Count
+		}
+	}
+
+	dto.Start = cmd.Start
+	dto.End = cmd.End
+	dto.Items = make([]ApiUsageStatDTO, 0, len(stats))
+
+	for k, v := range stats {
+		item := ApiUsageStatDTO{
+			Period: k.period,
+			Model:  k.model,
+			Status: k.status,
+			Count:  v.count,
+		}
+
+		if v.count > 0 {
+			item.AvgLatency = v.latency / int64(v.count)
+		}
+
+		dto.Items = append(dto.Items, item)
+	}
+
+	sort.Slice(dto.Items, func(i, j int) bool {
+		a, b := &dto.Items[i], &dto.Items[j]
+
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+
+		return a.Status < b.Status
+	})
+
+	return
+}
@@ -0,0 +1,234 @@
+package app
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/message"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	crepository "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+type ArenaService interface {
+	// Battle sends the prompt to the models concurrently and
+	// calls send with the pieces of answers until all the models are done.
+	Battle(cmd *ArenaBattleCmd, send func(*ArenaMessageDTO) error) (string, error)
+	Vote(*ArenaVoteCmd) (string, error)
+	Leaderboard() ([]ArenaRatingDTO, error)
+}
+
+func NewArenaService(
+	fm bigmodel.BigModel,
+	battles repository.ArenaBattle,
+	ratings repository.ArenaRating,
+	sender message.MessageProducer,
+) ArenaService {
+	return &arenaService{
+		fm:      fm,
+		sender:  sender,
+		battles: battles,
+		ratings: ratings,
+	}
+}
+
+type arenaService struct {
+	fm      bigmodel.BigModel
+	sender  message.MessageProducer
+	battles repository.ArenaBattle
+	ratings repository.ArenaRating
+}
+
+func (s *arenaService) Battle(cmd *ArenaBattleCmd, send func(*ArenaMessageDTO) error) (
+	code string, err error,
+) {
+	adapters := make([]bigmodel.Adapter, len(cmd.Models))
+	models := make([]string, len(cmd.Models))
+
+	for i, m := range cmd.Models {
+		a, err1 := s.fm.Adapters().Get(m.ModelName())
+		if err1 != nil || !a.Capabilities().Text {
+			code = ErrorChatUnsupportedModel
+			err = errors.New("the model can't chat")
+
+			return
+		}
+
+		adapters[i] = a
+		models[i] = a.Name()
+	}
+
+	b := domain.ArenaBattle{
+		User:      cmd.User,
+		Prompt:    cmd.Prompt,
+		Models:    models,
+		CreatedAt: utils.Now(),
+	}
+
+	if b.Id, err = s.battles.AddBattle(&b); err != nil {
+		return
+	}
+
+	if err = send(&ArenaMessageDTO{BattleId: b.Id}); err != nil {
+		return
+	}
+
+	out := make(chan ArenaMessageDTO)
+
+	var wg sync.WaitGroup
+	for _, a := range adapters {
+		wg.Add(1)
+
+		go func(a bigmodel.Adapter) {
+			defer wg.Done()
+
+			s.answer(a, cmd, out)
+		}(a)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	// keep reading even if sending failed, so that the answering goroutines can exit.
+	for msg := range out {
+		if err == nil {
+			err = send(&msg)
+		}
+	}
+
+	return
+}
+
+// answer writes the answer of the model to out, the last message is done or error.
+func (s *arenaService) answer(a bigmodel.Adapter, cmd *ArenaBattleCmd, out chan ArenaMessageDTO) {
+	t := domain.BigmodelType(a.Name())
+
+	_ = s.sender.SendBigModelStarted(&domain.BigModelStartedEvent{
+		Account:      cmd.User,
+		BigModelType: t,
+	})
+
+	fail := func(err error) {
+		out <- ArenaMessageDTO{
+			Model: a.Name(),
+			Code:  bigmodelErrorCode(err),
+			Error: err.Error(),
+		}
+	}
+
+	req := bigmodel.AdapterRequest{Text: cmd.Prompt}
+
+	if a.Capabilities().Stream {
+		ch := make(chan string)
+		if err := a.Stream(&req, ch); err != nil {
+			fail(err)
+
+			return
+		}
+
+		for msg := range ch {
+			if msg == bigmodel.StreamDone {
+				break
+			}
+
+			if msg == bigmodel.StreamAuditFailed {
+				fail(bigmodel.ErrorStreamAuditFailed)
+
+				return
+			}
+
+			out <- ArenaMessageDTO{Model: a.Name(), Text: msg}
+		}
+	} else {
+		v, err := a.Generate(&req)
+		if err != nil {
+			fail(err)
+
+			return
+		}
+
+		out <- ArenaMessageDTO{Model: a.Name(), Text: v.Text}
+	}
+
+	out <- ArenaMessageDTO{Model: a.Name(), Done: true}
+
+	_ = s.sender.SendBigModelFinished(&domain.BigModelFinishedEvent{
+		Account:      cmd.User,
+		BigModelType: t,
+	})
+}
+
+func (s *arenaService) Vote(cmd *ArenaVoteCmd) (code string, err error) {
+	b, err := s.battles.GetBattle(cmd.User, cmd.BattleId)
+	if err != nil {
+		if crepository.IsErrorResourceNotExists(err) {
+			code = ErrorArenaBattleNotFound
+		}
+
+		return
+	}
+
+	matches, err := b.Vote(cmd.Winner, utils.Now())
+	if err != nil {
+		code = ErrorArenaInvalidVote
+
+		return
+	}
+
+	if err = s.battles.SaveVote(&b); err != nil {
+		if crepository.IsErrorConcurrentUpdating(err) {
+			code = ErrorArenaVoted
+		}
+
+		return
+	}
+
+	v, err := s.ratings.ListRatings(b.Models)
+	if err != nil {
+		return
+	}
+
+	ratings := make(map[string]domain.ArenaRating, len(v))
+	for i := range v {
+		ratings[v[i].Model] = v[i]
+	}
+
+	changes := domain.EloChanges(ratings, matches, domain.ArenaEloK())
+
+	if err = s.ratings.AddChanges(changes); err != nil {
+		logrus.Errorf("update ratings of battle %s failed, err:%s", b.Id, err.Error())
+	}
+
+	return
+}
+
+func (s *arenaService) Leaderboard() ([]ArenaRatingDTO, error) {
+	v, err := s.ratings.ListRatings(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]ArenaRatingDTO, len(v))
+	for i := range v {
+		item := &v[i]
+
+		r[i] = ArenaRatingDTO{
+			Rank:    i + 1,
+			Model:   item.Model,
+			Rating:  math.Round(item.Rating()),
+			Wins:    item.Wins,
+			Losses:  item.Losses,
+			Ties:    item.Ties,
+			Battles: item.Battles(),
+		}
+	}
+
+	return r, nil
+}
@@ -1,17 +1,33 @@
 package app
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"time"
 
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
 	"github.com/opensourceways/xihe-server/bigmodel/domain/message"
 	types "github.com/opensourceways/xihe-server/domain"
 )
 
+// AsyncBigModelService runs the async tasks, the call to model is
+// interrupted when the context is done, such as the task is cancelled.
 type AsyncBigModelService interface {
-	WuKong(uint64, types.Account, *WuKongCmd) error
+	WuKong(context.Context, uint64, types.Account, *WuKongCmd) error
 	GetIdleEndpoint(bid string) (int, error)
+
+	// text generation
+	GLM2(context.Context, uint64, *GLM2Cmd) error
+	LLAMA2(context.Context, uint64, *LLAMA2Cmd) error
+	BaiChuan(context.Context, uint64, *BaiChuanCmd) error
+	PanGu(context.Context, uint64, types.Account, string) error
+	CodeGeex(context.Context, uint64, types.Account, *CodeGeexCmd) error
+
+	// RecordApiCall records the call of the api of model which is made by the async task
+	RecordApiCall(user types.Account, model string, latency time.Duration, err error) error
 }
 
 func NewAsyncBigModelService(
@@ -29,7 +45,7 @@ type asyncBigModelService struct {
 	sender message.MessageProducer
 }
 
-func (s *asyncBigModelService) WuKong(tid uint64, user types.Account, cmd *WuKongCmd) (err error) {
+func (s *asyncBigModelService) WuKong(ctx context.Context, tid uint64, user types.Account, cmd *WuKongCmd) (err error) {
 	// 1. inference
 	_ = s.sender.SendBigModelStarted(&domain.BigModelStartedEvent{
 		Account:      user,
@@ -37,20 +53,22 @@ func (s *asyncBigModelService) WuKong(tid uint64, user types.Account, cmd *WuKon
 	})
 
 	s.sender.SendWuKongAsyncTaskStart(&domain.WuKongAsyncTaskStartEvent{
-		Account: user,
-		TaskId:  tid,
+		Account:  user,
+		TaskId:   tid,
+		TaskType: cmd.EsType,
 	})
 
-	links, err := s.fm.GenPicturesByWuKong(user, &cmd.WuKongPictureMeta, cmd.EsType)
+	links, err := s.fm.GenPicturesByWuKong(ctx, user, &cmd.WuKongPictureMeta, cmd.EsType)
 	if err != nil {
-		if !bigmodel.IsErrorSensitiveInfo(err) {
+		if !bigmodel.IsErrorSensitiveInfo(err) && !bigmodel.IsErrorInvalidInput(err) {
 			err = errors.New("internal error")
 		}
 
 		s.sender.SendWuKongInferenceError(&domain.WuKongInferenceErrorEvent{
-			Account: user,
-			TaskId:  tid,
-			ErrMsg:  err.Error(),
+			Account:  user,
+			TaskId:   tid,
+			TaskType: cmd.EsType,
+			ErrMsg:   err.Error(),
 		})
 
 		return
@@ -58,12 +76,189 @@ func (s *asyncBigModelService) WuKong(tid uint64, user types.Account, cmd *WuKon
 
 	// 3. send msg
 	return s.sender.SendWuKongAsyncInferenceFinish(&domain.WuKongAsyncInferenceFinishEvent{
-		Account: user,
-		TaskId:  tid,
-		Links:   links,
+		Account:  user,
+		TaskId:   tid,
+		TaskType: cmd.EsType,
+		Links:    links,
 	})
 }
 
 func (s *asyncBigModelService) GetIdleEndpoint(bid string) (c int, err error) {
 	return s.fm.GetIdleEndpoint(bid)
 }
+
+// doTextTask runs the text generation of an async task and reports its result
+func (s *asyncBigModelService) doTextTask(
+	tid uint64, user types.Account, t domain.BigmodelType,
+	f func() (string, error),
+) error {
+	_ = s.sender.SendBigModelStarted(&domain.BigModelStartedEvent{
+		Account:      user,
+		BigModelType: t,
+	})
+
+	// the task type of text generation is the name of model
+	_ = s.sender.SendAsyncTaskStarted(&domain.AsyncTaskStartedEvent{
+		Account:  user,
+		TaskId:   tid,
+		TaskType: string(t),
+	})
+
+	v, err := f()
+	if err != nil {
+		if !bigmodel.IsErrorSensitiveInfo(err) && !bigmodel.IsErrorInvalidInput(err) {
+			err = errors.New("internal error")
+		}
+
+		_ = s.sender.SendAsyncTaskFailed(&domain.AsyncTaskFailedEvent{
+			Account:  user,
+			TaskId:   tid,
+			TaskType: string(t),
+			ErrMsg:   err.Error(),
+		})
+
+		return err
+	}
+
+	_ = s.sender.SendBigModelFinished(&domain.BigModelFinishedEvent{
+		Account:      user,
+		BigModelType: t,
+	})
+
+	return s.sender.SendAsyncTaskFinished(&domain.AsyncTaskFinishedEvent{
+		Account:  user,
+		TaskId:   tid,
+		TaskType: string(t),
+		Result:   asyncdomain.TextResult{Content: v}.MetaData(),
+	})
+}
+
+// receiveStream waits for the end of a stream,
+// the reply of stream is cumulative, so the last one is the whole answer.
+// It returns error if the output fails the audit.
+func receiveStream(ch chan string) (string, error) {
+	r := ""
+
+	for msg := range ch {
+		if msg == bigmodel.StreamDone {
+			break
+		}
+
+		if msg == bigmodel.StreamAuditFailed {
+			return "", bigmodel.ErrorStreamAuditFailed
+		}
+
+		if msg != "" {
+			r = msg
+		}
+	}
+
+	return r, nil
+}
+
+// receiveStreamContext returns the error of context if the stream is interrupted by it,
+// otherwise the part of answer would be taken as the whole one.
+func receiveStreamContext(ctx context.Context, ch chan string) (string, error) {
+	v, err := receiveStream(ch)
+	if err != nil {
+		return "", err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return v, nil
+}
+
+func (s *asyncBigModelService) GLM2(ctx context.Context, tid uint64, cmd *GLM2Cmd) error {
+	return s.doTextTask(tid, cmd.User, domain.BigmodelGLM2, func() (string, error) {
+		ch := make(chan string)
+
+		err := s.fm.GLM2(ctx, ch, &domain.GLM2Input{
+			Text:              cmd.Text,
+			Sampling:          cmd.Sampling,
+			History:           cmd.History,
+			TopK:              cmd.TopK,
+			TopP:              cmd.TopP,
+			Temperature:       cmd.Temperature,
+			RepetitionPenalty: cmd.RepetitionPenalty,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return receiveStreamContext(ctx, ch)
+	})
+}
+
+func (s *asyncBigModelService) LLAMA2(ctx context.Context, tid uint64, cmd *LLAMA2Cmd) error {
+	return s.doTextTask(tid, cmd.User, domain.BigmodelLLAMA2, func() (string, error) {
+		ch := make(chan string)
+
+		err := s.fm.LLAMA2(ctx, ch, &domain.LLAMA2Input{
+			Text:              cmd.Text,
+			Sampling:          cmd.Sampling,
+			History:           cmd.History,
+			TopK:              cmd.TopK,
+			TopP:              cmd.TopP,
+			Temperature:       cmd.Temperature,
+			RepetitionPenalty: cmd.RepetitionPenalty,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return receiveStreamContext(ctx, ch)
+	})
+}
+
+func (s *asyncBigModelService) BaiChuan(ctx context.Context, tid uint64, cmd *BaiChuanCmd) error {
+	return s.doTextTask(tid, cmd.User, domain.BigmodelBaiChuan, func() (string, error) {
+		_, v, err := s.fm.BaiChuan(ctx, &domain.BaiChuanInput{
+			Text:              cmd.Text,
+			Sampling:          cmd.Sampling,
+			TopK:              cmd.TopK,
+			TopP:              cmd.TopP,
+			Temperature:       cmd.Temperature,
+			RepetitionPenalty: cmd.RepetitionPenalty,
+		})
+
+		return v, err
+	})
+}
+
+func (s *asyncBigModelService) PanGu(ctx context.Context, tid uint64, user types.Account, q string) error {
+	return s.doTextTask(tid, user, domain.BigmodelPanGu, func() (string, error) {
+		return s.fm.PanGu(ctx, q)
+	})
+}
+
+func (s *asyncBigModelService) CodeGeex(ctx context.Context, tid uint64, user types.Account, cmd *CodeGeexCmd) error {
+	return s.doTextTask(tid, user, domain.BigmodelCodeGeex, func() (string, error) {
+		v, err := s.fm.CodeGeex(ctx, (*bigmodel.CodeGeexReq)(cmd))
+
+		return v.Result, err
+	})
+}
+
+func (s *asyncBigModelService) RecordApiCall(
+	user types.Account, model string, latency time.Duration, err error,
+) error {
+	status := http.StatusOK
+	if err != nil {
+		if bigmodel.IsErrorSensitiveInfo(err) || bigmodel.IsErrorInvalidInput(err) {
+			status = http.StatusBadRequest
+		} else {
+			status = http.StatusInternalServerError
+		}
+	}
+
+	return s.sender.SendApiCalled(&domain.ApiCalledEvent{
+		Account: user,
+		Model:   model,
+		Status:  status,
+		Latency: latency.Milliseconds(),
+		At:      time.Now().Add(-latency).Unix(),
+	})
+}
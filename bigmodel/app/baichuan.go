@@ -1,10 +1,26 @@
 package app
 
 import (
+	"context"
+
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
 )
 
 func (s bigModelService) BaiChuan(cmd *BaiChuanCmd) (code string, dto BaiChuanDTO, err error) {
+	var (
+		session domain.ChatSession
+		history []domain.History
+	)
+
+	if cmd.Session != "" {
+		session, history, code, err = s.chatSessionContext(
+			cmd.User, cmd.Session, domain.BigmodelBaiChuan, cmd.Text.BaiChuanText(),
+		)
+		if err != nil {
+			return
+		}
+	}
+
 	_ = s.sender.SendBigModelStarted(&domain.BigModelStartedEvent{
 		Account:      cmd.User,
 		BigModelType: domain.BigmodelBaiChuan,
@@ -12,16 +28,21 @@ func (s bigModelService) BaiChuan(cmd *BaiChuanCmd) (code string, dto BaiChuanDT
 
 	input := &domain.BaiChuanInput{
 		Text:              cmd.Text,
+		History:           history,
 		TopK:              cmd.TopK,
 		TopP:              cmd.TopP,
 		Temperature:       cmd.Temperature,
 		RepetitionPenalty: cmd.RepetitionPenalty,
 	}
 
-	if code, dto.Text, err = s.fm.BaiChuan(input); err != nil {
+	if code, dto.Text, err = s.fm.BaiChuan(context.Background(), input); err != nil {
 		return
 	}
 
+	if cmd.Session != "" {
+		s.saveChatRound(&session, cmd.Text.BaiChuanText(), dto.Text)
+	}
+
 	_ = s.sender.SendBigModelFinished(&domain.BigModelFinishedEvent{
 		Account:      cmd.User,
 		BigModelType: domain.BigmodelBaiChuan,
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"errors"
+
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
+	asyncrepo "github.com/opensourceways/xihe-server/async-server/domain/repository"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/async"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	commonrepo "github.com/opensourceways/xihe-server/common/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	crepository "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// BatchService runs the inputs of a JSONL file by the async server.
+// Each item of batch is a call of the api of model, so the api must be applied
+// and the quota left must be enough for all the items.
+type BatchService interface {
+	Create(*BatchCreateCmd) (BatchDTO, string, error)
+	List(types.Account) ([]BatchDTO, error)
+	Get(types.Account, uint64) (BatchDTO, string, error)
+	Cancel(types.Account, uint64) (string, error)
+
+	// Output returns the results of items in the order of the input
+	Output(*BatchOutputCmd) ([]BatchOutputDTO, string, error)
+}
+
+func NewBatchService(
+	batches async.AsyncBatch,
+	apiService repository.ApiService,
+	gateway ApiGatewayService,
+) BatchService {
+	return &batchService{
+		batches:    batches,
+		apiService: apiService,
+		gateway:    gateway,
+	}
+}
+
+type batchService struct {
+	batches    async.AsyncBatch
+	apiService repository.ApiService
+	gateway    ApiGatewayService
+}
+
+func (s *batchService) Create(cmd *BatchCreateCmd) (dto BatchDTO, code string, err error) {
+	items, err := asyncdomain.ParseBatchInput(cmd.TaskType, cmd.Input, domain.MaxBatchItems())
+	if err != nil {
+		code = ErrorBatchInvalidInput
+
+		return
+	}
+
+	if code, err = s.checkQuota(cmd.User, cmd.TaskType.TaskType(), len(items)); err != nil {
+		return
+	}
+
+	b := asyncdomain.Batch{
+		User:      cmd.User,
+		TaskType:  cmd.TaskType,
+		Status:    asyncdomain.TaskStatusWaiting,
+		Total:     len(items),
+		CreatedAt: utils.Now(),
+	}
+
+	if b.Id, err = s.batches.CreateBatch(&b, items); err != nil {
+		return
+	}
+
+	dto.toBatchDTO(&asyncrepo.BatchResp{
+		Batch:    b,
+		Progress: asyncdomain.BatchProgress{Waiting: b.Total},
+	})
+
+	return
+}
+
+// checkQuota checks whether the quota left is enough for the items,
+// the pending items of the other batches are counted in as used.
+func (s *batchService) checkQuota(user types.Account, model string, n int) (
+	code string, err error,
+) {
+	name, err := domain.NewModelName(model)
+	if err != nil {
+		return
+	}
+
+	v, err := s.apiService.GetApiByUserModel(user, name)
+	if err != nil {
+		if crepository.IsErrorResourceNotExists(err) {
+			code = ErrorApiNotApplied
+			err = errors.New("the api has not been applied")
+		}
+
+		return
+	}
+
+	if !v.Enabled {
+		code = ErrorApiDisabled
+		err = errors.New("the api is disabled")
+
+		return
+	}
+
+	usage, err := s.gateway.GetUsage(user, name)
+	if err != nil {
+		return
+	}
+
+	pending, err := s.batches.CountPendingItems(user, model)
+	if err != nil {
+		return
+	}
+
+	left := func(quota, used int) bool {
+		// 0 means unlimited
+		return quota <= 0 || quota-used-pending >= n
+	}
+
+	if !left(usage.DailyQuota, usage.DailyUsed) || !left(usage.MonthlyQuota, usage.MonthlyUsed) {
+		code = ErrorApiQuotaExceeded
+		err = errors.New("the quota of api is not enough for the batch")
+	}
+
+	return
+}
+
+func (s *batchService) List(user types.Account) ([]BatchDTO, error) {
+	v, err := s.batches.ListBatches(user, domain.MaxListedBatches())
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]BatchDTO, len(v))
+	for i := range v {
+		r[i].toBatchDTO(&v[i])
+	}
+
+	return r, nil
+}
+
+func (s *batchService) Get(user types.Account, id uint64) (dto BatchDTO, code string, err error) {
+	v, err := s.batches.GetBatch(user, id)
+	if err != nil {
+		code = batchErrorCode(err)
+
+		return
+	}
+
+	dto.toBatchDTO(&v)
+
+	return
+}
+
+func (s *batchService) Cancel(user types.Account, id uint64) (code string, err error) {
+	if err = s.batches.CancelBatch(user, id); err != nil {
+		code = batchErrorCode(err)
+	}
+
+	return
+}
+
+func (s *batchService) Output(cmd *BatchOutputCmd) (
+	dtos []BatchOutputDTO, code string, err error,
+) {
+	items, err := s.batches.ListBatchItems(cmd.User, cmd.BatchId)
+	if err != nil {
+		code = batchErrorCode(err)
+
+		return
+	}
+
+	dtos = make([]BatchOutputDTO, 0, len(items))
+	for i := range items {
+		if cmd.FailedOnly && !items[i].Status.IsDead() {
+			continue
+		}
+
+		dto := BatchOutputDTO{}
+		dto.toBatchOutputDTO(&items[i])
+
+		dtos = append(dtos, dto)
+	}
+
+	return
+}
+
+func batchErrorCode(err error) string {
+	if commonrepo.IsErrorResourceNotExists(err) {
+		return ErrorBatchNotFound
+	}
+
+	return ""
+}
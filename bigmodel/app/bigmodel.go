@@ -1,12 +1,14 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/url"
 	"strings"
 	"time"
 
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/domain/async"
 	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
@@ -47,10 +49,12 @@ type BigModelService interface {
 	// wukong
 	GenWuKongSamples(int) ([]string, error)
 	WuKong(types.Account, *WuKongCmd) (map[string]string, string, error)
+	WuKongUploadPicture(io.Reader, types.Account, string) (string, string, error)
 	WuKongHF(*WuKongHFCmd) (map[string]string, string, error)
 	WuKongInferenceAsync(types.Account, *WuKongCmd) (string, error)
 	GetWuKongWaitingTaskRank(types.Account) (WuKongRankDTO, error)
 	GetWuKongLastTaskResp(types.Account) ([]wukongPictureDTO, string, error)
+	CancelWuKongTask(types.Account, uint64) (string, error)
 	AddLikeFromTempPicture(*WuKongAddLikeFromTempCmd) (string, string, error)
 	AddLikeFromPublicPicture(*WuKongAddLikeFromPublicCmd) (string, string, error)
 	AddPublicFromTempPicture(*WuKongAddPublicFromTempCmd) (string, string, error)
@@ -65,7 +69,7 @@ type BigModelService interface {
 	ReGenerateDownloadURL(types.Account, string) (string, string, error)
 
 	//api service
-	ApplyApi(types.Account, domain.ModelName, string) error
+	ApplyApi(types.Account, domain.ModelName) error
 	WukongApi(types.Account, domain.ModelName, *WuKongApiCmd) (map[string]string, string, error)
 	GetApplyRecordByModel(types.Account, domain.ModelName) (ApiApplyRecordDTO, error)
 	GetApplyRecordByUser(types.Account) ([]ApiApplyRecordDTO, error)
@@ -85,6 +89,13 @@ type BigModelService interface {
 
 	// llama2
 	LLAMA2(*LLAMA2Cmd) (string, error)
+
+	// chat completion of glm2, llama2 and baichuan
+	ChatCompletion(*ChatCompletionCmd) (string, error)
+
+	// async task
+	TextInferenceAsync(*AsyncTextCmd) (string, error)
+	GetLastAsyncTask(types.Account, asyncdomain.TaskType) (AsyncTaskDTO, string, error)
 }
 
 func NewBigModelService(
@@ -98,6 +109,7 @@ func NewBigModelService(
 	apiService repository.ApiService,
 	apiInfo repository.ApiInfo,
 	userService userapp.RegService,
+	sessions repository.ChatSession,
 ) BigModelService {
 	return bigModelService{
 		fm:              fm,
@@ -112,9 +124,19 @@ func NewBigModelService(
 		apiService:      apiService,
 		apiInfo:         apiInfo,
 		userService:     userService,
+		sessions:        sessions,
 	}
 }
 
+var wukongTaskTypes = []string{"wukong", "wukong_4img"}
+
+// waitingTaskSince returns the time after which the waiting tasks are counted in the rank
+func waitingTaskSince() commondomain.Time {
+	t, _ := commondomain.NewTime(time.Now().Add(-300 * time.Second).Unix()) // TODO config
+
+	return t
+}
+
 type bigModelService struct {
 	fm bigmodel.BigModel
 
@@ -127,6 +149,7 @@ type bigModelService struct {
 	apiService    repository.ApiService
 	apiInfo       repository.ApiInfo
 	userService   userapp.RegService
+	sessions      repository.ChatSession
 
 	bigmodelService service.BigModelService
 
@@ -134,6 +157,10 @@ type bigModelService struct {
 }
 
 func (s bigModelService) setCode(err error) string {
+	return bigmodelErrorCode(err)
+}
+
+func bigmodelErrorCode(err error) string {
 	if err != nil && bigmodel.IsErrorSensitiveInfo(err) {
 		return ErrorBigModelSensitiveInfo
 	}
@@ -142,6 +169,10 @@ func (s bigModelService) setCode(err error) string {
 		return ErrorBigModelRecourseBusy
 	}
 
+	if err != nil && bigmodel.IsErrorInvalidInput(err) {
+		return ErrorBigModelInvalidInput
+	}
+
 	return ""
 }
 
@@ -162,7 +193,7 @@ func (s bigModelService) WuKong(
 		BigModelType: domain.BigmodelWuKong,
 	})
 
-	links, err = s.fm.GenPicturesByWuKong(user, &cmd.WuKongPictureMeta, cmd.EsType)
+	links, err = s.fm.GenPicturesByWuKong(context.Background(), user, &cmd.WuKongPictureMeta, cmd.EsType)
 	if err != nil {
 		code = s.setCode(err)
 	}
@@ -170,6 +201,23 @@ func (s bigModelService) WuKong(
 	return
 }
 
+// WuKongUploadPicture uploads the source or mask picture of img2img and inpainting,
+// and returns the path of it which is used by the later request.
+func (s bigModelService) WuKongUploadPicture(f io.Reader, user types.Account, name string) (
+	path string, code string, err error,
+) {
+	v, err := s.fm.WuKongUploadPicture(f, user, name)
+	if err != nil {
+		code = s.setCode(err)
+
+		return
+	}
+
+	path = v.OBSPath()
+
+	return
+}
+
 func (s bigModelService) WuKongHF(cmd *WuKongHFCmd) (
 	links map[string]string, code string, err error,
 ) {
@@ -178,7 +226,7 @@ func (s bigModelService) WuKongHF(cmd *WuKongHFCmd) (
 		BigModelType: domain.BigmodelWuKongHF,
 	})
 
-	links, err = s.fm.GenPicturesByWuKong(cmd.User, &cmd.WuKongPictureMeta, string(domain.BigmodelWuKongHF))
+	links, err = s.fm.GenPicturesByWuKong(context.Background(), cmd.User, &cmd.WuKongPictureMeta, string(domain.BigmodelWuKongHF))
 	if err != nil {
 		code = s.setCode(err)
 	}
@@ -194,52 +242,55 @@ func (s bigModelService) WukongApi(
 		BigModelType: domain.BigmodelWuKong,
 	})
 
-	links, err = s.fm.GenPicturesByWuKong(user, &cmd.WuKongPictureMeta, string(domain.BigmodelWuKongUser))
+	links, err = s.fm.GenPicturesByWuKong(context.Background(), user, &cmd.WuKongPictureMeta, string(domain.BigmodelWuKongUser))
 	if err != nil {
 		code = s.setCode(err)
 	}
 
-	a, _ := s.apiService.GetApiByUserModel(user, model)
-	err = s.apiService.AddApiCallCount(user, model, a.Version)
-
 	return
 }
 
 func (s bigModelService) WuKongInferenceAsync(user types.Account, cmd *WuKongCmd) (code string, err error) {
 	// content audit
-	if err = s.fm.CheckText(cmd.Desc.WuKongPictureDesc()); err != nil {
+	if err = s.fm.CheckText(string(domain.BigmodelWuKong), cmd.Desc.WuKongPictureDesc()); err != nil {
 		code = ErrorBigModelSensitiveInfo
 
 		return
 	}
 
 	return "", s.sender.SendWuKongInferenceStart(&domain.WuKongInferenceStartEvent{
-		Account: user,
-		Desc:    cmd.Desc,
-		Style:   cmd.Style,
-		EsStyle: cmd.EsType,
+		Account:  user,
+		Desc:     cmd.Desc,
+		Style:    cmd.Style,
+		Input:    cmd.WuKongPictureInput,
+		EsStyle:  cmd.EsType,
+		Priority: s.taskPriority(user, string(domain.BigmodelWuKong)),
 	})
 }
 
 func (s bigModelService) GetWuKongWaitingTaskRank(user types.Account) (dto WuKongRankDTO, err error) {
-	t, _ := commondomain.NewTime(time.Now().Add(-300 * time.Second).Unix()) // TODO config
-
 	var rank int
-	if rank, err = s.asynccli.GetWaitingTaskRank(user, t, []string{"wukong", "wukong_4img"}); err != nil {
-		if !commonrepo.IsErrorResourceNotExists(err) {
-			return
-		}
+	if rank, err = waitingTaskRank(s.asynccli, user, wukongTaskTypes); err != nil {
+		return
 	}
 
 	dto = WuKongRankDTO{
 		Rank: rank,
 	}
 
+	// the id of task is used to cancel it
+	if rank > 0 {
+		if t, err := s.asynccli.GetLastTask(user, wukongTaskTypes); err == nil &&
+			(t.Status.IsWaiting() || t.Status.IsRunning()) {
+			dto.TaskId = t.Id
+		}
+	}
+
 	return
 }
 
 func (s bigModelService) GetWuKongLastTaskResp(user types.Account) (dtos []wukongPictureDTO, code string, err error) {
-	p, err := s.asynccli.GetLastFinishedTask(user, []string{"wukong", "wukong_4img"})
+	p, err := s.asynccli.GetLastFinishedTask(user, wukongTaskTypes)
 	if err != nil {
 		if commonrepo.IsErrorResourceNotExists(err) {
 			code = ErrorWuKongNoPicture
@@ -268,6 +319,13 @@ func (s bigModelService) GetWuKongLastTaskResp(user types.Account) (dtos []wukon
 		return
 	}
 
+	if p.Status.IsCancelled() {
+		code = ErrorWuKongTaskCancelled
+		err = errors.New("the last task has been cancelled")
+
+		return
+	}
+
 	dtos = make([]wukongPictureDTO, len(p.Links.Links()))
 	for i := range p.Links.Links() {
 		opt, err := s.bigmodelService.LinkLikePublic(p.Links.Links()[i], user)
@@ -287,6 +345,16 @@ func (s bigModelService) GetWuKongLastTaskResp(user types.Account) (dtos []wukon
 	return
 }
 
+func (s bigModelService) CancelWuKongTask(user types.Account, id uint64) (code string, err error) {
+	if err = s.asynccli.CancelTask(user, id, wukongTaskTypes); err != nil {
+		if commonrepo.IsErrorResourceNotExists(err) {
+			code = ErrorAsyncTaskNotFound
+		}
+	}
+
+	return
+}
+
 func (s bigModelService) AddLikeFromTempPicture(cmd *WuKongAddLikeFromTempCmd) (
 	pid string, code string, err error,
 ) {
@@ -752,14 +820,13 @@ func (s bigModelService) ReGenerateDownloadURL(
 	return
 }
 
-func (s bigModelService) ApplyApi(user types.Account, model domain.ModelName, token string) (err error) {
+func (s bigModelService) ApplyApi(user types.Account, model domain.ModelName) (err error) {
 	now := utils.Now()
 	date := utils.ToDate(now)
 
 	a := domain.UserApiRecord{
 		User:      user,
 		ModelName: model,
-		Token:     token,
 		ApplyAt:   date,
 		UpdateAt:  date,
 		Enabled:   true,
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+const (
+	ChatRoleSystem    = "system"
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+)
+
+type ChatMessage struct {
+	Role    string
+	Content string
+}
+
+// ChatCompletionCmd is the conversation with the chat model.
+// The sampling parameters which are nil will be the default values of the model.
+type ChatCompletionCmd struct {
+	CH                chan string
+	User              types.Account
+	Model             domain.ModelName
+	Messages          []ChatMessage
+	TopK              domain.TopK
+	TopP              domain.TopP
+	Temperature       domain.Temperature
+	RepetitionPenalty domain.RepetitionPenalty
+}
+
+func (cmd *ChatCompletionCmd) Validate() error {
+	if cmd.Model == nil {
+		return errors.New("missing model")
+	}
+
+	if len(cmd.Messages) == 0 {
+		return errors.New("no messages")
+	}
+
+	for i := range cmd.Messages {
+		switch cmd.Messages[i].Role {
+		case ChatRoleSystem, ChatRoleUser, ChatRoleAssistant:
+		default:
+			return errors.New("invalid role of message")
+		}
+	}
+
+	if cmd.Messages[len(cmd.Messages)-1].Role != ChatRoleUser {
+		return errors.New("the last message must be from user")
+	}
+
+	return nil
+}
+
+func (cmd *ChatCompletionCmd) sampling() bool {
+	return cmd.TopK != nil || cmd.TopP != nil || cmd.Temperature != nil || cmd.RepetitionPenalty != nil
+}
+
+// prompt converts the messages to the current question and the history of conversation.
+// The system messages are put in front of the next question.
+func (cmd *ChatCompletionCmd) prompt() (text string, history [][2]string) {
+	var (
+		prefix   []string
+		question []string
+	)
+
+	for i := range cmd.Messages {
+		m := &cmd.Messages[i]
+
+		switch m.Role {
+		case ChatRoleSystem:
+			prefix = append(prefix, m.Content)
+
+		case ChatRoleUser:
+			question = append(question, m.Content)
+
+		case ChatRoleAssistant:
+			q := strings.Join(append(prefix, question...), "\n")
+			history = append(history, [2]string{q, m.Content})
+
+			prefix, question = nil, nil
+		}
+	}
+
+	text = strings.Join(append(prefix, question...), "\n")
+
+	return
+}
+
+func (s bigModelService) ChatCompletion(cmd *ChatCompletionCmd) (code string, err error) {
+	a, err := s.fm.Adapters().Get(cmd.Model.ModelName())
+	if err != nil || !a.Capabilities().Text {
+		code = ErrorChatUnsupportedModel
+		err = errors.New("the model can't chat")
+
+		return
+	}
+
+	text, history := cmd.prompt()
+
+	req := bigmodel.AdapterRequest{
+		Text:    text,
+		History: history,
+	}
+
+	if cmd.sampling() {
+		req.Sampling = &bigmodel.Sampling{
+			TopK:              cmd.TopK,
+			TopP:              cmd.TopP,
+			Temperature:       cmd.Temperature,
+			RepetitionPenalty: cmd.RepetitionPenalty,
+		}
+	}
+
+	t := domain.BigmodelType(a.Name())
+
+	_ = s.sender.SendBigModelStarted(&domain.BigModelStartedEvent{
+		Account:      cmd.User,
+		BigModelType: t,
+	})
+
+	finished := func() {
+		_ = s.sender.SendBigModelFinished(&domain.BigModelFinishedEvent{
+			Account:      cmd.User,
+			BigModelType: t,
+		})
+	}
+
+	if !a.Capabilities().Stream {
+		var v bigmodel.AdapterResponse
+		if v, err = a.Generate(&req); err != nil {
+			code = s.setCode(err)
+
+			return
+		}
+
+		finished()
+
+		go func() {
+			cmd.CH <- v.Text
+			cmd.CH <- bigmodel.StreamDone
+		}()
+
+		return
+	}
+
+	ch := make(chan string)
+	if err = a.Stream(&req, ch); err != nil {
+		code = s.setCode(err)
+
+		return
+	}
+
+	go forwardStream(ch, cmd.CH, finished)
+
+	return
+}
+
+// forwardStream forwards the stream from in to out and calls finished
+// when the stream ends without failing the audit.
+func forwardStream(in, out chan string, finished func()) {
+	for msg := range in {
+		out <- msg
+
+		if msg == bigmodel.StreamAuditFailed {
+			return
+		}
+
+		if msg == bigmodel.StreamDone {
+			finished()
+
+			return
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	crepository "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+type ChatSessionService interface {
+	Create(*ChatSessionCreateCmd) (ChatSessionDTO, string, error)
+	List(types.Account) ([]ChatSessionDTO, error)
+	Get(user types.Account, id string) (ChatSessionDetailDTO, string, error)
+	Rename(user types.Account, id string, title domain.ChatSessionTitle) (string, error)
+	Delete(user types.Account, id string) (string, error)
+}
+
+func NewChatSessionService(fm bigmodel.BigModel, sessions repository.ChatSession) ChatSessionService {
+	return &chatSessionService{
+		fm:       fm,
+		sessions: sessions,
+	}
+}
+
+type chatSessionService struct {
+	fm       bigmodel.BigModel
+	sessions repository.ChatSession
+}
+
+func (s *chatSessionService) Create(cmd *ChatSessionCreateCmd) (
+	dto ChatSessionDTO, code string, err error,
+) {
+	a, err := s.fm.Adapters().Get(cmd.Model.ModelName())
+	if err != nil || !a.Capabilities().Text {
+		code = ErrorChatUnsupportedModel
+		err = errors.New("the model can't chat")
+
+		return
+	}
+
+	n, err := s.sessions.CountSessions(cmd.User)
+	if err != nil {
+		return
+	}
+
+	if n >= domain.MaxChatSessions() {
+		code = ErrorChatSessionExceeded
+		err = errors.New("exceed the max number of chat sessions")
+
+		return
+	}
+
+	now := utils.Now()
+
+	v := domain.ChatSession{
+		Owner:     cmd.User,
+		Model:     cmd.Model,
+		Title:     cmd.Title,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if v.Id, err = s.sessions.AddSession(&v); err != nil {
+		return
+	}
+
+	dto = toChatSessionDTO(&v)
+
+	return
+}
+
+func (s *chatSessionService) List(user types.Account) ([]ChatSessionDTO, error) {
+	v, err := s.sessions.ListSessions(user)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]ChatSessionDTO, len(v))
+	for i := range v {
+		r[i] = toChatSessionDTO(&v[i])
+	}
+
+	return r, nil
+}
+
+func (s *chatSessionService) Get(user types.Account, id string) (
+	dto ChatSessionDetailDTO, code string, err error,
+) {
+	v, err := s.sessions.GetSession(user, id)
+	if err != nil {
+		code = chatSessionErrorCode(err)
+
+		return
+	}
+
+	dto.ChatSessionDTO = toChatSessionDTO(&v)
+	dto.Rounds = make([]ChatRoundDTO, len(v.Rounds))
+
+	for i := range v.Rounds {
+		item := &v.Rounds[i]
+
+		dto.Rounds[i] = ChatRoundDTO{
+			Question:  item.Question,
+			Answer:    item.Answer,
+			CreatedAt: item.CreatedAt,
+		}
+	}
+
+	return
+}
+
+func (s *chatSessionService) Rename(user types.Account, id string, title domain.ChatSessionTitle) (
+	code string, err error,
+) {
+	if err = s.sessions.RenameSession(user, id, title, utils.Now()); err != nil {
+		code = chatSessionErrorCode(err)
+	}
+
+	return
+}
+
+func (s *chatSessionService) Delete(user types.Account, id string) (code string, err error) {
+	if err = s.sessions.DeleteSession(user, id); err != nil {
+		code = chatSessionErrorCode(err)
+	}
+
+	return
+}
+
+func chatSessionErrorCode(err error) string {
+	if crepository.IsErrorResourceNotExists(err) {
+		return ErrorChatSessionNotFound
+	}
+
+	return ""
+}
+
+// chatSessionContext loads the session of the model and returns the history
+// which fits the context window of model together with the text.
+func (s bigModelService) chatSessionContext(
+	user types.Account, id string, model domain.BigmodelType, text string,
+) (session domain.ChatSession, history []domain.History, code string, err error) {
+	if session, err = s.sessions.GetSession(user, id); err != nil {
+		code = chatSessionErrorCode(err)
+
+		return
+	}
+
+	if !session.IsModel(string(model)) {
+		code = ErrorChatSessionUnmatchedModel
+		err = errors.New("the chat session does not belong to the model")
+
+		return
+	}
+
+	history = session.History(domain.MaxChatContextLength() - utils.StrLen(text))
+
+	return
+}
+
+func (s bigModelService) saveChatRound(session *domain.ChatSession, question, answer string) {
+	if answer == "" {
+		return
+	}
+
+	r := domain.ChatRound{
+		Question:  question,
+		Answer:    answer,
+		CreatedAt: utils.Now(),
+	}
+
+	err := s.sessions.AddRound(session.Owner, session.Id, &r, domain.MaxChatRounds())
+	if err != nil {
+		logrus.Errorf("save round of chat session %s failed, err:%s", session.Id, err.Error())
+	}
+}
+
+// forwardChatStream forwards the stream of model from in to out
+// and saves the answer to the session when the stream is done.
+// The reply of stream is cumulative, so the last one is the whole answer.
+// Nothing is saved if the answer fails the audit.
+func (s bigModelService) forwardChatStream(
+	session *domain.ChatSession, question string, in, out chan string,
+) {
+	answer := ""
+
+	for msg := range in {
+		out <- msg
+
+		if msg == bigmodel.StreamDone {
+			break
+		}
+
+		if msg == bigmodel.StreamAuditFailed {
+			return
+		}
+
+		if msg != "" {
+			answer = msg
+		}
+	}
+
+	s.saveChatRound(session, question, answer)
+}
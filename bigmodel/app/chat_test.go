@@ -0,0 +1,73 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChatCompletionCmdPrompt(t *testing.T) {
+	sys := func(s string) ChatMessage { return ChatMessage{Role: ChatRoleSystem, Content: s} }
+	user := func(s string) ChatMessage { return ChatMessage{Role: ChatRoleUser, Content: s} }
+	bot := func(s string) ChatMessage { return ChatMessage{Role: ChatRoleAssistant, Content: s} }
+
+	cases := []struct {
+		name     string
+		messages []ChatMessage
+		text     string
+		history  [][2]string
+	}{
+		{
+			name:     "single question",
+			messages: []ChatMessage{user("q")},
+			text:     "q",
+		},
+		{
+			name:     "system in front of question",
+			messages: []ChatMessage{sys("s"), user("q")},
+			text:     "s\nq",
+		},
+		{
+			name:     "history",
+			messages: []ChatMessage{user("q1"), bot("a1"), user("q2"), bot("a2"), user("q3")},
+			text:     "q3",
+			history:  [][2]string{{"q1", "a1"}, {"q2", "a2"}},
+		},
+		{
+			name:     "system only in front of the next question",
+			messages: []ChatMessage{sys("s"), user("q1"), bot("a1"), user("q2")},
+			text:     "q2",
+			history:  [][2]string{{"s\nq1", "a1"}},
+		},
+		{
+			name:     "system in the middle",
+			messages: []ChatMessage{user("q1"), bot("a1"), sys("s"), user("q2")},
+			text:     "s\nq2",
+			history:  [][2]string{{"q1", "a1"}},
+		},
+		{
+			name:     "consecutive questions are folded",
+			messages: []ChatMessage{user("q1"), user("q2"), bot("a"), user("q3"), user("q4")},
+			text:     "q3\nq4",
+			history:  [][2]string{{"q1\nq2", "a"}},
+		},
+		{
+			name:     "answer without question",
+			messages: []ChatMessage{bot("a0"), user("q")},
+			text:     "q",
+			history:  [][2]string{{"", "a0"}},
+		},
+	}
+
+	for _, c := range cases {
+		cmd := ChatCompletionCmd{Messages: c.messages}
+
+		text, history := cmd.prompt()
+		if text != c.text {
+			t.Errorf("%s: expect text %q, got %q", c.name, c.text, text)
+		}
+
+		if !reflect.DeepEqual(history, c.history) {
+			t.Errorf("%s: expect history %q, got %q", c.name, c.history, history)
+		}
+	}
+}
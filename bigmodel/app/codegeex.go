@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
 	types "github.com/opensourceways/xihe-server/domain"
@@ -14,7 +16,7 @@ func (s bigModelService) CodeGeex(user types.Account, cmd *CodeGeexCmd) (
 		BigModelType: domain.BigmodelCodeGeex,
 	})
 
-	if dto, err = s.fm.CodeGeex((*bigmodel.CodeGeexReq)(cmd)); err != nil {
+	if dto, err = s.fm.CodeGeex(context.Background(), (*bigmodel.CodeGeexReq)(cmd)); err != nil {
 		code = s.setCode(err)
 
 		return
@@ -4,10 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
+	abusedomain "github.com/opensourceways/xihe-server/abuse/domain"
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
+	asyncrepo "github.com/opensourceways/xihe-server/async-server/domain/repository"
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	bigmodeldomain "github.com/opensourceways/xihe-server/bigmodel/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/quota"
 	types "github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/repository"
 	userdomain "github.com/opensourceways/xihe-server/user/domain"
@@ -111,7 +116,7 @@ func (cmd *WuKongCmd) Validate() error {
 		return fmt.Errorf("style should less than %d", max)
 	}
 
-	return nil
+	return cmd.CheckMode()
 }
 
 type WuKongHFCmd struct {
@@ -249,7 +254,8 @@ type wukongPictureDTO struct {
 }
 
 type WuKongRankDTO struct {
-	Rank int `json:"rank"`
+	Rank   int    `json:"rank"`
+	TaskId uint64 `json:"task_id,omitempty"`
 }
 
 type AIDetectorCmd struct {
@@ -329,6 +335,8 @@ func (s bigModelService) toApiInfoDTO(
 
 // baichuan
 type BaiChuanCmd struct {
+	// Session is the id of chat session, the history is loaded from it if set
+	Session           string
 	User              types.Account
 	Sampling          bool
 	Text              domain.BaiChuanText
@@ -351,6 +359,8 @@ type BaiChuanDTO struct {
 
 // glm2
 type GLM2Cmd struct {
+	// Session is the id of chat session, the history is loaded from it if set
+	Session           string
 	CH                chan string
 	User              types.Account
 	History           []domain.History
@@ -376,6 +386,8 @@ type GLM2DTO struct {
 
 // llama2
 type LLAMA2Cmd struct {
+	// Session is the id of chat session, the history is loaded from it if set
+	Session           string
 	CH                chan string
 	User              types.Account
 	History           []domain.History
@@ -398,3 +410,482 @@ type LLAMA2DTO struct {
 	Reply        string `json:"reply"`
 	StreamStatus string `json:"stream_status"`
 }
+
+// async task
+type AsyncTextCmd struct {
+	User     types.Account
+	TaskType asyncdomain.TaskType
+	Text     string
+	Lang     string
+}
+
+func (cmd *AsyncTextCmd) Validate() error {
+	if cmd.TaskType == nil || !cmd.TaskType.IsTextGeneration() {
+		return errors.New("unsupported async task type")
+	}
+
+	if cmd.Text == "" {
+		return errors.New("missing text")
+	}
+
+	if cmd.TaskType.TaskType() == string(domain.BigmodelCodeGeex) && cmd.Lang == "" {
+		return errors.New("missing lang")
+	}
+
+	return nil
+}
+
+func (cmd *AsyncTextCmd) payload() asyncdomain.TaskPayload {
+	if cmd.TaskType.TaskType() == string(domain.BigmodelCodeGeex) {
+		return asyncdomain.CodeGeexPayload{
+			Lang:    cmd.Lang,
+			Content: cmd.Text,
+		}
+	}
+
+	return asyncdomain.TextPayload{Text: cmd.Text}
+}
+
+type AsyncTaskDTO struct {
+	Id        uint64 `json:"id"`
+	Status    string `json:"status"`
+	Content   string `json:"content,omitempty"`
+	Error     string `json:"error,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func (dto *AsyncTaskDTO) toAsyncTaskDTO(v *asyncrepo.TaskResp) {
+	dto.Id = v.Id
+	dto.Status = v.Status.TaskStatus()
+	dto.Error = v.ErrMsg
+
+	if v.CreatedAt != nil {
+		dto.CreatedAt = v.CreatedAt.Time()
+	}
+
+	if r, ok := v.Result.(asyncdomain.TextResult); ok {
+		dto.Content = r.Content
+	}
+}
+
+// AsyncTaskWatchCmd
+type AsyncTaskWatchCmd struct {
+	User     types.Account
+	TaskType []string
+}
+
+func NewAsyncTaskWatchCmd(user types.Account, model string) (cmd AsyncTaskWatchCmd, err error) {
+	cmd.User = user
+
+	if model == "" || model == "wukong" {
+		cmd.TaskType = wukongTaskTypes
+
+		return
+	}
+
+	t, err := asyncdomain.NewTaskType(model)
+	if err != nil || !t.IsTextGeneration() {
+		err = errors.New("unsupported async task")
+
+		return
+	}
+
+	cmd.TaskType = []string{t.TaskType()}
+
+	return
+}
+
+type AsyncTaskStatusDTO struct {
+	TaskId uint64            `json:"task_id,omitempty"`
+	Status string            `json:"status"`
+	Rank   int               `json:"rank"`
+	Error  string            `json:"error,omitempty"`
+	Result map[string]string `json:"result,omitempty"`
+}
+
+// api gateway
+type ApiRateLimitDTO struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	ResetAt   int64 `json:"reset_at"`
+}
+
+func (dto *ApiRateLimitDTO) toApiRateLimitDTO(r *quota.Result) {
+	dto.Limit = r.Limit
+	dto.Remaining = r.Remaining
+	dto.ResetAt = r.ResetAt
+}
+
+type ApiDailyUsageDTO struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+type ApiQuotaUsageDTO struct {
+	Model        string             `json:"model"`
+	DailyQuota   int                `json:"daily_quota"`
+	DailyUsed    int                `json:"daily_used"`
+	MonthlyQuota int                `json:"monthly_quota"`
+	MonthlyUsed  int                `json:"monthly_used"`
+	RPM          int                `json:"rpm"`
+	History      []ApiDailyUsageDTO `json:"history"`
+}
+
+// api key
+type ApiKeyCreateCmd struct {
+	User   types.Account
+	Model  domain.ModelName
+	Name   domain.ApiKeyName
+	Expiry int // days, 0 means the key never expires
+}
+
+type ApiKeyDTO struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Hint       string `json:"hint"`
+	Model      string `json:"model"`
+	CreatedAt  int64  `json:"created_at"`
+	ExpireAt   int64  `json:"expire_at"`
+	RevokedAt  int64  `json:"revoked_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+	Available  bool   `json:"available"`
+}
+
+func toApiKeyDTO(k *domain.ApiKey, now int64) ApiKeyDTO {
+	return ApiKeyDTO{
+		Id:         k.Id,
+		Name:       k.Name.ApiKeyName(),
+		Hint:       k.Hint,
+		Model:      k.Model.ModelName(),
+		CreatedAt:  k.CreatedAt,
+		ExpireAt:   k.ExpireAt,
+		RevokedAt:  k.RevokedAt,
+		LastUsedAt: k.LastUsedAt,
+		Available:  k.IsAvailable(now),
+	}
+}
+
+// ApiKeyCreatedDTO contains the key which is shown only once
+type ApiKeyCreatedDTO struct {
+	ApiKeyDTO
+
+	Key string `json:"key"`
+}
+
+// api usage
+type ApiUsageStatDTO struct {
+	Period     string `json:"period"`
+	Model      string `json:"model"`
+	Status     int    `json:"status"`
+	Count      int    `json:"count"`
+	AvgLatency int64  `json:"avg_latency"` // millisecond
+}
+
+type ApiUsageStatsDTO struct {
+	Start  string            `json:"start"`
+	End    string            `json:"end"`
+	Total  int               `json:"total"`
+	Failed int               `json:"failed"`
+	Items  []ApiUsageStatDTO `json:"items"`
+}
+
+// chat session
+type ChatSessionCreateCmd struct {
+	User  types.Account
+	Model domain.ModelName
+	Title domain.ChatSessionTitle
+}
+
+type ChatSessionDTO struct {
+	Id        string `json:"id"`
+	Model     string `json:"model"`
+	Title     string `json:"title"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+func toChatSessionDTO(s *domain.ChatSession) ChatSessionDTO {
+	return ChatSessionDTO{
+		Id:        s.Id,
+		Model:     s.Model.ModelName(),
+		Title:     s.Title.ChatSessionTitle(),
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+type ChatRoundDTO struct {
+	Question  string `json:"question"`
+	Answer    string `json:"answer"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type ChatSessionDetailDTO struct {
+	ChatSessionDTO
+
+	Rounds []ChatRoundDTO `json:"rounds"`
+}
+
+// prompt template
+type PromptCreateCmd struct {
+	User    types.Account
+	Model   domain.PromptModel
+	Title   domain.PromptTitle
+	Desc    domain.PromptDesc
+	Content domain.PromptContent
+	Public  bool
+}
+
+// PromptUpdateCmd changes the template, the item which is nil will not be changed
+type PromptUpdateCmd struct {
+	User    types.Account
+	Id      string
+	Title   domain.PromptTitle
+	Desc    domain.PromptDesc
+	Content domain.PromptContent
+	Public  *bool
+}
+
+type PromptListPublicCmd struct {
+	User types.Account
+	domain.PromptTemplateListOption
+}
+
+func (cmd *PromptListPublicCmd) Validate() error {
+	if cmd.PageNum < 1 {
+		return errors.New("page_num less than 1")
+	}
+
+	if cmd.CountPerPage < 1 || cmd.CountPerPage > 100 {
+		return errors.New("count_per_page should be between 1 and 100")
+	}
+
+	return nil
+}
+
+type PromptRunCmd struct {
+	User      types.Account
+	Id        string
+	Variables map[string]string
+	Lang      string // the language of code, it is required by codegeex
+}
+
+type PromptTemplateDTO struct {
+	Id         string   `json:"id"`
+	Owner      string   `json:"owner"`
+	Model      string   `json:"model"`
+	Title      string   `json:"title"`
+	Desc       string   `json:"desc"`
+	Content    string   `json:"content"`
+	Variables  []string `json:"variables"`
+	Public     bool     `json:"public"`
+	IsLiked    bool     `json:"is_liked"`
+	LikeCount  int      `json:"like_count"`
+	ForkCount  int      `json:"fork_count"`
+	ForkedFrom string   `json:"forked_from,omitempty"`
+	CreatedAt  int64    `json:"created_at"`
+	UpdatedAt  int64    `json:"updated_at"`
+}
+
+func toPromptTemplateDTO(t *domain.PromptTemplate, user types.Account) PromptTemplateDTO {
+	return PromptTemplateDTO{
+		Id:         t.Id,
+		Owner:      t.Owner.Account(),
+		Model:      t.Model.PromptModel(),
+		Title:      t.Title.PromptTitle(),
+		Desc:       t.Desc.PromptDesc(),
+		Content:    t.Content.PromptContent(),
+		Variables:  t.Content.Variables(),
+		Public:     t.Public,
+		IsLiked:    t.IsLikedBy(user),
+		LikeCount:  t.LikeCount,
+		ForkCount:  t.ForkCount,
+		ForkedFrom: t.ForkedFrom,
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+type PromptTemplatesDTO struct {
+	Total     int                 `json:"total"`
+	Templates []PromptTemplateDTO `json:"templates"`
+}
+
+type PromptRunDTO struct {
+	Prompt string `json:"prompt"`
+	Answer string `json:"answer"`
+}
+
+// gallery
+type GallerySaveCmd struct {
+	User   types.Account
+	Model  domain.GalleryModel
+	Path   string // the obs path of picture generated for the user
+	Desc   domain.GalleryDesc
+	Public bool
+}
+
+type GalleryListPublicCmd struct {
+	User types.Account
+	domain.GalleryListOption
+}
+
+func (cmd *GalleryListPublicCmd) Validate() error {
+	if cmd.PageNum < 1 {
+		return errors.New("page_num less than 1")
+	}
+
+	if cmd.CountPerPage < 1 || cmd.CountPerPage > 100 {
+		return errors.New("count_per_page should be between 1 and 100")
+	}
+
+	return nil
+}
+
+type GalleryReportCmd struct {
+	User   types.Account
+	Id     string
+	Reason abusedomain.ReportReason
+}
+
+type GalleryPictureDTO struct {
+	Id        string `json:"id"`
+	Owner     string `json:"owner"`
+	Model     string `json:"model"`
+	Link      string `json:"link"`
+	Desc      string `json:"desc"`
+	Public    bool   `json:"public"`
+	Hidden    bool   `json:"hidden"`
+	IsLiked   bool   `json:"is_liked"`
+	IsDigged  bool   `json:"is_digged"`
+	DiggCount int    `json:"digg_count"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type GalleryPicturesDTO struct {
+	Total    int                 `json:"total"`
+	Pictures []GalleryPictureDTO `json:"pictures"`
+}
+
+// arena
+type ArenaBattleCmd struct {
+	User   types.Account
+	Prompt string
+	Models []domain.ModelName
+}
+
+func (cmd *ArenaBattleCmd) Validate() error {
+	if strings.TrimSpace(cmd.Prompt) == "" {
+		return errors.New("no prompt")
+	}
+
+	if n := len(cmd.Models); n < 2 || n > domain.MaxArenaModels() {
+		return fmt.Errorf("the number of models should be between 2 and %d", domain.MaxArenaModels())
+	}
+
+	seen := map[string]bool{}
+	for _, m := range cmd.Models {
+		if seen[m.ModelName()] {
+			return errors.New("duplicate model")
+		}
+
+		seen[m.ModelName()] = true
+	}
+
+	return nil
+}
+
+// ArenaMessageDTO is the piece of answer of a model.
+// The first message only contains the id of battle.
+type ArenaMessageDTO struct {
+	BattleId string `json:"battle_id,omitempty"`
+	Model    string `json:"model,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Done     bool   `json:"done,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ArenaVoteCmd struct {
+	User     types.Account
+	BattleId string
+	Winner   string // the model or tie
+}
+
+type ArenaRatingDTO struct {
+	Rank    int     `json:"rank"`
+	Model   string  `json:"model"`
+	Rating  float64 `json:"rating"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Ties    int     `json:"ties"`
+	Battles int     `json:"battles"`
+}
+
+// batch
+type BatchCreateCmd struct {
+	User     types.Account
+	TaskType asyncdomain.TaskType
+	Input    []byte // the content of JSONL
+}
+
+type BatchDTO struct {
+	Id        uint64 `json:"id"`
+	Model     string `json:"model"`
+	Status    string `json:"status"`
+	Total     int    `json:"total"`
+	Waiting   int    `json:"waiting"`
+	Running   int    `json:"running"`
+	Finished  int    `json:"finished"`
+	Failed    int    `json:"failed"`
+	Cancelled int    `json:"cancelled"`
+	Progress  int    `json:"progress"` // percent
+	CreatedAt int64  `json:"created_at"`
+}
+
+func (dto *BatchDTO) toBatchDTO(v *asyncrepo.BatchResp) {
+	p := &v.Progress
+
+	*dto = BatchDTO{
+		Id:        v.Id,
+		Model:     v.TaskType.TaskType(),
+		Status:    v.Status.TaskStatus(),
+		Total:     v.Total,
+		Waiting:   p.Waiting,
+		Running:   p.Running,
+		Finished:  p.Finished,
+		Failed:    p.Failed,
+		Cancelled: p.Cancelled,
+		CreatedAt: v.CreatedAt,
+	}
+
+	if v.Total > 0 {
+		dto.Progress = p.Done() * 100 / v.Total
+	}
+}
+
+type BatchOutputCmd struct {
+	User       types.Account
+	BatchId    uint64
+	FailedOnly bool
+}
+
+// BatchOutputDTO is a line of the output of batch
+type BatchOutputDTO struct {
+	Index    int               `json:"index"`
+	CustomId string            `json:"custom_id,omitempty"`
+	Status   string            `json:"status"`
+	Result   map[string]string `json:"result,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+func (dto *BatchOutputDTO) toBatchOutputDTO(v *asyncrepo.BatchItemResp) {
+	dto.Index = v.Index
+	dto.CustomId = v.CustomId
+	dto.Status = v.Status.TaskStatus()
+	dto.Error = v.ErrMsg
+
+	if v.Result != nil {
+		dto.Result = v.Result.MetaData()
+	}
+}
@@ -11,6 +11,7 @@ const (
 	ErrorBigModelSensitiveInfo     = "bigmodel_sensitive_info"
 	ErrorBigModelRecourseBusy      = "bigmodel_resource_busy"
 	ErrorBigModelConcurrentRequest = "bigmodel_concurrent_request"
+	ErrorBigModelInvalidInput      = "bigmodel_invalid_input"
 
 	ErrorWuKongNoPicture        = "bigmodel_no_wukong_picture"
 	ErrorWuKongInvalidId        = "wukong_invalid_id"
@@ -20,4 +21,41 @@ const (
 	ErrorWuKongInvalidLink      = "wukong_invalid_link"
 	ErrorWuKongDuplicateLike    = "wukong_duplicate_like"
 	ErrorWuKongExccedMaxLikeNum = "wukong_excced_max_like_num"
+	ErrorWuKongTaskCancelled    = "wukong_task_cancelled"
+
+	ErrorAsyncTaskNotFound = "async_task_not_found"
+
+	ErrorBatchNotFound     = "bigmodel_batch_not_found"
+	ErrorBatchInvalidInput = "bigmodel_batch_invalid_input"
+
+	ErrorChatUnsupportedModel = "bigmodel_chat_unsupported_model"
+
+	ErrorChatSessionNotFound       = "bigmodel_chat_session_not_found"
+	ErrorChatSessionExceeded       = "bigmodel_chat_session_exceed_max_num"
+	ErrorChatSessionUnmatchedModel = "bigmodel_chat_session_unmatched_model"
+
+	ErrorPromptTemplateNotFound = "bigmodel_prompt_template_not_found"
+	ErrorPromptTemplateNotOwner = "bigmodel_prompt_template_not_owner"
+	ErrorPromptInvalidVariables = "bigmodel_prompt_invalid_variables"
+
+	ErrorGalleryPictureNotFound = "bigmodel_gallery_picture_not_found"
+	ErrorGalleryPictureExceeded = "bigmodel_gallery_picture_exceed_max_num"
+	ErrorGalleryLikeExceeded    = "bigmodel_gallery_like_exceed_max_num"
+	ErrorGalleryInvalidPath     = "bigmodel_gallery_invalid_path"
+	ErrorGalleryOwnPicture      = "bigmodel_gallery_own_picture"
+
+	ErrorArenaBattleNotFound = "bigmodel_arena_battle_not_found"
+	ErrorArenaInvalidVote    = "bigmodel_arena_invalid_vote"
+	ErrorArenaVoted          = "bigmodel_arena_voted"
+
+	ErrorApiNotApplied    = "bigmodel_api_not_applied"
+	ErrorApiDisabled      = "bigmodel_api_disabled"
+	ErrorApiRateLimited   = "bigmodel_api_rate_limited"
+	ErrorApiQuotaExceeded = "bigmodel_api_quota_exceeded"
+
+	ErrorApiKeyInvalid  = "bigmodel_api_key_invalid"
+	ErrorApiKeyExpired  = "bigmodel_api_key_expired"
+	ErrorApiKeyRevoked  = "bigmodel_api_key_revoked"
+	ErrorApiKeyNotFound = "bigmodel_api_key_not_found"
+	ErrorApiKeyExceeded = "bigmodel_api_key_exceed_max_num"
 )
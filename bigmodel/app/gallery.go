@@ -0,0 +1,367 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	abuseapp "github.com/opensourceways/xihe-server/abuse/app"
+	abusedomain "github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	crepository "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// GalleryService manages the pictures of image models, such as wukong and luojia.
+// The picture saved by user can be published to the gallery, and then the other
+// users can save it to their likes, digg or report it.
+type GalleryService interface {
+	Save(*GallerySaveCmd) (GalleryPictureDTO, string, error)
+	Delete(user types.Account, id string) (string, error)
+	SetPublic(user types.Account, id string, public bool) (string, error)
+	List(user types.Account, model domain.GalleryModel) ([]GalleryPictureDTO, error)
+	ListLikes(user types.Account, model domain.GalleryModel) ([]GalleryPictureDTO, error)
+
+	// gallery
+	ListPublic(*GalleryListPublicCmd) (GalleryPicturesDTO, error)
+	Like(user types.Account, id string) (string, error)
+	CancelLike(user types.Account, id string) (string, error)
+	Digg(user types.Account, id string) (int, string, error)
+	CancelDigg(user types.Account, id string) (int, string, error)
+	Report(*GalleryReportCmd) (string, error)
+}
+
+func NewGalleryService(
+	fm bigmodel.BigModel,
+	pictures repository.GalleryPicture,
+	abuse abuseapp.ReportService,
+) GalleryService {
+	return &galleryService{
+		fm:       fm,
+		abuse:    abuse,
+		pictures: pictures,
+	}
+}
+
+type galleryService struct {
+	fm       bigmodel.BigModel
+	abuse    abuseapp.ReportService
+	pictures repository.GalleryPicture
+}
+
+func (s *galleryService) Save(cmd *GallerySaveCmd) (dto GalleryPictureDTO, code string, err error) {
+	n, err := s.pictures.CountPictures(cmd.User)
+	if err != nil {
+		return
+	}
+
+	if n >= domain.MaxGalleryPictures() {
+		code = ErrorGalleryPictureExceeded
+		err = errors.New("exceed the max number of gallery pictures")
+
+		return
+	}
+
+	model := cmd.Model.GalleryModel()
+
+	if cmd.Public {
+		if code, err = s.checkDesc(model, cmd.Desc); err != nil {
+			return
+		}
+	}
+
+	path, err := s.fm.SaveGalleryPicture(model, cmd.User, cmd.Path)
+	if err != nil {
+		code = ErrorGalleryInvalidPath
+
+		return
+	}
+
+	p := domain.GalleryPicture{
+		Owner:     cmd.User,
+		Model:     cmd.Model,
+		Path:      path,
+		Desc:      cmd.Desc,
+		Public:    cmd.Public,
+		CreatedAt: utils.Now(),
+	}
+
+	if p.Id, err = s.pictures.AddPicture(&p); err != nil {
+		if err1 := s.fm.DeleteGalleryPicture(model, path); err1 != nil {
+			logrus.Errorf("delete gallery picture %s failed, err:%s", path, err1.Error())
+		}
+
+		return
+	}
+
+	dto, err = s.toGalleryPictureDTO(&p, cmd.User)
+
+	return
+}
+
+func (s *galleryService) Delete(user types.Account, id string) (code string, err error) {
+	p, code, err := s.getOwnPicture(user, id)
+	if err != nil {
+		return
+	}
+
+	if err = s.pictures.DeletePicture(user, id); err != nil {
+		code = galleryErrorCode(err)
+
+		return
+	}
+
+	if err1 := s.fm.DeleteGalleryPicture(p.Model.GalleryModel(), p.Path); err1 != nil {
+		logrus.Errorf("delete gallery picture %s failed, err:%s", p.Path, err1.Error())
+	}
+
+	return
+}
+
+func (s *galleryService) SetPublic(user types.Account, id string, public bool) (
+	code string, err error,
+) {
+	if public {
+		p, code1, err1 := s.getOwnPicture(user, id)
+		if err1 != nil {
+			return code1, err1
+		}
+
+		if code, err = s.checkDesc(p.Model.GalleryModel(), p.Desc); err != nil {
+			return
+		}
+	}
+
+	if err = s.pictures.UpdatePublic(user, id, public); err != nil {
+		code = galleryErrorCode(err)
+	}
+
+	return
+}
+
+// checkDesc checks the desc before the picture is published to the gallery
+func (s *galleryService) checkDesc(model string, desc domain.GalleryDesc) (code string, err error) {
+	v := desc.GalleryDesc()
+	if v == "" {
+		return
+	}
+
+	if err = s.fm.CheckText(model, v); err != nil {
+		code = ErrorBigModelSensitiveInfo
+	}
+
+	return
+}
+
+func (s *galleryService) List(user types.Account, model domain.GalleryModel) (
+	[]GalleryPictureDTO, error,
+) {
+	v, err := s.pictures.ListPictures(user, model)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toGalleryPictureDTOs(v, user), nil
+}
+
+// ListLikes lists the pictures of others which are saved to the likes of user.
+// The picture which is withdrawn from the gallery is not listed.
+func (s *galleryService) ListLikes(user types.Account, model domain.GalleryModel) (
+	[]GalleryPictureDTO, error,
+) {
+	v, err := s.pictures.ListLikes(user, model)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]domain.GalleryPicture, 0, len(v))
+	for i := range v {
+		if v[i].IsVisible(user) {
+			r = append(r, v[i])
+		}
+	}
+
+	return s.toGalleryPictureDTOs(r, user), nil
+}
+
+func (s *galleryService) ListPublic(cmd *GalleryListPublicCmd) (dto GalleryPicturesDTO, err error) {
+	v, err := s.pictures.ListPublicPictures(&cmd.GalleryListOption)
+	if err != nil {
+		return
+	}
+
+	dto.Total = v.Total
+	dto.Pictures = s.toGalleryPictureDTOs(v.Pictures, cmd.User)
+
+	return
+}
+
+func (s *galleryService) Like(user types.Account, id string) (code string, err error) {
+	p, code, err := s.getPublishedPicture(user, id)
+	if err != nil {
+		return
+	}
+
+	if p.IsOwner(user) {
+		code = ErrorGalleryOwnPicture
+		err = errors.New("can't like own picture")
+
+		return
+	}
+
+	if p.IsLikedBy(user) {
+		return
+	}
+
+	n, err := s.pictures.CountLikes(user)
+	if err != nil {
+		return
+	}
+
+	if n >= domain.MaxGalleryLikes() {
+		code = ErrorGalleryLikeExceeded
+		err = errors.New("exceed the max number of likes")
+
+		return
+	}
+
+	if err = s.pictures.AddLike(id, user); err != nil {
+		code = galleryErrorCode(err)
+	}
+
+	return
+}
+
+func (s *galleryService) CancelLike(user types.Account, id string) (code string, err error) {
+	if err = s.pictures.RemoveLike(id, user); err != nil {
+		code = galleryErrorCode(err)
+	}
+
+	return
+}
+
+func (s *galleryService) Digg(user types.Account, id string) (n int, code string, err error) {
+	if _, code, err = s.getPublishedPicture(user, id); err != nil {
+		return
+	}
+
+	if n, err = s.pictures.AddDigg(id, user); err != nil {
+		code = galleryErrorCode(err)
+	}
+
+	return
+}
+
+func (s *galleryService) CancelDigg(user types.Account, id string) (n int, code string, err error) {
+	if n, err = s.pictures.RemoveDigg(id, user); err != nil {
+		code = galleryErrorCode(err)
+	}
+
+	return
+}
+
+// Report puts the picture into the review queue of abuse,
+// it is hidden or deleted by the admin after the review.
+func (s *galleryService) Report(cmd *GalleryReportCmd) (code string, err error) {
+	p, code, err := s.getPublishedPicture(cmd.User, cmd.Id)
+	if err != nil {
+		return
+	}
+
+	return s.abuse.Report(&abuseapp.ReportCmd{
+		Item: abusedomain.Item{
+			Type:  abusedomain.ItemTypeGalleryPicture,
+			Owner: p.Owner,
+			Id:    p.Id,
+		},
+		Reporter: cmd.User,
+		Reason:   cmd.Reason,
+	})
+}
+
+func (s *galleryService) getPublishedPicture(user types.Account, id string) (
+	p domain.GalleryPicture, code string, err error,
+) {
+	if p, err = s.pictures.GetPicture(id); err != nil {
+		code = galleryErrorCode(err)
+
+		return
+	}
+
+	if !p.IsPublished() {
+		code = ErrorGalleryPictureNotFound
+		err = errors.New("the gallery picture does not exist")
+	}
+
+	return
+}
+
+func (s *galleryService) getOwnPicture(user types.Account, id string) (
+	p domain.GalleryPicture, code string, err error,
+) {
+	if p, err = s.pictures.GetPicture(id); err != nil {
+		code = galleryErrorCode(err)
+
+		return
+	}
+
+	if !p.IsOwner(user) {
+		code = ErrorGalleryPictureNotFound
+		err = errors.New("the gallery picture does not exist")
+	}
+
+	return
+}
+
+func (s *galleryService) toGalleryPictureDTOs(v []domain.GalleryPicture, user types.Account) []GalleryPictureDTO {
+	r := make([]GalleryPictureDTO, 0, len(v))
+
+	for i := range v {
+		dto, err := s.toGalleryPictureDTO(&v[i], user)
+		if err != nil {
+			logrus.Errorf("gen link of gallery picture %s failed, err:%s", v[i].Id, err.Error())
+
+			continue
+		}
+
+		r = append(r, dto)
+	}
+
+	return r
+}
+
+func (s *galleryService) toGalleryPictureDTO(p *domain.GalleryPicture, user types.Account) (
+	dto GalleryPictureDTO, err error,
+) {
+	link, err := s.fm.GenGalleryPictureLink(p.Model.GalleryModel(), p.Path)
+	if err != nil {
+		return
+	}
+
+	dto = GalleryPictureDTO{
+		Id:        p.Id,
+		Owner:     p.Owner.Account(),
+		Model:     p.Model.GalleryModel(),
+		Link:      link,
+		Desc:      p.Desc.GalleryDesc(),
+		Public:    p.Public,
+		Hidden:    p.Hidden,
+		IsLiked:   p.IsLikedBy(user),
+		IsDigged:  p.IsDiggedBy(user),
+		DiggCount: p.DiggCount,
+		CreatedAt: p.CreatedAt,
+	}
+
+	return
+}
+
+func galleryErrorCode(err error) string {
+	if crepository.IsErrorResourceNotExists(err) {
+		return ErrorGalleryPictureNotFound
+	}
+
+	return ""
+}
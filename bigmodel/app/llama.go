@@ -1,10 +1,26 @@
 package app
 
 import (
+	"context"
+
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
 )
 
 func (s bigModelService) LLAMA2(cmd *LLAMA2Cmd) (code string, err error) {
+	var session domain.ChatSession
+
+	ch, history := cmd.CH, cmd.History
+	if cmd.Session != "" {
+		session, history, code, err = s.chatSessionContext(
+			cmd.User, cmd.Session, domain.BigmodelLLAMA2, cmd.Text.LLAMA2Text(),
+		)
+		if err != nil {
+			return
+		}
+
+		ch = make(chan string)
+	}
+
 	_ = s.sender.SendBigModelStarted(&domain.BigModelStartedEvent{
 		Account:      cmd.User,
 		BigModelType: domain.BigmodelLLAMA2,
@@ -13,19 +29,23 @@ func (s bigModelService) LLAMA2(cmd *LLAMA2Cmd) (code string, err error) {
 	input := &domain.LLAMA2Input{
 		Text:              cmd.Text,
 		Sampling:          cmd.Sampling,
-		History:           cmd.History,
+		History:           history,
 		TopK:              cmd.TopK,
 		TopP:              cmd.TopP,
 		Temperature:       cmd.Temperature,
 		RepetitionPenalty: cmd.RepetitionPenalty,
 	}
 
-	if err = s.fm.LLAMA2(cmd.CH, input); err != nil {
+	if err = s.fm.LLAMA2(context.Background(), ch, input); err != nil {
 		code = s.setCode(err)
-		
+
 		return
 	}
 
+	if cmd.Session != "" {
+		go s.forwardChatStream(&session, cmd.Text.LLAMA2Text(), ch, cmd.CH)
+	}
+
 	_ = s.sender.SendBigModelFinished(&domain.BigModelFinishedEvent{
 		Account:      cmd.User,
 		BigModelType: domain.BigmodelLLAMA2,
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	types "github.com/opensourceways/xihe-server/domain"
 )
@@ -11,7 +13,7 @@ func (s bigModelService) PanGu(u types.Account, q string) (v string, code string
 		BigModelType: domain.BigmodelPanGu,
 	})
 
-	if v, err = s.fm.PanGu(q); err != nil {
+	if v, err = s.fm.PanGu(context.Background(), q); err != nil {
 		code = s.setCode(err)
 
 		return
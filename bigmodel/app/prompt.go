@@ -0,0 +1,368 @@
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	crepository "github.com/opensourceways/xihe-server/domain/repository"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// promptModerationScene is the policy of moderation for the public templates
+const promptModerationScene = "prompt_template"
+
+type PromptService interface {
+	Create(*PromptCreateCmd) (PromptTemplateDTO, string, error)
+	Update(*PromptUpdateCmd) (string, error)
+	Delete(user types.Account, id string) (string, error)
+	Get(user types.Account, id string) (PromptTemplateDTO, string, error)
+	List(types.Account) ([]PromptTemplateDTO, error)
+
+	// gallery
+	ListPublic(*PromptListPublicCmd) (PromptTemplatesDTO, error)
+	Like(user types.Account, id string) (int, string, error)
+	CancelLike(user types.Account, id string) (int, string, error)
+	Fork(user types.Account, id string) (PromptTemplateDTO, string, error)
+
+	// Run instantiates the template and calls the model with it
+	Run(*PromptRunCmd) (PromptRunDTO, string, error)
+}
+
+func NewPromptService(
+	templates repository.PromptTemplate, bm BigModelService, fm bigmodel.BigModel,
+) PromptService {
+	return &promptService{
+		bm:        bm,
+		fm:        fm,
+		templates: templates,
+	}
+}
+
+type promptService struct {
+	bm        BigModelService
+	fm        bigmodel.BigModel
+	templates repository.PromptTemplate
+}
+
+func (s *promptService) Create(cmd *PromptCreateCmd) (dto PromptTemplateDTO, code string, err error) {
+	now := utils.Now()
+
+	t := domain.PromptTemplate{
+		Owner:     cmd.User,
+		Model:     cmd.Model,
+		Title:     cmd.Title,
+		Desc:      cmd.Desc,
+		Content:   cmd.Content,
+		Public:    cmd.Public,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if code, err = s.checkPublic(&t); err != nil {
+		return
+	}
+
+	if t.Id, err = s.templates.AddTemplate(&t); err != nil {
+		return
+	}
+
+	dto = toPromptTemplateDTO(&t, cmd.User)
+
+	return
+}
+
+func (s *promptService) Update(cmd *PromptUpdateCmd) (code string, err error) {
+	t, code, err := s.getOwnTemplate(cmd.User, cmd.Id)
+	if err != nil {
+		return
+	}
+
+	if cmd.Title != nil {
+		t.Title = cmd.Title
+	}
+
+	if cmd.Desc != nil {
+		t.Desc = cmd.Desc
+	}
+
+	if cmd.Content != nil {
+		t.Content = cmd.Content
+	}
+
+	if cmd.Public != nil {
+		t.Public = *cmd.Public
+	}
+
+	if code, err = s.checkPublic(&t); err != nil {
+		return
+	}
+
+	t.UpdatedAt = utils.Now()
+
+	if err = s.templates.UpdateTemplate(&t); err != nil {
+		code = promptErrorCode(err)
+	}
+
+	return
+}
+
+func (s *promptService) Delete(user types.Account, id string) (code string, err error) {
+	if err = s.templates.DeleteTemplate(user, id); err != nil {
+		code = promptErrorCode(err)
+	}
+
+	return
+}
+
+func (s *promptService) Get(user types.Account, id string) (
+	dto PromptTemplateDTO, code string, err error,
+) {
+	t, code, err := s.getVisibleTemplate(user, id)
+	if err == nil {
+		dto = toPromptTemplateDTO(&t, user)
+	}
+
+	return
+}
+
+func (s *promptService) List(user types.Account) ([]PromptTemplateDTO, error) {
+	v, err := s.templates.ListTemplates(user)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]PromptTemplateDTO, len(v))
+	for i := range v {
+		r[i] = toPromptTemplateDTO(&v[i], user)
+	}
+
+	return r, nil
+}
+
+func (s *promptService) ListPublic(cmd *PromptListPublicCmd) (dto PromptTemplatesDTO, err error) {
+	v, err := s.templates.ListPublicTemplates(&cmd.PromptTemplateListOption)
+	if err != nil {
+		return
+	}
+
+	dto.Total = v.Total
+	dto.Templates = make([]PromptTemplateDTO, len(v.Templates))
+
+	for i := range v.Templates {
+		dto.Templates[i] = toPromptTemplateDTO(&v.Templates[i], cmd.User)
+	}
+
+	return
+}
+
+func (s *promptService) Like(user types.Account, id string) (n int, code string, err error) {
+	t, code, err := s.getVisibleTemplate(user, id)
+	if err != nil {
+		return
+	}
+
+	if !t.Public {
+		code = ErrorPromptTemplateNotFound
+		err = errors.New("only the public template can be liked")
+
+		return
+	}
+
+	if n, err = s.templates.AddLike(id, user); err != nil {
+		code = promptErrorCode(err)
+	}
+
+	return
+}
+
+func (s *promptService) CancelLike(user types.Account, id string) (n int, code string, err error) {
+	if n, err = s.templates.RemoveLike(id, user); err != nil {
+		code = promptErrorCode(err)
+	}
+
+	return
+}
+
+func (s *promptService) Fork(user types.Account, id string) (
+	dto PromptTemplateDTO, code string, err error,
+) {
+	t, code, err := s.getVisibleTemplate(user, id)
+	if err != nil {
+		return
+	}
+
+	v := t.Fork(user, utils.Now())
+
+	if v.Id, err = s.templates.AddTemplate(&v); err != nil {
+		return
+	}
+
+	if !t.IsOwner(user) {
+		_ = s.templates.IncreaseForkCount(id)
+	}
+
+	dto = toPromptTemplateDTO(&v, user)
+
+	return
+}
+
+func (s *promptService) Run(cmd *PromptRunCmd) (dto PromptRunDTO, code string, err error) {
+	t, code, err := s.getVisibleTemplate(cmd.User, cmd.Id)
+	if err != nil {
+		return
+	}
+
+	if dto.Prompt, err = t.Instantiate(cmd.Variables); err != nil {
+		code = ErrorPromptInvalidVariables
+
+		return
+	}
+
+	dto.Answer, code, err = s.call(cmd.User, t.Model.PromptModel(), dto.Prompt, cmd.Lang)
+
+	return
+}
+
+// call sends the prompt to the model and waits for the whole answer
+func (s *promptService) call(user types.Account, model, prompt, lang string) (
+	answer, code string, err error,
+) {
+	switch domain.BigmodelType(model) {
+	case domain.BigmodelPanGu:
+		return s.bm.PanGu(user, prompt)
+
+	case domain.BigmodelCodeGeex:
+		cmd := CodeGeexCmd{Content: prompt, Lang: lang}
+		if err = cmd.Validate(); err != nil {
+			code = ErrorBigModelInvalidInput
+
+			return
+		}
+
+		var v CodeGeexDTO
+		v, code, err = s.bm.CodeGeex(user, &cmd)
+		answer = v.Result
+
+		return
+
+	case domain.BigmodelBaiChuan:
+		cmd := BaiChuanCmd{User: user}
+		if cmd.Text, err = domain.NewBaiChuanText(prompt); err != nil {
+			code = ErrorBigModelInvalidInput
+
+			return
+		}
+		cmd.SetDefault()
+
+		var v BaiChuanDTO
+		code, v, err = s.bm.BaiChuan(&cmd)
+		answer = v.Text
+
+		return
+
+	case domain.BigmodelGLM2:
+		cmd := GLM2Cmd{User: user, CH: make(chan string)}
+		if cmd.Text, err = domain.NewGLM2Text(prompt); err != nil {
+			code = ErrorBigModelInvalidInput
+
+			return
+		}
+		cmd.SetDefault()
+
+		if code, err = s.bm.GLM2(&cmd); err != nil {
+			return
+		}
+
+		if answer, err = receiveStream(cmd.CH); err != nil {
+			code = bigmodelErrorCode(err)
+		}
+
+		return
+
+	case domain.BigmodelLLAMA2:
+		cmd := LLAMA2Cmd{User: user, CH: make(chan string)}
+		if cmd.Text, err = domain.NewLLAMA2Text(prompt); err != nil {
+			code = ErrorBigModelInvalidInput
+
+			return
+		}
+		cmd.SetDefault()
+
+		if code, err = s.bm.LLAMA2(&cmd); err != nil {
+			return
+		}
+
+		if answer, err = receiveStream(cmd.CH); err != nil {
+			code = bigmodelErrorCode(err)
+		}
+
+		return
+	}
+
+	err = errors.New("unsupported model of prompt")
+
+	return
+}
+
+// checkPublic checks the content of template before it is published to the gallery
+func (s *promptService) checkPublic(t *domain.PromptTemplate) (code string, err error) {
+	if !t.Public {
+		return
+	}
+
+	text := strings.Join([]string{
+		t.Title.PromptTitle(), t.Desc.PromptDesc(), t.Content.PromptContent(),
+	}, "\n")
+
+	if err = s.fm.CheckText(promptModerationScene, text); err != nil {
+		code = ErrorBigModelSensitiveInfo
+	}
+
+	return
+}
+
+func (s *promptService) getVisibleTemplate(user types.Account, id string) (
+	t domain.PromptTemplate, code string, err error,
+) {
+	if t, err = s.templates.GetTemplate(id); err != nil {
+		code = promptErrorCode(err)
+
+		return
+	}
+
+	if !t.IsVisible(user) {
+		code = ErrorPromptTemplateNotFound
+		err = errors.New("the prompt template does not exist")
+	}
+
+	return
+}
+
+func (s *promptService) getOwnTemplate(user types.Account, id string) (
+	t domain.PromptTemplate, code string, err error,
+) {
+	if t, err = s.templates.GetTemplate(id); err != nil {
+		code = promptErrorCode(err)
+
+		return
+	}
+
+	if !t.IsOwner(user) {
+		code = ErrorPromptTemplateNotOwner
+		err = errors.New("not the owner of prompt template")
+	}
+
+	return
+}
+
+func promptErrorCode(err error) string {
+	if crepository.IsErrorResourceNotExists(err) {
+		return ErrorPromptTemplateNotFound
+	}
+
+	return ""
+}
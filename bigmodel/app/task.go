@@ -0,0 +1,58 @@
+package app
+
+import (
+	"errors"
+
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	commonrepo "github.com/opensourceways/xihe-server/common/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+func (s bigModelService) TextInferenceAsync(cmd *AsyncTextCmd) (code string, err error) {
+	// content audit
+	if err = s.fm.CheckText(cmd.TaskType.TaskType(), cmd.Text); err != nil {
+		code = ErrorBigModelSensitiveInfo
+
+		return
+	}
+
+	return "", s.sender.SendAsyncTaskCreated(&domain.AsyncTaskCreatedEvent{
+		Account:  cmd.User,
+		TaskType: cmd.TaskType.TaskType(),
+		Payload:  cmd.payload().MetaData(),
+		Priority: s.taskPriority(cmd.User, cmd.TaskType.TaskType()),
+	})
+}
+
+// taskPriority prefers the user who has applied the api of the model
+func (s bigModelService) taskPriority(user types.Account, model string) int {
+	name, err := domain.NewModelName(model)
+	if err != nil {
+		return asyncdomain.TaskPriorityNormal.TaskPriority()
+	}
+
+	if v, err := s.apiService.GetApiByUserModel(user, name); err == nil && v.Enabled {
+		return asyncdomain.TaskPriorityApiUser.TaskPriority()
+	}
+
+	return asyncdomain.TaskPriorityNormal.TaskPriority()
+}
+
+func (s bigModelService) GetLastAsyncTask(user types.Account, t asyncdomain.TaskType) (
+	dto AsyncTaskDTO, code string, err error,
+) {
+	v, err := s.asynccli.GetLastTask(user, []string{t.TaskType()})
+	if err != nil {
+		if commonrepo.IsErrorResourceNotExists(err) {
+			code = ErrorAsyncTaskNotFound
+			err = errors.New("async task not found")
+		}
+
+		return
+	}
+
+	dto.toAsyncTaskDTO(&v)
+
+	return
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/async"
+	commonrepo "github.com/opensourceways/xihe-server/common/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type AsyncTaskStatusService interface {
+	// Watch pushes the status of the async tasks of user by send
+	// until stop is closed or it fails to send.
+	Watch(cmd *AsyncTaskWatchCmd, send func(*AsyncTaskStatusDTO) error, stop <-chan struct{}) error
+}
+
+func NewAsyncTaskStatusService(
+	asynccli async.AsyncTask,
+	hub async.TaskEventHub,
+) AsyncTaskStatusService {
+	return &asyncTaskStatusService{
+		asynccli: asynccli,
+		hub:      hub,
+		ranks:    rankCache{items: map[string]*queueRanks{}},
+	}
+}
+
+type asyncTaskStatusService struct {
+	asynccli async.AsyncTask
+	hub      async.TaskEventHub
+	ranks    rankCache
+}
+
+func (s *asyncTaskStatusService) Watch(
+	cmd *AsyncTaskWatchCmd, send func(*AsyncTaskStatusDTO) error, stop <-chan struct{},
+) error {
+	// subscribe before reading the current status, so no event is missed
+	events, unsubscribe := s.hub.Subscribe()
+	defer unsubscribe()
+
+	dto, err := s.current(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := send(&dto); err != nil {
+		return err
+	}
+
+	rank := dto.Rank
+	user := cmd.User.Account()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+
+		case e := <-events:
+			// the event of the other queue doesn't change the rank
+			if e.TaskType != "" && !isTaskTypeOf(e.TaskType, cmd.TaskType) {
+				continue
+			}
+
+			if e.User == user {
+				v := AsyncTaskStatusDTO{
+					TaskId: e.TaskId,
+					Status: e.Status,
+					Error:  e.ErrMsg,
+					Result: e.Result,
+				}
+
+				if v.Status == "waiting" {
+					if v.Rank, err = s.rankOfEvent(&e, cmd); err != nil {
+						return err
+					}
+				}
+
+				if err := send(&v); err != nil {
+					return err
+				}
+
+				rank = v.Rank
+
+				continue
+			}
+
+			// the queue is changed by the other users
+			if rank == 0 {
+				continue
+			}
+
+			n, err := s.rankOfEvent(&e, cmd)
+			if err != nil {
+				return err
+			}
+
+			if n != rank {
+				rank = n
+
+				if err := send(&AsyncTaskStatusDTO{Status: "waiting", Rank: n}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (s *asyncTaskStatusService) rank(cmd *AsyncTaskWatchCmd) (int, error) {
+	return waitingTaskRank(s.asynccli, cmd.User, cmd.TaskType)
+}
+
+// rankOfEvent loads the queue once for each event and shares it among the watchers
+func (s *asyncTaskStatusService) rankOfEvent(e *async.TaskEvent, cmd *AsyncTaskWatchCmd) (int, error) {
+	ranks, err := s.ranks.get(e.Seq, cmd.TaskType, func() (map[string]int, error) {
+		v, err := s.asynccli.GetWaitingTaskRanks(waitingTaskSince(), cmd.TaskType)
+		if err != nil && commonrepo.IsErrorResourceNotExists(err) {
+			err = nil
+		}
+
+		return v, err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return ranks[cmd.User.Account()], nil
+}
+
+func (s *asyncTaskStatusService) current(cmd *AsyncTaskWatchCmd) (dto AsyncTaskStatusDTO, err error) {
+	v, err := s.asynccli.GetLastTask(cmd.User, cmd.TaskType)
+	if err != nil {
+		if commonrepo.IsErrorResourceNotExists(err) {
+			err = nil
+		}
+
+		return
+	}
+
+	dto.TaskId = v.Id
+	dto.Status = v.Status.TaskStatus()
+	dto.Error = v.ErrMsg
+
+	if v.Result != nil {
+		dto.Result = v.Result.MetaData()
+	}
+
+	if v.Status.IsWaiting() || v.Status.IsRunning() {
+		dto.Rank, err = s.rank(cmd)
+	}
+
+	return
+}
+
+// waitingTaskRank returns the rank of the task of user in the queue
+func waitingTaskRank(cli async.AsyncTask, user types.Account, taskType []string) (int, error) {
+	rank, err := cli.GetWaitingTaskRank(user, waitingTaskSince(), taskType)
+	if err != nil && !commonrepo.IsErrorResourceNotExists(err) {
+		return 0, err
+	}
+
+	return rank, nil
+}
+
+func isTaskTypeOf(t string, taskType []string) bool {
+	for _, v := range taskType {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rankCache keeps the ranks of the queues at the latest event
+type rankCache struct {
+	lock  sync.Mutex
+	items map[string]*queueRanks // the key is the task types of queue
+}
+
+type queueRanks struct {
+	once  sync.Once
+	seq   uint64
+	ranks map[string]int
+	err   error
+}
+
+func (c *rankCache) get(seq uint64, taskType []string, load func() (map[string]int, error)) (
+	map[string]int, error,
+) {
+	key := strings.Join(taskType, ",")
+
+	c.lock.Lock()
+	item, ok := c.items[key]
+	// the ranks at a later event are newer, so they can be used too
+	if !ok || item.seq < seq {
+		item = &queueRanks{seq: seq}
+		c.items[key] = item
+	}
+	c.lock.Unlock()
+
+	item.once.Do(func() {
+		item.ranks, item.err = load()
+	})
+
+	return item.ranks, item.err
+}
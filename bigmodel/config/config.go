@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/bigmodels"
 	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/messageadapter"
 )
@@ -8,12 +9,14 @@ import (
 type Config struct {
 	bigmodels.Config
 
+	Domain  domain.Config         `json:"domain"`
 	Message messageadapter.Config `json:"message"`
 }
 
 func (cfg *Config) ConfigItems() []interface{} {
 	return []interface{}{
 		&cfg.Config,
+		&cfg.Domain,
 		&cfg.Message,
 	}
 }
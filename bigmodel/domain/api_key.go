@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+const (
+	apiKeyPrefix    = "xh-"
+	apiKeyRandBytes = 24
+	apiKeyHintLen   = 8
+)
+
+// ApiKey is the credential to call the api of a model.
+// Only the hash of the key is saved, the key itself is shown once at creation.
+type ApiKey struct {
+	Id         string
+	User       types.Account
+	Model      ModelName
+	Name       ApiKeyName
+	Hash       string
+	Hint       string // the beginning of the key, it helps the user to identify the key
+	CreatedAt  int64
+	ExpireAt   int64 // 0 means the key never expires
+	RevokedAt  int64
+	LastUsedAt int64
+}
+
+func (k *ApiKey) IsRevoked() bool {
+	return k.RevokedAt > 0
+}
+
+func (k *ApiKey) IsExpired(now int64) bool {
+	return k.ExpireAt > 0 && now >= k.ExpireAt
+}
+
+func (k *ApiKey) IsAvailable(now int64) bool {
+	return !k.IsRevoked() && !k.IsExpired(now)
+}
+
+// NewApiKey generates a key and returns it with the ApiKey which holds the hash of it.
+func NewApiKey(user types.Account, model ModelName, name ApiKeyName, now, expireAt int64) (
+	key string, k ApiKey, err error,
+) {
+	b := make([]byte, apiKeyRandBytes)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+
+	key = apiKeyPrefix + hex.EncodeToString(b)
+
+	k = ApiKey{
+		User:      user,
+		Model:     model,
+		Name:      name,
+		Hash:      HashApiKey(key),
+		Hint:      key[:len(apiKeyPrefix)+apiKeyHintLen],
+		CreatedAt: now,
+		ExpireAt:  expireAt,
+	}
+
+	return
+}
+
+func HashApiKey(key string) string {
+	v := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(v[:])
+}
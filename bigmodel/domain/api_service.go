@@ -10,6 +10,66 @@ type UserApiRecord struct {
 	Enabled   bool
 	ApplyAt   string
 	UpdateAt  string
-	Token     string
 	Version   int
+	Quota     ApiQuota
+
+	// Token is the legacy token issued before the api keys are supported,
+	// it is empty for the api applied after that.
+	Token string
+}
+
+// EffectiveQuota returns the quota of the api, the unset items are the default values.
+func (r *UserApiRecord) EffectiveQuota() ApiQuota {
+	q := r.Quota
+	d := &config.ApiQuota
+
+	if q.Daily <= 0 {
+		q.Daily = d.Daily
+	}
+
+	if q.Monthly <= 0 {
+		q.Monthly = d.Monthly
+	}
+
+	if q.RPM <= 0 {
+		q.RPM = d.RPM
+	}
+
+	return q
+}
+
+// ApiQuota limits the calls of api
+type ApiQuota struct {
+	Daily   int `json:"daily"`
+	Monthly int `json:"monthly"`
+	RPM     int `json:"rpm"` // requests per minute
+}
+
+func (q *ApiQuota) SetDefault() {
+	if q.Daily <= 0 {
+		q.Daily = 1000
+	}
+
+	if q.Monthly <= 0 {
+		q.Monthly = 20000
+	}
+
+	if q.RPM <= 0 {
+		q.RPM = 20
+	}
+}
+
+// ApiUsage is the statistics of the calls of api in an hour which end with the same status code
+type ApiUsage struct {
+	User    types.Account
+	Model   ModelName
+	Date    string
+	Hour    int
+	Status  int // http status code
+	Count   int
+	Latency int64 // the total latency of calls in millisecond
+}
+
+func (u *ApiUsage) IsSucceeded() bool {
+	return u.Status < 400
 }
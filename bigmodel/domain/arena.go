@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"errors"
+	"math"
+
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+const (
+	ArenaTie = "tie"
+
+	arenaInitialRating = 1000
+)
+
+// ArenaBattle is a prompt which is answered by several models side by side.
+// The user votes for the better answer and the ratings of models are updated.
+type ArenaBattle struct {
+	Id        string
+	User      types.Account
+	Prompt    string
+	Models    []string
+	Winner    string // the model which wins or ArenaTie, empty means it has not been voted
+	CreatedAt int64
+	VotedAt   int64
+}
+
+func (b *ArenaBattle) IsVoted() bool {
+	return b.Winner != ""
+}
+
+func (b *ArenaBattle) hasModel(model string) bool {
+	for _, v := range b.Models {
+		if v == model {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Vote sets the winner and returns the result of each pair of models.
+func (b *ArenaBattle) Vote(winner string, t int64) ([]ArenaMatch, error) {
+	if b.IsVoted() {
+		return nil, errors.New("the battle has been voted")
+	}
+
+	if winner != ArenaTie && !b.hasModel(winner) {
+		return nil, errors.New("the winner is not in the battle")
+	}
+
+	b.Winner = winner
+	b.VotedAt = t
+
+	var v []ArenaMatch
+
+	if winner == ArenaTie {
+		for i := range b.Models {
+			for j := i + 1; j < len(b.Models); j++ {
+				v = append(v, ArenaMatch{A: b.Models[i], B: b.Models[j], Tie: true})
+			}
+		}
+
+		return v, nil
+	}
+
+	for _, m := range b.Models {
+		if m != winner {
+			v = append(v, ArenaMatch{A: winner, B: m})
+		}
+	}
+
+	return v, nil
+}
+
+// ArenaMatch is the result of two models, A wins unless it is a tie.
+type ArenaMatch struct {
+	A   string
+	B   string
+	Tie bool
+}
+
+// ArenaRating is the Elo rating of model.
+// Score is the sum of changes, so that it can be updated atomically.
+type ArenaRating struct {
+	Model  string
+	Score  float64
+	Wins   int
+	Losses int
+	Ties   int
+}
+
+func (r *ArenaRating) Rating() float64 {
+	return arenaInitialRating + r.Score
+}
+
+func (r *ArenaRating) Battles() int {
+	return r.Wins + r.Losses + r.Ties
+}
+
+// EloChanges calculates the changes of ratings by the matches of a battle, the changes
+// are in the form of ArenaRating and will be added to the ratings.
+// All the changes are based on the ratings before the battle.
+func EloChanges(ratings map[string]ArenaRating, matches []ArenaMatch, k float64) []ArenaRating {
+	var changes []ArenaRating
+
+	index := map[string]int{}
+	get := func(model string) int {
+		i, ok := index[model]
+		if !ok {
+			i = len(changes)
+			index[model] = i
+			changes = append(changes, ArenaRating{Model: model})
+		}
+
+		return i
+	}
+
+	rating := func(model string) float64 {
+		v := ratings[model]
+
+		return v.Rating()
+	}
+
+	for _, m := range matches {
+		ra, rb := rating(m.A), rating(m.B)
+		ea := 1 / (1 + math.Pow(10, (rb-ra)/400))
+
+		sa := 1.0
+		if m.Tie {
+			sa = 0.5
+		}
+
+		d := k * (sa - ea)
+
+		i, j := get(m.A), get(m.B)
+		changes[i].Score += d
+		changes[j].Score -= d
+	}
+
+	// the model takes part in the battle once no matter how many matches it has
+	for _, m := range matches {
+		a, b := &changes[index[m.A]], &changes[index[m.B]]
+
+		if m.Tie {
+			a.Ties = 1
+			b.Ties = 1
+		} else {
+			a.Wins = 1
+			b.Losses = 1
+		}
+	}
+
+	return changes
+}
@@ -1,6 +1,7 @@
 package async
 
 import (
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
 	asyncrepo "github.com/opensourceways/xihe-server/async-server/domain/repository"
 	commondomain "github.com/opensourceways/xihe-server/common/domain"
 	types "github.com/opensourceways/xihe-server/domain"
@@ -8,5 +9,17 @@ import (
 
 type AsyncTask interface {
 	GetWaitingTaskRank(types.Account, commondomain.Time, []string) (int, error)
+	GetWaitingTaskRanks(commondomain.Time, []string) (map[string]int, error)
 	GetLastFinishedTask(types.Account, []string) (asyncrepo.WuKongResp, error)
+	GetLastTask(types.Account, []string) (asyncrepo.TaskResp, error)
+	CancelTask(types.Account, uint64, []string) error
+}
+
+type AsyncBatch interface {
+	CreateBatch(*asyncdomain.Batch, []asyncdomain.BatchItem) (uint64, error)
+	GetBatch(types.Account, uint64) (asyncrepo.BatchResp, error)
+	ListBatches(types.Account, int) ([]asyncrepo.BatchResp, error)
+	ListBatchItems(types.Account, uint64) ([]asyncrepo.BatchItemResp, error)
+	CancelBatch(types.Account, uint64) error
+	CountPendingItems(types.Account, string) (int, error)
 }
@@ -0,0 +1,20 @@
+package async
+
+// TaskEvent is the change of status of an async task
+type TaskEvent struct {
+	Seq      uint64 // it is set by the hub and increases with each event
+	User     string
+	TaskId   uint64 // it is 0 when the task is just created
+	TaskType string // it is empty if the producer of event doesn't know it
+	Status   string
+	ErrMsg   string
+	Result   map[string]string
+}
+
+// TaskEventHub dispatches the events of async tasks to the watchers
+type TaskEventHub interface {
+	Publish(*TaskEvent)
+
+	// Subscribe returns the channel of events and the function to unsubscribe
+	Subscribe() (<-chan TaskEvent, func())
+}
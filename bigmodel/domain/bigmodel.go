@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"fmt"
+
 	types "github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/utils"
 )
@@ -33,12 +35,73 @@ type WuKongPicture struct {
 	Version   int
 	CreatedAt string
 
+	// Hidden means the public picture is hidden from the gallery by the admin
+	Hidden bool
+
 	WuKongPictureMeta
 }
 
 type WuKongPictureMeta struct {
 	Style string
 	Desc  WuKongPictureDesc
+
+	WuKongPictureInput
+}
+
+// WuKongPictureInput is the input of img2img and inpainting besides the desc
+type WuKongPictureInput struct {
+	// Mode is text-to-image if it is nil
+	Mode WuKongMode
+
+	// Image is the source picture of img2img and inpainting
+	Image OBSPath
+
+	// Mask is the picture which marks the area of source picture to be repainted
+	Mask OBSPath
+}
+
+func NewWuKongPictureInput(mode, image, mask string) (r WuKongPictureInput, err error) {
+	if r.Mode, err = NewWuKongMode(mode); err != nil {
+		return
+	}
+
+	if image != "" {
+		if r.Image, err = NewOBSPath(image); err != nil {
+			return
+		}
+	}
+
+	if mask != "" {
+		if r.Mask, err = NewOBSPath(mask); err != nil {
+			return
+		}
+	}
+
+	err = r.CheckMode()
+
+	return
+}
+
+func (r *WuKongPictureInput) WuKongMode() WuKongMode {
+	if r.Mode == nil {
+		return WuKongModeTxt2Img
+	}
+
+	return r.Mode
+}
+
+func (r *WuKongPictureInput) CheckMode() error {
+	mode := r.WuKongMode()
+
+	if mode.NeedImage() != (r.Image != nil) {
+		return fmt.Errorf("the source picture is invalid for mode of %s", mode.WuKongMode())
+	}
+
+	if mode.NeedMask() != (r.Mask != nil) {
+		return fmt.Errorf("the mask picture is invalid for mode of %s", mode.WuKongMode())
+	}
+
+	return nil
 }
 
 func (r *WuKongPicture) IsOfficial() bool {
@@ -76,6 +139,7 @@ type GenPictureInput struct {
 type BaiChuanInput struct {
 	Text              BaiChuanText
 	Sampling          bool
+	History           []History
 	TopK              TopK
 	TopP              TopP
 	Temperature       Temperature
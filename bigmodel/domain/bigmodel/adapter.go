@@ -0,0 +1,131 @@
+package bigmodel
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+)
+
+// Capabilities describes what an adapter can do
+type Capabilities struct {
+	Text     bool `json:"text"`      // text in and text out
+	Stream   bool `json:"stream"`    // the output can be streamed
+	ImageIn  bool `json:"image_in"`  // accept images as input
+	ImageOut bool `json:"image_out"` // generate images
+}
+
+// Sampling is the parameters of sampling, the adapter uses its default if it is nil
+type Sampling struct {
+	TopK              domain.TopK
+	TopP              domain.TopP
+	Temperature       domain.Temperature
+	RepetitionPenalty domain.RepetitionPenalty
+}
+
+type AdapterRequest struct {
+	Text     string
+	History  [][2]string // pairs of question and answer
+	Images   []string    // links of the input images
+	Sampling *Sampling
+}
+
+type AdapterResponse struct {
+	Text   string
+	Images []string // links of the generated images
+}
+
+// Adapter is the common interface of the model backends
+type Adapter interface {
+	Name() string
+	Capabilities() Capabilities
+
+	// Generate returns the whole output
+	Generate(*AdapterRequest) (AdapterResponse, error)
+
+	// Stream sends the output to the channel piece by piece and ends with StreamDone,
+	// or StreamAuditFailed if the output fails the audit.
+	// It returns once the output starts.
+	Stream(*AdapterRequest, chan string) error
+
+	// IdleEndpoints returns the number of endpoints which can serve now
+	IdleEndpoints() int
+}
+
+// StreamDone is the last message of stream
+const StreamDone = "done"
+
+// StreamAuditFailed is the last message of stream when the output fails the audit,
+// the output received before it should be discarded.
+const StreamAuditFailed = "audit failed"
+
+var (
+	ErrorUnsupported = errors.New("unsupported by the model")
+
+	// ErrorStreamAuditFailed is the error of the stream which ends with StreamAuditFailed
+	ErrorStreamAuditFailed = NewErrorSensitiveInfo(errors.New("the output is sensitive"))
+)
+
+// AdapterRegistry holds the adapters by name
+type AdapterRegistry interface {
+	Register(Adapter) error
+	Get(name string) (Adapter, error)
+	Names() []string
+}
+
+func NewAdapterRegistry() AdapterRegistry {
+	return &adapterRegistry{
+		adapters: map[string]Adapter{},
+	}
+}
+
+type adapterRegistry struct {
+	lock     sync.RWMutex
+	adapters map[string]Adapter
+}
+
+// Register adds the adapter, its name should be a valid model name
+func (r *adapterRegistry) Register(a Adapter) error {
+	name := a.Name()
+	if name == "" {
+		return errors.New("missing name of adapter")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.adapters[name]; ok {
+		return fmt.Errorf("duplicate adapter: %s", name)
+	}
+
+	r.adapters[name] = a
+
+	return nil
+}
+
+func (r *adapterRegistry) Get(name string) (Adapter, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if a, ok := r.adapters[name]; ok {
+		return a, nil
+	}
+
+	return nil, fmt.Errorf("no adapter for model: %s", name)
+}
+
+func (r *adapterRegistry) Names() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	v := make([]string, 0, len(r.adapters))
+	for k := range r.adapters {
+		v = append(v, k)
+	}
+
+	sort.Strings(v)
+
+	return v
+}
@@ -30,3 +30,18 @@ func IsErrorBusySource(err error) bool {
 
 	return ok
 }
+
+// errorInvalidInput
+type errorInvalidInput struct {
+	error
+}
+
+func NewErrorInvalidInput(err error) errorInvalidInput {
+	return errorInvalidInput{err}
+}
+
+func IsErrorInvalidInput(err error) bool {
+	_, ok := err.(errorInvalidInput)
+
+	return ok
+}
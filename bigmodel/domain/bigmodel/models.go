@@ -1,6 +1,7 @@
 package bigmodel
 
 import (
+	"context"
 	"io"
 
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
@@ -20,13 +21,19 @@ type CodeGeexResp struct {
 type BigModel interface {
 	// common
 	GetIdleEndpoint(bid string) (c int, err error)
-	CheckText(content string) error
-	CheckImages(urls []string) error
+	CheckText(model, content string) error
+	CheckImages(model string, urls []string) error
+
+	// Adapters returns the registry of the models which are served by the adapters
+	Adapters() AdapterRegistry
+
+	// the calls which take a context are interrupted when the context is done
 
 	// wukong
 	GetWuKongSampleId() string
 	GenWuKongSampleNums(int) []int
-	GenPicturesByWuKong(types.Account, *domain.WuKongPictureMeta, string) (map[string]string, error)
+	GenPicturesByWuKong(context.Context, types.Account, *domain.WuKongPictureMeta, string) (map[string]string, error)
+	WuKongUploadPicture(f io.Reader, u types.Account, fileName string) (domain.OBSPath, error)
 	DeleteWuKongPicture(string) error
 	GenWuKongPictureLink(p string) (string, error)
 	MoveWuKongPictureToDir(string, string) error
@@ -35,6 +42,11 @@ type BigModel interface {
 	CheckWuKongPicturePublicToLike(types.Account, string) (string, error)
 	CheckWuKongPictureToPublic(types.Account, string) (domain.WuKongPictureMeta, string, error)
 
+	// gallery, the model is one of the gallery models
+	SaveGalleryPicture(model string, user types.Account, src string) (string, error)
+	GenGalleryPictureLink(model, p string) (string, error)
+	DeleteGalleryPicture(model, p string) error
+
 	// taichu
 	DescribePicture(io.Reader, string, int64, string) (string, error)
 	GenPicture(types.Account, string) (string, error)
@@ -49,20 +61,20 @@ type BigModel interface {
 	LuoJiaHF(io.Reader) (string, error)
 
 	// pangu
-	PanGu(string) (string, error)
+	PanGu(context.Context, string) (string, error)
 
 	// codegeex
-	CodeGeex(*CodeGeexReq) (CodeGeexResp, error)
+	CodeGeex(context.Context, *CodeGeexReq) (CodeGeexResp, error)
 
 	// ai detector
 	AIDetector(domain.AIDetectorInput) (bool, error)
 
 	// baichuan2
-	BaiChuan(*domain.BaiChuanInput) (string, string, error)
+	BaiChuan(context.Context, *domain.BaiChuanInput) (string, string, error)
 
 	// glm2
-	GLM2(chan string, *domain.GLM2Input) error
+	GLM2(context.Context, chan string, *domain.GLM2Input) error
 
 	// llama2
-	LLAMA2(chan string, *domain.LLAMA2Input) error
+	LLAMA2(context.Context, chan string, *domain.LLAMA2Input) error
 }
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	types "github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// ChatSession is the conversation of a user with a chat model.
+// The history is saved at server side, so the user can resume it on any device.
+type ChatSession struct {
+	Id        string
+	Owner     types.Account
+	Model     ModelName
+	Title     ChatSessionTitle
+	Rounds    []ChatRound // the oldest round is the first one
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+// ChatRound is a question of user and the answer of model.
+type ChatRound struct {
+	Question  string
+	Answer    string
+	CreatedAt int64
+}
+
+func (s *ChatSession) IsModel(model string) bool {
+	return s.Model != nil && s.Model.ModelName() == model
+}
+
+// History returns the latest rounds whose total length does not exceed max.
+// The rounds are truncated from the oldest one to fit the context window of model.
+func (s *ChatSession) History(max int) []History {
+	n := 0
+	i := len(s.Rounds)
+
+	for ; i > 0; i-- {
+		r := &s.Rounds[i-1]
+
+		n += utils.StrLen(r.Question) + utils.StrLen(r.Answer)
+		if n > max {
+			break
+		}
+	}
+
+	v := make([]History, 0, len(s.Rounds)-i)
+	for _, r := range s.Rounds[i:] {
+		v = append(v, history{r.Question, r.Answer})
+	}
+
+	return v
+}
@@ -0,0 +1,178 @@
+package domain
+
+var config Config
+
+func Init(cfg *Config) {
+	config = *cfg
+}
+
+type Config struct {
+	ApiQuota ApiQuota `json:"api_quota"`
+
+	// MaxApiKeys is the max number of api keys of a user for a model
+	MaxApiKeys int `json:"max_api_keys"`
+
+	ChatSession ChatSessionConfig `json:"chat_session"`
+	Arena       ArenaConfig       `json:"arena"`
+	Batch       BatchConfig       `json:"batch"`
+	Gallery     GalleryConfig     `json:"gallery"`
+
+	// AdapterModels are the models which are served by the adapters
+	// besides the builtin ones. They are the valid model names too.
+	AdapterModels []string `json:"adapter_models"`
+}
+
+func (cfg *Config) SetDefault() {
+	cfg.ApiQuota.SetDefault()
+
+	if cfg.MaxApiKeys <= 0 {
+		cfg.MaxApiKeys = 10
+	}
+
+	cfg.ChatSession.SetDefault()
+	cfg.Arena.SetDefault()
+	cfg.Batch.SetDefault()
+	cfg.Gallery.SetDefault()
+}
+
+func isAdapterModel(v string) bool {
+	for _, item := range config.AdapterModels {
+		if item == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func MaxApiKeys() int {
+	return config.MaxApiKeys
+}
+
+type ChatSessionConfig struct {
+	// MaxSessions is the max number of chat sessions of a user
+	MaxSessions int `json:"max_sessions"`
+
+	// MaxRounds is the max number of rounds which are kept in a session,
+	// the oldest rounds will be dropped.
+	MaxRounds int `json:"max_rounds"`
+
+	// MaxContextLength is the max length of the text including the history
+	// which is sent to the model. The oldest rounds are truncated to fit it.
+	MaxContextLength int `json:"max_context_length"`
+}
+
+func (cfg *ChatSessionConfig) SetDefault() {
+	if cfg.MaxSessions <= 0 {
+		cfg.MaxSessions = 50
+	}
+
+	if cfg.MaxRounds <= 0 {
+		cfg.MaxRounds = 100
+	}
+
+	if cfg.MaxContextLength <= 0 {
+		cfg.MaxContextLength = 3000
+	}
+}
+
+func MaxChatSessions() int {
+	return config.ChatSession.MaxSessions
+}
+
+func MaxChatRounds() int {
+	return config.ChatSession.MaxRounds
+}
+
+func MaxChatContextLength() int {
+	return config.ChatSession.MaxContextLength
+}
+
+type ArenaConfig struct {
+	// MaxModels is the max number of models in a battle
+	MaxModels int `json:"max_models"`
+
+	// EloK is the K-factor of Elo rating
+	EloK float64 `json:"elo_k"`
+}
+
+func (cfg *ArenaConfig) SetDefault() {
+	if cfg.MaxModels <= 0 {
+		cfg.MaxModels = 4
+	}
+
+	if cfg.EloK <= 0 {
+		cfg.EloK = 32
+	}
+}
+
+func MaxArenaModels() int {
+	return config.Arena.MaxModels
+}
+
+func ArenaEloK() float64 {
+	return config.Arena.EloK
+}
+
+type BatchConfig struct {
+	// MaxItems is the max number of lines in the input of a batch
+	MaxItems int `json:"max_items"`
+
+	// MaxFileSize is the max size of the input file, the unit is byte
+	MaxFileSize int64 `json:"max_file_size"`
+
+	// MaxListed is the max number of the latest batches which are listed
+	MaxListed int `json:"max_listed"`
+}
+
+func (cfg *BatchConfig) SetDefault() {
+	if cfg.MaxItems <= 0 {
+		cfg.MaxItems = 1000
+	}
+
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = 10 << 20
+	}
+
+	if cfg.MaxListed <= 0 {
+		cfg.MaxListed = 50
+	}
+}
+
+func MaxBatchItems() int {
+	return config.Batch.MaxItems
+}
+
+func MaxBatchFileSize() int64 {
+	return config.Batch.MaxFileSize
+}
+
+func MaxListedBatches() int {
+	return config.Batch.MaxListed
+}
+
+type GalleryConfig struct {
+	// MaxPictures is the max number of pictures saved by a user
+	MaxPictures int `json:"max_pictures"`
+
+	// MaxLikes is the max number of pictures of others which a user saves to likes
+	MaxLikes int `json:"max_likes"`
+}
+
+func (cfg *GalleryConfig) SetDefault() {
+	if cfg.MaxPictures <= 0 {
+		cfg.MaxPictures = 100
+	}
+
+	if cfg.MaxLikes <= 0 {
+		cfg.MaxLikes = 100
+	}
+}
+
+func MaxGalleryPictures() int {
+	return config.Gallery.MaxPictures
+}
+
+func MaxGalleryLikes() int {
+	return config.Gallery.MaxLikes
+}
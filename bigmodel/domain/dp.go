@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/opensourceways/xihe-server/utils"
@@ -28,10 +29,25 @@ const (
 	langZH = "zh"
 	langEN = "en"
 
-	modelNameWukong = "wukong"
+	wukongModeTxt2Img    = "txt2img"
+	wukongModeImg2Img    = "img2img"
+	wukongModeInpainting = "inpainting"
+
+	modelNameWukong     = "wukong"
+	modelNamePanGu      = "pangu"
+	modelNameLuoJia     = "luojia"
+	modelNameCodeGeex   = "codegeex"
+	modelNameAIDetector = "ai_detector"
+	modelNameBaiChuan   = "baichuan"
+	modelNameGLM2       = "glm2"
+	modelNameLLAMA2     = "llama2"
+
+	maxPromptVariables = 20
 )
 
 var (
+	rePromptVariable = regexp.MustCompile(`{{\s*([A-Za-z_][A-Za-z0-9_]{0,29})\s*}}`)
+
 	BigmodelVQA           = BigmodelType(bigmodelVQA)
 	BigmodelPanGu         = BigmodelType(bigmodelPanGu)
 	BigmodelLuoJia        = BigmodelType(bigmodelLuoJia)
@@ -48,6 +64,10 @@ var (
 	BigmodelGLM2          = BigmodelType(bigmodelGLM2)
 	BigmodelLLAMA2        = BigmodelType(bigmodelLLAMA2)
 
+	WuKongModeTxt2Img    = wukongMode(wukongModeTxt2Img)
+	WuKongModeImg2Img    = wukongMode(wukongModeImg2Img)
+	WuKongModeInpainting = wukongMode(wukongModeInpainting)
+
 	wukongPictureLevelMap = map[string]int{
 		"official": 2,
 		"good":     1,
@@ -110,6 +130,51 @@ func (r wukongPictureDesc) WuKongPictureDesc() string {
 	return string(r)
 }
 
+// WuKongMode is how the pictures are generated, text-to-image by default
+type WuKongMode interface {
+	WuKongMode() string
+	IsTxt2Img() bool
+
+	// NeedImage checks whether the source picture is required
+	NeedImage() bool
+
+	// NeedMask checks whether the mask of the source picture is required
+	NeedMask() bool
+}
+
+func NewWuKongMode(v string) (WuKongMode, error) {
+	if v == "" {
+		return WuKongModeTxt2Img, nil
+	}
+
+	b := v == wukongModeTxt2Img ||
+		v == wukongModeImg2Img ||
+		v == wukongModeInpainting
+	if !b {
+		return nil, errors.New("invalid wukong mode")
+	}
+
+	return wukongMode(v), nil
+}
+
+type wukongMode string
+
+func (r wukongMode) WuKongMode() string {
+	return string(r)
+}
+
+func (r wukongMode) IsTxt2Img() bool {
+	return string(r) == wukongModeTxt2Img
+}
+
+func (r wukongMode) NeedImage() bool {
+	return string(r) == wukongModeImg2Img || string(r) == wukongModeInpainting
+}
+
+func (r wukongMode) NeedMask() bool {
+	return string(r) == wukongModeInpainting
+}
+
 // wukong level
 type WuKongPictureLevel interface {
 	WuKongPictureLevel() string
@@ -266,8 +331,15 @@ type ModelName interface {
 }
 
 func NewModelName(v string) (ModelName, error) {
-	b := v == modelNameWukong
-	if !b {
+	b := v == modelNameWukong ||
+		v == modelNamePanGu ||
+		v == modelNameLuoJia ||
+		v == modelNameCodeGeex ||
+		v == modelNameAIDetector ||
+		v == modelNameBaiChuan ||
+		v == modelNameGLM2 ||
+		v == modelNameLLAMA2
+	if !b && !isAdapterModel(v) {
 		return nil, errors.New("invalid model name")
 	}
 	return modelName(v), nil
@@ -279,6 +351,202 @@ func (m modelName) ModelName() string {
 	return string(m)
 }
 
+// Api Key Name
+type ApiKeyName interface {
+	ApiKeyName() string
+}
+
+func NewApiKeyName(v string) (ApiKeyName, error) {
+	v = utils.XSSFilter(strings.TrimSpace(v))
+
+	if v == "" || utils.StrLen(v) > 30 {
+		return nil, errors.New("invalid api key name")
+	}
+
+	return apiKeyName(v), nil
+}
+
+type apiKeyName string
+
+func (r apiKeyName) ApiKeyName() string {
+	return string(r)
+}
+
+// Chat Session Title
+type ChatSessionTitle interface {
+	ChatSessionTitle() string
+}
+
+func NewChatSessionTitle(v string) (ChatSessionTitle, error) {
+	v = utils.XSSFilter(strings.TrimSpace(v))
+
+	if v == "" || utils.StrLen(v) > 50 {
+		return nil, errors.New("invalid chat session title")
+	}
+
+	return chatSessionTitle(v), nil
+}
+
+type chatSessionTitle string
+
+func (r chatSessionTitle) ChatSessionTitle() string {
+	return string(r)
+}
+
+// Prompt Model is the model which the prompt template can be used for.
+type PromptModel interface {
+	PromptModel() string
+}
+
+func NewPromptModel(v string) (PromptModel, error) {
+	b := v == modelNamePanGu ||
+		v == modelNameGLM2 ||
+		v == modelNameLLAMA2 ||
+		v == modelNameBaiChuan ||
+		v == modelNameCodeGeex
+	if !b {
+		return nil, errors.New("invalid prompt model")
+	}
+
+	return promptModel(v), nil
+}
+
+type promptModel string
+
+func (r promptModel) PromptModel() string {
+	return string(r)
+}
+
+// Prompt Title
+type PromptTitle interface {
+	PromptTitle() string
+}
+
+func NewPromptTitle(v string) (PromptTitle, error) {
+	v = utils.XSSFilter(strings.TrimSpace(v))
+
+	if v == "" || utils.StrLen(v) > 50 {
+		return nil, errors.New("invalid prompt title")
+	}
+
+	return promptTitle(v), nil
+}
+
+type promptTitle string
+
+func (r promptTitle) PromptTitle() string {
+	return string(r)
+}
+
+// Prompt Desc
+type PromptDesc interface {
+	PromptDesc() string
+}
+
+func NewPromptDesc(v string) (PromptDesc, error) {
+	v = utils.XSSFilter(strings.TrimSpace(v))
+
+	if utils.StrLen(v) > 200 {
+		return nil, errors.New("invalid prompt desc")
+	}
+
+	return promptDesc(v), nil
+}
+
+type promptDesc string
+
+func (r promptDesc) PromptDesc() string {
+	return string(r)
+}
+
+// Prompt Content is the text of template, the variable in it is like {{name}}.
+type PromptContent interface {
+	PromptContent() string
+	Variables() []string
+}
+
+func NewPromptContent(v string) (PromptContent, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, errors.New("no prompt content")
+	}
+
+	if max := 3000; utils.StrLen(v) > max { // TODO: to config
+		return nil, errors.New("invalid prompt content")
+	}
+
+	if n := len(promptContent(v).Variables()); n > maxPromptVariables {
+		return nil, fmt.Errorf("too many variables, max is %d", maxPromptVariables)
+	}
+
+	return promptContent(v), nil
+}
+
+type promptContent string
+
+func (r promptContent) PromptContent() string {
+	return string(r)
+}
+
+// Variables returns the distinct variables in the order of appearance
+func (r promptContent) Variables() []string {
+	m := rePromptVariable.FindAllStringSubmatch(string(r), -1)
+
+	v := make([]string, 0, len(m))
+	seen := map[string]bool{}
+
+	for _, item := range m {
+		if name := item[1]; !seen[name] {
+			seen[name] = true
+			v = append(v, name)
+		}
+	}
+
+	return v
+}
+
+// Gallery Model is the image-producing model whose pictures can be saved to the gallery.
+type GalleryModel interface {
+	GalleryModel() string
+}
+
+func NewGalleryModel(v string) (GalleryModel, error) {
+	b := v == bigmodelWuKong ||
+		v == bigmodelLuoJia ||
+		v == bigmodelVQA
+	if !b {
+		return nil, errors.New("invalid gallery model")
+	}
+
+	return galleryModel(v), nil
+}
+
+type galleryModel string
+
+func (r galleryModel) GalleryModel() string {
+	return string(r)
+}
+
+// Gallery Desc
+type GalleryDesc interface {
+	GalleryDesc() string
+}
+
+func NewGalleryDesc(v string) (GalleryDesc, error) {
+	v = utils.XSSFilter(strings.TrimSpace(v))
+
+	if utils.StrLen(v) > 200 {
+		return nil, errors.New("invalid gallery desc")
+	}
+
+	return galleryDesc(v), nil
+}
+
+type galleryDesc string
+
+func (r galleryDesc) GalleryDesc() string {
+	return string(r)
+}
+
 // baichuan text
 type BaiChuanText interface {
 	BaiChuanText() string
@@ -5,27 +5,32 @@ import (
 )
 
 type WuKongInferenceStartEvent struct {
-	Account types.Account
-	Desc    WuKongPictureDesc
-	Style   string
-	EsStyle string
+	Account  types.Account
+	Desc     WuKongPictureDesc
+	Style    string
+	Input    WuKongPictureInput
+	EsStyle  string
+	Priority int
 }
 
 type WuKongInferenceErrorEvent struct {
-	Account types.Account
-	TaskId  uint64
-	ErrMsg  string
+	Account  types.Account
+	TaskId   uint64
+	TaskType string
+	ErrMsg   string
 }
 
 type WuKongAsyncTaskStartEvent struct {
-	Account types.Account
-	TaskId  uint64
+	Account  types.Account
+	TaskId   uint64
+	TaskType string
 }
 
 type WuKongAsyncInferenceFinishEvent struct {
-	Account types.Account
-	TaskId  uint64
-	Links   map[string]string
+	Account  types.Account
+	TaskId   uint64
+	TaskType string
+	Links    map[string]string
 }
 
 type BigModelStartedEvent struct {
@@ -45,3 +50,41 @@ type WuKongPicturePublicizedEvent struct {
 type WuKongPictureLikedEvent struct {
 	Account types.Account
 }
+
+// async task
+type AsyncTaskCreatedEvent struct {
+	Account  types.Account
+	TaskType string
+	Payload  map[string]string
+	Priority int
+}
+
+type AsyncTaskStartedEvent struct {
+	Account  types.Account
+	TaskId   uint64
+	TaskType string
+}
+
+type AsyncTaskFinishedEvent struct {
+	Account  types.Account
+	TaskId   uint64
+	TaskType string
+	Result   map[string]string
+}
+
+type AsyncTaskFailedEvent struct {
+	Account  types.Account
+	TaskId   uint64
+	TaskType string
+	ErrMsg   string
+}
+
+// ApiCalledEvent is a call of the api of model which is not made by the gateway,
+// such as the item of batch.
+type ApiCalledEvent struct {
+	Account types.Account
+	Model   string
+	Status  int
+	Latency int64 // millisecond
+	At      int64
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+// GalleryPicture is a picture generated or used by an image model which is saved
+// by the owner. It can be published to the gallery, so that the other users can
+// save it to their likes, digg or report it.
+type GalleryPicture struct {
+	Id        string
+	Owner     types.Account
+	Model     GalleryModel
+	Path      string // the obs path of picture which is saved in the bucket of model
+	Desc      GalleryDesc
+	Public    bool
+	Hidden    bool     // it is hidden from the gallery by the admin after it is reported
+	Likes     []string // the users who save it to their likes
+	Diggs     []string // the users who digg it
+	DiggCount int
+	CreatedAt int64
+}
+
+func (p *GalleryPicture) IsOwner(u types.Account) bool {
+	return p.Owner != nil && u != nil && p.Owner.Account() == u.Account()
+}
+
+// IsPublished checks whether it can be seen in the gallery
+func (p *GalleryPicture) IsPublished() bool {
+	return p.Public && !p.Hidden
+}
+
+// IsVisible checks whether the user can see the picture
+func (p *GalleryPicture) IsVisible(u types.Account) bool {
+	return p.IsPublished() || p.IsOwner(u)
+}
+
+func (p *GalleryPicture) IsLikedBy(u types.Account) bool {
+	return u != nil && hasAccount(p.Likes, u.Account())
+}
+
+func (p *GalleryPicture) IsDiggedBy(u types.Account) bool {
+	return u != nil && hasAccount(p.Diggs, u.Account())
+}
+
+func hasAccount(v []string, a string) bool {
+	for _, item := range v {
+		if item == a {
+			return true
+		}
+	}
+
+	return false
+}
+
+type GalleryListOption struct {
+	Model        GalleryModel // all the models if nil
+	SortByDigg   bool         // sort by the created time if false
+	CountPerPage int
+	PageNum      int
+}
+
+type GalleryPictureList struct {
+	Total    int
+	Pictures []GalleryPicture
+}
@@ -16,6 +16,15 @@ type MessageProducer interface {
 	SendWuKongPicturePublicized(*domain.WuKongPicturePublicizedEvent) error
 	SendWuKongPictureLiked(*domain.WuKongPictureLikedEvent) error
 
+	// async task
+	SendAsyncTaskCreated(*domain.AsyncTaskCreatedEvent) error
+	SendAsyncTaskStarted(*domain.AsyncTaskStartedEvent) error
+	SendAsyncTaskFinished(*domain.AsyncTaskFinishedEvent) error
+	SendAsyncTaskFailed(*domain.AsyncTaskFailedEvent) error
+
+	// api
+	SendApiCalled(*domain.ApiCalledEvent) error
+
 	// common
 	SendBigModelStarted(*domain.BigModelStartedEvent) error
 	SendBigModelFinished(*domain.BigModelFinishedEvent) error
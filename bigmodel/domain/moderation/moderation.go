@@ -0,0 +1,37 @@
+package moderation
+
+const (
+	KindText  = "text"
+	KindImage = "image"
+)
+
+// Result is the conclusion of a provider
+type Result struct {
+	Blocked  bool
+	Provider string
+	Label    string // the category of the content, such as porn, ad
+	Reason   string // what hits the rule, such as the keyword
+}
+
+// Moderation is the provider which checks the content
+type Moderation interface {
+	Name() string
+	CheckText(content string) (Result, error)
+	CheckImages(urls []string) (Result, error)
+}
+
+// AuditRecord records the content which is blocked and why
+type AuditRecord struct {
+	Model     string
+	Kind      string
+	Content   string
+	Provider  string
+	Label     string
+	Reason    string
+	CreatedAt int64
+}
+
+// Auditor saves the audit records, it must not block the caller
+type Auditor interface {
+	Audit(*AuditRecord)
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+// PromptTemplate is the prompt with variables which can be reused for a text model.
+// It can be published to the gallery, so that other users can like or fork it.
+type PromptTemplate struct {
+	Id         string
+	Owner      types.Account
+	Model      PromptModel
+	Title      PromptTitle
+	Desc       PromptDesc
+	Content    PromptContent
+	Public     bool
+	Likes      []string // the users who like it
+	LikeCount  int
+	ForkCount  int
+	ForkedFrom string // the id of template which it is forked from
+	CreatedAt  int64
+	UpdatedAt  int64
+}
+
+func (t *PromptTemplate) IsOwner(u types.Account) bool {
+	return t.Owner != nil && u != nil && t.Owner.Account() == u.Account()
+}
+
+// IsVisible checks whether the user can see the template
+func (t *PromptTemplate) IsVisible(u types.Account) bool {
+	return t.Public || t.IsOwner(u)
+}
+
+func (t *PromptTemplate) IsLikedBy(u types.Account) bool {
+	if u == nil {
+		return false
+	}
+
+	for _, v := range t.Likes {
+		if v == u.Account() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Instantiate replaces the variables of template with the values.
+// All the variables must be set.
+func (t *PromptTemplate) Instantiate(values map[string]string) (string, error) {
+	for _, name := range t.Content.Variables() {
+		if _, ok := values[name]; !ok {
+			return "", fmt.Errorf("missing the value of variable: %s", name)
+		}
+	}
+
+	s := rePromptVariable.ReplaceAllStringFunc(t.Content.PromptContent(), func(v string) string {
+		return values[rePromptVariable.FindStringSubmatch(v)[1]]
+	})
+
+	return s, nil
+}
+
+// Fork copies the template for the user.
+func (t *PromptTemplate) Fork(u types.Account, now int64) PromptTemplate {
+	return PromptTemplate{
+		Owner:      u,
+		Model:      t.Model,
+		Title:      t.Title,
+		Desc:       t.Desc,
+		Content:    t.Content,
+		ForkedFrom: t.Id,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+type PromptTemplateListOption struct {
+	Model        PromptModel // all the models if nil
+	SortByLikes  bool        // sort by the updated time if false
+	CountPerPage int
+	PageNum      int
+}
+
+type PromptTemplateList struct {
+	Total     int
+	Templates []PromptTemplate
+}
@@ -0,0 +1,63 @@
+package quota
+
+import "time"
+
+const (
+	WindowMinute = "minute"
+	WindowDay    = "day"
+	WindowMonth  = "month"
+)
+
+// Limit is the max count of calls in a window
+type Limit struct {
+	Window string
+	Max    int
+}
+
+// Result is the state of the window which is exceeded,
+// or the one which has the least remaining if all the limits are satisfied.
+type Result struct {
+	Allowed   bool
+	Window    string
+	Limit     int
+	Remaining int
+	ResetAt   int64
+}
+
+type Limiter interface {
+	// Take consumes one call in all the windows of the key,
+	// nothing is consumed if any of the limits is exceeded.
+	Take(key string, limits []Limit, now time.Time) (Result, error)
+}
+
+// WindowEnd returns the time when the window which now is in ends
+func WindowEnd(window string, now time.Time) time.Time {
+	switch window {
+	case WindowMinute:
+		return now.Truncate(time.Minute).Add(time.Minute)
+
+	case WindowDay:
+		y, m, d := now.Date()
+
+		return time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+
+	default:
+		y, m, _ := now.Date()
+
+		return time.Date(y, m+1, 1, 0, 0, 0, 0, now.Location())
+	}
+}
+
+// WindowId identifies the window which now is in
+func WindowId(window string, now time.Time) string {
+	switch window {
+	case WindowMinute:
+		return now.Format("200601021504")
+
+	case WindowDay:
+		return now.Format("20060102")
+
+	default:
+		return now.Format("200601")
+	}
+}
@@ -0,0 +1,78 @@
+package quota
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+
+	cases := []struct {
+		name   string
+		window string
+		now    time.Time
+		id     string
+		end    time.Time
+	}{
+		{
+			name:   "minute",
+			window: WindowMinute,
+			now:    time.Date(2023, 5, 6, 10, 20, 30, 0, time.UTC),
+			id:     "202305061020",
+			end:    time.Date(2023, 5, 6, 10, 21, 0, 0, time.UTC),
+		},
+		{
+			name:   "minute at the beginning",
+			window: WindowMinute,
+			now:    time.Date(2023, 5, 6, 10, 20, 0, 0, time.UTC),
+			id:     "202305061020",
+			end:    time.Date(2023, 5, 6, 10, 21, 0, 0, time.UTC),
+		},
+		{
+			name:   "day",
+			window: WindowDay,
+			now:    time.Date(2023, 5, 6, 23, 59, 59, 0, time.UTC),
+			id:     "20230506",
+			end:    time.Date(2023, 5, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "day at the end of month",
+			window: WindowDay,
+			now:    time.Date(2023, 2, 28, 12, 0, 0, 0, time.UTC),
+			id:     "20230228",
+			end:    time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "day in location",
+			window: WindowDay,
+			now:    time.Date(2023, 5, 6, 20, 0, 0, 0, time.UTC).In(cst),
+			id:     "20230507",
+			end:    time.Date(2023, 5, 8, 0, 0, 0, 0, cst),
+		},
+		{
+			name:   "month",
+			window: WindowMonth,
+			now:    time.Date(2023, 5, 31, 10, 0, 0, 0, time.UTC),
+			id:     "202305",
+			end:    time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "month at the end of year",
+			window: WindowMonth,
+			now:    time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+			id:     "202312",
+			end:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		if got := WindowId(c.window, c.now); got != c.id {
+			t.Errorf("%s: expect id %s, got %s", c.name, c.id, got)
+		}
+
+		if got := WindowEnd(c.window, c.now); !got.Equal(c.end) {
+			t.Errorf("%s: expect end %v, got %v", c.name, c.end, got)
+		}
+	}
+}
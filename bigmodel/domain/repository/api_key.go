@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type ApiKey interface {
+	AddApiKey(*domain.ApiKey) (string, error)
+	GetApiKeyByHash(string) (domain.ApiKey, error)
+	ListApiKeys(types.Account, domain.ModelName) ([]domain.ApiKey, error)
+	RevokeApiKey(user types.Account, model domain.ModelName, id string, t int64) error
+	UpdateLastUsed(id string, t int64) error
+}
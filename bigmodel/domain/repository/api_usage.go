@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type ApiUsageListOption struct {
+	Model domain.ModelName // all the models if it is nil
+	Start string           // the first date, included
+	End   string           // the last date, included
+}
+
+type ApiUsage interface {
+	IncUsage(*domain.ApiUsage) error
+	ListUsage(types.Account, *ApiUsageListOption) ([]domain.ApiUsage, error)
+}
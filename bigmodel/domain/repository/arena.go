@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type ArenaBattle interface {
+	AddBattle(*domain.ArenaBattle) (string, error)
+	GetBattle(user types.Account, id string) (domain.ArenaBattle, error)
+
+	// SaveVote saves the winner only if the battle has not been voted
+	SaveVote(*domain.ArenaBattle) error
+}
+
+type ArenaRating interface {
+	// ListRatings returns the ratings of models, all the models if empty,
+	// the highest rating is the first one.
+	ListRatings(models []string) ([]domain.ArenaRating, error)
+
+	// AddChanges adds the changes to the ratings
+	AddChanges([]domain.ArenaRating) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type ChatSession interface {
+	AddSession(*domain.ChatSession) (string, error)
+	GetSession(owner types.Account, id string) (domain.ChatSession, error)
+
+	// ListSessions returns the sessions without rounds, the latest updated is the first one
+	ListSessions(types.Account) ([]domain.ChatSession, error)
+	CountSessions(types.Account) (int, error)
+
+	RenameSession(owner types.Account, id string, title domain.ChatSessionTitle, t int64) error
+	DeleteSession(owner types.Account, id string) error
+
+	// AddRound appends the round and keeps the latest ones only
+	AddRound(owner types.Account, id string, r *domain.ChatRound, keep int) error
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type GalleryPicture interface {
+	AddPicture(*domain.GalleryPicture) (string, error)
+	GetPicture(id string) (domain.GalleryPicture, error)
+	DeletePicture(owner types.Account, id string) error
+	UpdatePublic(owner types.Account, id string, public bool) error
+
+	// ListPictures and ListLikes list all the models if model is nil
+	ListPictures(owner types.Account, model domain.GalleryModel) ([]domain.GalleryPicture, error)
+	ListLikes(user types.Account, model domain.GalleryModel) ([]domain.GalleryPicture, error)
+	ListPublicPictures(*domain.GalleryListOption) (domain.GalleryPictureList, error)
+
+	CountPictures(owner types.Account) (int, error)
+	CountLikes(user types.Account) (int, error)
+
+	AddLike(id string, user types.Account) error
+	RemoveLike(id string, user types.Account) error
+
+	// AddDigg and RemoveDigg return the count of diggs after the change
+	AddDigg(id string, user types.Account) (int, error)
+	RemoveDigg(id string, user types.Account) (int, error)
+
+	UpdateHidden(id string, hidden bool) error
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+
+type ModerationAudit interface {
+	AddAudit(*moderation.AuditRecord) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+type PromptTemplate interface {
+	AddTemplate(*domain.PromptTemplate) (string, error)
+	GetTemplate(id string) (domain.PromptTemplate, error)
+	ListTemplates(owner types.Account) ([]domain.PromptTemplate, error)
+	ListPublicTemplates(*domain.PromptTemplateListOption) (domain.PromptTemplateList, error)
+
+	// UpdateTemplate saves the title, desc, content and public of template
+	UpdateTemplate(*domain.PromptTemplate) error
+	DeleteTemplate(owner types.Account, id string) error
+
+	// AddLike and RemoveLike return the count of likes after the change
+	AddLike(id string, user types.Account) (int, error)
+	RemoveLike(id string, user types.Account) (int, error)
+
+	IncreaseForkCount(id string) error
+}
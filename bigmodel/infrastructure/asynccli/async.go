@@ -20,6 +20,18 @@ func (impl *asyncImpl) GetWaitingTaskRank(user types.Account, time commondomain.
 	return impl.srv.GetWaitingTaskRank(user, time, taskType)
 }
 
+func (impl *asyncImpl) GetWaitingTaskRanks(time commondomain.Time, taskType []string) (map[string]int, error) {
+	return impl.srv.GetWaitingTaskRanks(time, taskType)
+}
+
 func (impl *asyncImpl) GetLastFinishedTask(user types.Account, taskType []string) (resp asyncrepo.WuKongResp, err error) {
 	return impl.srv.GetLastFinishedTask(user, taskType)
 }
+
+func (impl *asyncImpl) GetLastTask(user types.Account, taskType []string) (resp asyncrepo.TaskResp, err error) {
+	return impl.srv.GetLastTask(user, taskType)
+}
+
+func (impl *asyncImpl) CancelTask(user types.Account, id uint64, taskType []string) error {
+	return impl.srv.CancelTask(user, id, taskType)
+}
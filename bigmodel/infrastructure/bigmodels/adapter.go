@@ -0,0 +1,457 @@
+package bigmodels
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	libutils "github.com/opensourceways/community-robot-lib/utils"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+const (
+	// adapterKindText calls the endpoint which returns the whole text,
+	// the protocol is the same as baichuan.
+	adapterKindText = "text"
+
+	// adapterKindStreamText calls the endpoint which returns the text by stream,
+	// the protocol is the same as glm2 and llama2.
+	adapterKindStreamText = "stream_text"
+
+	adapterAuthCloud = "cloud"
+
+	streamStatusDone = "DONE"
+)
+
+// AdapterConfig configures a model which is served by the adapter of kind.
+type AdapterConfig struct {
+	Name      string         `json:"name"        required:"true"`
+	Kind      string         `json:"kind"        required:"true"`
+	Endpoints string         `json:"endpoints"   required:"true"`
+	Auth      string         `json:"auth"`
+	Sampling  SamplingConfig `json:"sampling"`
+
+	// MaxInputLength is the max length of the input text including the history
+	MaxInputLength int  `json:"max_input_length"`
+	SkipAudit      bool `json:"skip_audit"`
+}
+
+func (cfg *AdapterConfig) setDefault() {
+	cfg.Sampling.setDefault()
+
+	if cfg.MaxInputLength <= 0 {
+		cfg.MaxInputLength = 3000
+	}
+}
+
+func (cfg *AdapterConfig) validate() error {
+	if cfg.Kind != adapterKindText && cfg.Kind != adapterKindStreamText {
+		return fmt.Errorf("invalid kind of adapter %s", cfg.Name)
+	}
+
+	if cfg.Auth != "" && cfg.Auth != adapterAuthCloud {
+		return fmt.Errorf("invalid auth of adapter %s", cfg.Name)
+	}
+
+	_, err := (&Endpoints{}).parse(cfg.Endpoints)
+
+	return err
+}
+
+type SamplingConfig struct {
+	TopK              int     `json:"top_k"`
+	TopP              float64 `json:"top_p"`
+	Temperature       float64 `json:"temperature"`
+	RepetitionPenalty float64 `json:"repetition_penalty"`
+}
+
+func (cfg *SamplingConfig) setDefault() {
+	if cfg.TopK <= 0 {
+		cfg.TopK = 5
+	}
+
+	if cfg.TopP <= 0 {
+		cfg.TopP = 0.85
+	}
+
+	if cfg.Temperature <= 0 {
+		cfg.Temperature = 0.3
+	}
+
+	if cfg.RepetitionPenalty <= 0 {
+		cfg.RepetitionPenalty = 1.05
+	}
+}
+
+// isBuiltinAdapter returns true if the model has its own endpoints config,
+// so it can't be configured as an adapter again.
+func isBuiltinAdapter(name string) bool {
+	for _, item := range builtinAdapters(&Config{}) {
+		if item.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// builtinAdapters are the adapters of the models which have their own endpoints config
+func builtinAdapters(cfg *Config) []AdapterConfig {
+	v := []AdapterConfig{
+		{
+			Name:      "glm2",
+			Kind:      adapterKindStreamText,
+			Endpoints: cfg.Endpoints.GLM2,
+			Auth:      adapterAuthCloud,
+		},
+		{
+			Name:      "llama2",
+			Kind:      adapterKindStreamText,
+			Endpoints: cfg.Endpoints.LLAMA2,
+			Auth:      adapterAuthCloud,
+			Sampling: SamplingConfig{
+				TopK:              3,
+				TopP:              1,
+				Temperature:       1,
+				RepetitionPenalty: 1,
+			},
+		},
+		{
+			Name:      "baichuan",
+			Kind:      adapterKindText,
+			Endpoints: cfg.Endpoints.BaiChuan,
+			Auth:      adapterAuthCloud,
+		},
+	}
+
+	for i := range v {
+		v[i].setDefault()
+	}
+
+	return v
+}
+
+func (s *service) initAdapters(cfg *Config) error {
+	s.adapters = bigmodel.NewAdapterRegistry()
+
+	// the builtin adapters share the endpoints with the apis of them
+	shared := map[string]*endpointPool{
+		"glm2":     s.glm2Info.endpoints,
+		"llama2":   s.llama2Info.endpoints,
+		"baichuan": s.baichuanInfo.endpoints,
+	}
+
+	items := append(builtinAdapters(cfg), cfg.Adapters...)
+
+	for i := range items {
+		item := &items[i]
+
+		if _, err := domain.NewModelName(item.Name); err != nil {
+			return fmt.Errorf("adapter %s is not a model of domain", item.Name)
+		}
+
+		ec, ok := shared[item.Name]
+		if !ok {
+			var err error
+			if ec, err = s.pools.add(item.Name, item.Endpoints); err != nil {
+				return err
+			}
+		}
+
+		a := textAdapter{s: s, cfg: *item, endpoints: ec}
+
+		var err error
+		if item.Kind == adapterKindStreamText {
+			err = s.adapters.Register(&streamTextAdapter{a})
+		} else {
+			err = s.adapters.Register(&a)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) Adapters() bigmodel.AdapterRegistry {
+	return s.adapters
+}
+
+// textAdapter
+type textAdapterRequest struct {
+	Inputs            string      `json:"inputs"`
+	History           [][2]string `json:"history,omitempty"`
+	Sampling          bool        `json:"sampling"`
+	TopK              int         `json:"top_k"`
+	TopP              float64     `json:"top_p"`
+	Temperature       float64     `json:"temperature"`
+	RepetitionPenalty float64     `json:"repetition_penalty"`
+}
+
+type textAdapter struct {
+	s         *service
+	cfg       AdapterConfig
+	endpoints *endpointPool
+}
+
+func (a *textAdapter) Name() string {
+	return a.cfg.Name
+}
+
+func (a *textAdapter) Capabilities() bigmodel.Capabilities {
+	return bigmodel.Capabilities{Text: true}
+}
+
+func (a *textAdapter) IdleEndpoints() int {
+	return a.endpoints.idle()
+}
+
+func (a *textAdapter) Generate(req *bigmodel.AdapterRequest) (r bigmodel.AdapterResponse, err error) {
+	if err = a.checkInput(req); err != nil {
+		return
+	}
+
+	var resp baichuanResponse
+
+	err = a.endpoints.do(func(e string) error {
+		hr, err := a.newRequest(e, req)
+		if err != nil {
+			return err
+		}
+
+		_, err = a.s.hc.ForwardTo(hr, &resp)
+
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	if resp.Code != http.StatusOK || len(resp.Result) == 0 || len(resp.Result[0].TextGenerationText) == 0 {
+		err = fmt.Errorf("bigmodel %s inference generation error", a.cfg.Name)
+
+		return
+	}
+
+	r.Text = resp.getText()
+
+	err = a.checkOutput(r.Text)
+
+	return
+}
+
+func (a *textAdapter) Stream(req *bigmodel.AdapterRequest, ch chan string) error {
+	r, err := a.Generate(req)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ch <- r.Text
+		ch <- bigmodel.StreamDone
+	}()
+
+	return nil
+}
+
+func (a *textAdapter) checkInput(req *bigmodel.AdapterRequest) error {
+	if len(req.Images) > 0 {
+		return bigmodel.ErrorUnsupported
+	}
+
+	n := utils.StrLen(req.Text)
+	for i := range req.History {
+		n += utils.StrLen(req.History[i][0]) + utils.StrLen(req.History[i][1])
+	}
+
+	if req.Text == "" || n > a.cfg.MaxInputLength {
+		return bigmodel.NewErrorInvalidInput(
+			fmt.Errorf("the length of input should be between 1 and %d", a.cfg.MaxInputLength),
+		)
+	}
+
+	if a.cfg.SkipAudit {
+		return nil
+	}
+
+	return a.s.check.CheckText(a.cfg.Name, req.Text)
+}
+
+func (a *textAdapter) checkOutput(text string) error {
+	if a.cfg.SkipAudit || text == "" {
+		return nil
+	}
+
+	return a.s.check.CheckText(a.cfg.Name, text)
+}
+
+func (a *textAdapter) newRequest(endpoint string, req *bigmodel.AdapterRequest) (*http.Request, error) {
+	body, err := libutils.JsonMarshal(a.toRequest(req))
+	if err != nil {
+		return nil, err
+	}
+
+	hr, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	hr.Header.Set("Content-Type", "application/json")
+
+	if a.cfg.Auth == adapterAuthCloud {
+		t, err := genToken(&a.s.wukongInfo.cfg.CloudConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		hr.Header.Set("X-Auth-Token", t)
+	}
+
+	return hr, nil
+}
+
+func (a *textAdapter) toRequest(req *bigmodel.AdapterRequest) *textAdapterRequest {
+	d := &a.cfg.Sampling
+
+	r := &textAdapterRequest{
+		Inputs:            req.Text,
+		TopK:              d.TopK,
+		TopP:              d.TopP,
+		Temperature:       d.Temperature,
+		RepetitionPenalty: d.RepetitionPenalty,
+	}
+
+	// only the stream protocol accepts the history, it is put
+	// in front of the input for the others.
+	if a.cfg.Kind == adapterKindStreamText {
+		r.History = req.History
+	} else {
+		r.Inputs = historyToText(req.History, req.Text)
+	}
+
+	p := req.Sampling
+	if p == nil {
+		return r
+	}
+
+	r.Sampling = true
+
+	if p.TopK != nil {
+		r.TopK = p.TopK.TopK()
+	}
+
+	if p.TopP != nil {
+		r.TopP = p.TopP.TopP()
+	}
+
+	if p.Temperature != nil {
+		r.Temperature = p.Temperature.Temperature()
+	}
+
+	if p.RepetitionPenalty != nil {
+		r.RepetitionPenalty = p.RepetitionPenalty.RepetitionPenalty()
+	}
+
+	return r
+}
+
+// historyToText joins the history and the question as the whole input
+// of the model which can't accept the history.
+func historyToText(history [][2]string, text string) string {
+	if len(history) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	for i := range history {
+		b.WriteString(history[i][0])
+		b.WriteString("\n")
+		b.WriteString(history[i][1])
+		b.WriteString("\n")
+	}
+
+	b.WriteString(text)
+
+	return b.String()
+}
+
+// streamTextAdapter
+type streamTextAdapter struct {
+	textAdapter
+}
+
+func (a *streamTextAdapter) Capabilities() bigmodel.Capabilities {
+	return bigmodel.Capabilities{Text: true, Stream: true}
+}
+
+func (a *streamTextAdapter) Generate(req *bigmodel.AdapterRequest) (r bigmodel.AdapterResponse, err error) {
+	ch := make(chan string)
+	if err = a.Stream(req, ch); err != nil {
+		return
+	}
+
+	// the chunks are cumulative, so the last one is the whole output
+	for msg := range ch {
+		if msg == bigmodel.StreamDone {
+			break
+		}
+
+		if msg == bigmodel.StreamAuditFailed {
+			r.Text = ""
+			err = bigmodel.ErrorStreamAuditFailed
+
+			break
+		}
+
+		if msg != "" {
+			r.Text = msg
+		}
+	}
+
+	return
+}
+
+func (a *streamTextAdapter) Stream(req *bigmodel.AdapterRequest, ch chan string) error {
+	if err := a.checkInput(req); err != nil {
+		return err
+	}
+
+	return a.endpoints.doWait(func(e string, release func(error)) error {
+		return a.stream(e, release, req, ch)
+	})
+}
+
+// stream releases the endpoint when the stream ends
+func (a *streamTextAdapter) stream(
+	endpoint string, release func(error), req *bigmodel.AdapterRequest, ch chan string,
+) error {
+	hr, err := a.newRequest(endpoint, req)
+	if err != nil {
+		return err
+	}
+
+	hr.Header.Set("Connection", "keep-alive")
+	hr.Header.Set("Accept", "*/*")
+
+	resp, err := a.s.hc.Client.Do(hr)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+
+		return errors.New("unexpected status code of stream")
+	}
+
+	go readStream(resp.Body, release, a.checkOutput, ch)
+
+	return nil
+}
@@ -2,6 +2,7 @@ package bigmodels
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"net/http"
 
@@ -10,7 +11,7 @@ import (
 )
 
 type baichuanInfo struct {
-	endpoints chan string
+	endpoints *endpointPool
 }
 
 type baichuanRequest struct {
@@ -34,23 +35,15 @@ func (req baichuanResponse) getText() string {
 	return req.Result[0].TextGenerationText[0]
 }
 
-func newBaiChuanInfo(cfg *Config) (info baichuanInfo, err error) {
-
-	ce := &cfg.Endpoints
-	es, _ := ce.parse(ce.BaiChuan)
-
-	// init endpoints
-	info.endpoints = make(chan string, len(es))
-	for _, e := range es {
-		info.endpoints <- e
-	}
+func newBaiChuanInfo(cfg *Config, pools *endpointPools) (info baichuanInfo, err error) {
+	info.endpoints, err = pools.add("baichuan", cfg.Endpoints.BaiChuan)
 
 	return
 }
 
-func (s *service) BaiChuan(input *domain.BaiChuanInput) (code, r string, err error) {
+func (s *service) BaiChuan(ctx context.Context, input *domain.BaiChuanInput) (code, r string, err error) {
 	// input check
-	if err = s.check.check(input.Text.BaiChuanText()); err != nil {
+	if err = s.check.CheckText(string(domain.BigmodelBaiChuan), input.Text.BaiChuanText()); err != nil {
 		code = CodeInputTextAuditError
 
 		return
@@ -59,12 +52,12 @@ func (s *service) BaiChuan(input *domain.BaiChuanInput) (code, r string, err err
 	// call bigmodel baichuan
 	var resp baichuanResponse
 	f := func(e string) (err error) {
-		resp, err = s.genBaiChuan(e, input)
+		resp, err = s.genBaiChuan(ctx, e, input)
 
 		return
 	}
 
-	if err = s.doIfFree(s.baichuanInfo.endpoints, f); err != nil {
+	if err = s.baichuanInfo.endpoints.do(f); err != nil {
 		return
 	}
 
@@ -76,7 +69,7 @@ func (s *service) BaiChuan(input *domain.BaiChuanInput) (code, r string, err err
 		return
 	}
 
-	if err = s.check.check(resp.getText()); err != nil {
+	if err = s.check.CheckText(string(domain.BigmodelBaiChuan), resp.getText()); err != nil {
 		code = CodeOutputTextAuditError
 
 		return
@@ -86,7 +79,7 @@ func (s *service) BaiChuan(input *domain.BaiChuanInput) (code, r string, err err
 }
 
 func (s *service) genBaiChuan(
-	endpoint string, d *domain.BaiChuanInput,
+	ctx context.Context, endpoint string, d *domain.BaiChuanInput,
 ) (resp baichuanResponse, err error) {
 	t, err := genToken(&s.wukongInfo.cfg.CloudConfig)
 	if err != nil {
@@ -99,8 +92,8 @@ func (s *service) genBaiChuan(
 		return
 	}
 
-	req, err := http.NewRequest(
-		http.MethodPost, endpoint, bytes.NewBuffer(body),
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, endpoint, bytes.NewBuffer(body),
 	)
 	if err != nil {
 		return
@@ -117,8 +110,18 @@ func (s *service) genBaiChuan(
 }
 
 func toBaiChuanReq(d *domain.BaiChuanInput) baichuanRequest {
+	var history [][2]string
+	if len(d.History) > 0 {
+		history = make([][2]string, len(d.History))
+
+		for i := range d.History {
+			history[i] = d.History[i].History()
+		}
+	}
+
+	// baichuan can't accept the history, it is put in front of the input
 	return baichuanRequest{
-		Inputs:            d.Text.BaiChuanText(),
+		Inputs:            historyToText(history, d.Text.BaiChuanText()),
 		Sampling:          d.Sampling,
 		TopK:              d.TopK.TopK(),
 		TopP:              d.TopP.TopP(),
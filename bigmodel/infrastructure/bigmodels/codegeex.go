@@ -2,49 +2,41 @@ package bigmodels
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 
 	"github.com/opensourceways/community-robot-lib/utils"
 
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
 )
 
 type codegeexInfo struct {
-	endpoints chan string
+	endpoints *endpointPool
 }
 
-func newCodeGeexInfo(cfg *Config) codegeexInfo {
-	ce := &cfg.Endpoints
+func newCodeGeexInfo(cfg *Config, pools *endpointPools) (info codegeexInfo, err error) {
+	info.endpoints, err = pools.add("codegeex", cfg.Endpoints.CodeGeex)
 
-	es, _ := ce.parse(ce.CodeGeex)
-
-	v := codegeexInfo{
-		endpoints: make(chan string, len(es)),
-	}
-
-	for _, e := range es {
-		v.endpoints <- e
-	}
-
-	return v
+	return
 }
 
-func (s *service) CodeGeex(question *bigmodel.CodeGeexReq) (r bigmodel.CodeGeexResp, err error) {
-	if err = s.check.check(question.Content); err != nil {
+func (s *service) CodeGeex(ctx context.Context, question *bigmodel.CodeGeexReq) (r bigmodel.CodeGeexResp, err error) {
+	if err = s.check.CheckText(string(domain.BigmodelCodeGeex), question.Content); err != nil {
 		return
 	}
 
-	s.doIfFree(s.codegeexInfo.endpoints, func(e string) error {
-		r, err = s.sendReqToCodeGeex(e, question)
+	err = s.codegeexInfo.endpoints.do(func(e string) (err error) {
+		r, err = s.sendReqToCodeGeex(ctx, e, question)
 
-		return nil
+		return
 	})
 
 	return
 }
 
 func (s *service) sendReqToCodeGeex(
-	endpoint string, question *bigmodel.CodeGeexReq,
+	ctx context.Context, endpoint string, question *bigmodel.CodeGeexReq,
 ) (r bigmodel.CodeGeexResp, err error) {
 	opt := codegeexReq{
 		Samples: question.Content,
@@ -56,8 +48,8 @@ func (s *service) sendReqToCodeGeex(
 		return
 	}
 
-	req, err := http.NewRequest(
-		http.MethodPost, endpoint, bytes.NewBuffer(body),
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, endpoint, bytes.NewBuffer(body),
 	)
 	if err != nil {
 		return
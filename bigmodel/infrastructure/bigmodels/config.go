@@ -2,23 +2,39 @@ package bigmodels
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/moderationimpl"
 )
 
 type Config struct {
-	OBS        OBSConfig   `json:"obs"             required:"true"`
-	Cloud      CloudConfig `json:"cloud"           required:"true"`
-	WuKong     WuKong      `json:"wukong"          required:"true"`
-	Endpoints  Endpoints   `json:"endpoints"       required:"true"`
-	Moderation Moderation  `json:"moderation"      required:"true"`
+	OBS        OBSConfig             `json:"obs"             required:"true"`
+	Cloud      CloudConfig           `json:"cloud"           required:"true"`
+	WuKong     WuKong                `json:"wukong"          required:"true"`
+	Endpoints  Endpoints             `json:"endpoints"       required:"true"`
+	Moderation moderationimpl.Config `json:"moderation" required:"true"`
+
+	// Adapters are the models which are served by the adapters of registry.
+	// A new model can be added here if it speaks one of the protocols of the adapters.
+	Adapters []AdapterConfig `json:"adapters"`
+
+	// EndpointPool is the default config of the endpoint pools of all models.
+	EndpointPool EndpointPoolConfig `json:"endpoint_pool"`
+
+	// EndpointPools overrides the default config for the specified models.
+	EndpointPools []ModelEndpointPoolConfig `json:"endpoint_pools"`
 
 	MaxPictureSizeToDescribe int64 `json:"max_picture_size_to_describe"`
 	MaxPictureSizeToVQA      int64 `json:"max_picture_size_to_vqa"`
 }
 
 func (cfg *Config) SetDefault() {
+	cfg.OBS.setDefault()
 	cfg.WuKong.setDefault()
+	cfg.Moderation.SetDefault()
 
 	if cfg.MaxPictureSizeToDescribe <= 0 {
 		cfg.MaxPictureSizeToDescribe = 2 << 21
@@ -27,6 +43,16 @@ func (cfg *Config) SetDefault() {
 	if cfg.MaxPictureSizeToVQA <= 0 {
 		cfg.MaxPictureSizeToVQA = 2 << 21
 	}
+
+	for i := range cfg.Adapters {
+		cfg.Adapters[i].setDefault()
+	}
+
+	cfg.EndpointPool.setDefault()
+
+	for i := range cfg.EndpointPools {
+		cfg.EndpointPools[i].setDefault(&cfg.EndpointPool)
+	}
 }
 
 func (cfg *Config) Validate() error {
@@ -34,14 +60,207 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if err := cfg.Moderation.Validate(); err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for i := range cfg.Adapters {
+		item := &cfg.Adapters[i]
+
+		if names[item.Name] {
+			return fmt.Errorf("duplicate adapter: %s", item.Name)
+		}
+
+		if isBuiltinAdapter(item.Name) {
+			return fmt.Errorf("adapter %s is builtin, configure its endpoints in endpoints", item.Name)
+		}
+		names[item.Name] = true
+
+		if err := item.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := cfg.EndpointPool.validate(); err != nil {
+		return err
+	}
+
+	models := map[string]bool{}
+	for i := range cfg.EndpointPools {
+		item := &cfg.EndpointPools[i]
+
+		if models[item.Model] {
+			return fmt.Errorf("duplicate endpoint pool: %s", item.Model)
+		}
+		models[item.Model] = true
+
+		if err := item.validate(); err != nil {
+			return fmt.Errorf("invalid endpoint pool of %s, %s", item.Model, err.Error())
+		}
+	}
+
 	return cfg.Endpoints.validate()
 }
 
+func (cfg *Config) endpointPoolConfig(model string) EndpointPoolConfig {
+	for i := range cfg.EndpointPools {
+		if item := &cfg.EndpointPools[i]; item.Model == model {
+			return item.EndpointPoolConfig
+		}
+	}
+
+	return cfg.EndpointPool
+}
+
+// EndpointPoolConfig
+type EndpointPoolConfig struct {
+	// MaxConcurrency is the max number of requests which an endpoint can serve at once.
+	MaxConcurrency int `json:"max_concurrency"`
+
+	// WaitTimeout is the max time to wait for an available endpoint.
+	// The unit is second.
+	WaitTimeout int `json:"wait_timeout"`
+
+	// FailureThreshold is the number of consecutive failures which opens the circuit.
+	FailureThreshold int `json:"failure_threshold"`
+
+	// OpenDuration is the time the circuit keeps open before a trial request.
+	// The unit is second.
+	OpenDuration int `json:"open_duration"`
+
+	HealthCheck HealthCheckConfig `json:"health_check"`
+}
+
+func (cfg *EndpointPoolConfig) setDefault() {
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = 1
+	}
+
+	if cfg.WaitTimeout <= 0 {
+		cfg.WaitTimeout = 120
+	}
+
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = 30
+	}
+
+	cfg.HealthCheck.setDefault()
+}
+
+func (cfg *EndpointPoolConfig) validate() error {
+	return cfg.HealthCheck.validate()
+}
+
+func (cfg *EndpointPoolConfig) waitTimeout() time.Duration {
+	return time.Duration(cfg.WaitTimeout) * time.Second
+}
+
+func (cfg *EndpointPoolConfig) openDuration() time.Duration {
+	return time.Duration(cfg.OpenDuration) * time.Second
+}
+
+// ModelEndpointPoolConfig
+type ModelEndpointPoolConfig struct {
+	Model string `json:"model"  required:"true"`
+
+	EndpointPoolConfig
+}
+
+// setDefault fills the unset items by the default config
+func (cfg *ModelEndpointPoolConfig) setDefault(d *EndpointPoolConfig) {
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = d.MaxConcurrency
+	}
+
+	if cfg.WaitTimeout <= 0 {
+		cfg.WaitTimeout = d.WaitTimeout
+	}
+
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = d.FailureThreshold
+	}
+
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = d.OpenDuration
+	}
+
+	if cfg.HealthCheck.Path == "" {
+		cfg.HealthCheck.Path = d.HealthCheck.Path
+	}
+
+	if cfg.HealthCheck.Interval <= 0 {
+		cfg.HealthCheck.Interval = d.HealthCheck.Interval
+	}
+
+	if cfg.HealthCheck.Timeout <= 0 {
+		cfg.HealthCheck.Timeout = d.HealthCheck.Timeout
+	}
+}
+
+// HealthCheckConfig
+type HealthCheckConfig struct {
+	// Path is the health api which is appended to the endpoint to probe.
+	// The health check is disabled if it is empty, because the inference api
+	// of endpoint can't be probed by GET.
+	Path string `json:"path"`
+
+	// Interval is the interval between the probes. The unit is second.
+	Interval int `json:"interval"`
+
+	// Timeout is the timeout of a probe. The unit is second.
+	Timeout int `json:"timeout"`
+}
+
+func (cfg *HealthCheckConfig) setDefault() {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 3
+	}
+}
+
+func (cfg *HealthCheckConfig) validate() error {
+	if cfg.enabled() && cfg.Timeout >= cfg.Interval {
+		return errors.New("timeout of health check must be less than interval")
+	}
+
+	return nil
+}
+
+func (cfg *HealthCheckConfig) enabled() bool {
+	return cfg.Path != ""
+}
+
+func (cfg *HealthCheckConfig) interval() time.Duration {
+	return time.Duration(cfg.Interval) * time.Second
+}
+
+func (cfg *HealthCheckConfig) timeout() time.Duration {
+	return time.Duration(cfg.Timeout) * time.Second
+}
+
 type OBSConfig struct {
 	OBSAuthInfo
 
 	VQABucket    string `json:"vqa_bucket"             required:"true"`
 	LuoJiaBucket string `json:"luo_jia_bucket"         required:"true"`
+
+	// DownloadExpiry specifies the timeout to download a picture of gallery
+	// which is saved in the buckets above. The unit is second.
+	DownloadExpiry int `json:"download_expiry"`
+}
+
+func (cfg *OBSConfig) setDefault() {
+	if cfg.DownloadExpiry <= 0 {
+		cfg.DownloadExpiry = 3600
+	}
 }
 
 type OBSAuthInfo struct {
@@ -143,14 +362,6 @@ func (e *Endpoints) parse(s string) ([]string, error) {
 	return v, nil
 }
 
-type Moderation struct {
-	Endpoint   string `json:"endpoint"       required:"true"`
-	AccessKey  string `json:"access_key"     required:"true"`
-	SecretKey  string `json:"secret_key"     required:"true"`
-	IAMEndpint string `json:"iam_endpoint"   required:"true"`
-	Region     string `json:"region"         required:"true"`
-}
-
 type WuKong struct {
 	WuKongSample
 	CloudConfig
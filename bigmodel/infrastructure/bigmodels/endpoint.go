@@ -0,0 +1,403 @@
+package bigmodels
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+)
+
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half_open"
+
+	// latencyWeight is the weight of the newest latency in the moving average
+	latencyWeight = 0.2
+)
+
+var errAccessOverload = errors.New("access overload, please try again later")
+
+// endpointPools
+type endpointPools struct {
+	cfg   *Config
+	pools map[string]*endpointPool
+	names []string
+}
+
+func newEndpointPools(cfg *Config) *endpointPools {
+	return &endpointPools{
+		cfg:   cfg,
+		pools: map[string]*endpointPool{},
+	}
+}
+
+// add creates the pool of model for the endpoints which are separated by comma
+func (ps *endpointPools) add(model, endpoints string) (*endpointPool, error) {
+	if _, ok := ps.pools[model]; ok {
+		return nil, fmt.Errorf("duplicate endpoint pool: %s", model)
+	}
+
+	es, err := (&Endpoints{}).parse(endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newEndpointPool(model, es, ps.cfg.endpointPoolConfig(model))
+	p.startHealthCheck()
+
+	ps.pools[model] = p
+	ps.names = append(ps.names, model)
+
+	return p, nil
+}
+
+func (ps *endpointPools) get(model string) (*endpointPool, bool) {
+	p, ok := ps.pools[model]
+
+	return p, ok
+}
+
+func (ps *endpointPools) stats() map[string]endpointPoolStat {
+	v := make(map[string]endpointPoolStat, len(ps.names))
+
+	for _, name := range ps.names {
+		v[name] = ps.pools[name].stat()
+	}
+
+	return v
+}
+
+// endpoint
+type endpoint struct {
+	url string
+
+	inflight int
+	healthy  bool
+
+	state    string
+	failures int
+	openedAt time.Time
+
+	// latency is the moving average of the latency, 0 means unknown
+	latency time.Duration
+
+	requests      int64
+	totalFailures int64
+}
+
+func (e *endpoint) available(cfg *EndpointPoolConfig, now time.Time) bool {
+	if !e.healthy || e.inflight >= cfg.MaxConcurrency {
+		return false
+	}
+
+	switch e.state {
+	case circuitOpen:
+		return now.Sub(e.openedAt) >= cfg.openDuration()
+
+	case circuitHalfOpen:
+		// only one trial request is allowed when half open
+		return e.inflight == 0
+	}
+
+	return true
+}
+
+// cost is the expected latency to serve a new request by the endpoint.
+func (e *endpoint) cost() time.Duration {
+	return e.latency * time.Duration(e.inflight+1)
+}
+
+func (e *endpoint) done(cfg *EndpointPoolConfig, failed bool, latency time.Duration) {
+	e.inflight--
+
+	if failed {
+		e.failures++
+		e.totalFailures++
+
+		if e.state == circuitHalfOpen || e.failures >= cfg.FailureThreshold {
+			if e.state != circuitOpen {
+				logrus.Warnf("circuit of endpoint %s is open", e.url)
+			}
+
+			e.state = circuitOpen
+			e.openedAt = time.Now()
+		}
+
+		return
+	}
+
+	e.failures = 0
+	e.state = circuitClosed
+
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(
+			latencyWeight*float64(latency) + (1-latencyWeight)*float64(e.latency),
+		)
+	}
+}
+
+// endpointPool dispatches the requests to the endpoints of a model.
+// It prefers the endpoint which is expected to respond first and skips
+// the endpoints which are unhealthy, full or whose circuit is open.
+type endpointPool struct {
+	name string
+	cfg  EndpointPoolConfig
+
+	lock      sync.Mutex
+	endpoints []*endpoint
+	next      int
+	rejected  int64
+
+	// released is closed and replaced when an endpoint is released,
+	// so that the waiters can retry.
+	released chan struct{}
+}
+
+func newEndpointPool(name string, es []string, cfg EndpointPoolConfig) *endpointPool {
+	p := &endpointPool{
+		name:      name,
+		cfg:       cfg,
+		endpoints: make([]*endpoint, len(es)),
+		released:  make(chan struct{}),
+	}
+
+	for i, e := range es {
+		p.endpoints[i] = &endpoint{
+			url:     e,
+			state:   circuitClosed,
+			healthy: true,
+		}
+	}
+
+	return p
+}
+
+// acquire returns the chosen endpoint or nil if there is no available one.
+// The channel returned will be closed when any endpoint is released.
+func (p *endpointPool) acquire() (*endpoint, chan struct{}) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := time.Now()
+	n := len(p.endpoints)
+
+	var selected *endpoint
+	for i := 0; i < n; i++ {
+		e := p.endpoints[(p.next+i)%n]
+
+		if !e.available(&p.cfg, now) {
+			continue
+		}
+
+		if selected == nil || e.cost() < selected.cost() ||
+			(e.cost() == selected.cost() && e.inflight < selected.inflight) {
+			selected = e
+		}
+	}
+
+	if selected == nil {
+		return nil, p.released
+	}
+
+	p.next = (p.next + 1) % n
+
+	if selected.state == circuitOpen {
+		selected.state = circuitHalfOpen
+	}
+
+	selected.inflight++
+	selected.requests++
+
+	return selected, nil
+}
+
+func (p *endpointPool) release(e *endpoint, err error, start time.Time) {
+	// the sensitive info is not the fault of endpoint
+	failed := err != nil && !bigmodel.IsErrorSensitiveInfo(err)
+
+	p.lock.Lock()
+	e.done(&p.cfg, failed, time.Since(start))
+
+	close(p.released)
+	p.released = make(chan struct{})
+	p.lock.Unlock()
+}
+
+func (p *endpointPool) reject() error {
+	p.lock.Lock()
+	p.rejected++
+	p.lock.Unlock()
+
+	return bigmodel.NewErrorBusySource(errAccessOverload)
+}
+
+// do calls f with an available endpoint and returns busy error if there is none.
+func (p *endpointPool) do(f func(string) error) error {
+	e, _ := p.acquire()
+	if e == nil {
+		return p.reject()
+	}
+
+	start := time.Now()
+	err := f(e.url)
+	p.release(e, err, start)
+
+	return err
+}
+
+// doWait waits for an available endpoint and calls f with it.
+// The endpoint is held until release is called by f, which is useful
+// when the response is read asynchronously, such as the stream.
+// The endpoint is released automatically if f returns error.
+func (p *endpointPool) doWait(f func(e string, release func(error)) error) error {
+	timeout := time.After(p.cfg.waitTimeout())
+
+	for {
+		e, released := p.acquire()
+		if e != nil {
+			start := time.Now()
+
+			var once sync.Once
+			release := func(err error) {
+				once.Do(func() { p.release(e, err, start) })
+			}
+
+			err := f(e.url, release)
+			if err != nil {
+				release(err)
+			}
+
+			return err
+		}
+
+		select {
+		case <-released:
+		case <-timeout:
+			return p.reject()
+		}
+	}
+}
+
+// idle returns the number of requests which can be served at once now.
+func (p *endpointPool) idle() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := time.Now()
+
+	n := 0
+	for _, e := range p.endpoints {
+		if !e.available(&p.cfg, now) {
+			continue
+		}
+
+		if e.state == circuitClosed {
+			n += p.cfg.MaxConcurrency - e.inflight
+		} else {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (p *endpointPool) startHealthCheck() {
+	cfg := &p.cfg.HealthCheck
+	if !cfg.enabled() {
+		return
+	}
+
+	cli := http.Client{Timeout: cfg.timeout()}
+
+	go func() {
+		t := time.NewTicker(cfg.interval())
+		defer t.Stop()
+
+		for range t.C {
+			for i := range p.endpoints {
+				p.probe(&cli, i)
+			}
+		}
+	}()
+}
+
+func (p *endpointPool) probe(cli *http.Client, i int) {
+	// the url of endpoint will not change, so it is safe to read it without lock
+	url := strings.TrimSuffix(p.endpoints[i].url, "/") + "/" +
+		strings.TrimPrefix(p.cfg.HealthCheck.Path, "/")
+
+	healthy := false
+	if resp, err := cli.Get(url); err == nil {
+		resp.Body.Close()
+
+		healthy = resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	e := p.endpoints[i]
+	if e.healthy != healthy {
+		logrus.Infof("health of endpoint %d of %s changed to %v", i, p.name, healthy)
+	}
+
+	e.healthy = healthy
+
+	if healthy && e.state == circuitOpen {
+		e.state = circuitHalfOpen
+	}
+}
+
+// endpointPoolStat is the metrics of pool
+type endpointPoolStat struct {
+	Idle      int            `json:"idle"`
+	Rejected  int64          `json:"rejected"`
+	Endpoints []endpointStat `json:"endpoints"`
+}
+
+type endpointStat struct {
+	State          string  `json:"state"`
+	Healthy        bool    `json:"healthy"`
+	Inflight       int     `json:"inflight"`
+	MaxConcurrency int     `json:"max_concurrency"`
+	Requests       int64   `json:"requests"`
+	Failures       int64   `json:"failures"`
+	LatencyMS      float64 `json:"latency_ms"`
+}
+
+func (p *endpointPool) stat() endpointPoolStat {
+	idle := p.idle()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	v := endpointPoolStat{
+		Idle:      idle,
+		Rejected:  p.rejected,
+		Endpoints: make([]endpointStat, len(p.endpoints)),
+	}
+
+	// the url of endpoint is not exposed, because it may contain the secret
+	for i, e := range p.endpoints {
+		v.Endpoints[i] = endpointStat{
+			State:          e.state,
+			Healthy:        e.healthy,
+			Inflight:       e.inflight,
+			MaxConcurrency: p.cfg.MaxConcurrency,
+			Requests:       e.requests,
+			Failures:       e.totalFailures,
+			LatencyMS:      float64(e.latency) / float64(time.Millisecond),
+		}
+	}
+
+	return v
+}
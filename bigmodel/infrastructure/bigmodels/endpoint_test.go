@@ -0,0 +1,170 @@
+package bigmodels
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+)
+
+func TestEndpointPoolCircuitBreaker(t *testing.T) {
+	errFailed := errors.New("failed")
+	errSensitive := bigmodel.NewErrorSensitiveInfo(errors.New("sensitive"))
+
+	cases := []struct {
+		name string
+
+		// calls are the results of the requests in order
+		calls []error
+
+		// expired makes the open duration elapsed before the last call
+		expired bool
+
+		state    string
+		failures int
+
+		// rejected is true if the last call is rejected without calling the endpoint
+		rejected bool
+	}{
+		{
+			name:     "failures under threshold",
+			calls:    []error{errFailed, errFailed},
+			state:    circuitClosed,
+			failures: 2,
+		},
+		{
+			name:     "open at threshold",
+			calls:    []error{errFailed, errFailed, errFailed},
+			state:    circuitOpen,
+			failures: 3,
+		},
+		{
+			name:     "success resets failures",
+			calls:    []error{errFailed, errFailed, nil, errFailed},
+			state:    circuitClosed,
+			failures: 1,
+		},
+		{
+			name:     "sensitive info is not failure",
+			calls:    []error{errSensitive, errSensitive, errSensitive},
+			state:    circuitClosed,
+			failures: 0,
+		},
+		{
+			name:     "reject when open",
+			calls:    []error{errFailed, errFailed, errFailed, nil},
+			state:    circuitOpen,
+			failures: 3,
+			rejected: true,
+		},
+		{
+			name:     "close after the trial succeeds",
+			calls:    []error{errFailed, errFailed, errFailed, nil},
+			expired:  true,
+			state:    circuitClosed,
+			failures: 0,
+		},
+		{
+			name:     "open again after the trial fails",
+			calls:    []error{errFailed, errFailed, errFailed, errFailed},
+			expired:  true,
+			state:    circuitOpen,
+			failures: 4,
+		},
+	}
+
+	cfg := EndpointPoolConfig{FailureThreshold: 3, OpenDuration: 30}
+	cfg.setDefault()
+
+	for _, c := range cases {
+		p := newEndpointPool("test", []string{"e"}, cfg)
+		e := p.endpoints[0]
+
+		called := false
+		for i, v := range c.calls {
+			if c.expired && i == len(c.calls)-1 {
+				e.openedAt = time.Now().Add(-cfg.openDuration())
+			}
+
+			called = false
+			err := p.do(func(string) error {
+				called = true
+
+				return v
+			})
+
+			if !called && !bigmodel.IsErrorBusySource(err) {
+				t.Errorf("%s: expect busy error when rejected, got %v", c.name, err)
+			}
+		}
+
+		if called == c.rejected {
+			t.Errorf("%s: expect rejected=%v", c.name, c.rejected)
+		}
+
+		if e.state != c.state || e.failures != c.failures {
+			t.Errorf(
+				"%s: expect state %s and failures %d, got %s and %d",
+				c.name, c.state, c.failures, e.state, e.failures,
+			)
+		}
+
+		if e.inflight != 0 {
+			t.Errorf("%s: expect no inflight, got %d", c.name, e.inflight)
+		}
+	}
+}
+
+func TestEndpointPoolHalfOpen(t *testing.T) {
+	cfg := EndpointPoolConfig{MaxConcurrency: 2, FailureThreshold: 1, OpenDuration: 30}
+	cfg.setDefault()
+
+	p := newEndpointPool("test", []string{"e"}, cfg)
+	e := p.endpoints[0]
+
+	e.state = circuitOpen
+	e.openedAt = time.Now().Add(-cfg.openDuration())
+
+	// only one trial request is allowed when half open
+	if v, _ := p.acquire(); v != e || e.state != circuitHalfOpen {
+		t.Fatalf("expect the trial request, got %v in state %s", v, e.state)
+	}
+
+	if v, _ := p.acquire(); v != nil {
+		t.Error("expect no more request when half open")
+	}
+
+	if n := p.idle(); n != 0 {
+		t.Errorf("expect no idle when half open, got %d", n)
+	}
+}
+
+func TestEndpointPoolSkipOpen(t *testing.T) {
+	cfg := EndpointPoolConfig{MaxConcurrency: 1, FailureThreshold: 1, OpenDuration: 30}
+	cfg.setDefault()
+
+	p := newEndpointPool("test", []string{"a", "b"}, cfg)
+
+	p.endpoints[0].state = circuitOpen
+	p.endpoints[0].openedAt = time.Now()
+
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"first", "b"},
+		{"second", "b"},
+	}
+
+	for _, c := range cases {
+		got := ""
+		if err := p.do(func(e string) error { got = e; return nil }); err != nil {
+			t.Errorf("%s: unexpected err=%v", c.name, err)
+		}
+
+		if got != c.want {
+			t.Errorf("%s: expect endpoint %s, got %s", c.name, c.want, got)
+		}
+	}
+}
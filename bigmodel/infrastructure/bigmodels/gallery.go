@@ -0,0 +1,107 @@
+package bigmodels
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	types "github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// galleryStorage is where the gallery pictures of a model are saved.
+// The picture is copied to the gallery dir of the bucket in which it is generated.
+type galleryStorage struct {
+	cli    *obsService
+	bucket string
+	expiry int
+
+	// owns checks whether the picture of path is generated for the user
+	owns func(user types.Account, path string) bool
+}
+
+func (s *service) initGalleryStorages(cfg *Config) {
+	// ownsByDir checks whether the picture is under the user's own dir of the model
+	ownsByDir := func(dir string) func(types.Account, string) bool {
+		return func(user types.Account, p string) bool {
+			return strings.HasPrefix(p, dir+"/"+user.Account()+"/")
+		}
+	}
+
+	wukong := &s.wukongInfo
+
+	s.galleries = map[string]galleryStorage{
+		string(domain.BigmodelWuKong): {
+			cli:    &wukong.cli,
+			bucket: wukong.cfg.Bucket,
+			expiry: wukong.cfg.DownloadExpiry,
+			owns: func(user types.Account, p string) bool {
+				_, err := s.parseWuKongPictureMetaData(user, p)
+
+				return err == nil
+			},
+		},
+		string(domain.BigmodelLuoJia): {
+			cli:    &s.obs,
+			bucket: s.luojiaInfo.bucket,
+			expiry: cfg.OBS.DownloadExpiry,
+			owns:   ownsByDir("luojianet/infer"),
+		},
+		string(domain.BigmodelVQA): {
+			cli:    &s.obs,
+			bucket: s.vqaInfo.bucket,
+			expiry: cfg.OBS.DownloadExpiry,
+			owns:   ownsByDir("vqa"),
+		},
+	}
+}
+
+func (s *service) galleryStorage(model string) (*galleryStorage, error) {
+	v, ok := s.galleries[model]
+	if !ok {
+		return nil, fmt.Errorf("no gallery storage for model: %s", model)
+	}
+
+	return &v, nil
+}
+
+func (s *service) SaveGalleryPicture(model string, user types.Account, src string) (string, error) {
+	g, err := s.galleryStorage(model)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.Contains(src, "..") || !g.owns(user, src) {
+		return "", errors.New("invalid path")
+	}
+
+	dst := fmt.Sprintf(
+		"gallery/%s/%d_%s", user.Account(), utils.Now(), filepath.Base(src),
+	)
+
+	if err := g.cli.copyObject(g.bucket, dst, src); err != nil {
+		return "", err
+	}
+
+	return dst, nil
+}
+
+func (s *service) GenGalleryPictureLink(model, p string) (string, error) {
+	g, err := s.galleryStorage(model)
+	if err != nil {
+		return "", err
+	}
+
+	return g.cli.genFileDownloadURL(g.bucket, p, g.expiry)
+}
+
+func (s *service) DeleteGalleryPicture(model, p string) error {
+	g, err := s.galleryStorage(model)
+	if err != nil {
+		return err
+	}
+
+	return g.cli.deleteObject(g.bucket, p)
+}
@@ -7,38 +7,29 @@ import (
 
 	"github.com/opensourceways/community-robot-lib/utils"
 
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	types "github.com/opensourceways/xihe-server/domain"
 )
 
 type pictureGenInfo struct {
-	singlePictures   chan string
-	multiplePictures chan string
+	singlePictures   *endpointPool
+	multiplePictures *endpointPool
 }
 
-func newPictureGenInfo(cfg *Config) pictureGenInfo {
+func newPictureGenInfo(cfg *Config, pools *endpointPools) (info pictureGenInfo, err error) {
 	e := &cfg.Endpoints
 
-	se, _ := e.parse(e.SinglePicture)
-	me, _ := e.parse(e.MultiplePictures)
-
-	v := pictureGenInfo{
-		singlePictures:   make(chan string, len(se)),
-		multiplePictures: make(chan string, len(me)),
-	}
-
-	for _, e := range se {
-		v.singlePictures <- e
+	if info.singlePictures, err = pools.add("single_picture", e.SinglePicture); err != nil {
+		return
 	}
 
-	for _, e := range me {
-		v.multiplePictures <- e
-	}
+	info.multiplePictures, err = pools.add("multiple_pictures", e.MultiplePictures)
 
-	return v
+	return
 }
 
 func (s *service) GenPicture(user types.Account, desc string) (string, error) {
-	if err := s.check.check(desc); err != nil {
+	if err := s.check.CheckText(string(domain.BigmodelGenPicture), desc); err != nil {
 		return "", err
 	}
 
@@ -53,7 +44,7 @@ func (s *service) GenPicture(user types.Account, desc string) (string, error) {
 }
 
 func (s *service) GenPictures(user types.Account, desc string) ([]string, error) {
-	if err := s.check.check(desc); err != nil {
+	if err := s.check.CheckText(string(domain.BigmodelGenPicture), desc); err != nil {
 		return nil, err
 	}
 
@@ -69,9 +60,9 @@ func (s *service) GenPictures(user types.Account, desc string) ([]string, error)
 
 func (s *service) genPicture(
 	user types.Account, desc string,
-	ec chan string, result interface{},
+	ec *endpointPool, result interface{},
 ) error {
-	return s.doIfFree(ec, func(e string) error {
+	return ec.do(func(e string) error {
 		return s.sendReqToGenPicture(user, e, desc, result)
 	})
 }
@@ -1,15 +1,12 @@
 package bigmodels
 
 import (
-	"bufio"
 	"bytes"
-	"encoding/json"
+	"context"
 	"net/http"
-	"strings"
 
 	libutils "github.com/opensourceways/community-robot-lib/utils"
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
-	"github.com/sirupsen/logrus"
 )
 
 type glm2Request struct {
@@ -22,53 +19,39 @@ type glm2Request struct {
 	RepetitionPenalty float64     `json:"repetition_penalty"`
 }
 
-type glm2Response struct {
-	Reply        string `json:"reply"`
-	Code         int    `json:"code"`
-	Msg          string `json:"msg"`
-	StreamStatus string `json:"stream_status"`
-}
-
 type glm2Info struct {
-	endpoints chan string
+	endpoints *endpointPool
 }
 
-func newGLM2Info(cfg *Config) (info glm2Info, err error) {
-	ce := &cfg.Endpoints
-	es, _ := ce.parse(ce.GLM2)
-
-	// init endpoints
-	info.endpoints = make(chan string, len(es))
-	for _, e := range es {
-		info.endpoints <- e
-	}
+func newGLM2Info(cfg *Config, pools *endpointPools) (info glm2Info, err error) {
+	info.endpoints, err = pools.add("glm2", cfg.Endpoints.GLM2)
 
 	return
 }
 
-func (s *service) GLM2(ch chan string, input *domain.GLM2Input) (err error) {
+func (s *service) GLM2(ctx context.Context, ch chan string, input *domain.GLM2Input) (err error) {
 	// input audit
-	if err = s.check.check(input.Text.GLM2Text()); err != nil {
+	if err = s.check.CheckText(string(domain.BigmodelGLM2), input.Text.GLM2Text()); err != nil {
 		return
 	}
 
 	// call bigmodel glm2
-	f := func(ec chan string, e string) (err error) {
-		err = s.genGLM2(ec, ch, e, input)
+	f := func(e string, release func(error)) (err error) {
+		err = s.genGLM2(ctx, release, ch, e, input)
 
 		return
 	}
 
-	if err = s.doWaitAndEndpointNotReturned(s.glm2Info.endpoints, f); err != nil {
+	if err = s.glm2Info.endpoints.doWait(f); err != nil {
 		return
 	}
 
 	return
 }
 
-func (s *service) genGLM2(ec, ch chan string, endpoint string, input *domain.GLM2Input) (
-	err error,
-) {
+func (s *service) genGLM2(
+	ctx context.Context, release func(error), ch chan string, endpoint string, input *domain.GLM2Input,
+) (err error) {
 	t, err := genToken(&s.wukongInfo.cfg.CloudConfig)
 	if err != nil {
 		return
@@ -80,8 +63,8 @@ func (s *service) genGLM2(ec, ch chan string, endpoint string, input *domain.GLM
 		return
 	}
 
-	req, err := http.NewRequest(
-		http.MethodPost, endpoint, bytes.NewBuffer(body),
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, endpoint, bytes.NewBuffer(body),
 	)
 	if err != nil {
 		return
@@ -97,54 +80,9 @@ func (s *service) genGLM2(ec, ch chan string, endpoint string, input *domain.GLM
 		return
 	}
 
-	reader := bufio.NewReader(resp.Body)
-
-	var (
-		r     glm2Response
-		count int
-	)
-	go func() {
-		defer func() { ec <- endpoint }()
-		defer resp.Body.Close()
-
-		for {
-			line, err := reader.ReadString('\n')
-			if err != nil {
-				ch <- "done"
-
-				return
-			}
-
-			data := strings.Replace(string(line), "data: ", "", 1)
-			data = strings.TrimRight(data, "\x00")
-
-			if err = json.Unmarshal([]byte(data), &r); err != nil {
-				continue
-			}
-
-			if r.StreamStatus == "DONE" {
-				ch <- "done"
-
-				return
-			}
-
-			// response audit, skip 6 response
-			if r.Reply != "" && count > 6 {
-				count = 0
-
-				if err = s.check.check(r.Reply); err != nil {
-					logrus.Debugf("content audit not pass: %s", err.Error())
-
-					ch <- "done"
-
-					return
-				}
-			}
-
-			ch <- r.Reply
-			count += 1
-		}
-	}()
+	go readStream(resp.Body, release, func(v string) error {
+		return s.check.CheckText(string(domain.BigmodelGLM2), v)
+	}, ch)
 
 	return
 }
@@ -23,32 +23,24 @@ func (r *luojiaHFResp) result() string {
 
 type luojiaInfo struct {
 	bucket     string
-	endpoints  chan string
-	endpointHF chan string
+	endpoints  *endpointPool
+	endpointHF *endpointPool
 }
 
-func newLuoJiaInfo(cfg *Config) luojiaInfo {
+func newLuoJiaInfo(cfg *Config, pools *endpointPools) (info luojiaInfo, err error) {
 	ce := &cfg.Endpoints
 
-	es, _ := ce.parse(ce.LuoJia)
-	eshf, _ := ce.parse(ce.LuoJiaHF)
-
-	v := luojiaInfo{
-		endpoints:  make(chan string, len(es)),
-		endpointHF: make(chan string, len(eshf)),
-	}
-
-	for _, e := range es {
-		v.endpoints <- e
+	if info.endpoints, err = pools.add("luojia", ce.LuoJia); err != nil {
+		return
 	}
 
-	for _, e := range eshf {
-		v.endpointHF <- e
+	if info.endpointHF, err = pools.add("luojia_hf", ce.LuoJiaHF); err != nil {
+		return
 	}
 
-	v.bucket = cfg.OBS.LuoJiaBucket
+	info.bucket = cfg.OBS.LuoJiaBucket
 
-	return v
+	return
 }
 
 func (s *service) LuoJiaUploadPicture(f io.Reader, user types.Account) error {
@@ -60,20 +52,20 @@ func (s *service) LuoJiaUploadPicture(f io.Reader, user types.Account) error {
 }
 
 func (s *service) LuoJia(question string) (answer string, err error) {
-	s.doIfFree(s.luojiaInfo.endpoints, func(e string) error {
+	err = s.luojiaInfo.endpoints.do(func(e string) (err error) {
 		answer, err = s.sendReqToLuojia(e, question)
 
-		return err
+		return
 	})
 
 	return
 }
 
 func (s *service) LuoJiaHF(f io.Reader) (res string, err error) {
-	s.doIfFree(s.luojiaInfo.endpointHF, func(e string) error {
+	err = s.luojiaInfo.endpointHF.do(func(e string) (err error) {
 		res, err = s.sendReqToLuoJiaHF(e, f)
 
-		return err
+		return
 	})
 
 	return
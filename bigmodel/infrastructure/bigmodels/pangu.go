@@ -2,45 +2,38 @@ package bigmodels
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
 )
 
 type panguInfo struct {
-	endpoints chan string
+	endpoints *endpointPool
 }
 
-func newPanGuInfo(cfg *Config) panguInfo {
-	ce := &cfg.Endpoints
-
-	es, _ := ce.parse(ce.Pangu)
-
-	v := panguInfo{
-		endpoints: make(chan string, len(es)),
-	}
-
-	for _, e := range es {
-		v.endpoints <- e
-	}
+func newPanGuInfo(cfg *Config, pools *endpointPools) (info panguInfo, err error) {
+	info.endpoints, err = pools.add("pangu", cfg.Endpoints.Pangu)
 
-	return v
+	return
 }
 
-func (s *service) PanGu(question string) (answer string, err error) {
-	if err = s.check.check(question); err != nil {
+func (s *service) PanGu(ctx context.Context, question string) (answer string, err error) {
+	if err = s.check.CheckText(string(domain.BigmodelPanGu), question); err != nil {
 		return
 	}
 
-	s.doIfFree(s.panguInfo.endpoints, func(e string) error {
-		answer, err = s.sendReqToPangu(e, question)
+	err = s.panguInfo.endpoints.do(func(e string) (err error) {
+		answer, err = s.sendReqToPangu(ctx, e, question)
 
-		return err
+		return
 	})
 
 	return
 }
 
-func (s *service) sendReqToPangu(endpoint, question string) (answer string, err error) {
+func (s *service) sendReqToPangu(ctx context.Context, endpoint, question string) (answer string, err error) {
 	t, err := s.token()
 	if err != nil {
 		return
@@ -48,8 +41,8 @@ func (s *service) sendReqToPangu(endpoint, question string) (answer string, err
 
 	body := []byte(fmt.Sprintf(`{"question":"%s"}`, question))
 
-	req, err := http.NewRequest(
-		http.MethodPost, endpoint, bytes.NewBuffer(body),
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, endpoint, bytes.NewBuffer(body),
 	)
 	if err != nil {
 		return
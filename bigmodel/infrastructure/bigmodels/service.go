@@ -3,14 +3,15 @@ package bigmodels
 import (
 	"crypto/tls"
 	"errors"
+	"expvar"
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/opensourceways/community-robot-lib/utils"
 
 	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/moderationimpl"
 )
 
 var fm *service
@@ -21,13 +22,17 @@ func Init(cfg *Config) error {
 		return err
 	}
 
-	check := initTextCheck(&cfg.Moderation)
+	check, err := moderationimpl.NewService(&cfg.Moderation)
+	if err != nil {
+		return err
+	}
 
 	fm = &service{
 		obs:   obs,
 		check: check,
 		cfg:   cfg.Cloud,
 		hc:    utils.NewHttpClient(3),
+		pools: newEndpointPools(cfg),
 	}
 
 	http.DefaultClient.Transport = &http.Transport{
@@ -35,18 +40,53 @@ func Init(cfg *Config) error {
 	}
 
 	fm.vqaInfo = newVQAInfo(cfg)
-	fm.panguInfo = newPanGuInfo(cfg)
-	fm.luojiaInfo = newLuoJiaInfo(cfg)
-	fm.codegeexInfo = newCodeGeexInfo(cfg)
-	fm.pictureGenInfo = newPictureGenInfo(cfg)
 	fm.pictureDescInfo = newPictureDescInfo(cfg)
 	fm.aiDetectorInfo = newAIDetectorInfo(cfg)
-	fm.wukongInfo, err = newWuKongInfo(cfg)
-	fm.baichuanInfo, err = newBaiChuanInfo(cfg)
-	fm.glm2Info, err = newGLM2Info(cfg)
-	fm.llama2Info, err = newLLAMA2Info(cfg)
 
-	return err
+	if fm.panguInfo, err = newPanGuInfo(cfg, fm.pools); err != nil {
+		return err
+	}
+
+	if fm.luojiaInfo, err = newLuoJiaInfo(cfg, fm.pools); err != nil {
+		return err
+	}
+
+	if fm.codegeexInfo, err = newCodeGeexInfo(cfg, fm.pools); err != nil {
+		return err
+	}
+
+	if fm.pictureGenInfo, err = newPictureGenInfo(cfg, fm.pools); err != nil {
+		return err
+	}
+
+	if fm.wukongInfo, err = newWuKongInfo(cfg, fm.pools); err != nil {
+		return err
+	}
+
+	if fm.baichuanInfo, err = newBaiChuanInfo(cfg, fm.pools); err != nil {
+		return err
+	}
+
+	if fm.glm2Info, err = newGLM2Info(cfg, fm.pools); err != nil {
+		return err
+	}
+
+	if fm.llama2Info, err = newLLAMA2Info(cfg, fm.pools); err != nil {
+		return err
+	}
+
+	if err = fm.initAdapters(cfg); err != nil {
+		return err
+	}
+
+	fm.initGalleryStorages(cfg)
+
+	// the metrics can be read at /debug/vars of the metrics server
+	expvar.Publish("bigmodel_endpoints", expvar.Func(func() interface{} {
+		return fm.pools.stats()
+	}))
+
+	return nil
 }
 
 func NewBigModelService() bigmodel.BigModel {
@@ -56,9 +96,10 @@ func NewBigModelService() bigmodel.BigModel {
 type service struct {
 	cfg   CloudConfig
 	obs   obsService
-	check textCheckService
+	check moderationimpl.Service
 
-	hc utils.HttpClient
+	hc    utils.HttpClient
+	pools *endpointPools
 
 	vqaInfo         vqaInfo
 	panguInfo       panguInfo
@@ -71,6 +112,11 @@ type service struct {
 	baichuanInfo    baichuanInfo
 	glm2Info        glm2Info
 	llama2Info      llama2Info
+
+	adapters bigmodel.AdapterRegistry
+
+	// galleries is the storage of gallery pictures for each model
+	galleries map[string]galleryStorage
 }
 
 func (s *service) token() (string, error) {
@@ -125,41 +171,14 @@ func genToken(cfg *CloudConfig) (string, error) {
 	return t, nil
 }
 
-func (s *service) doIfFree(
-	ec chan string,
-	f func(string) error,
-) error {
-	select {
-	case e := <-ec:
-		err := f(e)
-		ec <- e
-
-		return err
-
-	default:
-		return bigmodel.NewErrorBusySource(errors.New("access overload, please try again later"))
+func (s *service) GetIdleEndpoint(bid string) (int, error) {
+	if p, ok := s.pools.get(bid); ok {
+		return p.idle(), nil
 	}
-}
 
-func (s *service) doWaitAndEndpointNotReturned(
-	ec chan string,
-	f func(chan string, string) error,
-) error {
-	select {
-	case e := <-ec:
-		return f(ec, e)
-	case <-time.After(2 * 60 * time.Second):
-		return bigmodel.NewErrorBusySource(errors.New("access overload, please try again later"))
+	if a, err := s.adapters.Get(bid); err == nil {
+		return a.IdleEndpoints(), nil
 	}
-}
 
-func (s *service) GetIdleEndpoint(bid string) (int, error) {
-	switch bid {
-	case "wukong":
-		return len(s.wukongInfo.endpoints), nil
-	case "wukong_4img":
-		return len(s.wukongInfo.endpoints4), nil
-	default:
-		return 0, errors.New("internal error, cannot found this bigmodel")
-	}
+	return 0, errors.New("internal error, cannot found this bigmodel")
 }
@@ -0,0 +1,101 @@
+package bigmodels
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+)
+
+// the reply is audited once every streamAuditInterval replies
+const streamAuditInterval = 7
+
+type streamResponse struct {
+	Reply        string `json:"reply"`
+	Code         int    `json:"code"`
+	Msg          string `json:"msg"`
+	StreamStatus string `json:"stream_status"`
+}
+
+// readStream forwards the replies of the stream protocol of glm2 and llama2 to ch.
+// The replies are cumulative, so it audits the latest one every few replies and the
+// last one at the end, then ends the stream with StreamAuditFailed if the audit fails.
+// The endpoint is released once the response ends, without waiting for the last
+// message to be received.
+func readStream(
+	body io.ReadCloser, release func(error), check func(string) error, ch chan string,
+) {
+	defer release(nil)
+	defer body.Close()
+
+	reader := bufio.NewReader(body)
+
+	var (
+		r       streamResponse
+		last    string
+		audited string
+		count   int
+	)
+
+	audit := func() bool {
+		if last == audited {
+			return true
+		}
+
+		if err := check(last); err != nil {
+			logrus.Debugf("content audit not pass: %s", err.Error())
+
+			ch <- bigmodel.StreamAuditFailed
+
+			return false
+		}
+
+		audited = last
+
+		return true
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			logrus.Debugf("stream read end or error: %s", err)
+
+			break
+		}
+
+		data := strings.Replace(line, "data: ", "", 1)
+		data = strings.TrimRight(data, "\x00")
+
+		if err = json.Unmarshal([]byte(data), &r); err != nil {
+			continue
+		}
+
+		if r.StreamStatus == streamStatusDone {
+			break
+		}
+
+		if r.Reply != "" {
+			last = r.Reply
+
+			if count++; count >= streamAuditInterval {
+				count = 0
+
+				if !audit() {
+					return
+				}
+			}
+		}
+
+		ch <- r.Reply
+	}
+
+	release(nil)
+
+	if audit() {
+		ch <- bigmodel.StreamDone
+	}
+}
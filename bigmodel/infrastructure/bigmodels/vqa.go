@@ -65,7 +65,7 @@ func newVQAInfo(cfg *Config) vqaInfo {
 }
 
 func (s *service) Ask(q domain.Question, f string) (string, error) {
-	if err := s.check.check(q.Question()); err != nil {
+	if err := s.check.CheckText(string(domain.BigmodelVQA), q.Question()); err != nil {
 		return "", err
 	}
 
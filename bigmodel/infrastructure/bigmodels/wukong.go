@@ -2,32 +2,39 @@ package bigmodels
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	libutils "github.com/opensourceways/community-robot-lib/utils"
+	"github.com/sirupsen/logrus"
 
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
 	types "github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/utils"
 )
 
 var reTimestamp = regexp.MustCompile("/[1-9][0-9]{9,}/")
 
+const wukongInputDir = "wukong/input"
+
 type wukongInfo struct {
 	cli           obsService
 	cfg           WuKong
 	maxBatch      int
-	endpoints     chan string
-	endpoints4    chan string
-	endpointsHF   chan string
-	endpointsUser chan string
+	endpoints     *endpointPool
+	endpoints4    *endpointPool
+	endpointsHF   *endpointPool
+	endpointsUser *endpointPool
 }
 
-func newWuKongInfo(cfg *Config) (wukongInfo, error) {
+func newWuKongInfo(cfg *Config, pools *endpointPools) (wukongInfo, error) {
 	v := &cfg.WuKong
 
 	cli, err := initOBS(&v.OBSAuthInfo)
@@ -42,33 +49,25 @@ func newWuKongInfo(cfg *Config) (wukongInfo, error) {
 	}
 
 	ce := &cfg.Endpoints
-	es, _ := ce.parse(ce.WuKong)
-	es4, _ := ce.parse(ce.WuKong4IMG)
-	eshf, _ := ce.parse(ce.WuKongHF)
-	esus, _ := ce.parse(ce.WuKongUser)
 
 	// init endpoints
-	info.endpoints = make(chan string, len(es))
-	for _, e := range es {
-		info.endpoints <- e
+	if info.endpoints, err = pools.add("wukong", ce.WuKong); err != nil {
+		return info, err
 	}
 
 	// init endpoints4
-	info.endpoints4 = make(chan string, len(es4))
-	for _, e := range es4 {
-		info.endpoints4 <- e
+	if info.endpoints4, err = pools.add("wukong_4img", ce.WuKong4IMG); err != nil {
+		return info, err
 	}
 
 	// init endpoints_hf
-	info.endpointsHF = make(chan string, len(eshf))
-	for _, e := range eshf {
-		info.endpointsHF <- e
+	if info.endpointsHF, err = pools.add("wukong_hf", ce.WuKongHF); err != nil {
+		return info, err
 	}
 
 	// init endpoint_user
-	info.endpointsUser = make(chan string, len(esus))
-	for _, e := range esus {
-		info.endpointsUser <- e
+	if info.endpointsUser, err = pools.add("wukong_user", ce.WuKongUser); err != nil {
+		return info, err
 	}
 
 	return info, nil
@@ -97,23 +96,96 @@ func (s *service) GetWuKongSampleId() string {
 	return s.wukongInfo.cfg.SampleId
 }
 
+// WuKongUploadPicture uploads the source or mask picture of img2img and inpainting.
+// The picture will be deleted if it does not pass the moderation.
+func (s *service) WuKongUploadPicture(f io.Reader, user types.Account, name string) (
+	domain.OBSPath, error,
+) {
+	info := &s.wukongInfo
+	bucket := info.cfg.Bucket
+
+	p := fmt.Sprintf(
+		"%s/%s/%d-%s", wukongInputDir, user.Account(), utils.Now(), filepath.Base(name),
+	)
+
+	if err := info.cli.createObject(f, bucket, p); err != nil {
+		return nil, err
+	}
+
+	link, err := info.cli.genFileDownloadURL(bucket, p, info.cfg.DownloadExpiry)
+	if err == nil {
+		err = s.check.CheckImages(string(domain.BigmodelWuKong), []string{link})
+	}
+
+	if err != nil {
+		if err1 := info.cli.deleteObject(bucket, p); err1 != nil {
+			logrus.Errorf("delete wukong input %s failed, err:%s", p, err1.Error())
+		}
+
+		return nil, err
+	}
+
+	return domain.NewOBSPath(p)
+}
+
+// genWuKongInputLink generates the link of input picture which must be uploaded by the user
+func (s *service) genWuKongInputLink(user types.Account, p domain.OBSPath) (string, error) {
+	v := p.OBSPath()
+
+	prefix := wukongInputDir + "/" + user.Account() + "/"
+	if filepath.Clean(v) != v || !strings.HasPrefix(v, prefix) {
+		return "", bigmodel.NewErrorInvalidInput(errors.New("invalid input picture"))
+	}
+
+	info := &s.wukongInfo
+
+	return info.cli.genFileDownloadURL(info.cfg.Bucket, v, info.cfg.DownloadExpiry)
+}
+
+func (s *service) genWuKongInputLinks(user types.Account, desc *domain.WuKongPictureMeta) (
+	image, mask string, err error,
+) {
+	if err = desc.CheckMode(); err != nil {
+		err = bigmodel.NewErrorInvalidInput(err)
+
+		return
+	}
+
+	if desc.Image != nil {
+		if image, err = s.genWuKongInputLink(user, desc.Image); err != nil {
+			return
+		}
+	}
+
+	if desc.Mask != nil {
+		mask, err = s.genWuKongInputLink(user, desc.Mask)
+	}
+
+	return
+}
+
 func (s *service) GenPicturesByWuKong(
-	user types.Account, desc *domain.WuKongPictureMeta, estype string,
+	ctx context.Context, user types.Account, desc *domain.WuKongPictureMeta, estype string,
 ) (map[string]string, error) {
-	if err := s.check.check(desc.Desc.WuKongPictureDesc()); err != nil {
+	if err := s.check.CheckText(estype, desc.Desc.WuKongPictureDesc()); err != nil {
+		return nil, err
+	}
+
+	image, mask, err := s.genWuKongInputLinks(user, desc)
+	if err != nil {
 		return nil, err
 	}
 
 	var v []string
 
 	f := func(e string) (err error) {
-		v, err = s.genPicturesByWuKong(e, user, desc)
+		v, err = s.genPicturesByWuKong(ctx, e, user, desc, image, mask)
 
 		return
 	}
 
 	// select endpoints
-	var es chan string
+	var es *endpointPool
 	switch estype {
 	case string(domain.BigmodelWuKong):
 		es = s.wukongInfo.endpoints
@@ -123,9 +195,11 @@ func (s *service) GenPicturesByWuKong(
 		es = s.wukongInfo.endpointsHF
 	case string(domain.BigmodelWuKongUser):
 		es = s.wukongInfo.endpointsUser
+	default:
+		return nil, errors.New("unknown type of wukong")
 	}
 
-	if err := s.doIfFree(es, f); err != nil {
+	if err := es.do(f); err != nil {
 		return nil, err
 	}
 
@@ -149,7 +223,7 @@ func (s *service) GenPicturesByWuKong(
 		checkUrls[i] = v
 		i++
 	}
-	if err := s.check.checkImages(checkUrls); err != nil {
+	if err := s.check.CheckImages(estype, checkUrls); err != nil {
 		return nil, err
 	}
 
@@ -157,7 +231,8 @@ func (s *service) GenPicturesByWuKong(
 }
 
 func (s *service) genPicturesByWuKong(
-	endpoint string, user types.Account, desc *domain.WuKongPictureMeta,
+	ctx context.Context, endpoint string, user types.Account, desc *domain.WuKongPictureMeta,
+	image, mask string,
 ) ([]string, error) {
 	t, err := genToken(&s.wukongInfo.cfg.CloudConfig)
 	if err != nil {
@@ -168,14 +243,17 @@ func (s *service) genPicturesByWuKong(
 		Style: desc.Style,
 		Desc:  desc.Desc.WuKongPictureDesc(),
 		User:  user.Account(),
+		Mode:  desc.WuKongMode().WuKongMode(),
+		Image: image,
+		Mask:  mask,
 	}
 	body, err := libutils.JsonMarshal(&opt)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(
-		http.MethodPost, endpoint, bytes.NewBuffer(body),
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, endpoint, bytes.NewBuffer(body),
 	)
 	if err != nil {
 		return nil, err
@@ -314,6 +392,9 @@ type wukongRequest struct {
 	Style string `json:"style"`
 	Desc  string `json:"desc"`
 	User  string `json:"user_name"`
+	Mode  string `json:"mode"`
+	Image string `json:"image_url,omitempty"`
+	Mask  string `json:"mask_url,omitempty"`
 }
 
 type wukongResponse struct {
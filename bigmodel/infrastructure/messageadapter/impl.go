@@ -35,9 +35,19 @@ func (impl *messageAdapter) SendWuKongInferenceStart(v *domain.WuKongInferenceSt
 			"task_type": v.EsStyle,
 			"style":     v.Style,
 			"desc":      v.Desc.WuKongPictureDesc(),
+			"mode":      v.Input.WuKongMode().WuKongMode(),
+			"priority":  strconv.Itoa(v.Priority),
 		},
 	}
 
+	if v.Input.Image != nil {
+		msg.Details["image"] = v.Input.Image.OBSPath()
+	}
+
+	if v.Input.Mask != nil {
+		msg.Details["mask"] = v.Input.Mask.OBSPath()
+	}
+
 	logrus.Debugf("Send WuKongInferenceStart: %v", msg)
 
 	return impl.publisher.Publish(cfg.Topic, &msg, nil)
@@ -49,9 +59,10 @@ func (impl *messageAdapter) SendWuKongInferenceError(v *domain.WuKongInferenceEr
 	msg := common.MsgNormal{
 		User: v.Account.Account(),
 		Details: map[string]string{
-			"task_id": strconv.Itoa(int(v.TaskId)),
-			"status":  "error",
-			"error":   v.ErrMsg,
+			"task_id":   strconv.Itoa(int(v.TaskId)),
+			"task_type": v.TaskType,
+			"status":    "error",
+			"error":     v.ErrMsg,
 		},
 	}
 
@@ -66,8 +77,9 @@ func (impl *messageAdapter) SendWuKongAsyncTaskStart(v *domain.WuKongAsyncTaskSt
 	msg := common.MsgNormal{
 		User: v.Account.Account(),
 		Details: map[string]string{
-			"status":  "running",
-			"task_id": strconv.Itoa(int(v.TaskId)),
+			"status":    "running",
+			"task_id":   strconv.Itoa(int(v.TaskId)),
+			"task_type": v.TaskType,
 		},
 	}
 
@@ -93,9 +105,10 @@ func (impl *messageAdapter) SendWuKongAsyncInferenceFinish(
 		Type: cfg.Name,
 		Desc: fmt.Sprintf("Tried wukong inference, task id is: %s", taskid),
 		Details: map[string]string{
-			"task_id": taskid,
-			"status":  "finished",
-			"links":   strings.TrimRight(ls, ","),
+			"task_id":   taskid,
+			"task_type": v.TaskType,
+			"status":    "finished",
+			"links":     strings.TrimRight(ls, ","),
 		},
 		CreatedAt: utils.Now(),
 	}
@@ -167,6 +180,112 @@ func (impl *messageAdapter) SendWuKongPictureLiked(v *domain.WuKongPictureLikedE
 	return impl.publisher.Publish(cfg.Topic, &msg, nil)
 }
 
+// async task
+func (impl *messageAdapter) SendAsyncTaskCreated(v *domain.AsyncTaskCreatedEvent) error {
+	cfg := &impl.cfg.AsyncTaskCreated
+
+	details := map[string]string{}
+	for k := range v.Payload {
+		details[k] = v.Payload[k]
+	}
+
+	details["status"] = "waiting"
+	details["task_type"] = v.TaskType
+	details["priority"] = strconv.Itoa(v.Priority)
+
+	msg := common.MsgNormal{
+		Type:      cfg.Name,
+		User:      v.Account.Account(),
+		Details:   details,
+		CreatedAt: utils.Now(),
+	}
+
+	logrus.Debugf("Send AsyncTaskCreated: %v", msg)
+
+	return impl.publisher.Publish(cfg.Topic, &msg, nil)
+}
+
+func (impl *messageAdapter) SendAsyncTaskStarted(v *domain.AsyncTaskStartedEvent) error {
+	cfg := &impl.cfg.AsyncTaskStarted
+
+	msg := common.MsgNormal{
+		Type: cfg.Name,
+		User: v.Account.Account(),
+		Details: map[string]string{
+			"status":    "running",
+			"task_id":   strconv.FormatUint(v.TaskId, 10),
+			"task_type": v.TaskType,
+		},
+		CreatedAt: utils.Now(),
+	}
+
+	logrus.Debugf("Send AsyncTaskStarted: %v", msg)
+
+	return impl.publisher.Publish(cfg.Topic, &msg, nil)
+}
+
+func (impl *messageAdapter) SendAsyncTaskFinished(v *domain.AsyncTaskFinishedEvent) error {
+	cfg := &impl.cfg.AsyncTaskFinished
+
+	details := map[string]string{}
+	for k := range v.Result {
+		details[k] = v.Result[k]
+	}
+
+	details["status"] = "finished"
+	details["task_id"] = strconv.FormatUint(v.TaskId, 10)
+	details["task_type"] = v.TaskType
+
+	msg := common.MsgNormal{
+		User:      v.Account.Account(),
+		Type:      cfg.Name,
+		Details:   details,
+		CreatedAt: utils.Now(),
+	}
+
+	logrus.Debugf("Send AsyncTaskFinished: %v", msg)
+
+	return impl.publisher.Publish(cfg.Topic, &msg, nil)
+}
+
+func (impl *messageAdapter) SendAsyncTaskFailed(v *domain.AsyncTaskFailedEvent) error {
+	cfg := &impl.cfg.AsyncTaskFailed
+
+	msg := common.MsgNormal{
+		Type: cfg.Name,
+		User: v.Account.Account(),
+		Details: map[string]string{
+			"task_id":   strconv.FormatUint(v.TaskId, 10),
+			"task_type": v.TaskType,
+			"status":    "error",
+			"error":     v.ErrMsg,
+		},
+		CreatedAt: utils.Now(),
+	}
+
+	logrus.Debugf("Send AsyncTaskFailed: %v", msg)
+
+	return impl.publisher.Publish(cfg.Topic, &msg, nil)
+}
+
+func (impl *messageAdapter) SendApiCalled(v *domain.ApiCalledEvent) error {
+	cfg := &impl.cfg.ApiCalled
+
+	msg := common.MsgNormal{
+		User:      v.Account.Account(),
+		CreatedAt: v.At,
+		Details: map[string]string{
+			"model":   v.Model,
+			"status":  strconv.Itoa(v.Status),
+			"latency": strconv.FormatInt(v.Latency, 10),
+		},
+	}
+
+	logrus.Debugf("Send ApiCalled: %v", msg)
+
+	return impl.publisher.Publish(cfg.Topic, &msg, nil)
+}
+
 // Config
 type Config struct {
 	// wukong
@@ -177,7 +296,42 @@ type Config struct {
 	PicturePublicized    common.TopicConfig `json:"picture_publicized"`
 	PictureLiked         common.TopicConfig `json:"picture_liked"`
 
+	// async task
+	AsyncTaskCreated  common.TopicConfig `json:"async_task_created"`
+	AsyncTaskStarted  common.TopicConfig `json:"async_task_started"`
+	AsyncTaskFinished common.TopicConfig `json:"async_task_finished"`
+	AsyncTaskFailed   common.TopicConfig `json:"async_task_failed"`
+
+	// api
+	ApiCalled common.TopicConfig `json:"api_called"`
+
 	// common
 	BigModelStarted  common.TopicConfig `json:"bigmodel_started"`
 	BigModelFinished common.TopicConfig `json:"bigmodel_finished"`
+
+	// moderation
+	ModerationBlocked common.TopicConfig `json:"moderation_blocked"`
+}
+
+// AsyncTaskTopics returns the topics of the status changes of async tasks
+func (cfg *Config) AsyncTaskTopics() []string {
+	items := []*common.TopicConfig{
+		&cfg.InferenceStart,
+		&cfg.InferenceError,
+		&cfg.InferenceAsyncStart,
+		&cfg.InferenceAsyncFinish,
+		&cfg.AsyncTaskCreated,
+		&cfg.AsyncTaskStarted,
+		&cfg.AsyncTaskFinished,
+		&cfg.AsyncTaskFailed,
+	}
+
+	r := make([]string, 0, len(items))
+	for _, v := range items {
+		if v.Topic != "" {
+			r = append(r, v.Topic)
+		}
+	}
+
+	return r
 }
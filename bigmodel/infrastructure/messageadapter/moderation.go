@@ -0,0 +1,47 @@
+package messageadapter
+
+import (
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+	common "github.com/opensourceways/xihe-server/common/domain/message"
+)
+
+// NewModerationAuditor sends the audit records to the topic,
+// they are saved by the message server.
+func NewModerationAuditor(cfg *Config, p common.Publisher) moderation.Auditor {
+	return &moderationAuditor{cfg: cfg.ModerationBlocked, publisher: p}
+}
+
+type moderationAuditor struct {
+	cfg       common.TopicConfig
+	publisher common.Publisher
+}
+
+func (impl *moderationAuditor) Audit(r *moderation.AuditRecord) {
+	logrus.Warnf(
+		"moderation blocked the %s of model %s by %s, label:%s, reason:%s",
+		r.Kind, r.Model, r.Provider, r.Label, r.Reason,
+	)
+
+	msg := common.MsgNormal{
+		Type: impl.cfg.Name,
+		Desc: "content is blocked by moderation",
+		Details: map[string]string{
+			"model":      r.Model,
+			"kind":       r.Kind,
+			"content":    r.Content,
+			"provider":   r.Provider,
+			"label":      r.Label,
+			"reason":     r.Reason,
+			"created_at": strconv.FormatInt(r.CreatedAt, 10),
+		},
+		CreatedAt: r.CreatedAt,
+	}
+
+	if err := impl.publisher.Publish(impl.cfg.Topic, &msg, nil); err != nil {
+		logrus.Errorf("send moderation audit failed, err:%s", err.Error())
+	}
+}
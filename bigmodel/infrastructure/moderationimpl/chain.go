@@ -0,0 +1,110 @@
+package moderationimpl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+)
+
+// chain asks the providers in order.
+// In the mode of fallback, the next provider is asked only if the previous one fails.
+// In the mode of all, the content is blocked if any of the providers blocks it.
+type chain struct {
+	providers []moderation.Moderation
+	mode      string
+	failOpen  bool
+	timeout   time.Duration
+}
+
+func (c *chain) Name() string {
+	return "chain"
+}
+
+func (c *chain) CheckText(content string) (moderation.Result, error) {
+	return c.check(func(p moderation.Moderation) (moderation.Result, error) {
+		return p.CheckText(content)
+	})
+}
+
+func (c *chain) CheckImages(urls []string) (moderation.Result, error) {
+	return c.check(func(p moderation.Moderation) (moderation.Result, error) {
+		return p.CheckImages(urls)
+	})
+}
+
+func (c *chain) check(f func(moderation.Moderation) (moderation.Result, error)) (
+	r moderation.Result, err error,
+) {
+	passed := false
+
+	for _, p := range c.providers {
+		v, e := c.call(p, f)
+		if e != nil {
+			if e == errorImageUnsupported {
+				continue
+			}
+
+			logrus.Warnf("moderation provider %s failed, err:%s", p.Name(), e.Error())
+
+			err = e
+
+			continue
+		}
+
+		if v.Blocked {
+			return v, nil
+		}
+
+		passed = true
+
+		if c.mode == ModeFallback {
+			return v, nil
+		}
+	}
+
+	// in the mode of all, every provider must pass the content
+	if passed && err == nil {
+		return moderation.Result{}, nil
+	}
+
+	if err == nil {
+		err = errors.New("no moderation provider")
+	}
+
+	if c.failOpen {
+		logrus.Warnf("the moderation providers failed, pass the content, err:%s", err.Error())
+
+		return moderation.Result{}, nil
+	}
+
+	return
+}
+
+// call returns error if the provider doesn't respond in time
+func (c *chain) call(
+	p moderation.Moderation, f func(moderation.Moderation) (moderation.Result, error),
+) (moderation.Result, error) {
+	type output struct {
+		r   moderation.Result
+		err error
+	}
+
+	ch := make(chan output, 1)
+
+	go func() {
+		r, err := f(p)
+		ch <- output{r, err}
+	}()
+
+	select {
+	case v := <-ch:
+		return v.r, v.err
+
+	case <-time.After(c.timeout):
+		return moderation.Result{}, fmt.Errorf("timeout after %s", c.timeout)
+	}
+}
@@ -0,0 +1,137 @@
+package moderationimpl
+
+import (
+	"fmt"
+)
+
+const (
+	ProviderHuawei = "huawei"
+	ProviderLocal  = "local"
+
+	ModeFallback = "fallback"
+	ModeAll      = "all"
+)
+
+type Config struct {
+	// the fields of Huawei are inline for the compatibility of old config
+	Huawei
+
+	Local    LocalConfig   `json:"local"`
+	Policy   Policy        `json:"policy"`
+	Policies []ModelPolicy `json:"policies"`
+
+	// Timeout is the max seconds to wait for a provider
+	Timeout int `json:"timeout"`
+
+	// MaxAuditContent is the max length of the content saved in the audit record
+	MaxAuditContent int `json:"max_audit_content"`
+}
+
+func (cfg *Config) SetDefault() {
+	cfg.Policy.setDefault(&Policy{
+		Providers: []string{ProviderHuawei},
+		Mode:      ModeFallback,
+	})
+
+	for i := range cfg.Policies {
+		cfg.Policies[i].setDefault(&cfg.Policy)
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5
+	}
+
+	if cfg.MaxAuditContent <= 0 {
+		cfg.MaxAuditContent = 500
+	}
+}
+
+func (cfg *Config) Validate() error {
+	if err := cfg.Local.validate(); err != nil {
+		return err
+	}
+
+	if err := cfg.Policy.validate(); err != nil {
+		return err
+	}
+
+	if err := cfg.checkLocal(&cfg.Policy); err != nil {
+		return err
+	}
+
+	models := map[string]bool{}
+	for i := range cfg.Policies {
+		item := &cfg.Policies[i]
+
+		if models[item.Model] {
+			return fmt.Errorf("duplicate moderation policy of model %s", item.Model)
+		}
+		models[item.Model] = true
+
+		if err := item.validate(); err != nil {
+			return err
+		}
+
+		if err := cfg.checkLocal(&item.Policy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkLocal avoids the local provider which passes everything
+func (cfg *Config) checkLocal(p *Policy) error {
+	if len(cfg.Local.Rules) > 0 {
+		return nil
+	}
+
+	for _, v := range p.Providers {
+		if v == ProviderLocal {
+			return fmt.Errorf("moderation provider %s has no rules", v)
+		}
+	}
+
+	return nil
+}
+
+// Policy decides how to check the content of a model
+type Policy struct {
+	Providers []string `json:"providers"`
+	Mode      string   `json:"mode"`
+
+	// FailOpen passes the content if the providers fail.
+	// In the mode of all, it is the failure of any provider.
+	FailOpen bool `json:"fail_open"`
+	Disabled bool `json:"disabled"`
+}
+
+func (p *Policy) setDefault(d *Policy) {
+	if len(p.Providers) == 0 {
+		p.Providers = d.Providers
+	}
+
+	if p.Mode == "" {
+		p.Mode = d.Mode
+	}
+}
+
+func (p *Policy) validate() error {
+	if p.Mode != ModeFallback && p.Mode != ModeAll {
+		return fmt.Errorf("invalid moderation mode %s", p.Mode)
+	}
+
+	for _, v := range p.Providers {
+		if v != ProviderHuawei && v != ProviderLocal {
+			return fmt.Errorf("unknown moderation provider %s", v)
+		}
+	}
+
+	return nil
+}
+
+type ModelPolicy struct {
+	Model string `json:"model"       required:"true"`
+
+	Policy
+}
@@ -0,0 +1,50 @@
+package moderationimpl
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	common "github.com/opensourceways/xihe-server/common/domain/moderation"
+)
+
+// NewContentModeration checks the content created by users by the same providers as bigmodel,
+// the scene of content is used as the model to find the policy.
+func NewContentModeration(s Service) common.Moderation {
+	return contentModeration{s}
+}
+
+type contentModeration struct {
+	s Service
+}
+
+func (m contentModeration) CheckText(scene, content string) error {
+	if content == "" {
+		return nil
+	}
+
+	err := m.s.CheckText(scene, content)
+	if err != nil && bigmodel.IsErrorSensitiveInfo(err) {
+		return common.NewErrorSensitiveContent(err)
+	}
+
+	return err
+}
+
+// NewRepoAuditor saves the audit records into the repository directly
+func NewRepoAuditor(repo repository.ModerationAudit) moderation.Auditor {
+	return repoAuditor{repo}
+}
+
+type repoAuditor struct {
+	repo repository.ModerationAudit
+}
+
+func (a repoAuditor) Audit(r *moderation.AuditRecord) {
+	logAuditor{}.Audit(r)
+
+	if err := a.repo.AddAudit(r); err != nil {
+		logrus.Errorf("save moderation audit failed, err:%s", err.Error())
+	}
+}
@@ -0,0 +1,174 @@
+package moderationimpl
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/basic"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/region"
+	hwmoderation "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/moderation/v3"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/moderation/v3/model"
+
+	moderationv2 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/moderation/v2"
+	modelv2 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/moderation/v2/model"
+	regionv2 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/moderation/v2/region"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+)
+
+const suggestionPass = "pass"
+
+type Huawei struct {
+	Endpoint   string `json:"endpoint"       required:"true"`
+	AccessKey  string `json:"access_key"     required:"true"`
+	SecretKey  string `json:"secret_key"     required:"true"`
+	IAMEndpint string `json:"iam_endpoint"   required:"true"`
+	Region     string `json:"region"         required:"true"`
+}
+
+func newHuawei(cfg *Huawei) moderation.Moderation {
+	auth := basic.NewCredentialsBuilder().
+		WithAk(cfg.AccessKey).
+		WithSk(cfg.SecretKey).
+		WithIamEndpointOverride(cfg.IAMEndpint).
+		Build()
+
+	cli := hwmoderation.NewModerationClient(
+		hwmoderation.ModerationClientBuilder().
+			WithRegion(region.NewRegion(cfg.Region, cfg.Endpoint)).
+			WithCredential(auth).
+			Build(),
+	)
+
+	authv2 := basic.NewCredentialsBuilder().
+		WithAk(cfg.AccessKey).
+		WithSk(cfg.SecretKey).
+		Build()
+
+	cliv2 := moderationv2.NewModerationClient(
+		hwmoderation.ModerationClientBuilder().
+			WithRegion(regionv2.ValueOf(cfg.Region)).
+			WithCredential(authv2).
+			Build(),
+	)
+
+	return &huawei{cli, cliv2}
+}
+
+// huawei is the moderation of Huawei Cloud
+type huawei struct {
+	cli   *hwmoderation.ModerationClient
+	cliv2 *moderationv2.ModerationClient
+}
+
+func (h *huawei) Name() string {
+	return ProviderHuawei
+}
+
+func (h *huawei) CheckText(content string) (r moderation.Result, err error) {
+	request := &model.RunTextModerationRequest{
+		Body: &model.TextDetectionReq{
+			Data: &model.TextDetectionDataReq{
+				Text: content,
+			},
+			EventType: "comment",
+		},
+	}
+
+	resp, err := h.cli.RunTextModeration(request)
+	if err != nil {
+		return
+	}
+
+	if resp.Result == nil || resp.Result.Suggestion == nil {
+		err = errors.New("no suggestion of text moderation")
+
+		return
+	}
+
+	r.Provider = ProviderHuawei
+
+	if *resp.Result.Suggestion == suggestionPass {
+		return
+	}
+
+	r.Blocked = true
+	r.Label = strValue(resp.Result.Label)
+	r.Reason = textReason(resp.Result.Details)
+
+	return
+}
+
+func (h *huawei) CheckImages(urls []string) (r moderation.Result, err error) {
+	request := &modelv2.RunImageBatchModerationRequest{}
+	var listCategoriesbody = []modelv2.ImageBatchModerationReqCategories{
+		modelv2.GetImageBatchModerationReqCategoriesEnum().ALL,
+	}
+	var listUrlsbody = urls
+	rule := "default"
+	request.Body = &modelv2.ImageBatchModerationReq{
+		ModerationRule: &rule,
+		Categories:     &listCategoriesbody,
+		Urls:           listUrlsbody,
+	}
+	resp, err := h.cliv2.RunImageBatchModeration(request)
+	if err != nil {
+		return
+	}
+
+	if resp.Result == nil {
+		err = errors.New("no result of image moderation")
+
+		return
+	}
+
+	r.Provider = ProviderHuawei
+
+	for _, res := range *resp.Result {
+		if res.Suggestion == nil {
+			err = errors.New("no suggestion of image moderation")
+
+			return
+		}
+
+		if *res.Suggestion != suggestionPass {
+			r.Blocked = true
+			r.Label = *res.Suggestion
+			r.Reason = strValue(res.Url)
+
+			return
+		}
+	}
+
+	return
+}
+
+// textReason collects the hit segments
+func textReason(details *[]model.TextDetectionResultDetail) string {
+	if details == nil {
+		return ""
+	}
+
+	var v []string
+	for _, d := range *details {
+		if d.Segments == nil {
+			continue
+		}
+
+		for _, s := range *d.Segments {
+			if s.Segment != nil {
+				v = append(v, *s.Segment)
+			}
+		}
+	}
+
+	return strings.Join(v, ",")
+}
+
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,135 @@
+package moderationimpl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+)
+
+var errorImageUnsupported = errors.New("local moderation can't check images")
+
+type LocalConfig struct {
+	Rules []LocalRule `json:"rules"`
+
+	// AllowList are the words which are removed before matching,
+	// so that they won't be blocked by the keywords or patterns which are part of them.
+	AllowList []string `json:"allow_list"`
+}
+
+func (cfg *LocalConfig) validate() error {
+	for i := range cfg.Rules {
+		for _, p := range cfg.Rules[i].Patterns {
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("invalid pattern %s of moderation rule %s", p, cfg.Rules[i].Label)
+			}
+		}
+	}
+
+	return nil
+}
+
+// LocalRule blocks the content which contains any keyword or matches any pattern
+type LocalRule struct {
+	Label    string   `json:"label"       required:"true"`
+	Keywords []string `json:"keywords"`
+	Patterns []string `json:"patterns"`
+}
+
+func newLocal(cfg *LocalConfig) (moderation.Moderation, error) {
+	l := &local{
+		rules: make([]localRule, len(cfg.Rules)),
+	}
+
+	for i := range cfg.Rules {
+		item := &cfg.Rules[i]
+		r := &l.rules[i]
+
+		r.label = item.Label
+
+		for _, k := range item.Keywords {
+			if k = strings.TrimSpace(k); k != "" {
+				r.keywords = append(r.keywords, strings.ToLower(k))
+			}
+		}
+
+		for _, p := range item.Patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, err
+			}
+
+			r.patterns = append(r.patterns, re)
+		}
+	}
+
+	if len(cfg.AllowList) > 0 {
+		v := make([]string, 0, 2*len(cfg.AllowList))
+		for _, w := range cfg.AllowList {
+			if w = strings.TrimSpace(w); w != "" {
+				v = append(v, strings.ToLower(w), " ")
+			}
+		}
+
+		l.allowed = strings.NewReplacer(v...)
+	}
+
+	return l, nil
+}
+
+type localRule struct {
+	label    string
+	keywords []string
+	patterns []*regexp.Regexp
+}
+
+// local is the moderation by the rules of config, which matches the keywords case-insensitively
+type local struct {
+	rules   []localRule
+	allowed *strings.Replacer
+}
+
+func (l *local) Name() string {
+	return ProviderLocal
+}
+
+func (l *local) CheckText(content string) (r moderation.Result, err error) {
+	r.Provider = ProviderLocal
+
+	s := strings.ToLower(content)
+	if l.allowed != nil {
+		s = l.allowed.Replace(s)
+	}
+
+	for i := range l.rules {
+		rule := &l.rules[i]
+
+		for _, k := range rule.keywords {
+			if strings.Contains(s, k) {
+				r.Blocked = true
+				r.Label = rule.label
+				r.Reason = "keyword: " + k
+
+				return
+			}
+		}
+
+		for _, re := range rule.patterns {
+			if re.MatchString(s) {
+				r.Blocked = true
+				r.Label = rule.label
+				r.Reason = "pattern: " + re.String()
+
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func (l *local) CheckImages(urls []string) (moderation.Result, error) {
+	return moderation.Result{}, errorImageUnsupported
+}
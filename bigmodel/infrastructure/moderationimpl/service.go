@@ -0,0 +1,152 @@
+package moderationimpl
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// Service checks the content of a model by the policy of it
+type Service interface {
+	CheckText(model, content string) error
+	CheckImages(model string, urls []string) error
+
+	// SetAuditor replaces the auditor which logs the records by default
+	SetAuditor(moderation.Auditor)
+}
+
+func NewService(cfg *Config) (Service, error) {
+	local, err := newLocal(&cfg.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := map[string]moderation.Moderation{
+		ProviderHuawei: newHuawei(&cfg.Huawei),
+		ProviderLocal:  local,
+	}
+
+	timeout := time.Duration(cfg.Timeout) * time.Second
+
+	newChain := func(p *Policy) *chain {
+		if p.Disabled {
+			return nil
+		}
+
+		v := make([]moderation.Moderation, len(p.Providers))
+		for i, name := range p.Providers {
+			v[i] = providers[name]
+		}
+
+		return &chain{
+			providers: v,
+			mode:      p.Mode,
+			failOpen:  p.FailOpen,
+			timeout:   timeout,
+		}
+	}
+
+	s := &service{
+		policy:     newChain(&cfg.Policy),
+		policies:   make(map[string]*chain, len(cfg.Policies)),
+		maxContent: cfg.MaxAuditContent,
+	}
+
+	for i := range cfg.Policies {
+		item := &cfg.Policies[i]
+		s.policies[item.Model] = newChain(&item.Policy)
+	}
+
+	s.SetAuditor(logAuditor{})
+
+	return s, nil
+}
+
+type auditorHolder struct {
+	moderation.Auditor
+}
+
+type service struct {
+	policy     *chain
+	policies   map[string]*chain
+	auditor    atomic.Value
+	maxContent int
+}
+
+func (s *service) SetAuditor(a moderation.Auditor) {
+	s.auditor.Store(auditorHolder{a})
+}
+
+func (s *service) CheckText(model, content string) error {
+	c := s.chainOf(model)
+	if c == nil {
+		return nil
+	}
+
+	r, err := c.CheckText(content)
+	if err != nil || !r.Blocked {
+		return err
+	}
+
+	s.audit(model, moderation.KindText, content, &r)
+
+	return bigmodel.NewErrorSensitiveInfo(errors.New("invalid text"))
+}
+
+func (s *service) CheckImages(model string, urls []string) error {
+	c := s.chainOf(model)
+	if c == nil {
+		return nil
+	}
+
+	r, err := c.CheckImages(urls)
+	if err != nil || !r.Blocked {
+		return err
+	}
+
+	s.audit(model, moderation.KindImage, strings.Join(urls, ","), &r)
+
+	return bigmodel.NewErrorSensitiveInfo(errors.New("the generated image is illegal, please try again"))
+}
+
+// chainOf returns nil if the moderation is disabled for the model
+func (s *service) chainOf(model string) *chain {
+	if c, ok := s.policies[model]; ok {
+		return c
+	}
+
+	return s.policy
+}
+
+func (s *service) audit(model, kind, content string, r *moderation.Result) {
+	if v := []rune(content); len(v) > s.maxContent {
+		content = string(v[:s.maxContent])
+	}
+
+	s.auditor.Load().(auditorHolder).Audit(&moderation.AuditRecord{
+		Model:     model,
+		Kind:      kind,
+		Content:   content,
+		Provider:  r.Provider,
+		Label:     r.Label,
+		Reason:    r.Reason,
+		CreatedAt: utils.Now(),
+	})
+}
+
+// logAuditor
+type logAuditor struct{}
+
+func (a logAuditor) Audit(r *moderation.AuditRecord) {
+	logrus.Warnf(
+		"moderation blocked the %s of model %s by %s, label:%s, reason:%s",
+		r.Kind, r.Model, r.Provider, r.Label, r.Reason,
+	)
+}
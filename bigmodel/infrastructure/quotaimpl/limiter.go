@@ -0,0 +1,109 @@
+package quotaimpl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/quota"
+)
+
+const (
+	keyPrefix = "bigmodel_api_quota"
+
+	timeout = 3 * time.Second
+)
+
+// takeScript increases the counter and sets its expiry at once,
+// so that the counter is never left without the expiry.
+var takeScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("EXPIREAT", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+func NewLimiter(cli *redis.Client) quota.Limiter {
+	return &limiter{cli: cli}
+}
+
+type limiter struct {
+	cli *redis.Client
+}
+
+func (impl *limiter) Take(key string, limits []quota.Limit, now time.Time) (r quota.Result, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	taken := make([]string, 0, len(limits))
+
+	// give back what has been taken when the call is rejected or failed
+	defer func() {
+		if err == nil && r.Allowed {
+			return
+		}
+
+		impl.giveBack(taken)
+	}()
+
+	r.Allowed = true
+	r.Remaining = -1
+
+	for i := range limits {
+		item := &limits[i]
+		if item.Max <= 0 {
+			continue
+		}
+
+		end := quota.WindowEnd(item.Window, now)
+		k := fmt.Sprintf("%s:%s:%s:%s", keyPrefix, key, item.Window, quota.WindowId(item.Window, now))
+
+		var n int64
+		if n, err = takeScript.Run(ctx, impl.cli, []string{k}, end.Unix()).Int64(); err != nil {
+			return
+		}
+
+		taken = append(taken, k)
+
+		v := quota.Result{
+			Allowed:   int(n) <= item.Max,
+			Window:    item.Window,
+			Limit:     item.Max,
+			Remaining: item.Max - int(n),
+			ResetAt:   end.Unix(),
+		}
+
+		if !v.Allowed {
+			v.Remaining = 0
+
+			return v, nil
+		}
+
+		if r.Remaining < 0 || v.Remaining < r.Remaining {
+			r = v
+		}
+	}
+
+	return
+}
+
+// giveBack decreases the counters by a new context, because the context of
+// taking may have been timeout which is one of the reasons to give back.
+func (impl *limiter) giveBack(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, k := range keys {
+		if err := impl.cli.Decr(ctx, k).Err(); err != nil {
+			logrus.Errorf("give back the quota of %s failed, err:%s", k, err.Error())
+		}
+	}
+}
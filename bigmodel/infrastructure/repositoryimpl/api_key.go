@@ -0,0 +1,185 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	repoerr "github.com/opensourceways/xihe-server/domain/repository"
+)
+
+func NewApiKey(m mongodbClient) repository.ApiKey {
+	return &apiKeyRepoImpl{m}
+}
+
+type apiKeyRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *apiKeyRepoImpl) AddApiKey(k *domain.ApiKey) (string, error) {
+	doc, err := genDoc(toApiKeyDoc(k))
+	if err != nil {
+		return "", err
+	}
+
+	doc[fieldId] = newId()
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.NewDocIfNotExist(ctx, bson.M{fieldHash: k.Hash}, doc)
+
+		return err
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocExists(err) {
+			err = repoerr.NewErrorDuplicateCreating(err)
+		}
+
+		return "", err
+	}
+
+	return doc[fieldId].(string), nil
+}
+
+func (impl *apiKeyRepoImpl) GetApiKeyByHash(hash string) (k domain.ApiKey, err error) {
+	var v dApiKey
+
+	f := func(ctx context.Context) error {
+		return impl.cli.GetDoc(ctx, bson.M{fieldHash: hash}, nil, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocNotExists(err) {
+			err = repoerr.NewErrorResourceNotExists(err)
+		}
+
+		return
+	}
+
+	err = v.toApiKey(&k)
+
+	return
+}
+
+func (impl *apiKeyRepoImpl) ListApiKeys(user types.Account, model domain.ModelName) (
+	r []domain.ApiKey, err error,
+) {
+	var v []dApiKey
+
+	f := func(ctx context.Context) error {
+		cursor, err := impl.cli.Collection().Find(
+			ctx,
+			bson.M{fiedUser: user.Account(), fieldModelName: model.ModelName()},
+			options.Find().SetSort(bson.M{"created_at": -1}),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		return
+	}
+
+	r = make([]domain.ApiKey, 0, len(v))
+	for i := range v {
+		var item domain.ApiKey
+		if err := v[i].toApiKey(&item); err != nil {
+			logrus.Errorf("invalid api key %s, err:%s", v[i].Id, err.Error())
+
+			continue
+		}
+
+		r = append(r, item)
+	}
+
+	return
+}
+
+func (impl *apiKeyRepoImpl) RevokeApiKey(
+	user types.Account, model domain.ModelName, id string, t int64,
+) error {
+	filter := bson.M{
+		fieldId:        id,
+		fiedUser:       user.Account(),
+		fieldModelName: model.ModelName(),
+		fieldRevokedAt: 0,
+	}
+
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().UpdateOne(
+			ctx, filter, bson.M{mongoCmdSet: bson.M{fieldRevokedAt: t}},
+		)
+		if err != nil {
+			return err
+		}
+
+		if r.MatchedCount == 0 {
+			return repoerr.NewErrorResourceNotExists(
+				errors.New("the api key does not exist or has been revoked"),
+			)
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (impl *apiKeyRepoImpl) UpdateLastUsed(id string, t int64) error {
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.Collection().UpdateOne(
+			ctx, bson.M{fieldId: id}, bson.M{mongoCmdSet: bson.M{fieldLastUsed: t}},
+		)
+
+		return err
+	}
+
+	return withContext(f)
+}
+
+func toApiKeyDoc(k *domain.ApiKey) dApiKey {
+	return dApiKey{
+		User:       k.User.Account(),
+		ModelName:  k.Model.ModelName(),
+		Name:       k.Name.ApiKeyName(),
+		Hash:       k.Hash,
+		Hint:       k.Hint,
+		CreatedAt:  k.CreatedAt,
+		ExpireAt:   k.ExpireAt,
+		RevokedAt:  k.RevokedAt,
+		LastUsedAt: k.LastUsedAt,
+	}
+}
+
+func (d *dApiKey) toApiKey(k *domain.ApiKey) (err error) {
+	if k.User, err = types.NewAccount(d.User); err != nil {
+		return
+	}
+
+	if k.Model, err = domain.NewModelName(d.ModelName); err != nil {
+		return
+	}
+
+	if k.Name, err = domain.NewApiKeyName(d.Name); err != nil {
+		return
+	}
+
+	k.Id = d.Id
+	k.Hash = d.Hash
+	k.Hint = d.Hint
+	k.CreatedAt = d.CreatedAt
+	k.ExpireAt = d.ExpireAt
+	k.RevokedAt = d.RevokedAt
+	k.LastUsedAt = d.LastUsedAt
+
+	return
+}
@@ -120,6 +120,12 @@ func (a *dApiApply) toUserApiRecord(d *domain.UserApiRecord) (err error) {
 	d.Token = a.Token
 	d.UpdateAt = a.UpdateAt
 	d.Version = a.Version
+	d.Quota = domain.ApiQuota{
+		Daily:   a.DailyQuota,
+		Monthly: a.MonthlyQuota,
+		RPM:     a.RPM,
+	}
+
 	return
 }
 
@@ -132,5 +138,9 @@ func toApiApplyDoc(d *domain.UserApiRecord) (bson.M, error) {
 		Token:     d.Token,
 		Enabled:   d.Enabled,
 		CallCount: 0,
+
+		DailyQuota:   d.Quota.Daily,
+		MonthlyQuota: d.Quota.Monthly,
+		RPM:          d.Quota.RPM,
 	})
 }
@@ -0,0 +1,114 @@
+package repositoryimpl
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+func NewApiUsage(m mongodbClient) repository.ApiUsage {
+	return &apiUsageRepoImpl{m}
+}
+
+type apiUsageRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *apiUsageRepoImpl) IncUsage(v *domain.ApiUsage) error {
+	filter := bson.M{
+		fiedUser:       v.User.Account(),
+		fieldModelName: v.Model.ModelName(),
+		fieldDate:      v.Date,
+		fieldHour:      v.Hour,
+		fieldStatus:    v.Status,
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.Collection().UpdateOne(
+			ctx, filter,
+			bson.M{"$inc": bson.M{fieldCount: v.Count, fieldLatency: v.Latency}},
+			options.Update().SetUpsert(true),
+		)
+
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (impl *apiUsageRepoImpl) ListUsage(user types.Account, opt *repository.ApiUsageListOption) (
+	r []domain.ApiUsage, err error,
+) {
+	filter := bson.M{
+		fiedUser: user.Account(),
+	}
+
+	if opt.Model != nil {
+		filter[fieldModelName] = opt.Model.ModelName()
+	}
+
+	date := bson.M{}
+	if opt.Start != "" {
+		date["$gte"] = opt.Start
+	}
+
+	if opt.End != "" {
+		date["$lte"] = opt.End
+	}
+
+	if len(date) > 0 {
+		filter[fieldDate] = date
+	}
+
+	var v []dApiUsage
+
+	f := func(ctx context.Context) error {
+		cursor, err := impl.cli.Collection().Find(
+			ctx, filter, options.Find().SetSort(bson.D{{Key: fieldDate, Value: 1}, {Key: fieldHour, Value: 1}}),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		return
+	}
+
+	r = make([]domain.ApiUsage, 0, len(v))
+	for i := range v {
+		var item domain.ApiUsage
+		if err := v[i].toApiUsage(&item); err != nil {
+			continue
+		}
+
+		r = append(r, item)
+	}
+
+	return
+}
+
+func (d *dApiUsage) toApiUsage(v *domain.ApiUsage) (err error) {
+	if v.User, err = types.NewAccount(d.User); err != nil {
+		return
+	}
+
+	if v.Model, err = domain.NewModelName(d.ModelName); err != nil {
+		return
+	}
+
+	v.Date = d.Date
+	v.Hour = d.Hour
+	v.Status = d.Status
+	v.Count = d.Count
+	v.Latency = d.Latency
+
+	return
+}
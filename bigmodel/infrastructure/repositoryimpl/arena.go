@@ -0,0 +1,180 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	repoerr "github.com/opensourceways/xihe-server/domain/repository"
+)
+
+// arena battle
+func NewArenaBattle(m mongodbClient) repository.ArenaBattle {
+	return &arenaBattleRepoImpl{m}
+}
+
+type arenaBattleRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *arenaBattleRepoImpl) AddBattle(b *domain.ArenaBattle) (string, error) {
+	doc, err := genDoc(dArenaBattle{
+		User:      b.User.Account(),
+		Prompt:    b.Prompt,
+		Models:    b.Models,
+		CreatedAt: b.CreatedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	doc[fieldId] = newId()
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.NewDocIfNotExist(ctx, bson.M{fieldId: doc[fieldId]}, doc)
+
+		return err
+	}
+
+	if err = withContext(f); err != nil {
+		return "", err
+	}
+
+	return doc[fieldId].(string), nil
+}
+
+func (impl *arenaBattleRepoImpl) GetBattle(user types.Account, id string) (
+	b domain.ArenaBattle, err error,
+) {
+	var v dArenaBattle
+
+	f := func(ctx context.Context) error {
+		return impl.cli.GetDoc(ctx, bson.M{fieldId: id, fiedUser: user.Account()}, nil, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocNotExists(err) {
+			err = repoerr.NewErrorResourceNotExists(errors.New("the battle does not exist"))
+		}
+
+		return
+	}
+
+	b = domain.ArenaBattle{
+		Id:        v.Id,
+		User:      user,
+		Prompt:    v.Prompt,
+		Models:    v.Models,
+		Winner:    v.Winner,
+		CreatedAt: v.CreatedAt,
+		VotedAt:   v.VotedAt,
+	}
+
+	return
+}
+
+func (impl *arenaBattleRepoImpl) SaveVote(b *domain.ArenaBattle) error {
+	filter := bson.M{
+		fieldId:     b.Id,
+		fiedUser:    b.User.Account(),
+		fieldWinner: "",
+	}
+
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().UpdateOne(
+			ctx, filter,
+			bson.M{mongoCmdSet: bson.M{fieldWinner: b.Winner, fieldVotedAt: b.VotedAt}},
+		)
+		if err != nil {
+			return err
+		}
+
+		if r.MatchedCount == 0 {
+			return repoerr.NewErrorConcurrentUpdating(errors.New("the battle has been voted"))
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+// arena rating
+func NewArenaRating(m mongodbClient) repository.ArenaRating {
+	return &arenaRatingRepoImpl{m}
+}
+
+type arenaRatingRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *arenaRatingRepoImpl) ListRatings(models []string) ([]domain.ArenaRating, error) {
+	filter := bson.M{}
+	if len(models) > 0 {
+		filter[fieldModel] = bson.M{"$in": models}
+	}
+
+	var v []dArenaRating
+
+	f := func(ctx context.Context) error {
+		cursor, err := impl.cli.Collection().Find(
+			ctx, filter, options.Find().SetSort(bson.M{fieldScore: -1}),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	r := make([]domain.ArenaRating, len(v))
+	for i := range v {
+		item := &v[i]
+
+		r[i] = domain.ArenaRating{
+			Model:  item.Model,
+			Score:  item.Score,
+			Wins:   item.Wins,
+			Losses: item.Losses,
+			Ties:   item.Ties,
+		}
+	}
+
+	return r, nil
+}
+
+func (impl *arenaRatingRepoImpl) AddChanges(changes []domain.ArenaRating) error {
+	f := func(ctx context.Context) error {
+		for i := range changes {
+			item := &changes[i]
+
+			_, err := impl.cli.Collection().UpdateOne(
+				ctx,
+				bson.M{fieldModel: item.Model},
+				bson.M{"$inc": bson.M{
+					fieldScore:  item.Score,
+					fieldWins:   item.Wins,
+					fieldLosses: item.Losses,
+					fieldTies:   item.Ties,
+				}},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
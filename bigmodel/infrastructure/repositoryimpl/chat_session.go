@@ -0,0 +1,248 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	repoerr "github.com/opensourceways/xihe-server/domain/repository"
+)
+
+func NewChatSession(m mongodbClient) repository.ChatSession {
+	return &chatSessionRepoImpl{m}
+}
+
+type chatSessionRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *chatSessionRepoImpl) sessionFilter(owner types.Account, id string) bson.M {
+	return bson.M{
+		fieldId:    id,
+		fieldOwner: owner.Account(),
+	}
+}
+
+func (impl *chatSessionRepoImpl) errSessionNotExists() error {
+	return repoerr.NewErrorResourceNotExists(errors.New("the chat session does not exist"))
+}
+
+func (impl *chatSessionRepoImpl) AddSession(s *domain.ChatSession) (string, error) {
+	doc, err := genDoc(toChatSessionDoc(s))
+	if err != nil {
+		return "", err
+	}
+
+	doc[fieldId] = newId()
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.NewDocIfNotExist(ctx, bson.M{fieldId: doc[fieldId]}, doc)
+
+		return err
+	}
+
+	if err = withContext(f); err != nil {
+		return "", err
+	}
+
+	return doc[fieldId].(string), nil
+}
+
+func (impl *chatSessionRepoImpl) GetSession(owner types.Account, id string) (
+	s domain.ChatSession, err error,
+) {
+	var v dChatSession
+
+	f := func(ctx context.Context) error {
+		return impl.cli.GetDoc(ctx, impl.sessionFilter(owner, id), nil, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocNotExists(err) {
+			err = impl.errSessionNotExists()
+		}
+
+		return
+	}
+
+	err = v.toChatSession(&s)
+
+	return
+}
+
+func (impl *chatSessionRepoImpl) ListSessions(owner types.Account) (
+	r []domain.ChatSession, err error,
+) {
+	var v []dChatSession
+
+	f := func(ctx context.Context) error {
+		cursor, err := impl.cli.Collection().Find(
+			ctx,
+			bson.M{fieldOwner: owner.Account()},
+			options.Find().
+				SetProjection(bson.M{fieldRounds: 0}).
+				SetSort(bson.M{fieldUpdatedAt: -1}),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		return
+	}
+
+	r = make([]domain.ChatSession, 0, len(v))
+	for i := range v {
+		var item domain.ChatSession
+		if err := v[i].toChatSession(&item); err != nil {
+			continue
+		}
+
+		r = append(r, item)
+	}
+
+	return
+}
+
+func (impl *chatSessionRepoImpl) CountSessions(owner types.Account) (n int, err error) {
+	f := func(ctx context.Context) error {
+		v, err := impl.cli.Collection().CountDocuments(ctx, bson.M{fieldOwner: owner.Account()})
+		n = int(v)
+
+		return err
+	}
+
+	err = withContext(f)
+
+	return
+}
+
+func (impl *chatSessionRepoImpl) RenameSession(
+	owner types.Account, id string, title domain.ChatSessionTitle, t int64,
+) error {
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().UpdateOne(
+			ctx, impl.sessionFilter(owner, id),
+			bson.M{mongoCmdSet: bson.M{
+				fieldTitle:     title.ChatSessionTitle(),
+				fieldUpdatedAt: t,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+
+		if r.MatchedCount == 0 {
+			return impl.errSessionNotExists()
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (impl *chatSessionRepoImpl) DeleteSession(owner types.Account, id string) error {
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().DeleteOne(ctx, impl.sessionFilter(owner, id))
+		if err != nil {
+			return err
+		}
+
+		if r.DeletedCount == 0 {
+			return impl.errSessionNotExists()
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (impl *chatSessionRepoImpl) AddRound(
+	owner types.Account, id string, r *domain.ChatRound, keep int,
+) error {
+	doc, err := genDoc(dChatRound{
+		Question:  r.Question,
+		Answer:    r.Answer,
+		CreatedAt: r.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		mongoCmdPush: bson.M{fieldRounds: bson.M{
+			"$each":  bson.A{doc},
+			"$slice": -keep,
+		}},
+		mongoCmdSet: bson.M{fieldUpdatedAt: r.CreatedAt},
+	}
+
+	f := func(ctx context.Context) error {
+		v, err := impl.cli.Collection().UpdateOne(ctx, impl.sessionFilter(owner, id), update)
+		if err != nil {
+			return err
+		}
+
+		if v.MatchedCount == 0 {
+			return impl.errSessionNotExists()
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func toChatSessionDoc(s *domain.ChatSession) dChatSession {
+	return dChatSession{
+		Owner:     s.Owner.Account(),
+		Model:     s.Model.ModelName(),
+		Title:     s.Title.ChatSessionTitle(),
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func (d *dChatSession) toChatSession(s *domain.ChatSession) (err error) {
+	if s.Owner, err = types.NewAccount(d.Owner); err != nil {
+		return
+	}
+
+	if s.Model, err = domain.NewModelName(d.Model); err != nil {
+		return
+	}
+
+	if s.Title, err = domain.NewChatSessionTitle(d.Title); err != nil {
+		return
+	}
+
+	s.Id = d.Id
+	s.CreatedAt = d.CreatedAt
+	s.UpdatedAt = d.UpdatedAt
+
+	if len(d.Rounds) > 0 {
+		s.Rounds = make([]domain.ChatRound, len(d.Rounds))
+
+		for i := range d.Rounds {
+			item := &d.Rounds[i]
+
+			s.Rounds[i] = domain.ChatRound{
+				Question:  item.Question,
+				Answer:    item.Answer,
+				CreatedAt: item.CreatedAt,
+			}
+		}
+	}
+
+	return
+}
@@ -13,6 +13,33 @@ const (
 	fieldModelName = "model_name"
 	fieldEnabled   = "enabled"
 	fieldCallCount = "call_count"
+	fieldDate      = "date"
+	fieldCount     = "count"
+	fieldHour      = "hour"
+	fieldStatus    = "status"
+	fieldLatency   = "latency"
+	fieldHash      = "hash"
+	fieldRevokedAt = "revoked_at"
+	fieldLastUsed  = "last_used_at"
+	fieldTitle     = "title"
+	fieldRounds    = "rounds"
+	fieldUpdatedAt = "updated_at"
+	fieldModel     = "model"
+	fieldPublic    = "public"
+	fieldDesc      = "desc"
+	fieldContent   = "content"
+	fieldLikeCount = "like_count"
+	fieldForkCount = "fork_count"
+	fieldWinner    = "winner"
+	fieldVotedAt   = "voted_at"
+	fieldScore     = "score"
+	fieldWins      = "wins"
+	fieldLosses    = "losses"
+	fieldTies      = "ties"
+	fieldHidden    = "hidden"
+	fieldDiggs     = "diggs"
+	fieldDiggCount = "digg_count"
+	fieldCreatedAt = "created_at"
 )
 
 type DCompetitorInfo struct {
@@ -99,6 +126,7 @@ type pictureItem struct {
 	DiggCount int      `bson:"digg_count" json:"digg_count"`
 	Version   int      `bson:"version"    json:"-"`
 	CreatedAt string   `bson:"created_at" json:"created_at"`
+	Hidden    bool     `bson:"hidden"     json:"hidden"`
 }
 
 type dApiApply struct {
@@ -110,6 +138,34 @@ type dApiApply struct {
 	Enabled   bool   `bson:"enabled"     json:"enabled"`
 	CallCount int    `bson:"call_count"  json:"call_count"`
 	Version   int    `bson:"version"     json:"-"`
+
+	// quota, it is the default value if unset
+	DailyQuota   int `bson:"daily_quota"    json:"daily_quota,omitempty"`
+	MonthlyQuota int `bson:"monthly_quota"  json:"monthly_quota,omitempty"`
+	RPM          int `bson:"rpm"            json:"rpm,omitempty"`
+}
+
+type dApiUsage struct {
+	User      string `bson:"user"        json:"user"`
+	ModelName string `bson:"model_name"  json:"model_name"`
+	Date      string `bson:"date"        json:"date"`
+	Hour      int    `bson:"hour"        json:"hour"`
+	Status    int    `bson:"status"      json:"status"`
+	Count     int    `bson:"count"       json:"count"`
+	Latency   int64  `bson:"latency"     json:"latency"`
+}
+
+type dApiKey struct {
+	Id         string `bson:"id"            json:"id"`
+	User       string `bson:"user"          json:"user"`
+	ModelName  string `bson:"model_name"    json:"model_name"`
+	Name       string `bson:"name"          json:"name"`
+	Hash       string `bson:"hash"          json:"hash"`
+	Hint       string `bson:"hint"          json:"hint"`
+	CreatedAt  int64  `bson:"created_at"    json:"created_at"`
+	ExpireAt   int64  `bson:"expire_at"     json:"expire_at"`
+	RevokedAt  int64  `bson:"revoked_at"    json:"revoked_at"`
+	LastUsedAt int64  `bson:"last_used_at"  json:"last_used_at"`
 }
 
 type dApiInfo struct {
@@ -118,3 +174,78 @@ type dApiInfo struct {
 	Endpoint string `bson:"endpoint"  json:"endpoint"`
 	Doc      string `bson:"doc"       json:"doc"`
 }
+
+type dModerationAudit struct {
+	Id        string `bson:"id"          json:"id"`
+	Model     string `bson:"model"       json:"model"`
+	Kind      string `bson:"kind"        json:"kind"`
+	Content   string `bson:"content"     json:"content"`
+	Provider  string `bson:"provider"    json:"provider"`
+	Label     string `bson:"label"       json:"label"`
+	Reason    string `bson:"reason"      json:"reason"`
+	CreatedAt int64  `bson:"created_at"  json:"created_at"`
+}
+
+type dChatSession struct {
+	Id        string       `bson:"id"          json:"id"`
+	Owner     string       `bson:"owner"       json:"owner"`
+	Model     string       `bson:"model"       json:"model"`
+	Title     string       `bson:"title"       json:"title"`
+	Rounds    []dChatRound `bson:"rounds"      json:"-"`
+	CreatedAt int64        `bson:"created_at"  json:"created_at"`
+	UpdatedAt int64        `bson:"updated_at"  json:"updated_at"`
+}
+
+type dChatRound struct {
+	Question  string `bson:"question"    json:"question"`
+	Answer    string `bson:"answer"      json:"answer"`
+	CreatedAt int64  `bson:"created_at"  json:"created_at"`
+}
+
+type dPromptTemplate struct {
+	Id         string   `bson:"id"           json:"id"`
+	Owner      string   `bson:"owner"        json:"owner"`
+	Model      string   `bson:"model"        json:"model"`
+	Title      string   `bson:"title"        json:"title"`
+	Desc       string   `bson:"desc"         json:"desc"`
+	Content    string   `bson:"content"      json:"content"`
+	Public     bool     `bson:"public"       json:"public"`
+	Likes      []string `bson:"likes"        json:"likes"`
+	LikeCount  int      `bson:"like_count"   json:"like_count"`
+	ForkCount  int      `bson:"fork_count"   json:"fork_count"`
+	ForkedFrom string   `bson:"forked_from"  json:"forked_from"`
+	CreatedAt  int64    `bson:"created_at"   json:"created_at"`
+	UpdatedAt  int64    `bson:"updated_at"   json:"updated_at"`
+}
+
+type dArenaBattle struct {
+	Id        string   `bson:"id"          json:"id"`
+	User      string   `bson:"user"        json:"user"`
+	Prompt    string   `bson:"prompt"      json:"prompt"`
+	Models    []string `bson:"models"      json:"models"`
+	Winner    string   `bson:"winner"      json:"winner"`
+	CreatedAt int64    `bson:"created_at"  json:"created_at"`
+	VotedAt   int64    `bson:"voted_at"    json:"voted_at"`
+}
+
+type dArenaRating struct {
+	Model  string  `bson:"model"   json:"model"`
+	Score  float64 `bson:"score"   json:"score"`
+	Wins   int     `bson:"wins"    json:"wins"`
+	Losses int     `bson:"losses"  json:"losses"`
+	Ties   int     `bson:"ties"    json:"ties"`
+}
+
+type dGalleryPicture struct {
+	Id        string   `bson:"id"          json:"id"`
+	Owner     string   `bson:"owner"       json:"owner"`
+	Model     string   `bson:"model"       json:"model"`
+	Path      string   `bson:"path"        json:"path"`
+	Desc      string   `bson:"desc"        json:"desc"`
+	Public    bool     `bson:"public"      json:"public"`
+	Hidden    bool     `bson:"hidden"      json:"hidden"`
+	Likes     []string `bson:"likes"       json:"likes"`
+	Diggs     []string `bson:"diggs"       json:"diggs"`
+	DiggCount int      `bson:"digg_count"  json:"digg_count"`
+	CreatedAt int64    `bson:"created_at"  json:"created_at"`
+}
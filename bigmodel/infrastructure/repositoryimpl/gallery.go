@@ -0,0 +1,351 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	repoerr "github.com/opensourceways/xihe-server/domain/repository"
+)
+
+func NewGalleryPicture(m mongodbClient) repository.GalleryPicture {
+	return &galleryPictureRepoImpl{m}
+}
+
+type galleryPictureRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *galleryPictureRepoImpl) errPictureNotExists() error {
+	return repoerr.NewErrorResourceNotExists(errors.New("the gallery picture does not exist"))
+}
+
+func (impl *galleryPictureRepoImpl) AddPicture(p *domain.GalleryPicture) (string, error) {
+	doc, err := genDoc(toGalleryPictureDoc(p))
+	if err != nil {
+		return "", err
+	}
+
+	doc[fieldId] = newId()
+	doc[fieldLikes] = bson.A{}
+	doc[fieldDiggs] = bson.A{}
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.NewDocIfNotExist(ctx, bson.M{fieldId: doc[fieldId]}, doc)
+
+		return err
+	}
+
+	if err = withContext(f); err != nil {
+		return "", err
+	}
+
+	return doc[fieldId].(string), nil
+}
+
+func (impl *galleryPictureRepoImpl) GetPicture(id string) (p domain.GalleryPicture, err error) {
+	var v dGalleryPicture
+
+	f := func(ctx context.Context) error {
+		return impl.cli.GetDoc(ctx, bson.M{fieldId: id}, nil, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocNotExists(err) {
+			err = impl.errPictureNotExists()
+		}
+
+		return
+	}
+
+	err = v.toGalleryPicture(&p)
+
+	return
+}
+
+func (impl *galleryPictureRepoImpl) DeletePicture(owner types.Account, id string) error {
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().DeleteOne(
+			ctx, bson.M{fieldId: id, fieldOwner: owner.Account()},
+		)
+		if err != nil {
+			return err
+		}
+
+		if r.DeletedCount == 0 {
+			return impl.errPictureNotExists()
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (impl *galleryPictureRepoImpl) UpdatePublic(owner types.Account, id string, public bool) error {
+	return impl.update(
+		bson.M{fieldId: id, fieldOwner: owner.Account()},
+		bson.M{mongoCmdSet: bson.M{fieldPublic: public}},
+	)
+}
+
+func (impl *galleryPictureRepoImpl) UpdateHidden(id string, hidden bool) error {
+	return impl.update(
+		bson.M{fieldId: id},
+		bson.M{mongoCmdSet: bson.M{fieldHidden: hidden}},
+	)
+}
+
+func (impl *galleryPictureRepoImpl) update(filter, update bson.M) error {
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+
+		if r.MatchedCount == 0 {
+			return impl.errPictureNotExists()
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (impl *galleryPictureRepoImpl) ListPictures(owner types.Account, model domain.GalleryModel) (
+	[]domain.GalleryPicture, error,
+) {
+	return impl.list(withGalleryModel(bson.M{fieldOwner: owner.Account()}, model))
+}
+
+func (impl *galleryPictureRepoImpl) ListLikes(user types.Account, model domain.GalleryModel) (
+	[]domain.GalleryPicture, error,
+) {
+	return impl.list(withGalleryModel(bson.M{fieldLikes: user.Account()}, model))
+}
+
+func (impl *galleryPictureRepoImpl) list(filter bson.M) ([]domain.GalleryPicture, error) {
+	var v []dGalleryPicture
+
+	f := func(ctx context.Context) error {
+		cursor, err := impl.cli.Collection().Find(
+			ctx, filter,
+			options.Find().SetSort(bson.M{fieldCreatedAt: -1}),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	return toGalleryPictures(v), nil
+}
+
+func (impl *galleryPictureRepoImpl) ListPublicPictures(opt *domain.GalleryListOption) (
+	r domain.GalleryPictureList, err error,
+) {
+	filter := withGalleryModel(bson.M{fieldPublic: true, fieldHidden: false}, opt.Model)
+
+	sort := bson.D{{Key: fieldCreatedAt, Value: -1}}
+	if opt.SortByDigg {
+		sort = bson.D{{Key: fieldDiggCount, Value: -1}, {Key: fieldCreatedAt, Value: -1}}
+	}
+
+	var v []dGalleryPicture
+
+	f := func(ctx context.Context) error {
+		n, err := impl.cli.Collection().CountDocuments(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		r.Total = int(n)
+
+		cursor, err := impl.cli.Collection().Find(
+			ctx, filter,
+			options.Find().
+				SetSort(sort).
+				SetSkip(int64(opt.CountPerPage*(opt.PageNum-1))).
+				SetLimit(int64(opt.CountPerPage)),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		return
+	}
+
+	r.Pictures = toGalleryPictures(v)
+
+	return
+}
+
+func (impl *galleryPictureRepoImpl) CountPictures(owner types.Account) (int, error) {
+	return impl.count(bson.M{fieldOwner: owner.Account()})
+}
+
+func (impl *galleryPictureRepoImpl) CountLikes(user types.Account) (int, error) {
+	return impl.count(bson.M{fieldLikes: user.Account()})
+}
+
+func (impl *galleryPictureRepoImpl) count(filter bson.M) (n int, err error) {
+	f := func(ctx context.Context) error {
+		v, err := impl.cli.Collection().CountDocuments(ctx, filter)
+		n = int(v)
+
+		return err
+	}
+
+	err = withContext(f)
+
+	return
+}
+
+func (impl *galleryPictureRepoImpl) AddLike(id string, user types.Account) error {
+	_, err := impl.updateArray(
+		bson.M{fieldId: id, fieldLikes: bson.M{"$ne": user.Account()}},
+		bson.M{mongoCmdPush: bson.M{fieldLikes: user.Account()}},
+		id, fieldLikes,
+	)
+
+	return err
+}
+
+func (impl *galleryPictureRepoImpl) RemoveLike(id string, user types.Account) error {
+	_, err := impl.updateArray(
+		bson.M{fieldId: id, fieldLikes: user.Account()},
+		bson.M{"$pull": bson.M{fieldLikes: user.Account()}},
+		id, fieldLikes,
+	)
+
+	return err
+}
+
+func (impl *galleryPictureRepoImpl) AddDigg(id string, user types.Account) (int, error) {
+	return impl.updateArray(
+		bson.M{fieldId: id, fieldDiggs: bson.M{"$ne": user.Account()}},
+		bson.M{
+			mongoCmdPush: bson.M{fieldDiggs: user.Account()},
+			"$inc":       bson.M{fieldDiggCount: 1},
+		},
+		id, fieldDiggs,
+	)
+}
+
+func (impl *galleryPictureRepoImpl) RemoveDigg(id string, user types.Account) (int, error) {
+	return impl.updateArray(
+		bson.M{fieldId: id, fieldDiggs: user.Account()},
+		bson.M{
+			"$pull": bson.M{fieldDiggs: user.Account()},
+			"$inc":  bson.M{fieldDiggCount: -1},
+		},
+		id, fieldDiggs,
+	)
+}
+
+// updateArray returns the length of the array field after the update,
+// the array is not changed if the filter does not match.
+func (impl *galleryPictureRepoImpl) updateArray(filter, update bson.M, id, field string) (
+	n int, err error,
+) {
+	var v dGalleryPicture
+
+	f := func(ctx context.Context) error {
+		if _, err := impl.cli.Collection().UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+
+		return impl.cli.GetDoc(ctx, bson.M{fieldId: id}, bson.M{field: 1}, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocNotExists(err) {
+			err = impl.errPictureNotExists()
+		}
+
+		return
+	}
+
+	switch field {
+	case fieldLikes:
+		n = len(v.Likes)
+	case fieldDiggs:
+		n = len(v.Diggs)
+	}
+
+	return
+}
+
+func withGalleryModel(filter bson.M, model domain.GalleryModel) bson.M {
+	if model != nil {
+		filter[fieldModel] = model.GalleryModel()
+	}
+
+	return filter
+}
+
+func toGalleryPictureDoc(p *domain.GalleryPicture) dGalleryPicture {
+	return dGalleryPicture{
+		Owner:     p.Owner.Account(),
+		Model:     p.Model.GalleryModel(),
+		Path:      p.Path,
+		Desc:      p.Desc.GalleryDesc(),
+		Public:    p.Public,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+func toGalleryPictures(v []dGalleryPicture) []domain.GalleryPicture {
+	r := make([]domain.GalleryPicture, 0, len(v))
+
+	for i := range v {
+		var item domain.GalleryPicture
+		if err := v[i].toGalleryPicture(&item); err != nil {
+			continue
+		}
+
+		r = append(r, item)
+	}
+
+	return r
+}
+
+func (d *dGalleryPicture) toGalleryPicture(p *domain.GalleryPicture) (err error) {
+	if p.Owner, err = types.NewAccount(d.Owner); err != nil {
+		return
+	}
+
+	if p.Model, err = domain.NewGalleryModel(d.Model); err != nil {
+		return
+	}
+
+	if p.Desc, err = domain.NewGalleryDesc(d.Desc); err != nil {
+		return
+	}
+
+	p.Id = d.Id
+	p.Path = d.Path
+	p.Public = d.Public
+	p.Hidden = d.Hidden
+	p.Likes = d.Likes
+	p.Diggs = d.Diggs
+	p.DiggCount = d.DiggCount
+	p.CreatedAt = d.CreatedAt
+
+	return
+}
@@ -0,0 +1,47 @@
+package repositoryimpl
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+)
+
+func NewModerationAudit(m mongodbClient) repository.ModerationAudit {
+	return &moderationAuditRepoImpl{m}
+}
+
+type moderationAuditRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *moderationAuditRepoImpl) AddAudit(r *moderation.AuditRecord) error {
+	doc, err := genDoc(toModerationAuditDoc(r))
+	if err != nil {
+		return err
+	}
+
+	doc[fieldId] = newId()
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.NewDocIfNotExist(ctx, bson.M{fieldId: doc[fieldId]}, doc)
+
+		return err
+	}
+
+	return withContext(f)
+}
+
+func toModerationAuditDoc(r *moderation.AuditRecord) dModerationAudit {
+	return dModerationAudit{
+		Model:     r.Model,
+		Kind:      r.Kind,
+		Content:   r.Content,
+		Provider:  r.Provider,
+		Label:     r.Label,
+		Reason:    r.Reason,
+		CreatedAt: r.CreatedAt,
+	}
+}
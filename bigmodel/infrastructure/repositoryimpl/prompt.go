@@ -0,0 +1,310 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	types "github.com/opensourceways/xihe-server/domain"
+	repoerr "github.com/opensourceways/xihe-server/domain/repository"
+)
+
+func NewPromptTemplate(m mongodbClient) repository.PromptTemplate {
+	return &promptTemplateRepoImpl{m}
+}
+
+type promptTemplateRepoImpl struct {
+	cli mongodbClient
+}
+
+func (impl *promptTemplateRepoImpl) errTemplateNotExists() error {
+	return repoerr.NewErrorResourceNotExists(errors.New("the prompt template does not exist"))
+}
+
+func (impl *promptTemplateRepoImpl) AddTemplate(t *domain.PromptTemplate) (string, error) {
+	doc, err := genDoc(toPromptTemplateDoc(t))
+	if err != nil {
+		return "", err
+	}
+
+	doc[fieldId] = newId()
+	doc[fieldLikes] = bson.A{}
+
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.NewDocIfNotExist(ctx, bson.M{fieldId: doc[fieldId]}, doc)
+
+		return err
+	}
+
+	if err = withContext(f); err != nil {
+		return "", err
+	}
+
+	return doc[fieldId].(string), nil
+}
+
+func (impl *promptTemplateRepoImpl) GetTemplate(id string) (t domain.PromptTemplate, err error) {
+	var v dPromptTemplate
+
+	f := func(ctx context.Context) error {
+		return impl.cli.GetDoc(ctx, bson.M{fieldId: id}, nil, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocNotExists(err) {
+			err = impl.errTemplateNotExists()
+		}
+
+		return
+	}
+
+	err = v.toPromptTemplate(&t)
+
+	return
+}
+
+func (impl *promptTemplateRepoImpl) ListTemplates(owner types.Account) (
+	[]domain.PromptTemplate, error,
+) {
+	var v []dPromptTemplate
+
+	f := func(ctx context.Context) error {
+		cursor, err := impl.cli.Collection().Find(
+			ctx,
+			bson.M{fieldOwner: owner.Account()},
+			options.Find().
+				SetProjection(bson.M{fieldLikes: 0}).
+				SetSort(bson.M{fieldUpdatedAt: -1}),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	return toPromptTemplates(v), nil
+}
+
+func (impl *promptTemplateRepoImpl) ListPublicTemplates(opt *domain.PromptTemplateListOption) (
+	r domain.PromptTemplateList, err error,
+) {
+	filter := bson.M{fieldPublic: true}
+	if opt.Model != nil {
+		filter[fieldModel] = opt.Model.PromptModel()
+	}
+
+	sort := bson.D{{Key: fieldUpdatedAt, Value: -1}}
+	if opt.SortByLikes {
+		sort = bson.D{{Key: fieldLikeCount, Value: -1}, {Key: fieldUpdatedAt, Value: -1}}
+	}
+
+	var v []dPromptTemplate
+
+	f := func(ctx context.Context) error {
+		n, err := impl.cli.Collection().CountDocuments(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		r.Total = int(n)
+
+		cursor, err := impl.cli.Collection().Find(
+			ctx, filter,
+			options.Find().
+				SetSort(sort).
+				SetSkip(int64(opt.CountPerPage*(opt.PageNum-1))).
+				SetLimit(int64(opt.CountPerPage)),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		return
+	}
+
+	r.Templates = toPromptTemplates(v)
+
+	return
+}
+
+func (impl *promptTemplateRepoImpl) UpdateTemplate(t *domain.PromptTemplate) error {
+	update := bson.M{
+		fieldTitle:     t.Title.PromptTitle(),
+		fieldDesc:      t.Desc.PromptDesc(),
+		fieldContent:   t.Content.PromptContent(),
+		fieldPublic:    t.Public,
+		fieldUpdatedAt: t.UpdatedAt,
+	}
+
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().UpdateOne(
+			ctx,
+			bson.M{fieldId: t.Id, fieldOwner: t.Owner.Account()},
+			bson.M{mongoCmdSet: update},
+		)
+		if err != nil {
+			return err
+		}
+
+		if r.MatchedCount == 0 {
+			return impl.errTemplateNotExists()
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (impl *promptTemplateRepoImpl) DeleteTemplate(owner types.Account, id string) error {
+	f := func(ctx context.Context) error {
+		r, err := impl.cli.Collection().DeleteOne(
+			ctx, bson.M{fieldId: id, fieldOwner: owner.Account()},
+		)
+		if err != nil {
+			return err
+		}
+
+		if r.DeletedCount == 0 {
+			return impl.errTemplateNotExists()
+		}
+
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (impl *promptTemplateRepoImpl) AddLike(id string, user types.Account) (int, error) {
+	return impl.updateLike(
+		bson.M{fieldId: id, fieldPublic: true, fieldLikes: bson.M{"$ne": user.Account()}},
+		bson.M{
+			mongoCmdPush: bson.M{fieldLikes: user.Account()},
+			"$inc":       bson.M{fieldLikeCount: 1},
+		},
+		id,
+	)
+}
+
+func (impl *promptTemplateRepoImpl) RemoveLike(id string, user types.Account) (int, error) {
+	return impl.updateLike(
+		bson.M{fieldId: id, fieldLikes: user.Account()},
+		bson.M{
+			"$pull": bson.M{fieldLikes: user.Account()},
+			"$inc":  bson.M{fieldLikeCount: -1},
+		},
+		id,
+	)
+}
+
+// updateLike returns the count of likes, it is not changed if the filter does not match.
+func (impl *promptTemplateRepoImpl) updateLike(filter, update bson.M, id string) (n int, err error) {
+	var v dPromptTemplate
+
+	f := func(ctx context.Context) error {
+		if _, err := impl.cli.Collection().UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+
+		return impl.cli.GetDoc(ctx, bson.M{fieldId: id}, bson.M{fieldLikeCount: 1}, &v)
+	}
+
+	if err = withContext(f); err != nil {
+		if impl.cli.IsDocNotExists(err) {
+			err = impl.errTemplateNotExists()
+		}
+
+		return
+	}
+
+	n = v.LikeCount
+
+	return
+}
+
+func (impl *promptTemplateRepoImpl) IncreaseForkCount(id string) error {
+	f := func(ctx context.Context) error {
+		_, err := impl.cli.Collection().UpdateOne(
+			ctx, bson.M{fieldId: id}, bson.M{"$inc": bson.M{fieldForkCount: 1}},
+		)
+
+		return err
+	}
+
+	return withContext(f)
+}
+
+func toPromptTemplateDoc(t *domain.PromptTemplate) dPromptTemplate {
+	return dPromptTemplate{
+		Owner:      t.Owner.Account(),
+		Model:      t.Model.PromptModel(),
+		Title:      t.Title.PromptTitle(),
+		Desc:       t.Desc.PromptDesc(),
+		Content:    t.Content.PromptContent(),
+		Public:     t.Public,
+		ForkedFrom: t.ForkedFrom,
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+func toPromptTemplates(v []dPromptTemplate) []domain.PromptTemplate {
+	r := make([]domain.PromptTemplate, 0, len(v))
+
+	for i := range v {
+		var item domain.PromptTemplate
+		if err := v[i].toPromptTemplate(&item); err != nil {
+			continue
+		}
+
+		r = append(r, item)
+	}
+
+	return r
+}
+
+func (d *dPromptTemplate) toPromptTemplate(t *domain.PromptTemplate) (err error) {
+	if t.Owner, err = types.NewAccount(d.Owner); err != nil {
+		return
+	}
+
+	if t.Model, err = domain.NewPromptModel(d.Model); err != nil {
+		return
+	}
+
+	if t.Title, err = domain.NewPromptTitle(d.Title); err != nil {
+		return
+	}
+
+	if t.Desc, err = domain.NewPromptDesc(d.Desc); err != nil {
+		return
+	}
+
+	if t.Content, err = domain.NewPromptContent(d.Content); err != nil {
+		return
+	}
+
+	t.Id = d.Id
+	t.Public = d.Public
+	t.Likes = d.Likes
+	t.LikeCount = d.LikeCount
+	t.ForkCount = d.ForkCount
+	t.ForkedFrom = d.ForkedFrom
+	t.CreatedAt = d.CreatedAt
+	t.UpdatedAt = d.UpdatedAt
+
+	return
+}
@@ -279,6 +279,12 @@ func (impl *wukongPictureRepoImpl) getByUserName(user, pid, field string) (
 		return
 	}
 
+	if len(v) == 0 {
+		err = commoninfra.NewErrorDataNotExists(errDocNotExists)
+
+		return
+	}
+
 	var l []pictureItem
 	if field == fieldLikes {
 		l = v[0].Likes
@@ -286,7 +292,7 @@ func (impl *wukongPictureRepoImpl) getByUserName(user, pid, field string) (
 		l = v[0].Publics
 	}
 
-	if len(v) == 0 || len(l) == 0 {
+	if len(l) == 0 {
 		err = commoninfra.NewErrorDataNotExists(errDocNotExists)
 
 		return
@@ -329,6 +335,11 @@ func (impl *wukongPictureRepoImpl) GetPublicsGlobal() (r []domain.WuKongPicture,
 	var c int
 	for i := range v {
 		for j := range v[i].Publics {
+			// the picture hidden by the admin is not in the gallery
+			if v[i].Publics[j].Hidden {
+				continue
+			}
+
 			if err = v[i].Publics[j].toWuKongPicture(&r[c]); err != nil {
 				return
 			}
@@ -336,6 +347,8 @@ func (impl *wukongPictureRepoImpl) GetPublicsGlobal() (r []domain.WuKongPicture,
 		}
 	}
 
+	r = r[:c]
+
 	sortWuKongPictureByTime(r)
 
 	return
@@ -411,6 +424,7 @@ func (r *pictureItem) toWuKongPicture(d *domain.WuKongPicture) (err error) {
 	d.DiggCount = r.DiggCount
 	d.Version = r.Version
 	d.CreatedAt = r.CreatedAt
+	d.Hidden = r.Hidden
 
 	return
 }
@@ -442,6 +456,7 @@ func toPictureItemDoc(d *domain.WuKongPicture) (bson.M, error) {
 		DiggCount: d.DiggCount,
 		Version:   d.Version,
 		CreatedAt: d.CreatedAt,
+		Hidden:    d.Hidden,
 	}
 
 	if d.Owner != nil {
@@ -0,0 +1,58 @@
+package taskeventhub
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/async"
+)
+
+const bufferSize = 16
+
+func NewTaskEventHub() async.TaskEventHub {
+	return &hub{
+		subscribers: map[int]chan async.TaskEvent{},
+	}
+}
+
+type hub struct {
+	lock        sync.RWMutex
+	seq         uint64
+	next        int
+	subscribers map[int]chan async.TaskEvent
+}
+
+func (h *hub) Publish(e *async.TaskEvent) {
+	e.Seq = atomic.AddUint64(&h.seq, 1)
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	for _, ch := range h.subscribers {
+		// don't block the consumer of mq by a slow watcher,
+		// the watcher will catch up on the next event.
+		select {
+		case ch <- *e:
+		default:
+			logrus.Warnf("drop the event of async task %d", e.TaskId)
+		}
+	}
+}
+
+func (h *hub) Subscribe() (<-chan async.TaskEvent, func()) {
+	ch := make(chan async.TaskEvent, bufferSize)
+
+	h.lock.Lock()
+	id := h.next
+	h.next++
+	h.subscribers[id] = ch
+	h.lock.Unlock()
+
+	return ch, func() {
+		h.lock.Lock()
+		delete(h.subscribers, id)
+		h.lock.Unlock()
+	}
+}
@@ -0,0 +1,63 @@
+package messagequeue
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	kfk "github.com/opensourceways/kafka-lib/agent"
+
+	"github.com/opensourceways/xihe-server/bigmodel/app"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	comsg "github.com/opensourceways/xihe-server/common/domain/message"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+const handleNameApiCalled = "api_called"
+
+// SubscribeApiCalled records the calls of api which are not made by the gateway
+func SubscribeApiCalled(s app.ApiGatewayService, topic string) error {
+	c := &apiConsumer{s: s}
+
+	return kfk.SubscribeWithStrategyOfRetry(
+		handleNameApiCalled,
+		c.handleEventApiCalled,
+		[]string{topic}, retryNum,
+	)
+}
+
+type apiConsumer struct {
+	s app.ApiGatewayService
+}
+
+func (c *apiConsumer) handleEventApiCalled(body []byte, h map[string]string) (err error) {
+	b := comsg.MsgNormal{}
+	if err = json.Unmarshal(body, &b); err != nil {
+		return
+	}
+
+	cmd := app.ApiCallRecordCmd{
+		At: time.Unix(b.CreatedAt, 0),
+	}
+
+	if cmd.User, err = types.NewAccount(b.User); err != nil {
+		return
+	}
+
+	if cmd.Model, err = domain.NewModelName(b.Details["model"]); err != nil {
+		return
+	}
+
+	if cmd.Status, err = strconv.Atoi(b.Details["status"]); err != nil {
+		return
+	}
+
+	latency, err := strconv.ParseInt(b.Details["latency"], 10, 64)
+	if err != nil {
+		return
+	}
+
+	cmd.Latency = time.Duration(latency) * time.Millisecond
+
+	return c.s.Record(&cmd)
+}
@@ -20,6 +20,8 @@ const (
 	handleNameWuKongInferenceError  = "wukong_inference_error"
 	handleNameWuKongAsyncTaskStart  = "wukong_async_task_start"
 	handleNameWuKongAsyncTaskFinish = "wukong_async_task_finish"
+	handleNameAsyncTaskCreated      = "async_task_created"
+	handleNameAsyncTaskUpdated      = "async_task_updated"
 )
 
 func Subscribe(s asyncapp.AsyncMessageService, topics *TopicConfig) (err error) {
@@ -53,10 +55,32 @@ func Subscribe(s asyncapp.AsyncMessageService, topics *TopicConfig) (err error)
 	}
 
 	// wukong async task finish
-	err = kfk.SubscribeWithStrategyOfRetry(
+	if err = kfk.SubscribeWithStrategyOfRetry(
 		handleNameWuKongAsyncTaskFinish,
 		c.handleEventBigModelWuKongAsyncTaskFinish,
 		[]string{topics.InferenceAsyncFinish}, retryNum,
+	); err != nil {
+		return
+	}
+
+	// async task created
+	if err = kfk.SubscribeWithStrategyOfRetry(
+		handleNameAsyncTaskCreated,
+		c.handleEventAsyncTaskCreated,
+		[]string{topics.AsyncTaskCreated}, retryNum,
+	); err != nil {
+		return
+	}
+
+	// async task started, finished or failed
+	err = kfk.SubscribeWithStrategyOfRetry(
+		handleNameAsyncTaskUpdated,
+		c.handleEventAsyncTaskUpdated,
+		[]string{
+			topics.AsyncTaskStarted,
+			topics.AsyncTaskFinished,
+			topics.AsyncTaskFailed,
+		}, retryNum,
 	)
 
 	return
@@ -82,16 +106,30 @@ func (c *consumer) handleEventBigModelWuKongInferenceStart(body []byte, h map[st
 		return err
 	}
 
+	input, err := bigmodeldomain.NewWuKongPictureInput(
+		b.Details["mode"], b.Details["image"], b.Details["mask"],
+	)
+	if err != nil {
+		return err
+	}
+
 	tt, err := asyncdomain.NewTaskType(b.Details["task_type"])
 	if err != nil {
 		return err
 	}
 
+	priority, err := toTaskPriority(b.Details["priority"])
+	if err != nil {
+		return err
+	}
+
 	v := asyncdomain.WuKongRequest{
 		User:     user,
 		TaskType: tt,
 		Style:    b.Details["style"],
 		Desc:     desc,
+		Input:    input,
+		Priority: priority,
 	}
 
 	return c.s.CreateWuKongTask(&v)
@@ -185,10 +223,111 @@ func (c *consumer) handleEventBigModelWuKongAsyncTaskFinish(body []byte, h map[s
 	return c.s.UpdateWuKongTask(&v)
 }
 
+func (c *consumer) handleEventAsyncTaskCreated(body []byte, h map[string]string) (err error) {
+	b := comsg.MsgNormal{}
+	if err = json.Unmarshal(body, &b); err != nil {
+		return
+	}
+
+	user, err := domain.NewAccount(b.User)
+	if err != nil {
+		return err
+	}
+
+	tt, err := asyncdomain.NewTaskType(b.Details["task_type"])
+	if err != nil {
+		return err
+	}
+
+	schema, err := asyncdomain.GetTaskSchema(tt)
+	if err != nil {
+		return err
+	}
+
+	payload, err := schema.Payload(b.Details)
+	if err != nil {
+		return err
+	}
+
+	priority, err := toTaskPriority(b.Details["priority"])
+	if err != nil {
+		return err
+	}
+
+	return c.s.CreateTask(&asyncdomain.TaskRequest{
+		User:     user,
+		TaskType: tt,
+		Payload:  payload,
+		Priority: priority,
+	})
+}
+
+// toTaskPriority is compatible with the messages which have no priority
+func toTaskPriority(v string) (asyncdomain.TaskPriority, error) {
+	if v == "" {
+		return asyncdomain.TaskPriorityNormal, nil
+	}
+
+	p, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return asyncdomain.NewTaskPriority(p)
+}
+
+func (c *consumer) handleEventAsyncTaskUpdated(body []byte, h map[string]string) (err error) {
+	b := comsg.MsgNormal{}
+	if err = json.Unmarshal(body, &b); err != nil {
+		return
+	}
+
+	status, err := asyncdomain.NewTaskStatus(b.Details["status"])
+	if err != nil {
+		return err
+	}
+
+	taskId, err := strconv.ParseUint(b.Details["task_id"], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	v := asyncrepo.TaskResp{
+		Task: asyncrepo.Task{
+			Id:     taskId,
+			Status: status,
+		},
+	}
+
+	switch {
+	case status.IsError():
+		v.ErrMsg = asyncdomain.ErrorOfTask(b.Details)
+
+	case status.IsFinished():
+		// the result is saved as it is, and decoded by the schema when being read
+		r := asyncdomain.RawTaskResult{}
+		for k := range b.Details {
+			if k != "status" && k != "task_id" && k != "task_type" {
+				r[k] = b.Details[k]
+			}
+		}
+
+		v.Result = r
+	}
+
+	return c.s.UpdateTask(&v)
+}
+
 type TopicConfig struct {
 	BigModelFinished     string `json:"bigmodel_finished"        required:"true"`
 	InferenceStart       string `json:"inference_start"`
 	InferenceError       string `json:"inference_error"`
 	InferenceAsyncStart  string `json:"inference_async_start"`
 	InferenceAsyncFinish string `json:"inference_async_finish"`
+	AsyncTaskCreated     string `json:"async_task_created"`
+	AsyncTaskStarted     string `json:"async_task_started"`
+	AsyncTaskFinished    string `json:"async_task_finished"`
+	AsyncTaskFailed      string `json:"async_task_failed"`
+	ModerationBlocked    string `json:"moderation_blocked"`
+	ApiCalled            string `json:"api_called"`
 }
@@ -0,0 +1,54 @@
+package messagequeue
+
+import (
+	"encoding/json"
+	"strconv"
+
+	kfk "github.com/opensourceways/kafka-lib/agent"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/moderation"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/repository"
+	comsg "github.com/opensourceways/xihe-server/common/domain/message"
+)
+
+const handleNameModerationAudit = "moderation_audit"
+
+// SubscribeModerationAudit saves the records of the content which is blocked by moderation
+func SubscribeModerationAudit(repo repository.ModerationAudit, topic string) error {
+	c := &auditConsumer{repo: repo}
+
+	return kfk.SubscribeWithStrategyOfRetry(
+		handleNameModerationAudit,
+		c.handleEventModerationBlocked,
+		[]string{topic}, retryNum,
+	)
+}
+
+type auditConsumer struct {
+	repo repository.ModerationAudit
+}
+
+func (c *auditConsumer) handleEventModerationBlocked(body []byte, h map[string]string) (err error) {
+	b := comsg.MsgNormal{}
+	if err = json.Unmarshal(body, &b); err != nil {
+		return
+	}
+
+	r := moderation.AuditRecord{
+		Model:     b.Details["model"],
+		Kind:      b.Details["kind"],
+		Content:   b.Details["content"],
+		Provider:  b.Details["provider"],
+		Label:     b.Details["label"],
+		Reason:    b.Details["reason"],
+		CreatedAt: b.CreatedAt,
+	}
+
+	if v := b.Details["created_at"]; v != "" {
+		if r.CreatedAt, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return
+		}
+	}
+
+	return c.repo.AddAudit(&r)
+}
@@ -0,0 +1,67 @@
+package messagequeue
+
+import (
+	"encoding/json"
+	"strconv"
+
+	kfk "github.com/opensourceways/kafka-lib/agent"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain/async"
+	comsg "github.com/opensourceways/xihe-server/common/domain/message"
+)
+
+const handleNameAsyncTaskStatus = "async_task_status"
+
+// SubscribeTaskStatus feeds the hub with the status changes of async tasks.
+// Every instance of server must use a different name so that it can receive all the events.
+func SubscribeTaskStatus(hub async.TaskEventHub, instance string, topics []string) error {
+	c := &statusConsumer{hub: hub}
+
+	return kfk.SubscribeWithStrategyOfRetry(
+		handleNameAsyncTaskStatus+"_"+instance,
+		c.handleEventTaskStatus,
+		topics, retryNum,
+	)
+}
+
+type statusConsumer struct {
+	hub async.TaskEventHub
+}
+
+func (c *statusConsumer) handleEventTaskStatus(body []byte, h map[string]string) (err error) {
+	b := comsg.MsgNormal{}
+	if err = json.Unmarshal(body, &b); err != nil {
+		return
+	}
+
+	if b.User == "" || b.Details["status"] == "" {
+		return
+	}
+
+	e := async.TaskEvent{
+		User:     b.User,
+		TaskType: b.Details["task_type"],
+		Status:   b.Details["status"],
+		ErrMsg:   b.Details["error"],
+	}
+
+	if v := b.Details["task_id"]; v != "" {
+		if e.TaskId, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return
+		}
+	}
+
+	if e.Status == "finished" {
+		e.Result = map[string]string{}
+
+		for k, v := range b.Details {
+			if k != "status" && k != "task_id" && k != "task_type" {
+				e.Result[k] = v
+			}
+		}
+	}
+
+	c.hub.Publish(&e)
+
+	return
+}
@@ -0,0 +1,33 @@
+package moderation
+
+// the scenes of the content which is created by users,
+// the policy of moderation can be configured for each of them.
+const (
+	SceneProject = "project"
+	SceneModel   = "model"
+	SceneDataset = "dataset"
+	SceneUser    = "user"
+	SceneTeam    = "competition_team"
+	SceneRepo    = "repo_file"
+)
+
+// Moderation checks the content which is created by users
+type Moderation interface {
+	// CheckText returns ErrorSensitiveContent if the content is blocked
+	CheckText(scene, content string) error
+}
+
+// ErrorSensitiveContent
+type ErrorSensitiveContent struct {
+	error
+}
+
+func NewErrorSensitiveContent(err error) ErrorSensitiveContent {
+	return ErrorSensitiveContent{err}
+}
+
+func IsErrorSensitiveContent(err error) bool {
+	_, ok := err.(ErrorSensitiveContent)
+
+	return ok
+}
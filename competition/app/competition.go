@@ -1,6 +1,7 @@
 package app
 
 import (
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/competition/domain"
 	"github.com/opensourceways/xihe-server/competition/domain/message"
 	"github.com/opensourceways/xihe-server/competition/domain/repository"
@@ -16,7 +17,7 @@ type CompetitionService interface {
 	CreateTeam(cid string, cmd *CompetitionTeamCreateCmd) (string, error)
 	JoinTeam(cid string, cmd *CompetitionTeamJoinCmd) (string, error)
 	GetMyTeam(cid string, competitor types.Account) (CompetitionTeamDTO, string, error)
-	ChangeTeamName(cid string, cmd *CmdToChangeCompetitionTeamName) error
+	ChangeTeamName(cid string, cmd *CmdToChangeCompetitionTeamName) (string, error)
 	TransferLeader(cid string, cmd *CmdToTransferTeamLeader) error
 	QuitTeam(cid string, competitor types.Account) error
 	DeleteMember(cid string, cmd *CmdToDeleteTeamMember) error
@@ -42,6 +43,7 @@ func NewCompetitionService(
 	producer message.MessageProducer,
 	uploader uploader.SubmissionFileUploader,
 	userCli user.User,
+	moderation moderation.Moderation,
 ) *competitionService {
 	return &competitionService{
 		repo:             repo,
@@ -50,6 +52,7 @@ func NewCompetitionService(
 		producer:         producer,
 		submissionServie: domain.NewSubmissionService(uploader),
 		userCli:          userCli,
+		moderation:       moderation,
 	}
 }
 
@@ -60,6 +63,7 @@ type competitionService struct {
 	producer         message.MessageProducer
 	submissionServie domain.SubmissionService
 	userCli          user.User
+	moderation       moderation.Moderation
 }
 
 // show competition detail
@@ -12,4 +12,5 @@ const (
 	errorDoesnotOwnProject   = "competition_doesnot_own_project"
 	errorDuplicateSubmission = "competition_duplicate_submission"
 	errorNoCorrespondingTeam = "competition_no_corresponding_team"
+	errorSensitiveTeamName   = "competition_sensitive_team_name"
 )
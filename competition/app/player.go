@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/competition/domain"
 	"github.com/opensourceways/xihe-server/competition/domain/repository"
 	types "github.com/opensourceways/xihe-server/domain"
@@ -52,6 +53,14 @@ func (s *competitionService) Apply(cid string, cmd *CompetitorApplyCmd) (code st
 func (s *competitionService) CreateTeam(cid string, cmd *CompetitionTeamCreateCmd) (
 	code string, err error,
 ) {
+	if err = s.checkTeamName(cmd.Name); err != nil {
+		if moderation.IsErrorSensitiveContent(err) {
+			code = errorSensitiveTeamName
+		}
+
+		return
+	}
+
 	p, version, err := s.playerRepo.FindPlayer(cid, cmd.User)
 	if err != nil {
 		return
@@ -165,17 +174,29 @@ func (s *competitionService) GetMyTeam(cid string, user types.Account) (
 	return
 }
 
-func (s *competitionService) ChangeTeamName(cid string, cmd *CmdToChangeCompetitionTeamName) error {
+func (s *competitionService) ChangeTeamName(cid string, cmd *CmdToChangeCompetitionTeamName) (
+	code string, err error,
+) {
+	if err = s.checkTeamName(cmd.Name); err != nil {
+		if moderation.IsErrorSensitiveContent(err) {
+			code = errorSensitiveTeamName
+		}
+
+		return
+	}
+
 	p, version, err := s.playerRepo.FindPlayer(cid, cmd.User)
 	if err != nil {
-		return err
+		return
 	}
 
 	if err = p.ChangeTeamName(cmd.Name); err != nil {
-		return err
+		return
 	}
 
-	return s.playerRepo.SaveTeamName(&p, version)
+	err = s.playerRepo.SaveTeamName(&p, version)
+
+	return
 }
 
 func (s *competitionService) TransferLeader(cid string, cmd *CmdToTransferTeamLeader) error {
@@ -253,3 +274,11 @@ func (s *competitionService) DissolveTeam(cid string, leader types.Account) erro
 
 	return s.playerRepo.DeletePlayer(&p, version)
 }
+
+func (s *competitionService) checkTeamName(name domain.TeamName) error {
+	if s.moderation == nil || name == nil {
+		return nil
+	}
+
+	return s.moderation.CheckText(moderation.SceneTeam, name.TeamName())
+}
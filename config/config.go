@@ -6,6 +6,7 @@ import (
 	"github.com/opensourceways/xihe-server/app"
 	asyncrepoimpl "github.com/opensourceways/xihe-server/async-server/infrastructure/repositoryimpl"
 	bigmodel "github.com/opensourceways/xihe-server/bigmodel/config"
+	bigmodeldomain "github.com/opensourceways/xihe-server/bigmodel/domain"
 	cloudmsg "github.com/opensourceways/xihe-server/cloud/infrastructure/messageadapter"
 	cloudrepoimpl "github.com/opensourceways/xihe-server/cloud/infrastructure/repositoryimpl"
 	common "github.com/opensourceways/xihe-server/common/config"
@@ -41,7 +42,7 @@ type Config struct {
 
 	Competition competition.Config              `json:"competition"  required:"true"`
 	Challenge   challengeimpl.Config            `json:"challenge"    required:"true"`
-	Training    trainingConfig                  `json:"training"     required:"true"`
+	Training    TrainingConfig                  `json:"training"     required:"true"`
 	Finetune    finetuneimpl.Config             `json:"finetune"     required:"true"`
 	BigModel    bigmodel.Config                 `json:"bigmodel"     required:"true"`
 	Authing     authingimpl.Config              `json:"authing"      required:"true"`
@@ -154,6 +155,11 @@ type MongodbCollections struct {
 	Project           string `json:"project"                required:"true"`
 	Activity          string `json:"activity"               required:"true"`
 	Training          string `json:"training"               required:"true"`
+	TrainingQueue     string `json:"training_queue"         required:"true"`
+	TrainingSweep     string `json:"training_sweep"         required:"true"`
+	TrainingMetric    string `json:"training_metric"        required:"true"`
+	TrainingSchedule  string `json:"training_schedule"      required:"true"`
+	TrainingNotice    string `json:"training_notice"        required:"true"`
 	Finetune          string `json:"finetune"               required:"true"`
 	Evaluate          string `json:"evaluate"               required:"true"`
 	Inference         string `json:"inference"              required:"true"`
@@ -170,6 +176,16 @@ type MongodbCollections struct {
 	CloudConf         string `json:"cloud_conf"             required:"true"`
 	ApiApply          string `json:"api_apply"              required:"true"`
 	ApiInfo           string `json:"api_info"               required:"true"`
+	ApiUsage          string `json:"api_usage"              required:"true"`
+	ApiKey            string `json:"api_key"                required:"true"`
+	ChatSession       string `json:"chat_session"           required:"true"`
+	PromptTemplate    string `json:"prompt_template"        required:"true"`
+	GalleryPicture    string `json:"gallery_picture"        required:"true"`
+	AbuseReport       string `json:"abuse_report"           required:"true"`
+	AbuseAudit        string `json:"abuse_audit"            required:"true"`
+	ArenaBattle       string `json:"arena_battle"           required:"true"`
+	ArenaRating       string `json:"arena_rating"           required:"true"`
+	ModerationAudit   string `json:"moderation_audit"       required:"true"`
 	PointsTask        string `json:"points_task"            required:"true"`
 	UserPoints        string `json:"user_points"            required:"true"`
 }
@@ -178,6 +194,8 @@ func (cfg *Config) InitDomainConfig() {
 	domain.Init(&cfg.Domain)
 
 	pointsdomain.Init(&cfg.Points.Domain)
+
+	bigmodeldomain.Init(&cfg.BigModel.Domain)
 }
 
 func (cfg *Config) InitAppConfig() {
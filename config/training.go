@@ -5,13 +5,13 @@ import (
 	"github.com/opensourceways/xihe-server/infrastructure/trainingimpl"
 )
 
-type trainingConfig struct {
+type TrainingConfig struct {
 	trainingimpl.Config
 
 	Message messages.TrainingConfig `json:"message" required:"true"`
 }
 
-func (cfg *trainingConfig) ConfigItems() []interface{} {
+func (cfg *TrainingConfig) ConfigItems() []interface{} {
 	return []interface{}{
 		&cfg.Config,
 		&cfg.Message,
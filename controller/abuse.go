@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/abuse/app"
+)
+
+func AddRouterForAbuseController(
+	rg *gin.RouterGroup,
+	s app.ReportService,
+) {
+	ctl := AbuseController{
+		s: s,
+	}
+
+	rg.POST("/v1/abuse/report", ctl.Report)
+}
+
+type AbuseController struct {
+	baseController
+
+	s app.ReportService
+}
+
+//	@Summary		Report
+//	@Description	report the public wukong picture, project, model or dataset which is abusive
+//	@Tags			Abuse
+//	@Param			body	body	abuseReportRequest	true	"body of report"
+//	@Accept			json
+//	@Success		201
+//	@Failure		400	bad_request_param		some	parameter	is	invalid
+//	@Failure		400	abuse_item_not_found	no		such		item
+//	@Failure		400	abuse_duplicate_report	reported	already
+//	@Failure		500	system_error			system	error
+//	@Router			/v1/abuse/report [post]
+func (ctl *AbuseController) Report(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := abuseReportRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd, err := req.toCmd(pl.DomainAccount())
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.s.Report(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, "success")
+	}
+}
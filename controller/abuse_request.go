@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/opensourceways/xihe-server/abuse/app"
+	abusedomain "github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/domain"
+)
+
+type abuseReportRequest struct {
+	Type   string `json:"type"`
+	Owner  string `json:"owner"`
+	Id     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+func (req *abuseReportRequest) toCmd(user domain.Account) (cmd app.ReportCmd, err error) {
+	if req.Id == "" {
+		err = errors.New("missing id")
+
+		return
+	}
+
+	if cmd.Item.Type, err = abusedomain.NewItemType(req.Type); err != nil {
+		return
+	}
+
+	if cmd.Item.Owner, err = domain.NewAccount(req.Owner); err != nil {
+		return
+	}
+
+	if cmd.Reason, err = abusedomain.NewReportReason(req.Reason); err != nil {
+		return
+	}
+
+	cmd.Item.Id = req.Id
+	cmd.Reporter = user
+
+	return
+}
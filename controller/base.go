@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"net"
@@ -13,6 +14,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/opensourceways/xihe-server/app"
+	bigmodelapp "github.com/opensourceways/xihe-server/bigmodel/app"
+	bigmodeldomain "github.com/opensourceways/xihe-server/bigmodel/domain"
 	common "github.com/opensourceways/xihe-server/common/domain"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/infrastructure/repositories"
@@ -27,6 +30,9 @@ const (
 	encodeUsername     = "encode-username"
 	headerSecWebsocket = "Sec-Websocket-Protocol"
 
+	headerAuthorization = "Authorization"
+	bearerPrefix        = "Bearer "
+
 	roleIndividuals = "individuals"
 	fileReadme      = "README.md"
 	fileApp         = "app.py"
@@ -640,27 +646,87 @@ func (ctl baseController) newRepo() repositories.Access {
 	return repositories.NewAccessRepo(int(apiConfig.TokenExpiry - 10))
 }
 
-func (ctl baseController) checkBigmodelApiToken(ctx *gin.Context) (user string, ok bool) {
+// legacyApiTokenExpiry is the validity in seconds of the legacy token of bigmodel api
+const legacyApiTokenExpiry = 5184000
+
+func (ctl baseController) checkBigmodelApiToken(
+	ctx *gin.Context, keys bigmodelapp.ApiKeyService,
+	s bigmodelapp.BigModelService, model bigmodeldomain.ModelName,
+) (user domain.Account, ok bool) {
 	v := ctx.GetHeader(Token)
-	deToken, err := ctl.decryptData(v)
+	if v == "" {
+		// compatible with the clients of OpenAI
+		v = strings.TrimPrefix(ctx.GetHeader(headerAuthorization), bearerPrefix)
+	}
+
+	if v == "" {
+		ctx.JSON(
+			http.StatusUnauthorized,
+			newResponseCodeMsg(errorBadRequestHeader, "no token"),
+		)
+
+		return
+	}
+
+	user, code, err := keys.Authenticate(v, model)
 	if err != nil {
+		// the legacy token is accepted until it is migrated to the api key
+		if code == bigmodelapp.ErrorApiKeyInvalid {
+			if user, ok = ctl.checkLegacyBigmodelApiToken(v, s, model); ok {
+				return
+			}
+		}
+
+		if code == "" {
+			ctl.sendRespWithInternalError(ctx, newResponseError(err))
+		} else {
+			ctx.JSON(http.StatusUnauthorized, newResponseCodeError(code, err))
+		}
+
 		return
 	}
 
-	strs := strings.Split(string(deToken), "+")
-	user = strs[0]
+	ok = true
 
-	time, err := strconv.ParseInt(strs[1], 10, 64)
+	return
+}
+
+// checkLegacyBigmodelApiToken checks the token issued when applying the api
+// before the api keys are supported. The token is the encrypted "user+time".
+func (ctl baseController) checkLegacyBigmodelApiToken(
+	token string, s bigmodelapp.BigModelService, model bigmodeldomain.ModelName,
+) (user domain.Account, ok bool) {
+	v, err := ctl.decryptData(token)
 	if err != nil {
 		return
 	}
 
-	if utils.Now()-time > 5184000 {
-		ctx.JSON(http.StatusBadRequest, newResponseCodeMsg(
-			errorBadRequestParam, "token expire",
-		))
+	strs := strings.Split(string(v), "+")
+	if len(strs) != 2 {
+		return
+	}
+
+	t, err := strconv.ParseInt(strs[1], 10, 64)
+	if err != nil || utils.Now()-t > legacyApiTokenExpiry {
+		return
+	}
+
+	if user, err = domain.NewAccount(strs[0]); err != nil {
 		return
 	}
 
-	return user, true
+	// the token must be the one saved in the applied api of model
+	r, err := s.GetApplyRecordByModel(user, model)
+	if err != nil || r.Token == "" {
+		return
+	}
+
+	saved, err := ctl.decryptDataForToken(r.Token)
+	if err != nil {
+		return
+	}
+
+	ok = subtle.ConstantTimeCompare(saved, []byte(token)) == 1
+
+	return
 }
@@ -12,9 +12,9 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/gorilla/websocket"
 
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/app"
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
-	types "github.com/opensourceways/xihe-server/domain"
 	userapp "github.com/opensourceways/xihe-server/user/app"
 	"github.com/opensourceways/xihe-server/utils"
 )
@@ -23,10 +23,18 @@ func AddRouterForBigModelController(
 	rg *gin.RouterGroup,
 	s app.BigModelService,
 	us userapp.RegService,
+	ts app.AsyncTaskStatusService,
+	api app.ApiGatewayService,
+	keys app.ApiKeyService,
+	sessions app.ChatSessionService,
 ) {
 	ctl := BigModelController{
-		s:  s,
-		us: us,
+		s:        s,
+		us:       us,
+		ts:       ts,
+		api:      api,
+		keys:     keys,
+		sessions: sessions,
 	}
 
 	// rg.POST("/v1/bigmodel/describe_picture", ctl.DescribePicture)
@@ -39,8 +47,12 @@ func AddRouterForBigModelController(
 	rg.POST("/v1/bigmodel/luojia", ctl.LuoJia)
 	rg.POST("/v1/bigmodel/wukong", ctl.WuKong)
 	rg.POST("/v1/bigmodel/wukong_async", ctl.WuKongAsync)
+	rg.POST("/v1/bigmodel/wukong/upload_picture", ctl.WuKongUploadPicture)
 	rg.GET("/v1/bigmodel/wukong/rank", ctl.WuKongRank)
 	rg.GET("/v1/bigmodel/wukong/task", ctl.WuKongLastFinisedTask)
+	rg.DELETE("/v1/bigmodel/wukong/task/:id", ctl.CancelWuKongTask)
+	rg.GET("/v1/bigmodel/async/status", ctl.WatchAsyncTask)
+	rg.GET("/v1/bigmodel/async/status/sse", ctl.WatchAsyncTaskBySSE)
 	rg.POST("/v1/bigmodel/wukong/like", ctl.AddLike)
 	rg.POST("/v1/bigmodel/wukong/public", ctl.AddPublic)
 	rg.GET("/v1/bigmodel/wukong/public", ctl.ListPublic)
@@ -57,19 +69,37 @@ func AddRouterForBigModelController(
 	rg.POST("/v1/bigmodel/baichuan2_7b_chat", ctl.BaiChuan)
 	rg.POST("/v1/bigmodel/glm2_6b", ctl.GLM2)
 	rg.POST("/v1/bigmodel/llama2_7b", ctl.LLAMA2)
+	rg.POST("/v1/bigmodel/async/:model", ctl.TextAsync)
+	rg.GET("/v1/bigmodel/async/:model/task", ctl.LastAsyncTask)
 
 	rg.POST("/v1/bigmodel/api/apply/:model", ctl.ApplyApi)
 	rg.GET("/v1/bigmodel/api/get", ctl.GetUserApplyRecord)
 	rg.GET("/v1/bigmodel/api/apply/:model", ctl.IsApplied)
-	rg.POST("/v1/bigmodel/api/wukong", ctl.WukongAPI)
+	rg.POST("/v1/bigmodel/api/:model", ctl.BigModelAPI)
+	rg.GET("/v1/bigmodel/api/quota/:model", ctl.GetApiQuota)
+	rg.GET("/v1/bigmodel/api/usage", ctl.GetApiUsage)
+	rg.POST("/v1/bigmodel/chat/completions", ctl.ChatCompletions)
+	rg.POST("/v1/bigmodel/api/key/:model", ctl.CreateApiKey)
+	rg.GET("/v1/bigmodel/api/key/:model", ctl.ListApiKeys)
+	rg.DELETE("/v1/bigmodel/api/key/:model/:id", ctl.RevokeApiKey)
 	rg.GET("/v1/bigmodel/apiinfo/get/:model", ctl.GetApiInfo)
+
+	rg.POST("/v1/bigmodel/chat/session", ctl.CreateChatSession)
+	rg.GET("/v1/bigmodel/chat/session", ctl.ListChatSessions)
+	rg.GET("/v1/bigmodel/chat/session/:id", ctl.GetChatSession)
+	rg.PUT("/v1/bigmodel/chat/session/:id", ctl.RenameChatSession)
+	rg.DELETE("/v1/bigmodel/chat/session/:id", ctl.DeleteChatSession)
 }
 
 type BigModelController struct {
 	baseController
 
-	s  app.BigModelService
-	us userapp.RegService
+	s        app.BigModelService
+	us       userapp.RegService
+	ts       app.AsyncTaskStatusService
+	api      app.ApiGatewayService
+	keys     app.ApiKeyService
+	sessions app.ChatSessionService
 }
 
 //	@Title			DescribePicture
@@ -468,6 +498,54 @@ func (ctl *BigModelController) WuKong(ctx *gin.Context) {
 	}
 }
 
+//	@Title			WuKongUploadPicture
+//	@Description	upload the source or mask picture for img2img and inpainting of WuKong
+//	@Tags			BigModel
+//	@Param			picture	formData	file	true	"picture"
+//	@Accept			json
+//	@Success		201	{object}		wukongUploadPictureResp
+//	@Failure		400	bigmodel_sensitive_info	picture	is	illegal
+//	@Failure		500	system_error			system	error
+//	@Router			/v1/bigmodel/wukong/upload_picture [post]
+func (ctl *BigModelController) WuKongUploadPicture(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	f, err := ctx.FormFile("picture")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, newResponseCodeMsg(
+			errorBadRequestBody, err.Error(),
+		))
+
+		return
+	}
+
+	if !utils.IsPictureName(f.Filename) {
+		ctl.sendBadRequestParamWithMsg(ctx, "image format not allowed")
+
+		return
+	}
+
+	p, err := f.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, newResponseCodeMsg(
+			errorBadRequestParam, "can't get picture",
+		))
+
+		return
+	}
+
+	defer p.Close()
+
+	if v, code, err := ctl.s.WuKongUploadPicture(p, pl.DomainAccount(), f.Filename); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, wukongUploadPictureResp{v})
+	}
+}
+
 //	@Title			WuKong
 //	@Description	send async wukong request task
 //	@Tags			BigModel
@@ -584,6 +662,138 @@ func (ctl *BigModelController) WuKongLastFinisedTask(ctx *gin.Context) {
 	}
 }
 
+//	@Title			CancelWuKongTask
+//	@Description	cancel the waiting or running wukong task
+//	@Tags			BigModel
+//	@Param			id	path	int	true	"task id"
+//	@Accept			json
+//	@Success		204
+//	@Failure		400	bad_request_param	id	is	invalid
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/wukong/task/{id} [delete]
+func (ctl *BigModelController) CancelWuKongTask(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.s.CancelWuKongTask(pl.DomainAccount(), id); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		utils.DoLog("", pl.Account, "cancel wukong task",
+			fmt.Sprintf("taskid: %d", id), "success")
+
+		ctl.sendRespOfDelete(ctx)
+	}
+}
+
+//	@Title			WatchAsyncTask
+//	@Description	push the status of async tasks over websocket
+//	@Tags			BigModel
+//	@Param			model	query	string	false	"wukong by default or the model of text generation"
+//	@Accept			json
+//	@Success		200	{object}		app.AsyncTaskStatusDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/async/status [get]
+func (ctl *BigModelController) WatchAsyncTask(ctx *gin.Context) {
+	pl, csrftoken, _, ok := ctl.checkTokenForWebsocket(ctx, false)
+	if !ok {
+		return
+	}
+
+	cmd, err := app.NewAsyncTaskWatchCmd(pl.DomainAccount(), ctx.Query("model"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	// setup websocket
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{csrftoken},
+		CheckOrigin: func(r *http.Request) bool {
+			return r.Header.Get(headerSecWebsocket) == csrftoken
+		},
+	}
+
+	ws, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+
+		return
+	}
+
+	defer ws.Close()
+
+	// the client closes the connection when it doesn't care about the tasks any more
+	stop := make(chan struct{})
+	go func() {
+		defer close(stop)
+
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = ctl.ts.Watch(&cmd, func(dto *app.AsyncTaskStatusDTO) error {
+		return ws.WriteJSON(newResponseData(dto))
+	}, stop)
+	if err != nil {
+		log.Debugf("watch async task done, err:%s", err.Error())
+	}
+}
+
+//	@Title			WatchAsyncTaskBySSE
+//	@Description	push the status of async tasks over server-sent events
+//	@Tags			BigModel
+//	@Param			model	query	string	false	"wukong by default or the model of text generation"
+//	@Accept			json
+//	@Success		200	{object}		app.AsyncTaskStatusDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/async/status/sse [get]
+func (ctl *BigModelController) WatchAsyncTaskBySSE(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	cmd, err := app.NewAsyncTaskWatchCmd(pl.DomainAccount(), ctx.Query("model"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream; charset=utf-8")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+
+	stop := make(chan struct{})
+	go func() {
+		<-ctx.Request.Context().Done()
+		close(stop)
+	}()
+
+	err = ctl.ts.Watch(&cmd, func(dto *app.AsyncTaskStatusDTO) error {
+		ctx.SSEvent("status", dto)
+		ctx.Writer.Flush()
+
+		return ctx.Request.Context().Err()
+	}, stop)
+	if err != nil {
+		log.Debugf("watch async task done, err:%s", err.Error())
+	}
+}
+
 //	@Title			AddLike
 //	@Description	add like to wukong picture
 //	@Tags			BigModel
@@ -1052,7 +1262,9 @@ func (ctl *BigModelController) GLM2(ctx *gin.Context) {
 			if code == app.ErrorBigModelRecourseBusy {
 				ctx.SSEvent("message", "access overload, please try again later")
 			} else if code == app.ErrorBigModelSensitiveInfo {
-				ctx.SSEvent("message", "I cannot answer such questions")
+				ctx.SSEvent("message", msgSensitiveAnswer)
+			} else if code == app.ErrorChatSessionNotFound {
+				ctx.SSEvent("message", "the chat session does not exist")
 			}
 
 			close(ch)
@@ -1063,24 +1275,7 @@ func (ctl *BigModelController) GLM2(ctx *gin.Context) {
 		return
 	}
 
-	ctx.Header("Content-Type", "text/event-stream; charset=utf-8")
-	ctx.Header("Cache-Control", "no-cache")
-	ctx.Header("Connection", "keep-alive")
-
-	ctx.Stream(func(w io.Writer) bool {
-		if msg, ok := <-ch; ok {
-			if msg == "done" {
-				ctx.SSEvent("status", "done")
-				close(ch)
-			} else {
-				ctx.SSEvent("message", msg)
-			}
-
-			return true
-		}
-
-		return false
-	})
+	sendTextStream(ctx, ch)
 }
 
 //	@Title			LLAMA2
@@ -1118,7 +1313,9 @@ func (ctl *BigModelController) LLAMA2(ctx *gin.Context) {
 			if code == app.ErrorBigModelRecourseBusy {
 				ctx.SSEvent("message", "access overload, please try again later")
 			} else if code == app.ErrorBigModelSensitiveInfo {
-				ctx.SSEvent("message", "I cannot answer such questions")
+				ctx.SSEvent("message", msgSensitiveAnswer)
+			} else if code == app.ErrorChatSessionNotFound {
+				ctx.SSEvent("message", "the chat session does not exist")
 			}
 
 			close(ch)
@@ -1129,132 +1326,140 @@ func (ctl *BigModelController) LLAMA2(ctx *gin.Context) {
 		return
 	}
 
-	ctx.Header("Content-Type", "text/event-stream; charset=utf-8")
-	ctx.Header("Cache-Control", "no-cache")
-	ctx.Header("Connection", "keep-alive")
-
-	ctx.Stream(func(w io.Writer) bool {
-		if msg, ok := <-ch; ok {
-			if msg == "done" {
-				ctx.SSEvent("status", "done")
-				close(ch)
-			} else {
-				ctx.SSEvent("message", msg)
-			}
-			return true
-		}
-		return false
-	})
+	sendTextStream(ctx, ch)
 }
 
-//	@Title			ApplyApi
-//	@Description	generates pictures by WuKong-hf
+//	@Title			TextAsync
+//	@Description	send async text generation task of glm2, llama2, baichuan, pangu or codegeex
 //	@Tags			BigModel
-//	@Param			body	body	wukongHFRequest	true	"body of wukong"
+//	@Param			model	path	string				true	"model name"
+//	@Param			body	body	asyncTextRequest	true	"body of async task"
 //	@Accept			json
-//	@Success		201	{object}				wukongPicturesGenerateResp
-//	@Failure		500	system_error			system	error
-//	@Failure		404	bigmodel_sensitive_info	picture	error
-//	@Router			/v1/bigmodel/api/apply/{model} [post]
-func (ctl *BigModelController) ApplyApi(ctx *gin.Context) {
-	model, err := domain.NewModelName(ctx.Param("model"))
-	if err != nil {
-		ctl.sendBadRequestParam(ctx, err)
-		return
-	}
-
+//	@Success		201
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/async/{model} [post]
+func (ctl *BigModelController) TextAsync(ctx *gin.Context) {
 	pl, _, ok := ctl.checkUserApiToken(ctx, false)
 	if !ok {
 		return
 	}
 
-	b, err := ctl.s.IsApplyModel(pl.DomainAccount(), model)
-	if b || err != nil {
-		ctl.sendBadRequestParamWithMsg(ctx, "already applied")
-		return
-	}
-
-	req := applyApiReq{}
+	req := asyncTextRequest{}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, respBadRequestBody)
+		ctl.sendBadRequestBody(ctx)
 
 		return
 	}
 
-	cmd, err := req.toCmd(pl.DomainAccount())
+	cmd, err := req.toCmd(pl.DomainAccount(), ctx.Param("model"))
 	if err != nil {
 		ctl.sendBadRequestParam(ctx, err)
+
 		return
 	}
 
-	v := pl.Account + "+" + strconv.FormatInt(utils.Now(), 10)
-	token, err := ctl.encryptData(v)
-	if err != nil {
-		return
+	if code, err := ctl.s.TextInferenceAsync(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		utils.DoLog("", pl.Account, "create async text generation task",
+			fmt.Sprintf("model: %s", ctx.Param("model")), "success")
+
+		ctl.sendRespOfPost(ctx, "")
 	}
-	enToken, err := ctl.encryptDataForToken(token)
-	if err != nil {
+}
+
+//	@Title			LastAsyncTask
+//	@Description	get the last async task of the model
+//	@Tags			BigModel
+//	@Param			model	path	string	true	"model name"
+//	@Accept			json
+//	@Success		200	{object}		app.AsyncTaskDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/async/{model}/task [get]
+func (ctl *BigModelController) LastAsyncTask(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
 		return
 	}
 
-	if err = ctl.us.UpsertUserRegInfo(&cmd); err != nil {
-		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	t, err := asyncdomain.NewTaskType(ctx.Param("model"))
+	if err != nil || !t.IsTextGeneration() {
+		ctl.sendBadRequestParamWithMsg(ctx, "unsupported model")
+
+		return
 	}
 
-	if err := ctl.s.ApplyApi(pl.DomainAccount(), model, enToken); err != nil {
-		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	v, code, err := ctl.s.GetLastAsyncTask(pl.DomainAccount(), t)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
 	} else {
-		ctl.sendRespOfPost(ctx, "success")
+		ctl.sendRespOfGet(ctx, v)
 	}
 }
 
-//	@Title			WuKong
-//	@Description	generates pictures by WuKong
+//	@Title			ApplyApi
+//	@Description	apply for the api of model, a default api key is issued and shown only once
 //	@Tags			BigModel
-//	@Param			body	body	wukongRequest	true	"body of wukong"
+//	@Param			model	path	string		true	"model name"
+//	@Param			body	body	applyApiReq	true	"body of apply"
 //	@Accept			json
-//	@Success		201	{object}		wukongPicturesGenerateResp
+//	@Success		201	{object}		app.ApiKeyCreatedDTO
 //	@Failure		500	system_error	system	error
-//	@Router			/v1/bigmodel/api/{model} [post]
-func (ctl *BigModelController) WukongAPI(ctx *gin.Context) {
-	user, ok := ctl.checkBigmodelApiToken(ctx)
-	if !ok {
-		return
-	}
-	ac, err := types.NewAccount(user)
+//	@Router			/v1/bigmodel/api/apply/{model} [post]
+func (ctl *BigModelController) ApplyApi(ctx *gin.Context) {
+	model, err := domain.NewModelName(ctx.Param("model"))
 	if err != nil {
 		ctl.sendBadRequestParam(ctx, err)
 		return
 	}
-	model, err := domain.NewModelName("wukong")
-	if err != nil {
-		ctl.sendBadRequestParam(ctx, err)
+
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
 		return
 	}
 
-	_, err = ctl.s.GetApplyRecordByModel(ac, model)
-	if err != nil {
-		ctl.sendBadRequestParam(ctx, err)
+	b, err := ctl.s.IsApplyModel(pl.DomainAccount(), model)
+	if b || err != nil {
+		ctl.sendBadRequestParamWithMsg(ctx, "already applied")
 		return
 	}
 
-	req := wukongApiRequest{}
+	req := applyApiReq{}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctl.sendBadRequestBody(ctx)
+		ctx.JSON(http.StatusBadRequest, respBadRequestBody)
 
 		return
 	}
 
-	cmd, err := req.toCmd()
+	cmd, err := req.toCmd(pl.DomainAccount())
 	if err != nil {
 		ctl.sendBadRequestParam(ctx, err)
 		return
 	}
 
-	if v, code, err := ctl.s.WukongApi(ac, model, &cmd); err != nil {
+	if err = ctl.us.UpsertUserRegInfo(&cmd); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+
+		return
+	}
+
+	if err := ctl.s.ApplyApi(pl.DomainAccount(), model); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+
+		return
+	}
+
+	name, _ := domain.NewApiKeyName(defaultApiKeyName)
+
+	v, code, err := ctl.keys.Create(&app.ApiKeyCreateCmd{
+		User:  pl.DomainAccount(),
+		Model: model,
+		Name:  name,
+	})
+	if err != nil {
 		ctl.sendCodeMessage(ctx, code, err)
 	} else {
-		ctl.sendRespOfPost(ctx, wukongPicturesGenerateResp{v})
+		ctl.sendRespOfPost(ctx, v)
 	}
 }
 
@@ -1272,20 +1477,29 @@ func (ctl *BigModelController) GetUserApplyRecord(ctx *gin.Context) {
 	}
 
 	v, err := ctl.s.GetApplyRecordByUser(pl.DomainAccount())
+	if err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+
+		return
+	}
 
+	// the legacy token is saved encrypted
 	for i := range v {
+		if v[i].Token == "" {
+			continue
+		}
+
 		deToken, err := ctl.decryptDataForToken(v[i].Token)
 		if err != nil {
+			ctl.sendRespWithInternalError(ctx, newResponseError(err))
+
 			return
 		}
+
 		v[i].Token = string(deToken)
 	}
 
-	if err != nil {
-		ctl.sendCodeMessage(ctx, "", err)
-	} else {
-		ctl.sendRespOfGet(ctx, v)
-	}
+	ctl.sendRespOfGet(ctx, v)
 }
 
 //	@Title			IsApplied
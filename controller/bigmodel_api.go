@@ -0,0 +1,630 @@
+package controller
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/bigmodel/app"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	types "github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+	headerRetryAfter         = "Retry-After"
+
+	defaultApiKeyName = "default"
+)
+
+// apiHandler serves the api of a model, it returns true if the call is successful.
+type apiHandler func(*BigModelController, *gin.Context, types.Account) bool
+
+var apiHandlers = map[string]apiHandler{
+	"wukong":      (*BigModelController).wukongApi,
+	"pangu":       (*BigModelController).panguApi,
+	"luojia":      (*BigModelController).luojiaApi,
+	"codegeex":    (*BigModelController).codegeexApi,
+	"ai_detector": (*BigModelController).aiDetectorApi,
+	"baichuan":    (*BigModelController).baichuanApi,
+	"glm2":        (*BigModelController).glm2Api,
+	"llama2":      (*BigModelController).llama2Api,
+}
+
+//	@Title			BigModelAPI
+//	@Description	call the api of bigmodel by the api token
+//	@Tags			BigModel
+//	@Param			model	path	string	true	"model name"
+//	@Accept			json
+//	@Success		201
+//	@Failure		400	bad_request_param	model	is	invalid
+//	@Failure		429	bigmodel_api_rate_limited	too	many	requests
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/api/{model} [post]
+func (ctl *BigModelController) BigModelAPI(ctx *gin.Context) {
+	model, err := domain.NewModelName(ctx.Param("model"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	h, ok := apiHandlers[model.ModelName()]
+	if !ok {
+		ctl.sendBadRequestParamWithMsg(ctx, "the model has no api")
+
+		return
+	}
+
+	ctl.serveApi(ctx, model, h)
+}
+
+// serveApi authenticates the api key, checks the quota and records the call
+// before and after serving the api of model by the handler.
+func (ctl *BigModelController) serveApi(ctx *gin.Context, model domain.ModelName, h apiHandler) {
+	ac, ok := ctl.checkBigmodelApiToken(ctx, ctl.keys, ctl.s, model)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	succeeded := false
+
+	defer func() {
+		ctl.recordApiCall(ctx, ac, model, start, succeeded)
+	}()
+
+	limit, code, err := ctl.api.Access(ac, model)
+	setRateLimitHeader(ctx, &limit)
+
+	if err != nil {
+		if code == app.ErrorApiRateLimited || code == app.ErrorApiQuotaExceeded {
+			ctx.Header(headerRetryAfter, strconv.FormatInt(limit.ResetAt-time.Now().Unix(), 10))
+			ctx.JSON(http.StatusTooManyRequests, newResponseCodeError(code, err))
+		} else {
+			ctl.sendCodeMessage(ctx, code, err)
+		}
+
+		return
+	}
+
+	succeeded = h(ctl, ctx, ac)
+}
+
+func (ctl *BigModelController) recordApiCall(
+	ctx *gin.Context, user types.Account, model domain.ModelName,
+	start time.Time, succeeded bool,
+) {
+	status := ctx.Writer.Status()
+	if !succeeded && status < http.StatusBadRequest {
+		// the error happened after the response had been written, such as the stream
+		status = http.StatusInternalServerError
+	}
+
+	err := ctl.api.Record(&app.ApiCallRecordCmd{
+		User:    user,
+		Model:   model,
+		Status:  status,
+		Latency: time.Since(start),
+		At:      start,
+	})
+	if err != nil {
+		log.Errorf("record api call of %s failed, err:%s", model.ModelName(), err.Error())
+	}
+}
+
+func setRateLimitHeader(ctx *gin.Context, v *app.ApiRateLimitDTO) {
+	if v.Limit <= 0 {
+		return
+	}
+
+	ctx.Header(headerRateLimitLimit, strconv.Itoa(v.Limit))
+	ctx.Header(headerRateLimitRemaining, strconv.Itoa(v.Remaining))
+	ctx.Header(headerRateLimitReset, strconv.FormatInt(v.ResetAt, 10))
+}
+
+//	@Title			GetApiQuota
+//	@Description	get the quota and usage of the api of model
+//	@Tags			BigModel
+//	@Param			model	path	string	true	"model name"
+//	@Accept			json
+//	@Success		200	{object}		app.ApiQuotaUsageDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/api/quota/{model} [get]
+func (ctl *BigModelController) GetApiQuota(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	model, err := domain.NewModelName(ctx.Param("model"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.api.GetUsage(pl.DomainAccount(), model); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			CreateApiKey
+//	@Description	create an api key of model, the key is shown only once
+//	@Tags			BigModel
+//	@Param			model	path	string					true	"model name"
+//	@Param			body	body	apiKeyCreateRequest		true	"body of api key"
+//	@Accept			json
+//	@Success		201	{object}				app.ApiKeyCreatedDTO
+//	@Failure		400	bad_request_param		some	parameter	is	invalid
+//	@Failure		400	bigmodel_api_key_exceed_max_num	too	many	keys
+//	@Failure		500	system_error			system	error
+//	@Router			/v1/bigmodel/api/key/{model} [post]
+func (ctl *BigModelController) CreateApiKey(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	model, err := domain.NewModelName(ctx.Param("model"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	req := apiKeyCreateRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd, err := req.toCmd(pl.DomainAccount(), model)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, code, err := ctl.keys.Create(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, v)
+	}
+}
+
+//	@Title			ListApiKeys
+//	@Description	list the api keys of model
+//	@Tags			BigModel
+//	@Param			model	path	string	true	"model name"
+//	@Accept			json
+//	@Success		200	{object}		app.ApiKeyDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/api/key/{model} [get]
+func (ctl *BigModelController) ListApiKeys(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	model, err := domain.NewModelName(ctx.Param("model"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.keys.List(pl.DomainAccount(), model); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			RevokeApiKey
+//	@Description	revoke an api key
+//	@Tags			BigModel
+//	@Param			model	path	string	true	"model name"
+//	@Param			id		path	string	true	"id of api key"
+//	@Accept			json
+//	@Success		204
+//	@Failure		400	bigmodel_api_key_not_found	no	such	key
+//	@Failure		500	system_error				system	error
+//	@Router			/v1/bigmodel/api/key/{model}/{id} [delete]
+func (ctl *BigModelController) RevokeApiKey(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	model, err := domain.NewModelName(ctx.Param("model"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.keys.Revoke(pl.DomainAccount(), model, ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfDelete(ctx)
+	}
+}
+
+//	@Title			GetApiUsage
+//	@Description	get the usage statistics of api by status code
+//	@Tags			BigModel
+//	@Param			model		query	string	false	"model name, all the models if empty"
+//	@Param			start		query	string	false	"start date, such as 2023-01-02, default is 29 days before the end"
+//	@Param			end			query	string	false	"end date, default is today"
+//	@Param			granularity	query	string	false	"day or hour, default is day"
+//	@Param			format		query	string	false	"json or csv, default is json"
+//	@Accept			json
+//	@Success		200	{object}			app.ApiUsageStatsDTO
+//	@Failure		400	bad_request_param	some	parameter	is	invalid
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/api/usage [get]
+func (ctl *BigModelController) GetApiUsage(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	cmd, err := ctl.getApiUsageStatsCmd(ctx, pl.DomainAccount())
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	v, err := ctl.api.ListUsageStats(&cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+
+		return
+	}
+
+	if ctx.Query("format") != "csv" {
+		ctl.sendRespOfGet(ctx, v)
+
+		return
+	}
+
+	ctx.Header(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=api_usage_%s_%s.csv", v.Start, v.End),
+	)
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Status(http.StatusOK)
+
+	if err := writeApiUsageCSV(ctx.Writer, &v); err != nil {
+		log.Errorf("write api usage csv failed, err:%s", err.Error())
+	}
+}
+
+func (ctl *BigModelController) getApiUsageStatsCmd(ctx *gin.Context, user types.Account) (
+	cmd app.ApiUsageStatsCmd, err error,
+) {
+	cmd.User = user
+
+	if v := ctx.Query("model"); v != "" {
+		if cmd.Model, err = domain.NewModelName(v); err != nil {
+			return
+		}
+	}
+
+	switch ctx.Query("granularity") {
+	case "", "day":
+	case "hour":
+		cmd.Hourly = true
+	default:
+		err = errors.New("invalid granularity")
+
+		return
+	}
+
+	if cmd.End = ctx.Query("end"); cmd.End == "" {
+		cmd.End = utils.Date()
+	}
+
+	if cmd.Start = ctx.Query("start"); cmd.Start == "" {
+		end, e := time.Parse("2006-01-02", cmd.End)
+		if e != nil {
+			err = errors.New("invalid end date")
+
+			return
+		}
+
+		cmd.Start = end.AddDate(0, 0, -29).Format("2006-01-02")
+	}
+
+	err = cmd.Validate()
+
+	return
+}
+
+func writeApiUsageCSV(w io.Writer, v *app.ApiUsageStatsDTO) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"period", "model", "status", "count", "avg_latency_ms"}); err != nil {
+		return err
+	}
+
+	for i := range v.Items {
+		item := &v.Items[i]
+
+		err := cw.Write([]string{
+			item.Period,
+			item.Model,
+			strconv.Itoa(item.Status),
+			strconv.Itoa(item.Count),
+			strconv.FormatInt(item.AvgLatency, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func (ctl *BigModelController) wukongApi(ctx *gin.Context, user types.Account) bool {
+	req := wukongApiRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return false
+	}
+
+	cmd, err := req.toCmd()
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return false
+	}
+
+	model, _ := domain.NewModelName("wukong")
+
+	v, code, err := ctl.s.WukongApi(user, model, &cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return false
+	}
+
+	ctl.sendRespOfPost(ctx, wukongPicturesGenerateResp{v})
+
+	return true
+}
+
+func (ctl *BigModelController) panguApi(ctx *gin.Context, user types.Account) bool {
+	req := panguRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return false
+	}
+
+	v, code, err := ctl.s.PanGu(user, req.Question)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return false
+	}
+
+	ctl.sendRespOfPost(ctx, panguResp{v})
+
+	return true
+}
+
+func (ctl *BigModelController) luojiaApi(ctx *gin.Context, user types.Account) bool {
+	v, err := ctl.s.LuoJia(user)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+
+		return false
+	}
+
+	ctl.sendRespOfPost(ctx, luojiaResp{v})
+
+	return true
+}
+
+func (ctl *BigModelController) codegeexApi(ctx *gin.Context, user types.Account) bool {
+	req := CodeGeexRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return false
+	}
+
+	cmd, err := req.toCmd()
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return false
+	}
+
+	v, code, err := ctl.s.CodeGeex(user, &cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return false
+	}
+
+	ctl.sendRespOfPost(ctx, v)
+
+	return true
+}
+
+func (ctl *BigModelController) aiDetectorApi(ctx *gin.Context, user types.Account) bool {
+	req := aiDetectorReq{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return false
+	}
+
+	cmd, err := req.toCmd(user)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return false
+	}
+
+	code, ismachine, err := ctl.s.AIDetector(&cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return false
+	}
+
+	ctl.sendRespOfPost(ctx, aiDetectorResp{ismachine})
+
+	return true
+}
+
+func (ctl *BigModelController) baichuanApi(ctx *gin.Context, user types.Account) bool {
+	req := baichuanReq{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return false
+	}
+
+	cmd, err := req.toCmd(user)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return false
+	}
+
+	code, dto, err := ctl.s.BaiChuan(&cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return false
+	}
+
+	ctl.sendRespOfPost(ctx, dto)
+
+	return true
+}
+
+func (ctl *BigModelController) glm2Api(ctx *gin.Context, user types.Account) bool {
+	req := glm2Request{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return false
+	}
+
+	ch := make(chan string)
+	cmd, err := req.toCmd(ch, user)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return false
+	}
+
+	code, err := ctl.s.GLM2(&cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return false
+	}
+
+	sendTextStream(ctx, ch)
+
+	return true
+}
+
+func (ctl *BigModelController) llama2Api(ctx *gin.Context, user types.Account) bool {
+	req := llama2Request{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return false
+	}
+
+	ch := make(chan string)
+	cmd, err := req.toCmd(ch, user)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return false
+	}
+
+	code, err := ctl.s.LLAMA2(&cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return false
+	}
+
+	sendTextStream(ctx, ch)
+
+	return true
+}
+
+// msgSensitiveAnswer is sent instead of the answer which fails the audit
+const msgSensitiveAnswer = "I cannot answer such questions"
+
+// sendTextStream sends the reply of conversational model as server-sent events
+func sendTextStream(ctx *gin.Context, ch chan string) {
+	ctx.Header("Content-Type", "text/event-stream; charset=utf-8")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+
+	gone := ctx.Stream(func(w io.Writer) bool {
+		if msg, ok := <-ch; ok {
+			switch msg {
+			case bigmodel.StreamDone:
+				ctx.SSEvent("status", "done")
+				close(ch)
+
+			case bigmodel.StreamAuditFailed:
+				// the answer sent before is replaced, because the reply is cumulative
+				ctx.SSEvent("message", msgSensitiveAnswer)
+				ctx.SSEvent("status", "done")
+				close(ch)
+
+			default:
+				ctx.SSEvent("message", msg)
+			}
+
+			return true
+		}
+
+		return false
+	})
+
+	if gone {
+		go drainStream(ch)
+	}
+}
+
+// drainStream reads the rest of stream after the client is gone, so that
+// the model writing the stream can finish and release its endpoint.
+func drainStream(ch chan string) {
+	for msg := range ch {
+		if msg == bigmodel.StreamDone || msg == bigmodel.StreamAuditFailed {
+			return
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/opensourceways/xihe-server/bigmodel/app"
+)
+
+func AddRouterForArenaController(
+	rg *gin.RouterGroup,
+	s app.ArenaService,
+) {
+	ctl := ArenaController{
+		s: s,
+	}
+
+	rg.GET("/v1/bigmodel/arena/ws", ctl.Battle)
+	rg.POST("/v1/bigmodel/arena/:id/vote", ctl.Vote)
+	rg.GET("/v1/bigmodel/arena/leaderboard", ctl.Leaderboard)
+}
+
+type ArenaController struct {
+	baseController
+
+	s app.ArenaService
+}
+
+//	@Title			Battle
+//	@Description	send a prompt to several models and push their answers over websocket.
+//	@Description	the first message from client is the arenaBattleRequest.
+//	@Tags			Arena
+//	@Accept			json
+//	@Success		200	{object}		app.ArenaMessageDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/arena/ws [get]
+func (ctl *ArenaController) Battle(ctx *gin.Context) {
+	pl, csrftoken, _, ok := ctl.checkTokenForWebsocket(ctx, false)
+	if !ok {
+		return
+	}
+
+	// setup websocket
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{csrftoken},
+		CheckOrigin: func(r *http.Request) bool {
+			return r.Header.Get(headerSecWebsocket) == csrftoken
+		},
+	}
+
+	ws, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+
+		return
+	}
+
+	defer ws.Close()
+
+	req := arenaBattleRequest{}
+	if err := ws.ReadJSON(&req); err != nil {
+		_ = ws.WriteJSON(respBadRequestBody)
+
+		return
+	}
+
+	cmd, err := req.toCmd(pl.DomainAccount())
+	if err != nil {
+		_ = ws.WriteJSON(newResponseCodeError(errorBadRequestParam, err))
+
+		return
+	}
+
+	code, err := ctl.s.Battle(&cmd, func(dto *app.ArenaMessageDTO) error {
+		return ws.WriteJSON(newResponseData(dto))
+	})
+	if err != nil {
+		if code != "" {
+			_ = ws.WriteJSON(newResponseCodeError(code, err))
+		}
+
+		log.Debugf("arena battle done, err:%s", err.Error())
+	}
+}
+
+//	@Title			Vote
+//	@Description	vote for the better answer of battle, the winner is the model or tie
+//	@Tags			Arena
+//	@Param			id		path	string				true	"id of battle"
+//	@Param			body	body	arenaVoteRequest	true	"body of vote"
+//	@Accept			json
+//	@Success		201
+//	@Failure		400	bigmodel_arena_invalid_vote	invalid	winner
+//	@Failure		400	bigmodel_arena_voted		the		battle	has	been	voted
+//	@Failure		500	system_error				system	error
+//	@Router			/v1/bigmodel/arena/{id}/vote [post]
+func (ctl *ArenaController) Vote(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := arenaVoteRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd := app.ArenaVoteCmd{
+		User:     pl.DomainAccount(),
+		BattleId: ctx.Param("id"),
+		Winner:   req.Winner,
+	}
+
+	if code, err := ctl.s.Vote(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, "success")
+	}
+}
+
+//	@Title			Leaderboard
+//	@Description	get the Elo leaderboard of models
+//	@Tags			Arena
+//	@Accept			json
+//	@Success		200	{object}		app.ArenaRatingDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/arena/leaderboard [get]
+func (ctl *ArenaController) Leaderboard(ctx *gin.Context) {
+	if _, _, ok := ctl.checkUserApiToken(ctx, true); !ok {
+		return
+	}
+
+	if v, err := ctl.s.Leaderboard(); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
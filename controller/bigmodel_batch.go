@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/app"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+func AddRouterForBatchController(
+	rg *gin.RouterGroup,
+	s app.BatchService,
+) {
+	ctl := BatchController{
+		s: s,
+	}
+
+	rg.POST("/v1/bigmodel/batch/:model", ctl.Create)
+	rg.GET("/v1/bigmodel/batch", ctl.List)
+	rg.GET("/v1/bigmodel/batch/:id", ctl.Get)
+	rg.GET("/v1/bigmodel/batch/:id/output", ctl.Output)
+	rg.DELETE("/v1/bigmodel/batch/:id", ctl.Cancel)
+}
+
+type BatchController struct {
+	baseController
+
+	s app.BatchService
+}
+
+//	@Title			Create
+//	@Description	create a batch job by a JSONL file whose line is like {"custom_id": "1", "text": "hello"}.
+//	@Description	the fields of line are the same as the async task of model.
+//	@Tags			Batch
+//	@Param			model	path		string	true	"wukong, glm2, llama2, baichuan, pangu or codegeex"
+//	@Param			file	formData	file	true	"JSONL file"
+//	@Accept			json
+//	@Success		201	{object}						app.BatchDTO
+//	@Failure		400	bigmodel_batch_invalid_input	invalid	input
+//	@Failure		400	bigmodel_api_not_applied		api		not	applied
+//	@Failure		400	bigmodel_api_quota_exceeded		quota	is	not	enough
+//	@Failure		500	system_error					system	error
+//	@Router			/v1/bigmodel/batch/{model} [post]
+func (ctl *BatchController) Create(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	t, err := asyncdomain.NewBatchTaskType(ctx.Param("model"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	f, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, newResponseCodeMsg(
+			errorBadRequestBody, err.Error(),
+		))
+
+		return
+	}
+
+	if f.Size > domain.MaxBatchFileSize() {
+		ctl.sendBadRequestParamWithMsg(ctx, "too big file")
+
+		return
+	}
+
+	p, err := f.Open()
+	if err != nil {
+		ctl.sendBadRequestParamWithMsg(ctx, "can't read file")
+
+		return
+	}
+
+	defer p.Close()
+
+	input, err := io.ReadAll(p)
+	if err != nil {
+		ctl.sendBadRequestParamWithMsg(ctx, "can't read file")
+
+		return
+	}
+
+	cmd := app.BatchCreateCmd{
+		User:     pl.DomainAccount(),
+		TaskType: t,
+		Input:    input,
+	}
+
+	if v, code, err := ctl.s.Create(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		utils.DoLog("", pl.Account, "create batch",
+			fmt.Sprintf("model: %s, items: %d", t.TaskType(), v.Total), "success")
+
+		ctl.sendRespOfPost(ctx, v)
+	}
+}
+
+//	@Title			List
+//	@Description	list the latest batch jobs
+//	@Tags			Batch
+//	@Accept			json
+//	@Success		200	{object}		app.BatchDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/batch [get]
+func (ctl *BatchController) List(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, err := ctl.s.List(pl.DomainAccount()); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			Get
+//	@Description	get the progress of batch job
+//	@Tags			Batch
+//	@Param			id	path	string	true	"id of batch"
+//	@Accept			json
+//	@Success		200	{object}					app.BatchDTO
+//	@Failure		404	bigmodel_batch_not_found	no		batch
+//	@Failure		500	system_error				system	error
+//	@Router			/v1/bigmodel/batch/{id} [get]
+func (ctl *BatchController) Get(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, code, err := ctl.s.Get(pl.DomainAccount(), id); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			Output
+//	@Description	download the results of batch job as JSONL, the line is app.BatchOutputDTO
+//	@Tags			Batch
+//	@Param			id			path	string	true	"id of batch"
+//	@Param			failed_only	query	bool	false	"only the failed items, it is the report of failure"
+//	@Accept			json
+//	@Success		200
+//	@Failure		404	bigmodel_batch_not_found	no		batch
+//	@Failure		500	system_error				system	error
+//	@Router			/v1/bigmodel/batch/{id}/output [get]
+func (ctl *BatchController) Output(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	cmd := app.BatchOutputCmd{
+		User:       pl.DomainAccount(),
+		BatchId:    id,
+		FailedOnly: ctx.Query("failed_only") == "true",
+	}
+
+	v, code, err := ctl.s.Output(&cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return
+	}
+
+	ctx.Header(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=batch_%d_output.jsonl", id),
+	)
+	ctx.Header("Content-Type", "application/jsonl")
+	ctx.Status(http.StatusOK)
+
+	if err := writeBatchOutput(ctx.Writer, v); err != nil {
+		log.Errorf("write output of batch %d failed, err:%s", id, err.Error())
+	}
+}
+
+//	@Title			Cancel
+//	@Description	cancel the batch job, the items which are done are kept
+//	@Tags			Batch
+//	@Param			id	path	string	true	"id of batch"
+//	@Accept			json
+//	@Success		204
+//	@Failure		404	bigmodel_batch_not_found	no		waiting	or	running	batch
+//	@Failure		500	system_error				system	error
+//	@Router			/v1/bigmodel/batch/{id} [delete]
+func (ctl *BatchController) Cancel(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.s.Cancel(pl.DomainAccount(), id); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		utils.DoLog("", pl.Account, "cancel batch",
+			fmt.Sprintf("batch: %d", id), "success")
+
+		ctl.sendRespOfDelete(ctx)
+	}
+}
+
+func writeBatchOutput(w io.Writer, items []app.BatchOutputDTO) error {
+	enc := json.NewEncoder(w)
+
+	for i := range items {
+		if err := enc.Encode(&items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,314 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/bigmodel/app"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+	"github.com/opensourceways/xihe-server/bigmodel/domain/bigmodel"
+	types "github.com/opensourceways/xihe-server/domain"
+)
+
+const (
+	chatObject      = "chat.completion"
+	chatChunkObject = "chat.completion.chunk"
+	chatFinishStop  = "stop"
+
+	// chatFinishFilter is the finish reason when the answer fails the audit
+	chatFinishFilter = "content_filter"
+)
+
+// chatCompletionRequest is the request of chat completion which is compatible with OpenAI.
+// TopK and RepetitionPenalty are the extensions.
+type chatCompletionRequest struct {
+	Model             string               `json:"model"`
+	Messages          []chatMessageRequest `json:"messages"`
+	Stream            bool                 `json:"stream"`
+	TopK              *int                 `json:"top_k"`
+	TopP              *float64             `json:"top_p"`
+	Temperature       *float64             `json:"temperature"`
+	RepetitionPenalty *float64             `json:"repetition_penalty"`
+}
+
+type chatMessageRequest struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (req *chatCompletionRequest) toCmd(ch chan string, user types.Account) (
+	cmd app.ChatCompletionCmd, err error,
+) {
+	if cmd.Model, err = domain.NewModelName(req.Model); err != nil {
+		return
+	}
+
+	if req.TopK != nil {
+		if cmd.TopK, err = domain.NewTopK(*req.TopK); err != nil {
+			return
+		}
+	}
+
+	if req.TopP != nil {
+		if cmd.TopP, err = domain.NewTopP(*req.TopP); err != nil {
+			return
+		}
+	}
+
+	if req.Temperature != nil {
+		if cmd.Temperature, err = domain.NewTemperature(*req.Temperature); err != nil {
+			return
+		}
+	}
+
+	if req.RepetitionPenalty != nil {
+		if cmd.RepetitionPenalty, err = domain.NewRepetitionPenalty(*req.RepetitionPenalty); err != nil {
+			return
+		}
+	}
+
+	cmd.Messages = make([]app.ChatMessage, len(req.Messages))
+	for i := range req.Messages {
+		cmd.Messages[i] = app.ChatMessage{
+			Role:    req.Messages[i].Role,
+			Content: req.Messages[i].Content,
+		}
+	}
+
+	cmd.CH = ch
+	cmd.User = user
+
+	err = cmd.Validate()
+
+	return
+}
+
+type chatCompletionResp struct {
+	Id      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+}
+
+type chatChoice struct {
+	Index        int                 `json:"index"`
+	Message      *chatMessageRequest `json:"message,omitempty"`
+	Delta        *chatMessageRequest `json:"delta,omitempty"`
+	FinishReason *string             `json:"finish_reason"`
+}
+
+type chatErrorResp struct {
+	Error chatError `json:"error"`
+}
+
+type chatError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+}
+
+//	@Title			ChatCompletions
+//	@Description	chat with the models which support text by the api which is compatible with OpenAI
+//	@Tags			BigModel
+//	@Param			body	body	chatCompletionRequest	true	"body of chat completion"
+//	@Accept			json
+//	@Success		200	{object}			chatCompletionResp
+//	@Failure		400	bad_request_body	invalid	body
+//	@Failure		400	bad_request_param	some	parameter	is	invalid
+//	@Failure		401	bigmodel_api_key_invalid	invalid	api	key
+//	@Failure		429	bigmodel_api_rate_limited	too	many	requests
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/chat/completions [post]
+func (ctl *BigModelController) ChatCompletions(ctx *gin.Context) {
+	req := chatCompletionRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		sendChatError(ctx, http.StatusBadRequest, errorBadRequestBody, err)
+
+		return
+	}
+
+	model, err := domain.NewModelName(req.Model)
+	if err != nil {
+		sendChatError(
+			ctx, http.StatusBadRequest, errorBadRequestParam,
+			errors.New("unsupported chat model"),
+		)
+
+		return
+	}
+
+	ctl.serveApi(ctx, model, func(ctl *BigModelController, ctx *gin.Context, user types.Account) bool {
+		return ctl.chatCompletionsApi(ctx, user, &req)
+	})
+}
+
+func (ctl *BigModelController) chatCompletionsApi(
+	ctx *gin.Context, user types.Account, req *chatCompletionRequest,
+) bool {
+	ch := make(chan string)
+	cmd, err := req.toCmd(ch, user)
+	if err != nil {
+		sendChatError(ctx, http.StatusBadRequest, errorBadRequestParam, err)
+
+		return false
+	}
+
+	code, err := ctl.s.ChatCompletion(&cmd)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if code != "" {
+			status = http.StatusBadRequest
+		}
+
+		sendChatError(ctx, status, code, err)
+
+		return false
+	}
+
+	resp := chatCompletionResp{
+		Id:      fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano()),
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+	}
+
+	if req.Stream {
+		sendChatStream(ctx, ch, &resp)
+	} else {
+		sendChatCompletion(ctx, ch, &resp)
+	}
+
+	return true
+}
+
+// sendChatCompletion keeps only the last chunk, because the chunks of
+// the stream models are cumulative.
+func sendChatCompletion(ctx *gin.Context, ch chan string, resp *chatCompletionResp) {
+	content := ""
+
+	for msg := range ch {
+		if msg == bigmodel.StreamDone {
+			close(ch)
+
+			break
+		}
+
+		if msg == bigmodel.StreamAuditFailed {
+			close(ch)
+
+			sendChatError(
+				ctx, http.StatusBadRequest, app.ErrorBigModelSensitiveInfo,
+				bigmodel.ErrorStreamAuditFailed,
+			)
+
+			return
+		}
+
+		if msg != "" {
+			content = msg
+		}
+	}
+
+	finish := chatFinishStop
+
+	resp.Object = chatObject
+	resp.Choices = []chatChoice{{
+		Message: &chatMessageRequest{
+			Role:    app.ChatRoleAssistant,
+			Content: content,
+		},
+		FinishReason: &finish,
+	}}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// sendChatStream sends the chunks in the format of server-sent events of OpenAI,
+// which has only the data field and ends with [DONE].
+func sendChatStream(ctx *gin.Context, ch chan string, resp *chatCompletionResp) {
+	ctx.Header("Content-Type", "text/event-stream; charset=utf-8")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+
+	resp.Object = chatChunkObject
+
+	write := func(w io.Writer, choice chatChoice) {
+		resp.Choices = []chatChoice{choice}
+
+		if b, err := json.Marshal(resp); err == nil {
+			fmt.Fprintf(w, "data: %s\n\n", b)
+		}
+	}
+
+	first := true
+	prev := ""
+
+	gone := ctx.Stream(func(w io.Writer) bool {
+		msg, ok := <-ch
+		if !ok {
+			return false
+		}
+
+		if msg == bigmodel.StreamDone || msg == bigmodel.StreamAuditFailed {
+			// the chunks sent can't be withdrawn, so the client is told
+			// by the finish reason that the answer fails the audit.
+			finish := chatFinishStop
+			if msg == bigmodel.StreamAuditFailed {
+				finish = chatFinishFilter
+			}
+
+			write(w, chatChoice{Delta: &chatMessageRequest{}, FinishReason: &finish})
+			fmt.Fprint(w, "data: [DONE]\n\n")
+
+			close(ch)
+
+			return false
+		}
+
+		// the chunks are cumulative, so only the new part is sent
+		content := strings.TrimPrefix(msg, prev)
+		if msg != "" {
+			prev = msg
+		}
+
+		if content == "" {
+			return true
+		}
+
+		delta := chatMessageRequest{Content: content}
+		if first {
+			delta.Role = app.ChatRoleAssistant
+			first = false
+		}
+
+		write(w, chatChoice{Delta: &delta})
+
+		return true
+	})
+
+	if gone {
+		go drainStream(ch)
+	}
+}
+
+func sendChatError(ctx *gin.Context, status int, code string, err error) {
+	typ := "invalid_request_error"
+	if status >= http.StatusInternalServerError {
+		typ = "server_error"
+	}
+
+	ctx.JSON(status, chatErrorResp{
+		Error: chatError{
+			Message: err.Error(),
+			Type:    typ,
+			Code:    code,
+		},
+	})
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+)
+
+//	@Title			CreateChatSession
+//	@Description	create a chat session, the history of it is saved at server side
+//	@Tags			BigModel
+//	@Param			body	body	chatSessionCreateRequest	true	"body of chat session"
+//	@Accept			json
+//	@Success		201	{object}								app.ChatSessionDTO
+//	@Failure		400	bad_request_param						some	parameter	is	invalid
+//	@Failure		400	bigmodel_chat_unsupported_model			the		model		can't	chat
+//	@Failure		400	bigmodel_chat_session_exceed_max_num	too		many		sessions
+//	@Failure		500	system_error							system	error
+//	@Router			/v1/bigmodel/chat/session [post]
+func (ctl *BigModelController) CreateChatSession(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := chatSessionCreateRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd, err := req.toCmd(pl.DomainAccount())
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, code, err := ctl.sessions.Create(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, v)
+	}
+}
+
+//	@Title			ListChatSessions
+//	@Description	list the chat sessions, the latest updated is the first one
+//	@Tags			BigModel
+//	@Accept			json
+//	@Success		200	{object}		app.ChatSessionDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/chat/session [get]
+func (ctl *BigModelController) ListChatSessions(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, err := ctl.sessions.List(pl.DomainAccount()); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			GetChatSession
+//	@Description	get the chat session with its history
+//	@Tags			BigModel
+//	@Param			id	path	string	true	"id of chat session"
+//	@Accept			json
+//	@Success		200	{object}						app.ChatSessionDetailDTO
+//	@Failure		400	bigmodel_chat_session_not_found	no		such	session
+//	@Failure		500	system_error					system	error
+//	@Router			/v1/bigmodel/chat/session/{id} [get]
+func (ctl *BigModelController) GetChatSession(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, code, err := ctl.sessions.Get(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			RenameChatSession
+//	@Description	rename the chat session
+//	@Tags			BigModel
+//	@Param			id		path	string						true	"id of chat session"
+//	@Param			body	body	chatSessionRenameRequest	true	"body of chat session"
+//	@Accept			json
+//	@Success		202
+//	@Failure		400	bad_request_param				some	parameter	is	invalid
+//	@Failure		400	bigmodel_chat_session_not_found	no		such		session
+//	@Failure		500	system_error					system	error
+//	@Router			/v1/bigmodel/chat/session/{id} [put]
+func (ctl *BigModelController) RenameChatSession(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := chatSessionRenameRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	title, err := domain.NewChatSessionTitle(req.Title)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.sessions.Rename(pl.DomainAccount(), ctx.Param("id"), title); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPut(ctx, nil)
+	}
+}
+
+//	@Title			DeleteChatSession
+//	@Description	delete the chat session and its history
+//	@Tags			BigModel
+//	@Param			id	path	string	true	"id of chat session"
+//	@Accept			json
+//	@Success		204
+//	@Failure		400	bigmodel_chat_session_not_found	no		such	session
+//	@Failure		500	system_error					system	error
+//	@Router			/v1/bigmodel/chat/session/{id} [delete]
+func (ctl *BigModelController) DeleteChatSession(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if code, err := ctl.sessions.Delete(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfDelete(ctx)
+	}
+}
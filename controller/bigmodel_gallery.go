@@ -0,0 +1,385 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/bigmodel/app"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+)
+
+func AddRouterForGalleryController(
+	rg *gin.RouterGroup,
+	s app.GalleryService,
+) {
+	ctl := GalleryController{
+		s: s,
+	}
+
+	rg.POST("/v1/bigmodel/gallery", ctl.Save)
+	rg.GET("/v1/bigmodel/gallery", ctl.List)
+	rg.GET("/v1/bigmodel/gallery/likes", ctl.ListLikes)
+	rg.GET("/v1/bigmodel/gallery/public", ctl.ListPublic)
+	rg.PUT("/v1/bigmodel/gallery/:id/public", ctl.SetPublic)
+	rg.DELETE("/v1/bigmodel/gallery/:id", ctl.Delete)
+	rg.POST("/v1/bigmodel/gallery/:id/like", ctl.Like)
+	rg.DELETE("/v1/bigmodel/gallery/:id/like", ctl.CancelLike)
+	rg.POST("/v1/bigmodel/gallery/:id/digg", ctl.Digg)
+	rg.DELETE("/v1/bigmodel/gallery/:id/digg", ctl.CancelDigg)
+	rg.POST("/v1/bigmodel/gallery/:id/report", ctl.Report)
+}
+
+type GalleryController struct {
+	baseController
+
+	s app.GalleryService
+}
+
+//	@Title			Save
+//	@Description	save the picture generated by an image model, such as wukong, luojia or vqa
+//	@Tags			Gallery
+//	@Param			body	body	gallerySaveRequest	true	"body of picture"
+//	@Accept			json
+//	@Success		201	{object}								app.GalleryPictureDTO
+//	@Failure		400	bad_request_param						some	parameter	is	invalid
+//	@Failure		400	bigmodel_gallery_invalid_path			invalid	path
+//	@Failure		400	bigmodel_gallery_picture_exceed_max_num	too		many		pictures
+//	@Failure		500	system_error							system	error
+//	@Router			/v1/bigmodel/gallery [post]
+func (ctl *GalleryController) Save(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := gallerySaveRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd, err := req.toCmd(pl.DomainAccount())
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, code, err := ctl.s.Save(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, v)
+	}
+}
+
+//	@Title			List
+//	@Description	list the pictures saved by user
+//	@Tags			Gallery
+//	@Param			model	query	string	false	"model of picture, all the models if empty"
+//	@Accept			json
+//	@Success		200	{object}			app.GalleryPictureDTO
+//	@Failure		400	bad_request_param	some	parameter	is	invalid
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/gallery [get]
+func (ctl *GalleryController) List(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	model, err := ctl.getGalleryModel(ctx)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.s.List(pl.DomainAccount(), model); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			ListLikes
+//	@Description	list the pictures of others which are saved to the likes of user
+//	@Tags			Gallery
+//	@Param			model	query	string	false	"model of picture, all the models if empty"
+//	@Accept			json
+//	@Success		200	{object}			app.GalleryPictureDTO
+//	@Failure		400	bad_request_param	some	parameter	is	invalid
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/gallery/likes [get]
+func (ctl *GalleryController) ListLikes(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	model, err := ctl.getGalleryModel(ctx)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.s.ListLikes(pl.DomainAccount(), model); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			ListPublic
+//	@Description	list the public pictures in the gallery
+//	@Tags			Gallery
+//	@Param			model			query	string	false	"model of picture, all the models if empty"
+//	@Param			sort_by			query	string	false	"digg or created_at, default is created_at"
+//	@Param			count_per_page	query	int		true	"count per page"
+//	@Param			page_num		query	int		true	"page num which starts from 1"
+//	@Accept			json
+//	@Success		200	{object}			app.GalleryPicturesDTO
+//	@Failure		400	bad_request_param	some	parameter	is	invalid
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/gallery/public [get]
+func (ctl *GalleryController) ListPublic(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, true)
+	if !ok {
+		return
+	}
+
+	cmd := app.GalleryListPublicCmd{}
+
+	f := func() (err error) {
+		if v := ctl.getQueryParameter(ctx, "count_per_page"); v != "" {
+			if cmd.CountPerPage, err = strconv.Atoi(v); err != nil {
+				return
+			}
+		}
+
+		if v := ctl.getQueryParameter(ctx, "page_num"); v != "" {
+			if cmd.PageNum, err = strconv.Atoi(v); err != nil {
+				return
+			}
+		}
+
+		if cmd.Model, err = ctl.getGalleryModel(ctx); err != nil {
+			return
+		}
+
+		cmd.SortByDigg = ctl.getQueryParameter(ctx, "sort_by") == "digg"
+		cmd.User = pl.DomainAccount()
+
+		return
+	}
+
+	if err := f(); err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if err := cmd.Validate(); err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.s.ListPublic(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			SetPublic
+//	@Description	publish the picture to the gallery or withdraw it
+//	@Tags			Gallery
+//	@Param			id		path	string					true	"id of picture"
+//	@Param			body	body	galleryPublicRequest	true	"body of public"
+//	@Accept			json
+//	@Success		202
+//	@Failure		400	bigmodel_gallery_picture_not_found	no		such	picture
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/gallery/{id}/public [put]
+func (ctl *GalleryController) SetPublic(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := galleryPublicRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	code, err := ctl.s.SetPublic(pl.DomainAccount(), ctx.Param("id"), req.Public)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPut(ctx, nil)
+	}
+}
+
+//	@Title			Delete
+//	@Description	delete the picture saved by user
+//	@Tags			Gallery
+//	@Param			id	path	string	true	"id of picture"
+//	@Accept			json
+//	@Success		204
+//	@Failure		400	bigmodel_gallery_picture_not_found	no		such	picture
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/gallery/{id} [delete]
+func (ctl *GalleryController) Delete(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if code, err := ctl.s.Delete(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfDelete(ctx)
+	}
+}
+
+//	@Title			Like
+//	@Description	save the public picture to the likes of user
+//	@Tags			Gallery
+//	@Param			id	path	string	true	"id of picture"
+//	@Accept			json
+//	@Success		201
+//	@Failure		400	bigmodel_gallery_picture_not_found		no		such	picture
+//	@Failure		400	bigmodel_gallery_like_exceed_max_num	too		many	likes
+//	@Failure		500	system_error							system	error
+//	@Router			/v1/bigmodel/gallery/{id}/like [post]
+func (ctl *GalleryController) Like(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if code, err := ctl.s.Like(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, nil)
+	}
+}
+
+//	@Title			CancelLike
+//	@Description	remove the picture from the likes of user
+//	@Tags			Gallery
+//	@Param			id	path	string	true	"id of picture"
+//	@Accept			json
+//	@Success		204
+//	@Failure		400	bigmodel_gallery_picture_not_found	no		such	picture
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/gallery/{id}/like [delete]
+func (ctl *GalleryController) CancelLike(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if code, err := ctl.s.CancelLike(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfDelete(ctx)
+	}
+}
+
+//	@Title			Digg
+//	@Description	digg the public picture
+//	@Tags			Gallery
+//	@Param			id	path	string	true	"id of picture"
+//	@Accept			json
+//	@Success		201	{object}							galleryDiggResp
+//	@Failure		400	bigmodel_gallery_picture_not_found	no		such	picture
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/gallery/{id}/digg [post]
+func (ctl *GalleryController) Digg(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if n, code, err := ctl.s.Digg(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, galleryDiggResp{n})
+	}
+}
+
+//	@Title			CancelDigg
+//	@Description	cancel the digg of picture
+//	@Tags			Gallery
+//	@Param			id	path	string	true	"id of picture"
+//	@Accept			json
+//	@Success		202	{object}							galleryDiggResp
+//	@Failure		400	bigmodel_gallery_picture_not_found	no		such	picture
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/gallery/{id}/digg [delete]
+func (ctl *GalleryController) CancelDigg(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if n, code, err := ctl.s.CancelDigg(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPut(ctx, galleryDiggResp{n})
+	}
+}
+
+//	@Title			Report
+//	@Description	report the public picture, it will be reviewed by the admin
+//	@Tags			Gallery
+//	@Param			id		path	string					true	"id of picture"
+//	@Param			body	body	galleryReportRequest	true	"body of report"
+//	@Accept			json
+//	@Success		201
+//	@Failure		400	bad_request_param			some	parameter	is	invalid
+//	@Failure		400	abuse_duplicate_report		reported	already
+//	@Failure		400	abuse_report_own_item		can't		report		own	item
+//	@Failure		500	system_error				system		error
+//	@Router			/v1/bigmodel/gallery/{id}/report [post]
+func (ctl *GalleryController) Report(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := galleryReportRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd, err := req.toCmd(pl.DomainAccount(), ctx.Param("id"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.s.Report(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, nil)
+	}
+}
+
+func (ctl *GalleryController) getGalleryModel(ctx *gin.Context) (domain.GalleryModel, error) {
+	v := ctl.getQueryParameter(ctx, "model")
+	if v == "" {
+		return nil, nil
+	}
+
+	return domain.NewGalleryModel(v)
+}
@@ -0,0 +1,337 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/bigmodel/app"
+	"github.com/opensourceways/xihe-server/bigmodel/domain"
+)
+
+func AddRouterForPromptController(
+	rg *gin.RouterGroup,
+	s app.PromptService,
+) {
+	ctl := PromptController{
+		s: s,
+	}
+
+	rg.POST("/v1/bigmodel/prompt", ctl.Create)
+	rg.GET("/v1/bigmodel/prompt", ctl.List)
+	rg.GET("/v1/bigmodel/prompt/gallery", ctl.ListPublic)
+	rg.GET("/v1/bigmodel/prompt/:id", ctl.Get)
+	rg.PUT("/v1/bigmodel/prompt/:id", ctl.Update)
+	rg.DELETE("/v1/bigmodel/prompt/:id", ctl.Delete)
+	rg.POST("/v1/bigmodel/prompt/:id/like", ctl.Like)
+	rg.DELETE("/v1/bigmodel/prompt/:id/like", ctl.CancelLike)
+	rg.POST("/v1/bigmodel/prompt/:id/fork", ctl.Fork)
+	rg.POST("/v1/bigmodel/prompt/:id/run", ctl.Run)
+}
+
+type PromptController struct {
+	baseController
+
+	s app.PromptService
+}
+
+//	@Title			Create
+//	@Description	create a prompt template, the variable in content is like {{name}}
+//	@Tags			Prompt
+//	@Param			body	body	promptCreateRequest	true	"body of prompt template"
+//	@Accept			json
+//	@Success		201	{object}			app.PromptTemplateDTO
+//	@Failure		400	bad_request_param	some	parameter	is	invalid
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/prompt [post]
+func (ctl *PromptController) Create(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := promptCreateRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd, err := req.toCmd(pl.DomainAccount())
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, code, err := ctl.s.Create(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, v)
+	}
+}
+
+//	@Title			List
+//	@Description	list the prompt templates of user
+//	@Tags			Prompt
+//	@Accept			json
+//	@Success		200	{object}		app.PromptTemplateDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/bigmodel/prompt [get]
+func (ctl *PromptController) List(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, err := ctl.s.List(pl.DomainAccount()); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			ListPublic
+//	@Description	list the prompt templates in the gallery
+//	@Tags			Prompt
+//	@Param			model			query	string	false	"model of template, all the models if empty"
+//	@Param			sort_by			query	string	false	"likes or updated_at, default is updated_at"
+//	@Param			count_per_page	query	int		true	"count per page"
+//	@Param			page_num		query	int		true	"page num which starts from 1"
+//	@Accept			json
+//	@Success		200	{object}			app.PromptTemplatesDTO
+//	@Failure		400	bad_request_param	some	parameter	is	invalid
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/bigmodel/prompt/gallery [get]
+func (ctl *PromptController) ListPublic(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, true)
+	if !ok {
+		return
+	}
+
+	cmd := app.PromptListPublicCmd{}
+
+	f := func() (err error) {
+		if v := ctl.getQueryParameter(ctx, "count_per_page"); v != "" {
+			if cmd.CountPerPage, err = strconv.Atoi(v); err != nil {
+				return
+			}
+		}
+
+		if v := ctl.getQueryParameter(ctx, "page_num"); v != "" {
+			if cmd.PageNum, err = strconv.Atoi(v); err != nil {
+				return
+			}
+		}
+
+		if v := ctl.getQueryParameter(ctx, "model"); v != "" {
+			if cmd.Model, err = domain.NewPromptModel(v); err != nil {
+				return
+			}
+		}
+
+		cmd.SortByLikes = ctl.getQueryParameter(ctx, "sort_by") == "likes"
+		cmd.User = pl.DomainAccount()
+
+		return
+	}
+
+	if err := f(); err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if err := cmd.Validate(); err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.s.ListPublic(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, "", err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			Get
+//	@Description	get the prompt template
+//	@Tags			Prompt
+//	@Param			id	path	string	true	"id of prompt template"
+//	@Accept			json
+//	@Success		200	{object}							app.PromptTemplateDTO
+//	@Failure		400	bigmodel_prompt_template_not_found	no		such	template
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/prompt/{id} [get]
+func (ctl *PromptController) Get(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, true)
+	if !ok {
+		return
+	}
+
+	if v, code, err := ctl.s.Get(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Title			Update
+//	@Description	update the prompt template, it is published to gallery if public is true
+//	@Tags			Prompt
+//	@Param			id		path	string				true	"id of prompt template"
+//	@Param			body	body	promptUpdateRequest	true	"body of prompt template"
+//	@Accept			json
+//	@Success		202
+//	@Failure		400	bad_request_param					some	parameter	is	invalid
+//	@Failure		400	bigmodel_prompt_template_not_owner	not		the			owner
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/prompt/{id} [put]
+func (ctl *PromptController) Update(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := promptUpdateRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd, err := req.toCmd(pl.DomainAccount(), ctx.Param("id"))
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.s.Update(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPut(ctx, nil)
+	}
+}
+
+//	@Title			Delete
+//	@Description	delete the prompt template
+//	@Tags			Prompt
+//	@Param			id	path	string	true	"id of prompt template"
+//	@Accept			json
+//	@Success		204
+//	@Failure		400	bigmodel_prompt_template_not_found	no		such	template
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/prompt/{id} [delete]
+func (ctl *PromptController) Delete(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if code, err := ctl.s.Delete(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfDelete(ctx)
+	}
+}
+
+//	@Title			Like
+//	@Description	like the prompt template in gallery
+//	@Tags			Prompt
+//	@Param			id	path	string	true	"id of prompt template"
+//	@Accept			json
+//	@Success		201	{object}							promptLikeResp
+//	@Failure		400	bigmodel_prompt_template_not_found	no		such	template
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/prompt/{id}/like [post]
+func (ctl *PromptController) Like(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if n, code, err := ctl.s.Like(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, promptLikeResp{n})
+	}
+}
+
+//	@Title			CancelLike
+//	@Description	cancel the like of prompt template
+//	@Tags			Prompt
+//	@Param			id	path	string	true	"id of prompt template"
+//	@Accept			json
+//	@Success		202	{object}							promptLikeResp
+//	@Failure		400	bigmodel_prompt_template_not_found	no		such	template
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/prompt/{id}/like [delete]
+func (ctl *PromptController) CancelLike(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if n, code, err := ctl.s.CancelLike(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPut(ctx, promptLikeResp{n})
+	}
+}
+
+//	@Title			Fork
+//	@Description	fork the prompt template to the templates of user
+//	@Tags			Prompt
+//	@Param			id	path	string	true	"id of prompt template"
+//	@Accept			json
+//	@Success		201	{object}							app.PromptTemplateDTO
+//	@Failure		400	bigmodel_prompt_template_not_found	no		such	template
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/prompt/{id}/fork [post]
+func (ctl *PromptController) Fork(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, code, err := ctl.s.Fork(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, v)
+	}
+}
+
+//	@Title			Run
+//	@Description	instantiate the prompt template with the variables and call the model
+//	@Tags			Prompt
+//	@Param			id		path	string				true	"id of prompt template"
+//	@Param			body	body	promptRunRequest	true	"values of variables"
+//	@Accept			json
+//	@Success		201	{object}							app.PromptRunDTO
+//	@Failure		400	bigmodel_prompt_invalid_variables	some	variables	are	missing
+//	@Failure		400	bigmodel_resource_busy				the		model		is	busy
+//	@Failure		500	system_error						system	error
+//	@Router			/v1/bigmodel/prompt/{id}/run [post]
+func (ctl *PromptController) Run(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	req := promptRunRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	cmd := req.toCmd(pl.DomainAccount(), ctx.Param("id"))
+
+	if v, code, err := ctl.s.Run(&cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPost(ctx, v)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"io"
 
+	abusedomain "github.com/opensourceways/xihe-server/abuse/domain"
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
 	"github.com/opensourceways/xihe-server/bigmodel/app"
 	"github.com/opensourceways/xihe-server/bigmodel/domain"
 	types "github.com/opensourceways/xihe-server/domain"
@@ -16,6 +18,8 @@ const (
 	tempAccountHF       = "wukong_hf"
 	tempAccountVQAHF    = "vqa_hf"
 	tempAccountLuoJiaHF = "luojia_hf"
+
+	defaultChatSessionTitle = "New Chat"
 )
 
 type describePictureResp struct {
@@ -146,6 +150,12 @@ type wukongRequest struct {
 	Desc        string `json:"desc"`
 	Style       string `json:"style"`
 	ImgQuantity int    `json:"img_quantity"`
+
+	// Mode is one of txt2img, img2img and inpainting, txt2img by default.
+	// Image and Mask are the paths returned by uploading the pictures.
+	Mode  string `json:"mode"`
+	Image string `json:"image"`
+	Mask  string `json:"mask"`
 }
 
 func (req *wukongRequest) toCmd() (cmd app.WuKongCmd, err error) {
@@ -155,6 +165,11 @@ func (req *wukongRequest) toCmd() (cmd app.WuKongCmd, err error) {
 		return
 	}
 
+	cmd.WuKongPictureInput, err = domain.NewWuKongPictureInput(req.Mode, req.Image, req.Mask)
+	if err != nil {
+		return
+	}
+
 	switch req.ImgQuantity {
 	case 4:
 		cmd.EsType = string(domain.BigmodelWuKong4Img)
@@ -205,6 +220,10 @@ func (req *wukongApiRequest) toCmd() (cmd app.WuKongApiCmd, err error) {
 	return
 }
 
+type wukongUploadPictureResp struct {
+	Path string `json:"path"`
+}
+
 type wukongPicturesGenerateResp struct {
 	Pictures map[string]string `json:"pictures"`
 }
@@ -394,6 +413,7 @@ type isApplyResp struct {
 // baichuan
 type baichuanReq struct {
 	Text              string  `json:"text"`
+	SessionId         string  `json:"session_id"`
 	Sampling          bool    `json:"sampling"`
 	TopK              int     `json:"top_k"`
 	TopP              float64 `json:"top_p"`
@@ -426,6 +446,7 @@ func (req *baichuanReq) toCmd(user types.Account) (cmd app.BaiChuanCmd, err erro
 		cmd.SetDefault()
 	}
 
+	cmd.Session = req.SessionId
 	cmd.User = user
 	cmd.Sampling = req.Sampling
 
@@ -435,6 +456,7 @@ func (req *baichuanReq) toCmd(user types.Account) (cmd app.BaiChuanCmd, err erro
 // glm2
 type glm2Request struct {
 	Text              string      `json:"text"`
+	SessionId         string      `json:"session_id"`
 	History           [][2]string `json:"history"`
 	Sampling          bool        `json:"sampling"`
 	TopK              int         `json:"top_k"`
@@ -477,6 +499,7 @@ func (req *glm2Request) toCmd(ch chan string, user types.Account) (cmd app.GLM2C
 
 	cmd.CH = ch
 	cmd.Sampling = req.Sampling
+	cmd.Session = req.SessionId
 	cmd.User = user
 
 	return
@@ -485,6 +508,7 @@ func (req *glm2Request) toCmd(ch chan string, user types.Account) (cmd app.GLM2C
 // llama2
 type llama2Request struct {
 	Text              string      `json:"text"`
+	SessionId         string      `json:"session_id"`
 	History           [][2]string `json:"history"`
 	Sampling          bool        `json:"sampling"`
 	TopK              int         `json:"top_k"`
@@ -527,7 +551,246 @@ func (req *llama2Request) toCmd(ch chan string, user types.Account) (cmd app.LLA
 
 	cmd.CH = ch
 	cmd.Sampling = req.Sampling
+	cmd.Session = req.SessionId
+	cmd.User = user
+
+	return
+}
+
+// async task
+type asyncTextRequest struct {
+	Text string `json:"text"`
+	Lang string `json:"lang"`
+}
+
+func (req *asyncTextRequest) toCmd(user types.Account, model string) (cmd app.AsyncTextCmd, err error) {
+	if cmd.TaskType, err = asyncdomain.NewTaskType(model); err != nil {
+		return
+	}
+
+	cmd.User = user
+	cmd.Text = req.Text
+	cmd.Lang = req.Lang
+
+	err = cmd.Validate()
+
+	return
+}
+
+type apiKeyCreateRequest struct {
+	Name   string `json:"name"`
+	Expiry int    `json:"expiry"` // days, 0 means the key never expires
+}
+
+func (req *apiKeyCreateRequest) toCmd(user types.Account, model domain.ModelName) (
+	cmd app.ApiKeyCreateCmd, err error,
+) {
+	if req.Expiry < 0 || req.Expiry > 365 {
+		err = errors.New("expiry should be between 0 and 365 days")
+
+		return
+	}
+
+	if cmd.Name, err = domain.NewApiKeyName(req.Name); err != nil {
+		return
+	}
+
+	cmd.User = user
+	cmd.Model = model
+	cmd.Expiry = req.Expiry
+
+	return
+}
+
+type chatSessionCreateRequest struct {
+	Model string `json:"model"`
+	Title string `json:"title"`
+}
+
+func (req *chatSessionCreateRequest) toCmd(user types.Account) (
+	cmd app.ChatSessionCreateCmd, err error,
+) {
+	if cmd.Model, err = domain.NewModelName(req.Model); err != nil {
+		return
+	}
+
+	if req.Title == "" {
+		req.Title = defaultChatSessionTitle
+	}
+
+	if cmd.Title, err = domain.NewChatSessionTitle(req.Title); err != nil {
+		return
+	}
+
+	cmd.User = user
+
+	return
+}
+
+type chatSessionRenameRequest struct {
+	Title string `json:"title"`
+}
+
+type promptCreateRequest struct {
+	Model   string `json:"model"`
+	Title   string `json:"title"`
+	Desc    string `json:"desc"`
+	Content string `json:"content"`
+	Public  bool   `json:"public"`
+}
+
+func (req *promptCreateRequest) toCmd(user types.Account) (cmd app.PromptCreateCmd, err error) {
+	if cmd.Model, err = domain.NewPromptModel(req.Model); err != nil {
+		return
+	}
+
+	if cmd.Title, err = domain.NewPromptTitle(req.Title); err != nil {
+		return
+	}
+
+	if cmd.Desc, err = domain.NewPromptDesc(req.Desc); err != nil {
+		return
+	}
+
+	if cmd.Content, err = domain.NewPromptContent(req.Content); err != nil {
+		return
+	}
+
 	cmd.User = user
+	cmd.Public = req.Public
 
 	return
 }
+
+type promptUpdateRequest struct {
+	Title   *string `json:"title"`
+	Desc    *string `json:"desc"`
+	Content *string `json:"content"`
+	Public  *bool   `json:"public"`
+}
+
+func (req *promptUpdateRequest) toCmd(user types.Account, id string) (
+	cmd app.PromptUpdateCmd, err error,
+) {
+	if req.Title != nil {
+		if cmd.Title, err = domain.NewPromptTitle(*req.Title); err != nil {
+			return
+		}
+	}
+
+	if req.Desc != nil {
+		if cmd.Desc, err = domain.NewPromptDesc(*req.Desc); err != nil {
+			return
+		}
+	}
+
+	if req.Content != nil {
+		if cmd.Content, err = domain.NewPromptContent(*req.Content); err != nil {
+			return
+		}
+	}
+
+	cmd.User = user
+	cmd.Id = id
+	cmd.Public = req.Public
+
+	return
+}
+
+type promptRunRequest struct {
+	Variables map[string]string `json:"variables"`
+	Lang      string            `json:"lang"`
+}
+
+func (req *promptRunRequest) toCmd(user types.Account, id string) app.PromptRunCmd {
+	return app.PromptRunCmd{
+		User:      user,
+		Id:        id,
+		Variables: req.Variables,
+		Lang:      req.Lang,
+	}
+}
+
+type promptLikeResp struct {
+	LikeCount int `json:"like_count"`
+}
+
+type arenaBattleRequest struct {
+	Prompt string   `json:"prompt"`
+	Models []string `json:"models"`
+}
+
+func (req *arenaBattleRequest) toCmd(user types.Account) (cmd app.ArenaBattleCmd, err error) {
+	cmd.Models = make([]domain.ModelName, len(req.Models))
+
+	for i := range req.Models {
+		if cmd.Models[i], err = domain.NewModelName(req.Models[i]); err != nil {
+			return
+		}
+	}
+
+	cmd.User = user
+	cmd.Prompt = req.Prompt
+
+	err = cmd.Validate()
+
+	return
+}
+
+type arenaVoteRequest struct {
+	Winner string `json:"winner"`
+}
+
+type gallerySaveRequest struct {
+	Model  string `json:"model"`
+	Path   string `json:"path"`
+	Desc   string `json:"desc"`
+	Public bool   `json:"public"`
+}
+
+func (req *gallerySaveRequest) toCmd(user types.Account) (cmd app.GallerySaveCmd, err error) {
+	if cmd.Model, err = domain.NewGalleryModel(req.Model); err != nil {
+		return
+	}
+
+	if cmd.Desc, err = domain.NewGalleryDesc(req.Desc); err != nil {
+		return
+	}
+
+	if req.Path == "" {
+		err = errors.New("missing path")
+
+		return
+	}
+
+	cmd.User = user
+	cmd.Path = req.Path
+	cmd.Public = req.Public
+
+	return
+}
+
+type galleryPublicRequest struct {
+	Public bool `json:"public"`
+}
+
+type galleryReportRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (req *galleryReportRequest) toCmd(user types.Account, id string) (
+	cmd app.GalleryReportCmd, err error,
+) {
+	if cmd.Reason, err = abusedomain.NewReportReason(req.Reason); err != nil {
+		return
+	}
+
+	cmd.User = user
+	cmd.Id = id
+
+	return
+}
+
+type galleryDiggResp struct {
+	DiggCount int `json:"digg_count"`
+}
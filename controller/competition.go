@@ -480,8 +480,8 @@ func (ctl *CompetitionController) ChangeName(ctx *gin.Context) {
 		return
 	}
 
-	if err := ctl.s.ChangeTeamName(ctx.Param("id"), &cmd); err != nil {
-		ctl.sendCodeMessage(ctx, "", err)
+	if code, err := ctl.s.ChangeTeamName(ctx.Param("id"), &cmd); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
 	} else {
 		ctl.sendRespOfPut(ctx, "success")
 	}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/opensourceways/xihe-server/app"
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
 	"github.com/opensourceways/xihe-server/domain/platform"
@@ -24,6 +25,7 @@ func AddRouterForDatasetController(
 	tags repository.Tags,
 	like repository.Like,
 	sender message.ResourceProducer,
+	moderation moderation.Moderation,
 	newPlatformRepository func(token, namespace string) platform.Repository,
 ) {
 	ctl := DatasetController{
@@ -31,7 +33,7 @@ func AddRouterForDatasetController(
 		repo: repo,
 		tags: tags,
 		like: like,
-		s:    app.NewDatasetService(user, repo, proj, model, activity, nil, sender),
+		s:    app.NewDatasetService(user, repo, proj, model, activity, nil, sender, moderation),
 
 		newPlatformRepository: newPlatformRepository,
 	}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/opensourceways/xihe-server/app"
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
 	"github.com/opensourceways/xihe-server/domain/platform"
@@ -24,6 +25,7 @@ func AddRouterForModelController(
 	tags repository.Tags,
 	like repository.Like,
 	sender message.ResourceProducer,
+	moderation moderation.Moderation,
 	newPlatformRepository func(token, namespace string) platform.Repository,
 ) {
 	ctl := ModelController{
@@ -32,7 +34,7 @@ func AddRouterForModelController(
 		dataset: dataset,
 		tags:    tags,
 		like:    like,
-		s:       app.NewModelService(user, repo, proj, dataset, activity, nil, sender),
+		s:       app.NewModelService(user, repo, proj, dataset, activity, nil, sender, moderation),
 
 		newPlatformRepository: newPlatformRepository,
 	}
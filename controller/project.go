@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/opensourceways/xihe-server/app"
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
 	"github.com/opensourceways/xihe-server/domain/platform"
@@ -25,6 +26,7 @@ func AddRouterForProjectController(
 	tags repository.Tags,
 	like repository.Like,
 	sender message.ResourceProducer,
+	moderation moderation.Moderation,
 	newPlatformRepository func(token, namespace string) platform.Repository,
 ) {
 	ctl := ProjectController{
@@ -35,7 +37,7 @@ func AddRouterForProjectController(
 		tags:    tags,
 		like:    like,
 		s: app.NewProjectService(
-			user, repo, model, dataset, activity, nil, sender,
+			user, repo, model, dataset, activity, nil, sender, moderation,
 		),
 
 		newPlatformRepository: newPlatformRepository,
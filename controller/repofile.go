@@ -22,10 +22,11 @@ func AddRouterForRepoFileController(
 	project repository.Project,
 	dataset repository.Dataset,
 	sender message.RepoMessageProducer,
+	producer message.RepoFileProducer,
 	us uapp.UserService,
 ) {
 	ctl := RepoFileController{
-		s:       app.NewRepoFileService(p, sender),
+		s:       app.NewRepoFileService(p, sender, producer),
 		us:      us,
 		model:   model,
 		project: project,
@@ -79,7 +80,7 @@ func (ctl *RepoFileController) Create(ctx *gin.Context) {
 		return
 	}
 
-	info, err := ctl.getRepoFileInfo(ctx, pl.DomainAccount())
+	info, obj, err := ctl.getRepoFileInfoAndResource(ctx, pl.DomainAccount())
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, newResponseCodeError(
 			errorBadRequestParam, err,
@@ -91,6 +92,7 @@ func (ctl *RepoFileController) Create(ctx *gin.Context) {
 	cmd := app.RepoFileCreateCmd{
 		RepoFileInfo:    info,
 		RepoFileContent: req.toContent(),
+		Resource:        obj,
 	}
 	if err = cmd.Validate(); err != nil {
 		ctx.JSON(http.StatusBadRequest, newResponseCodeError(
@@ -136,7 +138,7 @@ func (ctl *RepoFileController) Update(ctx *gin.Context) {
 		return
 	}
 
-	info, err := ctl.getRepoFileInfo(ctx, pl.DomainAccount())
+	info, obj, err := ctl.getRepoFileInfoAndResource(ctx, pl.DomainAccount())
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, newResponseCodeError(
 			errorBadRequestParam, err,
@@ -148,6 +150,7 @@ func (ctl *RepoFileController) Update(ctx *gin.Context) {
 	cmd := app.RepoFileUpdateCmd{
 		RepoFileInfo:    info,
 		RepoFileContent: req.toContent(),
+		Resource:        obj,
 	}
 	if err = cmd.Validate(); err != nil {
 		ctx.JSON(http.StatusBadRequest, newResponseCodeError(
@@ -578,6 +581,14 @@ func (ctl *RepoFileController) getRepoDirInfo(ctx *gin.Context, user domain.Acco
 
 func (ctl *RepoFileController) getRepoFileInfo(ctx *gin.Context, user domain.Account) (
 	info app.RepoFileInfo, err error,
+) {
+	info, _, err = ctl.getRepoFileInfoAndResource(ctx, user)
+
+	return
+}
+
+func (ctl *RepoFileController) getRepoFileInfoAndResource(ctx *gin.Context, user domain.Account) (
+	info app.RepoFileInfo, obj domain.ResourceObject, err error,
 ) {
 	v, err := ctl.getRepoInfo(ctx, user)
 	if err != nil {
@@ -586,7 +597,12 @@ func (ctl *RepoFileController) getRepoFileInfo(ctx *gin.Context, user domain.Acc
 
 	info.RepoId = v.RepoId
 
-	info.Path, err = domain.NewFilePath(ctx.Param("path"))
+	if info.Path, err = domain.NewFilePath(ctx.Param("path")); err != nil {
+		return
+	}
+
+	obj.Type = v.rt
+	obj.ResourceIndex = v.ResourceIndex()
 
 	return
 }
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/opensourceways/xihe-server/app"
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/domain/repository"
 )
 
@@ -19,6 +20,7 @@ const (
 	errorUpdateLFSFile       = "update_lfs_file"
 	errorPreviewLFSFile      = "preview_lfs_file"
 	errorUnavailableRepoFile = "unavailable_repo_file"
+	errorSensitiveContent    = "sensitive_content"
 )
 
 var (
@@ -56,6 +58,9 @@ func newResponseError(err error) responseData {
 	case app.ErrorExceedMaxRelatedResourceNum:
 		code = errorExccedMaxNum
 
+	case app.ErrorTrainingQueueFull:
+		code = errorExccedMaxNum
+
 	case app.ErrorUpdateLFSFile:
 		code = errorUpdateLFSFile
 
@@ -64,6 +69,9 @@ func newResponseError(err error) responseData {
 
 	case app.ErrorPreviewLFSFile:
 		code = errorPreviewLFSFile
+
+	case moderation.ErrorSensitiveContent:
+		code = errorSensitiveContent
 	
 	default:
 		
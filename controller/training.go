@@ -11,6 +11,7 @@ import (
 	"github.com/opensourceways/xihe-server/app"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/domain/message"
+	"github.com/opensourceways/xihe-server/domain/platform"
 	"github.com/opensourceways/xihe-server/domain/repository"
 	"github.com/opensourceways/xihe-server/domain/training"
 	"github.com/opensourceways/xihe-server/utils"
@@ -20,18 +21,34 @@ func AddRouterForTrainingController(
 	rg *gin.RouterGroup,
 	ts training.Training,
 	repo repository.Training,
+	queue repository.TrainingQueue,
+	sweep repository.TrainingSweep,
+	metric repository.TrainingMetric,
+	schedule repository.TrainingSchedule,
+	notice repository.TrainingScheduleNotice,
 	model repository.Model,
 	project repository.Project,
 	dataset repository.Dataset,
 	sender message.MessageProducer,
+	ms app.ModelService,
+	rf platform.RepoFile,
+	newPlatformRepository func(token, namespace string) platform.Repository,
 ) {
+	service := app.NewTrainingService(
+		ts, repo, queue, sender, ms, rf, schedule, apiConfig.MaxTrainingRecordNum,
+	)
+
 	ctl := TrainingController{
-		ts: app.NewTrainingService(
-			ts, repo, sender, apiConfig.MaxTrainingRecordNum,
-		),
-		model:   model,
-		project: project,
-		dataset: dataset,
+		ts:       service,
+		sweep:    app.NewTrainingSweepService(service, sweep, metric),
+		metric:   app.NewTrainingMetricService(ts, repo, metric),
+		schedule: app.NewTrainingScheduleService(service, repo, schedule),
+		notice:   app.NewTrainingNoticeService(notice),
+		model:    model,
+		project:  project,
+		dataset:  dataset,
+
+		newPlatformRepository: newPlatformRepository,
 	}
 
 	rg.POST("/v1/train/project/:pid/training", checkUserEmailMiddleware(&ctl.baseController), ctl.Create)
@@ -45,16 +62,38 @@ func AddRouterForTrainingController(
 	)
 	rg.GET("/v1/train/project/:pid/training/:id", ctl.Get)
 	rg.DELETE("v1/train/project/:pid/training/:id", ctl.Delete)
+	rg.GET("/v1/train/queue", ctl.ListQueue)
+	rg.PUT("/v1/train/project/:pid/training/:id/queue", ctl.MoveQueued)
+	rg.DELETE("/v1/train/project/:pid/training/:id/queue", ctl.CancelQueued)
+	rg.POST("/v1/train/project/:pid/sweep", checkUserEmailMiddleware(&ctl.baseController), ctl.CreateSweep)
+	rg.GET("/v1/train/project/:pid/sweep", ctl.ListSweeps)
+	rg.GET("/v1/train/project/:pid/sweep/:id", ctl.GetSweep)
+	rg.GET("/v1/train/project/:pid/training/:id/metric", ctl.GetMetrics)
+	rg.GET("/v1/train/project/:pid/metric", ctl.CompareMetrics)
+	rg.POST("/v1/train/project/:pid/training/:id/model", checkUserEmailMiddleware(&ctl.baseController), ctl.PublishOutput)
+	rg.POST("/v1/train/project/:pid/training/:id/schedule", checkUserEmailMiddleware(&ctl.baseController), ctl.CreateSchedule)
+	rg.GET("/v1/train/project/:pid/schedule", ctl.ListSchedules)
+	rg.GET("/v1/train/project/:pid/schedule/:id", ctl.GetSchedule)
+	rg.PUT("/v1/train/project/:pid/schedule/:id", ctl.SetSchedulePaused)
+	rg.DELETE("/v1/train/project/:pid/schedule/:id", ctl.DeleteSchedule)
+	rg.GET("/v1/train/schedule/notice", ctl.ListScheduleNotices)
 }
 
 type TrainingController struct {
 	baseController
 
-	ts app.TrainingService
+	ts     app.TrainingService
+	sweep  app.TrainingSweepService
+	metric app.TrainingMetricService
+
+	schedule app.TrainingScheduleService
+	notice   app.TrainingNoticeService
 
 	model   repository.Model
 	project repository.Project
 	dataset repository.Dataset
+
+	newPlatformRepository func(string, string) platform.Repository
 }
 
 //	@Summary		Create
@@ -95,6 +134,8 @@ func (ctl *TrainingController) Create(ctx *gin.Context) {
 		return
 	}
 
+	cmd.Token = pl.PlatformToken
+
 	if !ctl.setModelsInput(ctx, cmd, pl.DomainAccount(), req.Models) {
 		return
 	}
@@ -136,12 +177,20 @@ func (ctl *TrainingController) Create(ctx *gin.Context) {
 //	@Failure		500	system_error		system	error
 //	@Router			/v1/train/project/{pid}/training/{id} [post]
 func (ctl *TrainingController) Recreate(ctx *gin.Context) {
-	info, ok := ctl.getTrainingInfo(ctx)
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
 	if !ok {
 		return
 	}
 
-	v, err := ctl.ts.Recreate(&info)
+	info := domain.TrainingIndex{
+		Project: domain.ResourceIndex{
+			Owner: pl.DomainAccount(),
+			Id:    ctx.Param("pid"),
+		},
+		TrainingId: ctx.Param("id"),
+	}
+
+	v, err := ctl.ts.Recreate(&info, pl.PlatformToken)
 	if err != nil {
 		ctl.sendRespWithInternalError(ctx, newResponseError(err))
 
@@ -501,6 +550,144 @@ func (ctl *TrainingController) GetResultDownloadURL(ctx *gin.Context) {
 	}
 }
 
+//	@Summary		ListQueue
+//	@Description	list the running and queued trainings of user
+//	@Tags			Training
+//	@Accept			json
+//	@Success		200	{object}		app.TrainingQueueDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/train/queue [get]
+func (ctl *TrainingController) ListQueue(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, err := ctl.ts.ListQueue(pl.DomainAccount()); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Summary		MoveQueued
+//	@Description	move the queued training to the position of queue
+//	@Tags			Training
+//	@Param			pid		path	string						true	"project id"
+//	@Param			id		path	string						true	"training id"
+//	@Param			body	body	trainingQueueMoveRequest	true	"body of moving training"
+//	@Accept			json
+//	@Success		202
+//	@Failure		400	bad_request_body				can't	parse	request	body
+//	@Failure		400	train_not_queued				the		training	is	not	queued
+//	@Failure		400	train_invalid_queue_position	invalid	position
+//	@Failure		500	system_error					system	error
+//	@Router			/v1/train/project/{pid}/training/{id}/queue [put]
+func (ctl *TrainingController) MoveQueued(ctx *gin.Context) {
+	req := trainingQueueMoveRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	info, ok := ctl.getTrainingInfo(ctx)
+	if !ok {
+		return
+	}
+
+	if code, err := ctl.ts.MoveQueued(&info, req.Position); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfPut(ctx, "success")
+	}
+}
+
+//	@Summary		CancelQueued
+//	@Description	cancel the queued training
+//	@Tags			Training
+//	@Param			pid	path	string	true	"project id"
+//	@Param			id	path	string	true	"training id"
+//	@Accept			json
+//	@Success		204
+//	@Failure		400	train_not_queued	the		training	is	not	queued
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/train/project/{pid}/training/{id}/queue [delete]
+func (ctl *TrainingController) CancelQueued(ctx *gin.Context) {
+	info, ok := ctl.getTrainingInfo(ctx)
+	if !ok {
+		return
+	}
+
+	if code, err := ctl.ts.CancelQueued(&info); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		utils.DoLog("", info.Project.Owner.Account(), "cancel queued training",
+			fmt.Sprintf("projectid: %s, trainingid: %s", info.Project.Id, info.TrainingId), "success")
+
+		ctl.sendRespOfDelete(ctx)
+	}
+}
+
+//	@Summary		PublishOutput
+//	@Description	publish the output and lineage of training as a new version of model
+//	@Tags			Training
+//	@Param			pid		path	string					true	"project id"
+//	@Param			id		path	string					true	"training id"
+//	@Param			body	body	trainingPublishRequest	true	"body of publishing training"
+//	@Accept			json
+//	@Success		201	{object}				app.TrainingPublishDTO
+//	@Failure		400	bad_request_body		can't	parse		request	body
+//	@Failure		400	bad_request_param		some	parameter	of		body	is	invalid
+//	@Failure		400	train_no_output			the		training	has		no		output
+//	@Failure		400	train_output_too_large	the		output		is		too		large
+//	@Failure		500	system_error			system	error
+//	@Router			/v1/train/project/{pid}/training/{id}/model [post]
+func (ctl *TrainingController) PublishOutput(ctx *gin.Context) {
+	req := trainingPublishRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	info := domain.TrainingIndex{
+		Project: domain.ResourceIndex{
+			Owner: pl.DomainAccount(),
+			Id:    ctx.Param("pid"),
+		},
+		TrainingId: ctx.Param("id"),
+	}
+
+	cmd, err := req.toCmd(&info, pl.PlatformUserInfo())
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	pr := ctl.newPlatformRepository(
+		pl.PlatformToken, pl.PlatformUserNamespaceId,
+	)
+
+	v, code, err := ctl.ts.PublishOutput(&cmd, pr)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return
+	}
+
+	utils.DoLog("", pl.Account, "publish training output",
+		fmt.Sprintf("projectid: %s, trainingid: %s, model: %s", info.Project.Id, info.TrainingId, v.ModelName), "success")
+
+	ctl.sendRespOfPost(ctx, v)
+}
+
 func (ctl *TrainingController) getTrainingInfo(ctx *gin.Context) (domain.TrainingIndex, bool) {
 	pl, _, ok := ctl.checkUserApiToken(ctx, false)
 	if !ok {
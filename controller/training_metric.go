@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/app"
+	"github.com/opensourceways/xihe-server/domain"
+)
+
+//	@Summary		GetMetrics
+//	@Description	get the time series of metrics reported by the training
+//	@Tags			Training
+//	@Param			pid	path	string	true	"project id"
+//	@Param			id	path	string	true	"training id"
+//	@Accept			json
+//	@Success		200	{object}		app.TrainingMetricDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/train/project/{pid}/training/{id}/metric [get]
+func (ctl *TrainingController) GetMetrics(ctx *gin.Context) {
+	info, ok := ctl.getTrainingInfo(ctx)
+	if !ok {
+		return
+	}
+
+	if v, err := ctl.metric.Get(&info); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Summary		CompareMetrics
+//	@Description	overlay the metrics of several trainings of project
+//	@Tags			Training
+//	@Param			pid			path	string	true	"project id"
+//	@Param			training	query	string	true	"training ids separated by comma"
+//	@Param			name		query	string	false	"metric names separated by comma, all metrics if empty"
+//	@Accept			json
+//	@Success		200	{object}			app.TrainingMetricDTO
+//	@Failure		400	bad_request_param	some	parameter	of	query	is	invalid
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/train/project/{pid}/metric [get]
+func (ctl *TrainingController) CompareMetrics(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	cmd := app.TrainingMetricCompareCmd{
+		Project: domain.ResourceIndex{
+			Owner: pl.DomainAccount(),
+			Id:    ctx.Param("pid"),
+		},
+		TrainingIds: splitQueryList(ctl.getQueryParameter(ctx, "training")),
+		Names:       splitQueryList(ctl.getQueryParameter(ctx, "name")),
+	}
+
+	if err := cmd.Validate(); err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.metric.Compare(&cmd); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+func splitQueryList(v string) []string {
+	if v == "" {
+		return nil
+	}
+
+	items := strings.Split(v, ",")
+
+	r := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			r = append(r, item)
+		}
+	}
+
+	return r
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/opensourceways/xihe-server/app"
 	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/platform"
 	"github.com/opensourceways/xihe-server/domain/repository"
 )
 
@@ -16,6 +17,94 @@ type trainingCreateResp struct {
 	Id string `json:"id"`
 }
 
+type trainingQueueMoveRequest struct {
+	// Position starts from 1 which is the head of queue
+	Position int `json:"position"`
+}
+
+// trainingPublishRequest publishes the output of training to the model
+// of user. The Protocol and RepoType are required only if the model
+// doesn't exist and will be created.
+type trainingPublishRequest struct {
+	Name     string `json:"name" required:"true"`
+	Desc     string `json:"desc"`
+	Title    string `json:"title"`
+	Protocol string `json:"protocol"`
+	RepoType string `json:"repo_type"`
+}
+
+func (req *trainingPublishRequest) toCmd(
+	info *domain.TrainingIndex, user platform.UserInfo,
+) (cmd app.TrainingPublishCmd, err error) {
+	cmd.TrainingIndex = *info
+	cmd.User = user
+	cmd.Model.Owner = user.User
+
+	m := &cmd.Model
+
+	if m.Name, err = domain.NewResourceName(req.Name); err != nil {
+		return
+	}
+
+	if m.Desc, err = domain.NewResourceDesc(req.Desc); err != nil {
+		return
+	}
+
+	if req.Title == "" {
+		req.Title = req.Name
+	}
+
+	if m.Title, err = domain.NewResourceTitle(req.Title); err != nil {
+		return
+	}
+
+	if req.Protocol != "" {
+		if m.Protocol, err = domain.NewProtocolName(req.Protocol); err != nil {
+			return
+		}
+	}
+
+	if req.RepoType != "" {
+		if m.RepoType, err = domain.NewRepoType(req.RepoType); err != nil {
+			return
+		}
+	}
+
+	err = cmd.Validate()
+
+	return
+}
+
+type trainingScheduleCreateRequest struct {
+	// Spec is the cron expression of 5 fields, such as "0 2 * * *"
+	Spec string `json:"spec" required:"true"`
+
+	// Timezone is the IANA name, such as "Asia/Shanghai"
+	Timezone string `json:"timezone" required:"true"`
+}
+
+func (req *trainingScheduleCreateRequest) toCmd(
+	info *domain.TrainingIndex,
+) (cmd app.TrainingScheduleCreateCmd, err error) {
+	cmd.TrainingIndex = *info
+
+	if cmd.Spec, err = domain.NewCronSpec(req.Spec); err != nil {
+		return
+	}
+
+	if cmd.Timezone, err = domain.NewTimezone(req.Timezone); err != nil {
+		return
+	}
+
+	err = cmd.Validate()
+
+	return
+}
+
+type trainingScheduleUpdateRequest struct {
+	Paused bool `json:"paused"`
+}
+
 type trainingLogResp struct {
 	LogURL string `json:"log_url"`
 }
@@ -93,6 +182,82 @@ func (req *TrainingCreateRequest) toKeyValue(kv []KeyValue) (r []domain.KeyValue
 	return
 }
 
+type TrainingSweepCreateRequest struct {
+	TrainingCreateRequest
+
+	// Strategy is grid or random
+	Strategy string `json:"strategy"`
+	// Metric is the name of metric reported by the trials
+	Metric string `json:"metric"`
+	// Goal is maximize or minimize
+	Goal       string                   `json:"goal"`
+	MaxTrials  int                      `json:"max_trials"`
+	Parameters []trainingSweepParameter `json:"parameters"`
+}
+
+func (req *TrainingSweepCreateRequest) toCmd(cmd *app.TrainingSweepCreateCmd) (err error) {
+	if err = req.TrainingCreateRequest.toCmd(&cmd.TrainingCreateCmd); err != nil {
+		return
+	}
+
+	if cmd.Strategy, err = domain.NewSweepStrategy(req.Strategy); err != nil {
+		return
+	}
+
+	if cmd.Metric.Name, err = domain.NewCustomizedKey(req.Metric); err != nil {
+		return
+	}
+
+	if cmd.Metric.Goal, err = domain.NewSweepGoal(req.Goal); err != nil {
+		return
+	}
+
+	cmd.MaxTrials = req.MaxTrials
+
+	cmd.Parameters = make([]domain.SweepParameter, len(req.Parameters))
+	for i := range req.Parameters {
+		if cmd.Parameters[i], err = req.Parameters[i].toSweepParameter(); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// trainingSweepParameter takes the values if they are set, otherwise
+// the range of [min, max]. Step 0 means the range is continuous.
+type trainingSweepParameter struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+	Min    float64  `json:"min"`
+	Max    float64  `json:"max"`
+	Step   float64  `json:"step"`
+}
+
+func (p *trainingSweepParameter) toSweepParameter() (r domain.SweepParameter, err error) {
+	if r.Key, err = domain.NewCustomizedKey(p.Key); err != nil {
+		return
+	}
+
+	if n := len(p.Values); n > 0 {
+		r.Values = make([]domain.CustomizedValue, n)
+
+		for i := range p.Values {
+			if r.Values[i], err = domain.NewCustomizedValue(p.Values[i]); err != nil {
+				return
+			}
+		}
+	}
+
+	r.Range = domain.SweepRange{
+		Min:  p.Min,
+		Max:  p.Max,
+		Step: p.Step,
+	}
+
+	return
+}
+
 type Compute struct {
 	Type    string `json:"type"`
 	Flavor  string `json:"flavor"`
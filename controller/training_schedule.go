@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+//	@Summary		CreateSchedule
+//	@Description	create the schedule which recreates the training periodically
+//	@Tags			Training
+//	@Param			pid		path	string							true	"project id"
+//	@Param			id		path	string							true	"training id"
+//	@Param			body	body	trainingScheduleCreateRequest	true	"body of creating schedule"
+//	@Accept			json
+//	@Success		201	{object}				trainingCreateResp
+//	@Failure		400	bad_request_body		can't	parse		request	body
+//	@Failure		400	bad_request_param		some	parameter	of		body	is	invalid
+//	@Failure		400	train_not_found			the		training	is		not		found
+//	@Failure		400	train_invalid_schedule	the		runs		are		too		frequent
+//	@Failure		500	system_error			system	error
+//	@Router			/v1/train/project/{pid}/training/{id}/schedule [post]
+func (ctl *TrainingController) CreateSchedule(ctx *gin.Context) {
+	req := trainingScheduleCreateRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	info, ok := ctl.getTrainingInfo(ctx)
+	if !ok {
+		return
+	}
+
+	cmd, err := req.toCmd(&info)
+	if err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	v, code, err := ctl.schedule.Create(&cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return
+	}
+
+	utils.DoLog("", info.Project.Owner.Account(), "create training schedule",
+		fmt.Sprintf("projectid: %s, trainingid: %s, scheduleid: %s", info.Project.Id, info.TrainingId, v), "success")
+
+	ctl.sendRespOfPost(ctx, trainingCreateResp{v})
+}
+
+//	@Summary		ListSchedules
+//	@Description	list training schedules of project
+//	@Tags			Training
+//	@Param			pid	path	string	true	"project id"
+//	@Accept			json
+//	@Success		200	{object}		app.TrainingScheduleSummaryDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/train/project/{pid}/schedule [get]
+func (ctl *TrainingController) ListSchedules(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, err := ctl.schedule.List(pl.DomainAccount(), ctx.Param("pid")); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Summary		GetSchedule
+//	@Description	get training schedule with the history of its runs
+//	@Tags			Training
+//	@Param			pid	path	string	true	"project id"
+//	@Param			id	path	string	true	"schedule id"
+//	@Accept			json
+//	@Success		200	{object}					app.TrainingScheduleDTO
+//	@Failure		400	train_schedule_not_found	the		schedule	is	not	found
+//	@Failure		500	system_error				system	error
+//	@Router			/v1/train/project/{pid}/schedule/{id} [get]
+func (ctl *TrainingController) GetSchedule(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, code, err := ctl.schedule.Get(pl.DomainAccount(), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Summary		SetSchedulePaused
+//	@Description	pause or resume the training schedule
+//	@Tags			Training
+//	@Param			pid		path	string							true	"project id"
+//	@Param			id		path	string							true	"schedule id"
+//	@Param			body	body	trainingScheduleUpdateRequest	true	"body of pausing or resuming schedule"
+//	@Accept			json
+//	@Success		202
+//	@Failure		400	bad_request_body			can't	parse		request	body
+//	@Failure		400	train_schedule_not_found	the		schedule	is		not	found
+//	@Failure		500	system_error				system	error
+//	@Router			/v1/train/project/{pid}/schedule/{id} [put]
+func (ctl *TrainingController) SetSchedulePaused(ctx *gin.Context) {
+	req := trainingScheduleUpdateRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctl.sendBadRequestBody(ctx)
+
+		return
+	}
+
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+
+	if code, err := ctl.schedule.SetPaused(pl.DomainAccount(), id, req.Paused); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		utils.DoLog("", pl.Account, "set training schedule paused",
+			fmt.Sprintf("scheduleid: %s, paused: %t", id, req.Paused), "success")
+
+		ctl.sendRespOfPut(ctx, "success")
+	}
+}
+
+//	@Summary		DeleteSchedule
+//	@Description	delete the training schedule, the trainings it spawned are kept
+//	@Tags			Training
+//	@Param			pid	path	string	true	"project id"
+//	@Param			id	path	string	true	"schedule id"
+//	@Accept			json
+//	@Success		204
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/train/project/{pid}/schedule/{id} [delete]
+func (ctl *TrainingController) DeleteSchedule(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+
+	if err := ctl.schedule.Delete(pl.DomainAccount(), id); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	} else {
+		utils.DoLog("", pl.Account, "delete training schedule",
+			fmt.Sprintf("scheduleid: %s", id), "success")
+
+		ctl.sendRespOfDelete(ctx)
+	}
+}
+
+//	@Summary		ListScheduleNotices
+//	@Description	list the notices of the failed runs of training schedules from the latest
+//	@Tags			Training
+//	@Accept			json
+//	@Success		200	{object}		app.TrainingScheduleNoticeDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/train/schedule/notice [get]
+func (ctl *TrainingController) ListScheduleNotices(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, err := ctl.notice.List(pl.DomainAccount()); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opensourceways/xihe-server/app"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+//	@Summary		CreateSweep
+//	@Description	create hyperparameter sweep which runs each trial as a training
+//	@Tags			Training
+//	@Param			pid		path	string						true	"project id"
+//	@Param			body	body	TrainingSweepCreateRequest	true	"body of creating sweep"
+//	@Accept			json
+//	@Success		201	{object}			trainingCreateResp
+//	@Failure		400	bad_request_body	can't	parse		request	body
+//	@Failure		400	bad_request_param	some	parameter	of		body	is	invalid
+//	@Failure		400	train_invalid_sweep	invalid	sweep
+//	@Failure		400	train_excced_max_num	too	many	trainings	of	project
+//	@Failure		400	train_queue_full	the	training	queue	is	full
+//	@Failure		500	system_error		system	error
+//	@Router			/v1/train/project/{pid}/sweep [post]
+func (ctl *TrainingController) CreateSweep(ctx *gin.Context) {
+	req := TrainingSweepCreateRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, respBadRequestBody)
+
+		return
+	}
+
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	cmd := new(app.TrainingSweepCreateCmd)
+
+	if err := req.toCmd(cmd); err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	user := pl.DomainAccount()
+	tc := &cmd.TrainingCreateCmd
+
+	if !ctl.setProjectInfo(ctx, tc, user, ctx.Param("pid")) {
+		return
+	}
+
+	tc.Token = pl.PlatformToken
+
+	if !ctl.setModelsInput(ctx, tc, user, req.Models) {
+		return
+	}
+
+	if !ctl.setDatasetsInput(ctx, tc, user, req.Datasets) {
+		return
+	}
+
+	if err := cmd.Validate(); err != nil {
+		ctl.sendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	v, code, err := ctl.sweep.Create(cmd)
+	if err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+
+		return
+	}
+
+	utils.DoLog("", pl.Account, "create training sweep",
+		fmt.Sprintf("projectid: %s, sweepid: %s", ctx.Param("pid"), v), "success")
+
+	ctl.sendRespOfPost(ctx, trainingCreateResp{v})
+}
+
+//	@Summary		ListSweeps
+//	@Description	list hyperparameter sweeps of project
+//	@Tags			Training
+//	@Param			pid	path	string	true	"project id"
+//	@Accept			json
+//	@Success		200	{object}		app.TrainingSweepSummaryDTO
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/train/project/{pid}/sweep [get]
+func (ctl *TrainingController) ListSweeps(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, err := ctl.sweep.List(pl.DomainAccount(), ctx.Param("pid")); err != nil {
+		ctl.sendRespWithInternalError(ctx, newResponseError(err))
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
+
+//	@Summary		GetSweep
+//	@Description	get hyperparameter sweep with the comparison of its trials
+//	@Tags			Training
+//	@Param			pid	path	string	true	"project id"
+//	@Param			id	path	string	true	"sweep id"
+//	@Accept			json
+//	@Success		200	{object}		app.TrainingSweepDTO
+//	@Failure		400	train_sweep_not_found	no	such	sweep	in	the	project
+//	@Failure		500	system_error	system	error
+//	@Router			/v1/train/project/{pid}/sweep/{id} [get]
+func (ctl *TrainingController) GetSweep(ctx *gin.Context) {
+	pl, _, ok := ctl.checkUserApiToken(ctx, false)
+	if !ok {
+		return
+	}
+
+	if v, code, err := ctl.sweep.Get(pl.DomainAccount(), ctx.Param("pid"), ctx.Param("id")); err != nil {
+		ctl.sendCodeMessage(ctx, code, err)
+	} else {
+		ctl.sendRespOfGet(ctx, v)
+	}
+}
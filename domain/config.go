@@ -38,6 +38,32 @@ type Config struct {
 	MinTrainingNameLength int `json:"min_training_name_length"`
 	MaxTrainingDescLength int `json:"max_training_desc_length"`
 
+	// MaxRunningTrainings is the max number of trainings of a user which can run
+	// at the same time, the others wait in the queue of user.
+	MaxRunningTrainings int `json:"max_running_trainings"`
+	MaxQueuedTrainings  int `json:"max_queued_trainings"`
+
+	// MaxSweepTrials is the max number of trainings a sweep can expand into.
+	MaxSweepTrials int `json:"max_sweep_trials"`
+
+	// MaxTrainingOutputSize is the max bytes of the output which can be published.
+	MaxTrainingOutputSize int64 `json:"max_training_output_size"`
+
+	// MaxTrainingMetricPoints is the max number of points kept in the series
+	// of a training metric, the earliest ones are dropped when it is exceeded.
+	MaxTrainingMetricPoints int `json:"max_training_metric_points"`
+
+	// MaxTrainingScheduleRuns is the max number of runs kept in the history
+	// of a training schedule, the earliest ones are dropped when it is exceeded.
+	MaxTrainingScheduleRuns int `json:"max_training_schedule_runs"`
+
+	// MaxTrainingScheduleNotices is the max number of notices kept for a user,
+	// the earliest ones are dropped when it is exceeded.
+	MaxTrainingScheduleNotices int `json:"max_training_schedule_notices"`
+
+	// MinTrainingScheduleInterval is the min seconds between two runs of a schedule.
+	MinTrainingScheduleInterval int `json:"min_training_schedule_interval"`
+
 	MaxFinetuneNameLength int `json:"max_finetune_name_length"`
 	MinFinetuneNameLength int `json:"min_finetune_name_length"`
 
@@ -96,6 +122,38 @@ func (cfg *Config) SetDefault() {
 		cfg.MaxTrainingDescLength = 100
 	}
 
+	if cfg.MaxRunningTrainings <= 0 {
+		cfg.MaxRunningTrainings = 1
+	}
+
+	if cfg.MaxQueuedTrainings <= 0 {
+		cfg.MaxQueuedTrainings = 10
+	}
+
+	if cfg.MaxSweepTrials <= 0 {
+		cfg.MaxSweepTrials = 10
+	}
+
+	if cfg.MaxTrainingOutputSize <= 0 {
+		cfg.MaxTrainingOutputSize = 1 << 30
+	}
+
+	if cfg.MaxTrainingMetricPoints <= 0 {
+		cfg.MaxTrainingMetricPoints = 10000
+	}
+
+	if cfg.MaxTrainingScheduleRuns <= 0 {
+		cfg.MaxTrainingScheduleRuns = 30
+	}
+
+	if cfg.MaxTrainingScheduleNotices <= 0 {
+		cfg.MaxTrainingScheduleNotices = 50
+	}
+
+	if cfg.MinTrainingScheduleInterval <= 0 {
+		cfg.MinTrainingScheduleInterval = 3600
+	}
+
 	if cfg.WuKongPictureMaxDescLength <= 0 {
 		cfg.WuKongPictureMaxDescLength = 75
 	}
@@ -110,6 +168,11 @@ func (r *Config) Validate() error {
 		return errors.New("invalid name length")
 	}
 
+	// all the trials of a sweep wait in the training queue at first
+	if r.MaxSweepTrials > r.MaxQueuedTrainings {
+		return errors.New("max_sweep_trials should not be greater than max_queued_trainings")
+	}
+
 	r.covers = sets.NewString(r.Covers...)
 	r.protocols = sets.NewString(r.Protocols...)
 	r.projectType = sets.NewString(r.ProjectType...)
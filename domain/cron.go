@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxCronSearchYears = 5
+
+// the fields of cron spec: minute, hour, day of month, month, day of week
+var cronFields = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// cronSpec is the standard cron expression of 5 fields. Each field supports
+// '*', a value, a range 'a-b', a step '*/n' or 'a-b/n' and a list of them.
+type cronSpec struct {
+	expr string
+
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+func parseCronSpec(expr string) (cronSpec, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return cronSpec{}, errors.New("the cron spec should have 5 fields")
+	}
+
+	var bits [len(cronFields)]uint64
+	for i := range fields {
+		v, err := parseCronField(fields[i], cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return cronSpec{}, fmt.Errorf(
+				"invalid %s of cron spec, %s", cronFields[i].name, err.Error(),
+			)
+		}
+
+		bits[i] = v
+	}
+
+	// both 0 and 7 are Sunday
+	if dow := &bits[4]; *dow&(1<<7) != 0 {
+		*dow = (*dow | 1) &^ (1 << 7)
+	}
+
+	return cronSpec{
+		expr:    strings.Join(fields, " "),
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var r uint64
+
+	for _, item := range strings.Split(field, ",") {
+		rg, step := item, 1
+		hasStep := false
+
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, errors.New("invalid step")
+			}
+
+			rg, step, hasStep = item[:i], n, true
+		}
+
+		lo, hi := min, max
+
+		if rg != "*" {
+			var err error
+
+			if i := strings.Index(rg, "-"); i >= 0 {
+				if lo, err = strconv.Atoi(rg[:i]); err != nil {
+					return 0, errors.New("invalid range")
+				}
+
+				if hi, err = strconv.Atoi(rg[i+1:]); err != nil {
+					return 0, errors.New("invalid range")
+				}
+			} else {
+				if lo, err = strconv.Atoi(rg); err != nil {
+					return 0, errors.New("invalid value")
+				}
+
+				if !hasStep {
+					hi = lo
+				}
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("the value should be between %d to %d", min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			r |= 1 << uint(v)
+		}
+	}
+
+	return r, nil
+}
+
+func (c cronSpec) CronSpec() string {
+	return c.expr
+}
+
+// Next returns the first time after t which matches the spec in the location
+// of t, and the zero time if there is none in the next years.
+func (c cronSpec) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxCronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !hasCronBit(c.month, int(t.Month())) {
+			t = cronForward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+
+			continue
+		}
+
+		if !c.matchDay(t) {
+			t = cronForward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+
+			continue
+		}
+
+		if !hasCronBit(c.hour, t.Hour()) {
+			t = cronForward(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+
+			continue
+		}
+
+		if !hasCronBit(c.minute, t.Minute()) {
+			next := t.Add(time.Minute)
+
+			// skip the hour repeated when the clock is turned back, so
+			// the spec will not match the same wall clock twice.
+			if next.Day() == t.Day() && cronWallClock(next) < cronWallClock(t) {
+				next = cronForward(t, time.Date(
+					t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc,
+				))
+			}
+
+			t = next
+
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay follows the cron that the day matches either the day of month
+// or the day of week if both of them are restricted.
+func (c cronSpec) matchDay(t time.Time) bool {
+	dom := hasCronBit(c.dom, t.Day())
+	dow := hasCronBit(c.dow, int(t.Weekday()))
+
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+func hasCronBit(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+func cronWallClock(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// cronForward makes sure the search goes forward. The wall clock skipped when
+// the clock is turned forward is normalized to the time before t by time.Date,
+// so move it by the skipped hour.
+func cronForward(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+
+	return next.Add(time.Hour)
+}
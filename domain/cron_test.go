@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronSpec(t *testing.T) {
+	cases := []struct {
+		expr string
+		ok   bool
+	}{
+		{"* * * * *", true},
+		{"0 3 * * 1-5", true},
+		{"*/15 0-6/2 1,15 * 7", true},
+		{"  0   3 * *   *  ", true},
+		{"0 3 * *", false},
+		{"0 3 * * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * * 13 *", false},
+		{"* * * * 8", false},
+		{"*/0 * * * *", false},
+		{"5-1 * * * *", false},
+		{"a * * * *", false},
+		{"1- * * * *", false},
+		{"1,,2 * * * *", false},
+	}
+
+	for _, c := range cases {
+		_, err := parseCronSpec(c.expr)
+		if ok := err == nil; ok != c.ok {
+			t.Errorf("parse %q, expect ok=%v, got err=%v", c.expr, c.ok, err)
+		}
+	}
+}
+
+func TestCronSpecNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tz data: %v", err)
+	}
+
+	at := func(loc *time.Location, s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return v
+	}
+
+	cases := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			from: at(time.UTC, "2023-01-01 10:00").Add(30 * time.Second),
+			want: at(time.UTC, "2023-01-01 10:01"),
+		},
+		{
+			name: "strictly after",
+			expr: "0 3 * * *",
+			from: at(time.UTC, "2023-01-01 03:00"),
+			want: at(time.UTC, "2023-01-02 03:00"),
+		},
+		{
+			name: "step and range",
+			expr: "*/20 9-17/4 * * *",
+			from: at(time.UTC, "2023-01-01 13:41"),
+			want: at(time.UTC, "2023-01-01 17:00"),
+		},
+		{
+			name: "list",
+			expr: "5,35 * * * *",
+			from: at(time.UTC, "2023-01-01 10:06"),
+			want: at(time.UTC, "2023-01-01 10:35"),
+		},
+		{
+			name: "month rollover",
+			expr: "0 0 1 * *",
+			from: at(time.UTC, "2023-12-15 00:00"),
+			want: at(time.UTC, "2024-01-01 00:00"),
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			from: at(time.UTC, "2023-03-01 00:00"),
+			want: at(time.UTC, "2024-02-29 00:00"),
+		},
+		{
+			name: "none in the search years",
+			expr: "0 0 31 2 *",
+			from: at(time.UTC, "2023-01-01 00:00"),
+		},
+		{
+			// 2023-01-01 is a Sunday
+			name: "7 is Sunday",
+			expr: "0 0 * * 7",
+			from: at(time.UTC, "2022-12-28 00:00"),
+			want: at(time.UTC, "2023-01-01 00:00"),
+		},
+		{
+			name: "dom only",
+			expr: "0 0 13 * *",
+			from: at(time.UTC, "2023-01-01 00:00"),
+			want: at(time.UTC, "2023-01-13 00:00"),
+		},
+		{
+			// 2023-01-02 is a Monday
+			name: "dow only",
+			expr: "0 0 * * 1",
+			from: at(time.UTC, "2023-01-01 00:00"),
+			want: at(time.UTC, "2023-01-02 00:00"),
+		},
+		{
+			name: "dom or dow, dow first",
+			expr: "0 0 13 * 1",
+			from: at(time.UTC, "2023-01-01 00:00"),
+			want: at(time.UTC, "2023-01-02 00:00"),
+		},
+		{
+			// 2023-01-13 is a Friday, the Mondays around are 9th and 16th
+			name: "dom or dow, dom first",
+			expr: "0 0 13 * 1",
+			from: at(time.UTC, "2023-01-09 00:00"),
+			want: at(time.UTC, "2023-01-13 00:00"),
+		},
+		{
+			// the day of week is restricted by the step of '*', so the day
+			// must match both of them. 2023-08-13 is a Sunday.
+			name: "dom and starred dow",
+			expr: "0 0 13 * */5",
+			from: at(time.UTC, "2023-01-14 00:00"),
+			want: at(time.UTC, "2023-08-13 00:00"),
+		},
+		{
+			name: "in location",
+			expr: "0 9 * * *",
+			from: at(ny, "2023-01-01 10:00"),
+			want: at(ny, "2023-01-02 09:00"),
+		},
+		{
+			// 02:30 does not exist on 2023-03-12 in New York
+			name: "dst skipped hour",
+			expr: "30 2 * * *",
+			from: at(ny, "2023-03-12 00:00"),
+			want: at(ny, "2023-03-13 02:30"),
+		},
+		{
+			name: "dst after skipped hour",
+			expr: "30 3 * * *",
+			from: at(ny, "2023-03-12 00:00"),
+			want: time.Date(2023, 3, 12, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			// 01:30 happens twice on 2023-11-05 in New York
+			name: "dst repeated hour first",
+			expr: "30 1 * * *",
+			from: at(ny, "2023-11-05 00:00"),
+			want: time.Date(2023, 11, 5, 5, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "dst repeated hour once",
+			expr: "30 1 * * *",
+			from: time.Date(2023, 11, 5, 5, 30, 0, 0, time.UTC).In(ny),
+			want: at(ny, "2023-11-06 01:30"),
+		},
+		{
+			name: "dst after repeated hour",
+			expr: "0 2 * * *",
+			from: time.Date(2023, 11, 5, 5, 30, 0, 0, time.UTC).In(ny),
+			want: time.Date(2023, 11, 5, 7, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		spec, err := parseCronSpec(c.expr)
+		if err != nil {
+			t.Fatalf("%s: parse %q failed, %v", c.name, c.expr, err)
+		}
+
+		if got := spec.Next(c.from); !got.Equal(c.want) {
+			t.Errorf("%s: expect %v, got %v", c.name, c.want, got)
+		}
+	}
+}
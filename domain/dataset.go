@@ -18,6 +18,7 @@ type Dataset struct {
 	// following fileds is not under the controlling of version
 	LikeCount     int
 	DownloadCount int
+	ReviewStatus  string
 
 	RelatedModels   RelatedResources
 	RelatedProjects RelatedResources
@@ -27,6 +28,10 @@ func (d *Dataset) IsPrivate() bool {
 	return d.RepoType.RepoType() == RepoTypePrivate
 }
 
+func (d *Dataset) IsHidden() bool {
+	return d.ReviewStatus == ReviewStatusHidden
+}
+
 func (d *Dataset) ResourceIndex() ResourceIndex {
 	return ResourceIndex{
 		Owner: d.Owner,
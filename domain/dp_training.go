@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"time"
+	// embed the timezone database in case the image doesn't have it
+	_ "time/tzdata"
 
 	"github.com/opensourceways/xihe-server/utils"
 )
@@ -23,6 +26,12 @@ const (
 	computeVersionCannMS19_1 = "mindspore_1.9.0-cann_6.0.RC1-py_3.7-ubuntu_18.04-amd64"
 	computeVersionCannMS19_2 = "mindspore_1.9.0-cann_6.0.RC1-py_3.9-ubuntu_18.04-amd64"
 	computeVersionCannMS20   = "mindspore_2.0.0-cann_6.3.RC1-py_3.9-ubuntu_18.04-amd64"
+
+	sweepStrategyGrid   = "grid"
+	sweepStrategyRandom = "random"
+
+	sweepGoalMaximize = "maximize"
+	sweepGoalMinimize = "minimize"
 )
 
 var (
@@ -312,3 +321,109 @@ type inputeFilePath string
 func (r inputeFilePath) InputeFilePath() string {
 	return string(r)
 }
+
+// SweepStrategy
+type SweepStrategy interface {
+	SweepStrategy() string
+	IsGrid() bool
+}
+
+func NewSweepStrategy(v string) (SweepStrategy, error) {
+	if v != sweepStrategyGrid && v != sweepStrategyRandom {
+		return nil, errors.New("unknown sweep strategy")
+	}
+
+	return sweepStrategy(v), nil
+}
+
+type sweepStrategy string
+
+func (r sweepStrategy) SweepStrategy() string {
+	return string(r)
+}
+
+func (r sweepStrategy) IsGrid() bool {
+	return string(r) == sweepStrategyGrid
+}
+
+// SweepGoal
+type SweepGoal interface {
+	SweepGoal() string
+	IsBetter(a, b float64) bool
+}
+
+func NewSweepGoal(v string) (SweepGoal, error) {
+	if v != sweepGoalMaximize && v != sweepGoalMinimize {
+		return nil, errors.New("unknown sweep goal")
+	}
+
+	return sweepGoal(v), nil
+}
+
+type sweepGoal string
+
+func (r sweepGoal) SweepGoal() string {
+	return string(r)
+}
+
+// IsBetter returns true if the metric a is better than b
+func (r sweepGoal) IsBetter(a, b float64) bool {
+	if string(r) == sweepGoalMaximize {
+		return a > b
+	}
+
+	return a < b
+}
+
+// CronSpec
+type CronSpec interface {
+	CronSpec() string
+
+	// Next returns the first time after t which matches the spec
+	// in the location of t, and the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+func NewCronSpec(v string) (CronSpec, error) {
+	s, err := parseCronSpec(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Next(time.Now()).IsZero() {
+		return nil, errors.New("the cron spec never matches")
+	}
+
+	return s, nil
+}
+
+// Timezone
+type Timezone interface {
+	Timezone() string
+	Location() *time.Location
+}
+
+func NewTimezone(v string) (Timezone, error) {
+	if v == "" || v == "Local" {
+		return nil, errors.New("empty timezone")
+	}
+
+	loc, err := time.LoadLocation(v)
+	if err != nil {
+		return nil, errors.New("unknown timezone")
+	}
+
+	return timezone{loc}, nil
+}
+
+type timezone struct {
+	loc *time.Location
+}
+
+func (r timezone) Timezone() string {
+	return r.loc.String()
+}
+
+func (r timezone) Location() *time.Location {
+	return r.loc
+}
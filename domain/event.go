@@ -7,6 +7,16 @@ type TrainingCreatedEvent struct {
 	TrainingInputs []Input
 }
 
+// TrainingScheduleRunFailedEvent notifies the owner that the run of schedule
+// failed to create the training or the training failed.
+type TrainingScheduleRunFailedEvent struct {
+	Account    Account
+	ScheduleId string
+	ProjectId  string
+	TrainingId string
+	Reason     string
+}
+
 type UserSignedInEvent struct {
 	Account Account
 }
@@ -49,6 +49,7 @@ type EventHandler interface {
 	FinetuneHandler
 	InferenceHandler
 	EvaluateHandler
+	RepoFileHandler
 }
 
 type LikeHandler interface {
@@ -95,3 +96,7 @@ type InferenceHandler interface {
 type EvaluateHandler interface {
 	HandleEventCreateEvaluate(*EvaluateInfo) error
 }
+
+type RepoFileHandler interface {
+	HandleEventRepoFileCommitted(*RepoFileCommitted) error
+}
@@ -4,6 +4,17 @@ import (
 	"github.com/opensourceways/xihe-server/domain"
 )
 
+// RepoFileCommitted is the event of committing a text file which should be re-checked
+type RepoFileCommitted struct {
+	Resource domain.ResourceObject
+	Path     string
+	Content  string
+}
+
+type RepoFileProducer interface {
+	SendRepoFileCommitted(*RepoFileCommitted) error
+}
+
 type RepoMessageProducer interface {
 	SendRepoDownloaded(*domain.RepoDownloadedEvent) error
 	IncreaseDownload(*domain.ResourceObject) error
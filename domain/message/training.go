@@ -6,4 +6,5 @@ import (
 
 type MessageProducer interface {
 	SendTrainingCreated(*domain.TrainingCreatedEvent) error
+	SendTrainingScheduleRunFailed(*domain.TrainingScheduleRunFailedEvent) error
 }
@@ -21,6 +21,7 @@ type Model struct {
 	LikeCount       int
 	DownloadCount   int
 	RelatedProjects RelatedResources
+	ReviewStatus    string
 }
 
 func (m *Model) MaxRelatedResourceNum() int {
@@ -31,6 +32,10 @@ func (m *Model) IsPrivate() bool {
 	return m.RepoType.RepoType() == RepoTypePrivate
 }
 
+func (m *Model) IsHidden() bool {
+	return m.ReviewStatus == ReviewStatusHidden
+}
+
 func (m *Model) ResourceIndex() ResourceIndex {
 	return ResourceIndex{
 		Owner: m.Owner,
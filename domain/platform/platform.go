@@ -96,8 +96,16 @@ type RepoFile interface {
 	GenLFSDownloadURL(sha string) (string, error)
 	GetDirFileInfo(u *UserInfo, d *RepoDirFile) (sha string, exist bool, err error)
 	DownloadRepo(u *UserInfo, repoId string, handle func(io.Reader, int64)) error
+
+	// UploadLFSFile uploads the content as the LFS object of repo,
+	// and returns the content of the pointer file to commit.
+	UploadLFSFile(u *UserInfo, repo domain.ResourceName, r io.Reader) (string, error)
 }
 
+// MaxRepoFileSize is the max size of file which can be committed directly,
+// the larger one should be uploaded as the LFS file.
+const MaxRepoFileSize = 200 * 1024 // TODO 200KB to config
+
 func (r *RepoFileContent) IsOverSize() bool {
 	var decodeSize int
 	if r.IsEncoded {
@@ -106,7 +114,7 @@ func (r *RepoFileContent) IsOverSize() bool {
 		decodeSize = len(*r.Content)
 	}
 
-	return decodeSize > 200*1024 // TODO 200KB to config
+	return decodeSize > MaxRepoFileSize
 }
 
 func (r *RepoFileInfo) BlacklistFilter() bool {
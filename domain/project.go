@@ -24,6 +24,7 @@ type Project struct {
 	LikeCount     int
 	ForkCount     int
 	DownloadCount int
+	ReviewStatus  string
 }
 
 func (p *Project) MaxRelatedResourceNum() int {
@@ -34,6 +35,10 @@ func (p *Project) IsPrivate() bool {
 	return p.RepoType.RepoType() == RepoTypePrivate
 }
 
+func (p *Project) IsHidden() bool {
+	return p.ReviewStatus == ReviewStatusHidden
+}
+
 func (p *Project) IsOnline() bool {
 	return p.RepoType.RepoType() == RepoTypeOnline
 }
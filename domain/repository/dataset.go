@@ -18,6 +18,7 @@ type UserDatasetsInfo struct {
 type Dataset interface {
 	Save(*domain.Dataset) (domain.Dataset, error)
 	Delete(*domain.ResourceIndex) error
+	SetReviewStatus(*domain.ResourceIndex, string) error
 	Get(domain.Account, string) (domain.Dataset, error)
 	GetByName(domain.Account, domain.ResourceName) (domain.Dataset, error)
 	GetSummaryByName(domain.Account, domain.ResourceName) (domain.ResourceSummary, error)
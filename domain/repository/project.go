@@ -73,6 +73,7 @@ type ProjectSummary struct {
 type Project interface {
 	Save(*domain.Project) (domain.Project, error)
 	Delete(*domain.ResourceIndex) error
+	SetReviewStatus(*domain.ResourceIndex, string) error
 	Get(domain.Account, string) (domain.Project, error)
 	GetByName(domain.Account, domain.ResourceName) (domain.Project, error)
 	GetSummary(domain.Account, string) (ProjectSummary, error)
@@ -4,12 +4,20 @@ import (
 	"github.com/opensourceways/xihe-server/domain"
 )
 
+type TrainingStatus struct {
+	domain.TrainingIndex
+	Status string
+}
+
 type Training interface {
 	Save(*domain.UserTraining, int) (string, error)
 	Get(*domain.TrainingIndex) (domain.UserTraining, error)
 	Delete(*domain.TrainingIndex) error
 	List(user domain.Account, projectId string) ([]domain.TrainingSummary, int, error)
 
+	// ListStatus returns the status of trainings of all the projects of user
+	ListStatus(user domain.Account) ([]TrainingStatus, error)
+
 	GetTrainingConfig(*domain.TrainingIndex) (domain.TrainingConfig, error)
 
 	SaveJob(*domain.TrainingIndex, *domain.JobInfo) error
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/domain"
+)
+
+type TrainingMetric interface {
+	// Append adds the points to the end of series and keeps
+	// at most MaxTrainingMetricPoints points.
+	Append(*domain.TrainingMetric) error
+
+	// List returns the series of the trainings of project.
+	// It returns all the metrics if names is empty.
+	List(project *domain.ResourceIndex, trainingIds, names []string) ([]domain.TrainingMetric, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/domain"
+)
+
+type TrainingQueue interface {
+	// Get returns an empty queue if the user has no queue
+	Get(domain.Account) (domain.TrainingQueue, error)
+
+	// Save returns ErrorConcurrentUpdating if the queue is changed after Get
+	Save(*domain.TrainingQueue) error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/domain"
+)
+
+type TrainingSchedule interface {
+	Save(*domain.TrainingSchedule) (string, error)
+	Get(owner domain.Account, scheduleId string) (domain.TrainingSchedule, error)
+	List(owner domain.Account, projectId string) ([]domain.TrainingSchedule, error)
+	Delete(owner domain.Account, scheduleId string) error
+
+	// FindToHandle returns the schedules which are due at the time
+	// or have the runs not done.
+	FindToHandle(now int64) ([]domain.TrainingSchedule, error)
+
+	// Update returns ErrorConcurrentUpdating if the schedule is changed after Get
+	Update(*domain.TrainingSchedule) error
+}
+
+type TrainingScheduleNotice interface {
+	// Add keeps at most MaxTrainingScheduleNotices notices of the owner.
+	Add(owner domain.Account, notice *domain.TrainingScheduleNotice) error
+
+	// List returns the notices of owner from the earliest to the latest
+	List(owner domain.Account) ([]domain.TrainingScheduleNotice, error)
+}
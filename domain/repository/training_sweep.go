@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"github.com/opensourceways/xihe-server/domain"
+)
+
+type TrainingSweep interface {
+	Save(*domain.TrainingSweep) (string, error)
+	Get(owner domain.Account, sweepId string) (domain.TrainingSweep, error)
+	List(owner domain.Account, projectId string) ([]domain.TrainingSweep, error)
+}
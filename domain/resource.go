@@ -6,6 +6,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// ReviewStatusHidden means the resource is hidden from others
+// until it is reviewed because its content is flagged.
+const ReviewStatusHidden = "hidden"
+
 type ResourceObjects struct {
 	Type    ResourceType
 	Objects []ResourceIndex
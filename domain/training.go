@@ -9,6 +9,9 @@ type UserTraining struct {
 
 	CreatedAt int64
 
+	// Commit is the last commit of the code dir when the training is created
+	Commit string
+
 	// following fileds is not under the controlling of version
 	Job       JobInfo
 	JobDetail JobDetail
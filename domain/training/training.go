@@ -1,6 +1,8 @@
 package training
 
 import (
+	"io"
+
 	"github.com/opensourceways/xihe-server/domain"
 )
 
@@ -10,5 +12,10 @@ type Training interface {
 	TerminateJob(endpoint, jobId string) error
 	GetLogPreviewURL(endpoint, jobId string) (string, error)
 	IsJobDone(status string) bool
+	IsJobFailed(status string) bool
 	GetFileDownloadURL(endpoint, file string) (string, error)
+
+	// DownloadFile streams the file to handle without loading it into memory,
+	// the size is -1 if it is unknown.
+	DownloadFile(endpoint, file string, handle func(r io.Reader, size int64) error) error
 }
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// TrainingMetric is the series of a scalar metric, such as loss
+// and accuracy, reported step by step during the training.
+type TrainingMetric struct {
+	TrainingIndex
+
+	Name   CustomizedKey
+	Points []MetricPoint
+}
+
+type MetricPoint struct {
+	Step  int
+	Value float64
+
+	// ReportedAt is the time when the point is received
+	ReportedAt int64
+}
+
+// Latest returns the point of the last step, which is the final
+// value of the metric after the training is done.
+func (m *TrainingMetric) Latest() (p MetricPoint, ok bool) {
+	for i := range m.Points {
+		if item := &m.Points[i]; !ok || item.Step >= p.Step {
+			p, ok = *item, true
+		}
+	}
+
+	return
+}
+
+func (m *TrainingMetric) Validate() error {
+	if m.Name == nil || len(m.Points) == 0 {
+		return errors.New("empty metric")
+	}
+
+	if n := DomainConfig.MaxTrainingMetricPoints; len(m.Points) > n {
+		return fmt.Errorf("the points of a metric should be less than %d", n)
+	}
+
+	for i := range m.Points {
+		p := &m.Points[i]
+
+		if p.Step < 0 || math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			return fmt.Errorf("invalid point of metric: %s", m.Name.CustomizedKey())
+		}
+	}
+
+	return nil
+}
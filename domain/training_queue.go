@@ -0,0 +1,116 @@
+package domain
+
+import "errors"
+
+// TrainingQueue is the queue of trainings of a user. At most MaxRunningTrainings
+// trainings can be running at the same time, the others wait in the queue.
+type TrainingQueue struct {
+	Owner   Account
+	Running []TrainingIndex
+	Queued  []QueuedTraining
+	Version int
+}
+
+type QueuedTraining struct {
+	TrainingIndex
+
+	CreatedAt int64
+}
+
+// Position returns the position of the training in the queue starting from 1,
+// and 0 means it is not waiting in the queue.
+func (q *TrainingQueue) Position(trainingId string) int {
+	for i := range q.Queued {
+		if q.Queued[i].TrainingId == trainingId {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+func (q *TrainingQueue) IsFull() bool {
+	return len(q.Queued) >= DomainConfig.MaxQueuedTrainings
+}
+
+func (q *TrainingQueue) Enqueue(t *QueuedTraining) error {
+	if q.IsFull() {
+		return errors.New("the training queue is full")
+	}
+
+	q.Queued = append(q.Queued, *t)
+
+	return nil
+}
+
+// Dispatch moves the trainings from the head of queue to the running
+// until there is no free slot, and returns the moved ones.
+func (q *TrainingQueue) Dispatch() []TrainingIndex {
+	n := DomainConfig.MaxRunningTrainings - len(q.Running)
+	if n <= 0 || len(q.Queued) == 0 {
+		return nil
+	}
+
+	if n > len(q.Queued) {
+		n = len(q.Queued)
+	}
+
+	r := make([]TrainingIndex, n)
+	for i := range r {
+		r[i] = q.Queued[i].TrainingIndex
+	}
+
+	q.Queued = q.Queued[n:]
+	q.Running = append(q.Running, r...)
+
+	return r
+}
+
+// Release frees the slot of the running training.
+func (q *TrainingQueue) Release(trainingId string) bool {
+	for i := range q.Running {
+		if q.Running[i].TrainingId == trainingId {
+			q.Running = append(q.Running[:i], q.Running[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Remove removes the training which is waiting in the queue.
+func (q *TrainingQueue) Remove(trainingId string) bool {
+	if i := q.Position(trainingId); i > 0 {
+		q.Queued = append(q.Queued[:i-1], q.Queued[i:]...)
+
+		return true
+	}
+
+	return false
+}
+
+// Move moves the waiting training to the position which starts from 1.
+func (q *TrainingQueue) Move(trainingId string, position int) error {
+	i := q.Position(trainingId)
+	if i == 0 {
+		return errors.New("the training is not in the queue")
+	}
+
+	if position < 1 || position > len(q.Queued) {
+		return errors.New("invalid position")
+	}
+
+	t := q.Queued[i-1]
+
+	v := append(q.Queued[:i-1:i-1], q.Queued[i:]...)
+
+	r := make([]QueuedTraining, 0, len(q.Queued))
+	r = append(r, v[:position-1]...)
+	r = append(r, t)
+	r = append(r, v[position-1:]...)
+
+	q.Queued = r
+
+	return nil
+}
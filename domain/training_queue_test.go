@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestTrainingQueue(ids ...string) TrainingQueue {
+	q := TrainingQueue{}
+	for _, id := range ids {
+		q.Queued = append(q.Queued, QueuedTraining{
+			TrainingIndex: TrainingIndex{TrainingId: id},
+		})
+	}
+
+	return q
+}
+
+func queuedTrainingIds(q *TrainingQueue) []string {
+	r := make([]string, len(q.Queued))
+	for i := range q.Queued {
+		r[i] = q.Queued[i].TrainingId
+	}
+
+	return r
+}
+
+func TestTrainingQueueMove(t *testing.T) {
+	cases := []struct {
+		name     string
+		id       string
+		position int
+		want     []string
+		ok       bool
+	}{
+		{"to head", "c", 1, []string{"c", "a", "b", "d"}, true},
+		{"to tail", "a", 4, []string{"b", "c", "d", "a"}, true},
+		{"backward", "b", 3, []string{"a", "c", "b", "d"}, true},
+		{"forward", "d", 2, []string{"a", "d", "b", "c"}, true},
+		{"same position", "b", 2, []string{"a", "b", "c", "d"}, true},
+		{"not in queue", "e", 1, []string{"a", "b", "c", "d"}, false},
+		{"position 0", "a", 0, []string{"a", "b", "c", "d"}, false},
+		{"position out of queue", "a", 5, []string{"a", "b", "c", "d"}, false},
+	}
+
+	for _, c := range cases {
+		q := newTestTrainingQueue("a", "b", "c", "d")
+
+		err := q.Move(c.id, c.position)
+		if ok := err == nil; ok != c.ok {
+			t.Errorf("%s: expect ok=%v, got err=%v", c.name, c.ok, err)
+		}
+
+		if got := queuedTrainingIds(&q); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expect %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestTrainingQueueRemove(t *testing.T) {
+	cases := []struct {
+		name string
+		id   string
+		want []string
+		ok   bool
+	}{
+		{"head", "a", []string{"b", "c"}, true},
+		{"middle", "b", []string{"a", "c"}, true},
+		{"tail", "c", []string{"a", "b"}, true},
+		{"not in queue", "d", []string{"a", "b", "c"}, false},
+	}
+
+	for _, c := range cases {
+		q := newTestTrainingQueue("a", "b", "c")
+
+		if ok := q.Remove(c.id); ok != c.ok {
+			t.Errorf("%s: expect removed=%v, got %v", c.name, c.ok, ok)
+		}
+
+		if got := queuedTrainingIds(&q); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expect %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestTrainingQueueDispatch(t *testing.T) {
+	DomainConfig.MaxRunningTrainings = 2
+
+	cases := []struct {
+		name    string
+		running []string
+		queued  []string
+		want    []string
+		left    []string
+	}{
+		{"free slots", nil, []string{"a", "b", "c"}, []string{"a", "b"}, []string{"c"}},
+		{"one slot", []string{"x"}, []string{"a", "b"}, []string{"a"}, []string{"b"}},
+		{"no slot", []string{"x", "y"}, []string{"a"}, nil, []string{"a"}},
+		{"empty queue", nil, nil, nil, []string{}},
+	}
+
+	for _, c := range cases {
+		q := newTestTrainingQueue(c.queued...)
+		for _, id := range c.running {
+			q.Running = append(q.Running, TrainingIndex{TrainingId: id})
+		}
+
+		var got []string
+		for _, item := range q.Dispatch() {
+			got = append(got, item.TrainingId)
+		}
+
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expect dispatched %v, got %v", c.name, c.want, got)
+		}
+
+		if left := queuedTrainingIds(&q); !reflect.DeepEqual(left, c.left) {
+			t.Errorf("%s: expect left %v, got %v", c.name, c.left, left)
+		}
+
+		if n := len(c.running) + len(c.want); len(q.Running) != n {
+			t.Errorf("%s: expect %d running, got %d", c.name, n, len(q.Running))
+		}
+	}
+}
+
+func TestTrainingQueueEnqueue(t *testing.T) {
+	DomainConfig.MaxQueuedTrainings = 2
+
+	q := newTestTrainingQueue("a")
+
+	if err := q.Enqueue(&QueuedTraining{TrainingIndex: TrainingIndex{TrainingId: "b"}}); err != nil {
+		t.Fatalf("expect enqueued, got err=%v", err)
+	}
+
+	if !q.IsFull() {
+		t.Error("expect the queue is full")
+	}
+
+	if err := q.Enqueue(&QueuedTraining{TrainingIndex: TrainingIndex{TrainingId: "c"}}); err == nil {
+		t.Error("expect the error of full queue")
+	}
+
+	if got := q.Position("b"); got != 2 {
+		t.Errorf("expect position 2, got %d", got)
+	}
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// scheduleIntervalCheckRuns is the max runs checked for the interval of schedule,
+// which is more than the runs in a year at the interval of an hour.
+const scheduleIntervalCheckRuns = 10000
+
+// TrainingSchedule recreates the training periodically at the times which
+// match the cron spec in the timezone. The new training reuses the config
+// of the training which the schedule is attached to.
+type TrainingSchedule struct {
+	Id         string
+	Owner      Account
+	ProjectId  string
+	TrainingId string
+	Spec       CronSpec
+	Timezone   Timezone
+	Paused     bool
+	NextRunAt  int64
+	Runs       []ScheduleRun
+	CreatedAt  int64
+	Version    int
+}
+
+// ScheduleRun is a run spawned by the schedule. The TrainingId is empty
+// if it failed to create the training, and the Error is the reason.
+type ScheduleRun struct {
+	TrainingId string
+	RunAt      int64
+	Status     string
+	Error      string
+	Done       bool
+	Failed     bool
+}
+
+func (s *TrainingSchedule) TrainingIndex() TrainingIndex {
+	return TrainingIndex{
+		Project: ResourceIndex{
+			Owner: s.Owner,
+			Id:    s.ProjectId,
+		},
+		TrainingId: s.TrainingId,
+	}
+}
+
+// CheckInterval checks whether the runs are too frequent. The gap between runs
+// varies, such as "0,59 0 * * *", so it checks all the runs in a cycle of the spec,
+// which is at most a year or scheduleIntervalCheckRuns runs.
+func (s *TrainingSchedule) CheckInterval(now int64) error {
+	t := s.Spec.Next(time.Unix(now, 0).In(s.Timezone.Location()))
+	if t.IsZero() {
+		return errors.New("the cron spec never matches")
+	}
+
+	min := int64(DomainConfig.MinTrainingScheduleInterval)
+	end := t.AddDate(1, 0, 0)
+
+	for i := 0; i < scheduleIntervalCheckRuns && t.Before(end); i++ {
+		n := s.Spec.Next(t)
+		if n.IsZero() {
+			break
+		}
+
+		if n.Unix()-t.Unix() < min {
+			return fmt.Errorf(
+				"the interval of runs should not be less than %d seconds",
+				DomainConfig.MinTrainingScheduleInterval,
+			)
+		}
+
+		t = n
+	}
+
+	return nil
+}
+
+// ScheduleNext sets the time of next run after now. The NextRunAt
+// is 0 if there is no more run.
+func (s *TrainingSchedule) ScheduleNext(now int64) {
+	t := s.Spec.Next(time.Unix(now, 0).In(s.Timezone.Location()))
+	if t.IsZero() {
+		s.NextRunAt = 0
+	} else {
+		s.NextRunAt = t.Unix()
+	}
+}
+
+func (s *TrainingSchedule) IsDue(now int64) bool {
+	return !s.Paused && s.NextRunAt > 0 && s.NextRunAt <= now
+}
+
+// Pause stops spawning runs, and returns false if it is paused already.
+func (s *TrainingSchedule) Pause() bool {
+	if s.Paused {
+		return false
+	}
+
+	s.Paused = true
+
+	return true
+}
+
+// Resume restarts spawning runs from now on, the runs missed
+// during the pause are skipped.
+func (s *TrainingSchedule) Resume(now int64) bool {
+	if !s.Paused {
+		return false
+	}
+
+	s.Paused = false
+	s.ScheduleNext(now)
+
+	return true
+}
+
+// AddRun records the run and drops the earliest ones
+// if there are more than MaxTrainingScheduleRuns runs.
+func (s *TrainingSchedule) AddRun(r *ScheduleRun) {
+	s.Runs = append(s.Runs, *r)
+
+	if n := len(s.Runs) - DomainConfig.MaxTrainingScheduleRuns; n > 0 {
+		s.Runs = s.Runs[n:]
+	}
+}
+
+// PendingRuns returns the trainings of runs which are not done.
+func (s *TrainingSchedule) PendingRuns() []string {
+	var r []string
+
+	for i := range s.Runs {
+		if item := &s.Runs[i]; !item.Done && item.TrainingId != "" {
+			r = append(r, item.TrainingId)
+		}
+	}
+
+	return r
+}
+
+// FinishRun sets the final status of run, and returns false
+// if the run doesn't exist or is done already.
+func (s *TrainingSchedule) FinishRun(trainingId, status string, failed bool) bool {
+	for i := range s.Runs {
+		if item := &s.Runs[i]; item.TrainingId == trainingId {
+			if item.Done {
+				return false
+			}
+
+			item.Status = status
+			item.Done = true
+			item.Failed = failed
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// TrainingScheduleNotice tells the owner that a run of the schedule failed.
+// The TrainingId is empty if it failed to create the training.
+type TrainingScheduleNotice struct {
+	ScheduleId string
+	ProjectId  string
+	TrainingId string
+	Reason     string
+	CreatedAt  int64
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrainingScheduleCheckInterval(t *testing.T) {
+	DomainConfig.MinTrainingScheduleInterval = 3600
+
+	tz, err := NewTimezone("UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		expr string
+		now  time.Time
+		ok   bool
+	}{
+		{
+			name: "hourly",
+			expr: "0 * * * *",
+			now:  time.Date(2023, 1, 1, 0, 30, 0, 0, time.UTC),
+			ok:   true,
+		},
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			now:  time.Date(2023, 1, 1, 0, 30, 0, 0, time.UTC),
+		},
+		{
+			// the first two runs are 59 minutes apart
+			name: "short gap first",
+			expr: "0,59 0 * * *",
+			now:  time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			// the first two runs are 23 hours apart, and the next two are 1 minute
+			name: "short gap later",
+			expr: "0,1 * * * *",
+			now:  time.Date(2023, 1, 1, 0, 30, 0, 0, time.UTC),
+		},
+		{
+			// the runs are only in February, which is 11 months later
+			name: "in a month",
+			expr: "0 0,12 * 2 *",
+			now:  time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			ok:   true,
+		},
+		{
+			name: "short gap in a month",
+			expr: "0,30 0 * 2 *",
+			now:  time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		spec, err := NewCronSpec(c.expr)
+		if err != nil {
+			t.Fatalf("%s: parse %q failed, %v", c.name, c.expr, err)
+		}
+
+		s := TrainingSchedule{Spec: spec, Timezone: tz}
+
+		err = s.CheckInterval(c.now.Unix())
+		if ok := err == nil; ok != c.ok {
+			t.Errorf("%s: expect ok=%v, got err=%v", c.name, c.ok, err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const maxSweepRangeValues = 100
+
+// TrainingSweep searches the hyperparameters of a training. Each combination
+// of the hyperparameters is a trial which runs as a separate training.
+type TrainingSweep struct {
+	Id         string
+	Owner      Account
+	ProjectId  string
+	Name       TrainingName
+	Strategy   SweepStrategy
+	Metric     SweepMetric
+	MaxTrials  int
+	Parameters []SweepParameter
+	Trials     []SweepTrial
+	CreatedAt  int64
+	Version    int
+}
+
+type SweepMetric struct {
+	Name CustomizedKey
+	Goal SweepGoal
+}
+
+// SweepParameter is the hyperparameter to sweep. It takes the Values
+// if they are set, otherwise the values in the Range.
+type SweepParameter struct {
+	Key    CustomizedKey
+	Values []CustomizedValue
+	Range  SweepRange
+}
+
+// SweepRange is [Min, Max]. Step 0 means the range is continuous
+// which is only allowed by the random strategy.
+type SweepRange struct {
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+type SweepTrial struct {
+	TrainingId      string
+	Hyperparameters []KeyValue
+}
+
+// GenTrials expands the parameters into the hyperparameters of trials.
+func (s *TrainingSweep) GenTrials() ([][]KeyValue, error) {
+	if s.MaxTrials <= 0 || s.MaxTrials > DomainConfig.MaxSweepTrials {
+		return nil, fmt.Errorf(
+			"the max trials should be between 1 to %d", DomainConfig.MaxSweepTrials,
+		)
+	}
+
+	if len(s.Parameters) == 0 {
+		return nil, errors.New("no parameters to sweep")
+	}
+
+	keys := sets.NewString()
+	values := make([][]CustomizedValue, len(s.Parameters))
+
+	for i := range s.Parameters {
+		p := &s.Parameters[i]
+
+		k := p.Key.CustomizedKey()
+		if keys.Has(k) {
+			return nil, fmt.Errorf("duplicate parameter: %s", k)
+		}
+		keys.Insert(k)
+
+		v, err := p.values()
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter: %s, %s", k, err.Error())
+		}
+
+		if len(v) == 0 && s.Strategy.IsGrid() {
+			return nil, fmt.Errorf("the step of parameter: %s is required by grid", k)
+		}
+
+		values[i] = v
+	}
+
+	if s.Strategy.IsGrid() {
+		return s.gridTrials(values), nil
+	}
+
+	return s.randomTrials(values), nil
+}
+
+// gridTrials takes the combinations in order until the max trials is reached.
+func (s *TrainingSweep) gridTrials(values [][]CustomizedValue) [][]KeyValue {
+	r := [][]KeyValue{}
+	pos := make([]int, len(values))
+
+	for len(r) < s.MaxTrials {
+		kv := make([]KeyValue, len(values))
+		for i := range values {
+			kv[i] = KeyValue{Key: s.Parameters[i].Key, Value: values[i][pos[i]]}
+		}
+		r = append(r, kv)
+
+		i := len(pos) - 1
+		for ; i >= 0; i-- {
+			if pos[i]++; pos[i] < len(values[i]) {
+				break
+			}
+			pos[i] = 0
+		}
+
+		if i < 0 {
+			break
+		}
+	}
+
+	return r
+}
+
+// randomTrials samples the different combinations. It may return fewer
+// trials than the max trials if the combinations are not enough.
+func (s *TrainingSweep) randomTrials(values [][]CustomizedValue) [][]KeyValue {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	r := [][]KeyValue{}
+	done := sets.NewString()
+
+	for n := 0; len(r) < s.MaxTrials && n < s.MaxTrials*10; n++ {
+		kv := make([]KeyValue, len(values))
+		ids := make([]string, len(values))
+
+		for i := range values {
+			var v CustomizedValue
+
+			if vs := values[i]; len(vs) > 0 {
+				v = vs[rnd.Intn(len(vs))]
+			} else {
+				rg := &s.Parameters[i].Range
+				v = customizedValue(formatSweepValue(rg.Min + rnd.Float64()*(rg.Max-rg.Min)))
+			}
+
+			kv[i] = KeyValue{Key: s.Parameters[i].Key, Value: v}
+			ids[i] = v.CustomizedValue()
+		}
+
+		if id := strings.Join(ids, ","); !done.Has(id) {
+			done.Insert(id)
+			r = append(r, kv)
+		}
+	}
+
+	return r
+}
+
+// BestTrial returns the index of the trial which has the best metric,
+// and -1 if none of the trials reports the metric.
+// The metrics is keyed by the training id of trial.
+func (s *TrainingSweep) BestTrial(metrics map[string]float64) int {
+	r := -1
+	best := 0.0
+
+	for i := range s.Trials {
+		v, ok := metrics[s.Trials[i].TrainingId]
+		if !ok {
+			continue
+		}
+
+		if r < 0 || s.Metric.Goal.IsBetter(v, best) {
+			r, best = i, v
+		}
+	}
+
+	return r
+}
+
+func (p *SweepParameter) values() ([]CustomizedValue, error) {
+	if len(p.Values) > 0 {
+		return p.Values, nil
+	}
+
+	rg := &p.Range
+	if math.IsNaN(rg.Min) || math.IsNaN(rg.Max) ||
+		math.IsInf(rg.Min, 0) || math.IsInf(rg.Max, 0) || rg.Min > rg.Max {
+		return nil, errors.New("invalid range")
+	}
+
+	if rg.Step < 0 {
+		return nil, errors.New("invalid step")
+	}
+
+	if rg.Step == 0 {
+		return nil, nil
+	}
+
+	n := math.Floor((rg.Max-rg.Min)/rg.Step) + 1
+	if math.IsInf(n, 0) || n > maxSweepRangeValues {
+		return nil, fmt.Errorf("the range has more than %d values", maxSweepRangeValues)
+	}
+
+	r := make([]CustomizedValue, int(n))
+	for i := range r {
+		r[i] = customizedValue(formatSweepValue(rg.Min + float64(i)*rg.Step))
+	}
+
+	return r, nil
+}
+
+func formatSweepValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 10, 64)
+}
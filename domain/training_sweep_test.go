@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestSweepParameter(t *testing.T, key string, values []string, rg SweepRange) SweepParameter {
+	k, err := NewCustomizedKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := SweepParameter{Key: k, Range: rg}
+	for _, v := range values {
+		cv, err := NewCustomizedValue(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p.Values = append(p.Values, cv)
+	}
+
+	return p
+}
+
+func sweepTrialStrings(trials [][]KeyValue) []string {
+	r := make([]string, len(trials))
+	for i, kv := range trials {
+		s := make([]string, len(kv))
+		for j := range kv {
+			s[j] = kv[j].Key.CustomizedKey() + "=" + kv[j].Value.CustomizedValue()
+		}
+
+		r[i] = strings.Join(s, ",")
+	}
+
+	return r
+}
+
+func TestTrainingSweepGenGridTrials(t *testing.T) {
+	DomainConfig.MaxSweepTrials = 20
+
+	grid, _ := NewSweepStrategy(sweepStrategyGrid)
+
+	lr := newTestSweepParameter(t, "lr", []string{"0.1", "0.2"}, SweepRange{})
+	bs := newTestSweepParameter(t, "bs", nil, SweepRange{Min: 1, Max: 3, Step: 1})
+
+	cases := []struct {
+		name   string
+		params []SweepParameter
+		max    int
+		want   []string
+		ok     bool
+	}{
+		{
+			name:   "all combinations",
+			params: []SweepParameter{lr, bs},
+			max:    10,
+			want: []string{
+				"lr=0.1,bs=1", "lr=0.1,bs=2", "lr=0.1,bs=3",
+				"lr=0.2,bs=1", "lr=0.2,bs=2", "lr=0.2,bs=3",
+			},
+			ok: true,
+		},
+		{
+			name:   "limited by max trials",
+			params: []SweepParameter{lr, bs},
+			max:    4,
+			want:   []string{"lr=0.1,bs=1", "lr=0.1,bs=2", "lr=0.1,bs=3", "lr=0.2,bs=1"},
+			ok:     true,
+		},
+		{
+			name: "float step",
+			params: []SweepParameter{
+				newTestSweepParameter(t, "dropout", nil, SweepRange{Min: 0, Max: 1, Step: 0.25}),
+			},
+			max:  10,
+			want: []string{"dropout=0", "dropout=0.25", "dropout=0.5", "dropout=0.75", "dropout=1"},
+			ok:   true,
+		},
+		{
+			name:   "no max trials",
+			params: []SweepParameter{lr},
+		},
+		{
+			name:   "too many max trials",
+			params: []SweepParameter{lr},
+			max:    21,
+		},
+		{
+			name: "no parameters",
+			max:  1,
+		},
+		{
+			name:   "duplicate parameters",
+			params: []SweepParameter{lr, lr},
+			max:    1,
+		},
+		{
+			name: "continuous range",
+			params: []SweepParameter{
+				newTestSweepParameter(t, "lr", nil, SweepRange{Min: 0, Max: 1}),
+			},
+			max: 1,
+		},
+		{
+			name: "invalid range",
+			params: []SweepParameter{
+				newTestSweepParameter(t, "lr", nil, SweepRange{Min: 1, Max: 0, Step: 0.1}),
+			},
+			max: 1,
+		},
+		{
+			name: "too many values in range",
+			params: []SweepParameter{
+				newTestSweepParameter(t, "epoch", nil, SweepRange{Min: 0, Max: 1000, Step: 1}),
+			},
+			max: 1,
+		},
+	}
+
+	for _, c := range cases {
+		s := TrainingSweep{Strategy: grid, MaxTrials: c.max, Parameters: c.params}
+
+		v, err := s.GenTrials()
+		if ok := err == nil; ok != c.ok {
+			t.Errorf("%s: expect ok=%v, got err=%v", c.name, c.ok, err)
+
+			continue
+		}
+
+		if got := sweepTrialStrings(v); c.ok && !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expect %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestTrainingSweepGenRandomTrials(t *testing.T) {
+	DomainConfig.MaxSweepTrials = 20
+
+	random, _ := NewSweepStrategy(sweepStrategyRandom)
+
+	cases := []struct {
+		name   string
+		params []SweepParameter
+		max    int
+		want   int
+	}{
+		{
+			name: "fewer combinations than max trials",
+			params: []SweepParameter{
+				newTestSweepParameter(t, "opt", []string{"adam", "sgd"}, SweepRange{}),
+				newTestSweepParameter(t, "bs", []string{"32"}, SweepRange{}),
+			},
+			max:  5,
+			want: 2,
+		},
+		{
+			name: "continuous range",
+			params: []SweepParameter{
+				newTestSweepParameter(t, "lr", nil, SweepRange{Min: 0.001, Max: 0.1}),
+			},
+			max:  5,
+			want: 5,
+		},
+	}
+
+	for _, c := range cases {
+		s := TrainingSweep{Strategy: random, MaxTrials: c.max, Parameters: c.params}
+
+		v, err := s.GenTrials()
+		if err != nil {
+			t.Errorf("%s: unexpected err=%v", c.name, err)
+
+			continue
+		}
+
+		got := sweepTrialStrings(v)
+		if len(got) != c.want {
+			t.Errorf("%s: expect %d trials, got %v", c.name, c.want, got)
+		}
+
+		done := map[string]bool{}
+		for _, item := range got {
+			if done[item] {
+				t.Errorf("%s: duplicate trial %s", c.name, item)
+			}
+			done[item] = true
+		}
+
+		// the sampled values must be in the range
+		for i := range s.Parameters {
+			rg := &s.Parameters[i].Range
+			if len(s.Parameters[i].Values) > 0 {
+				continue
+			}
+
+			for _, kv := range v {
+				f, err := strconv.ParseFloat(kv[i].Value.CustomizedValue(), 64)
+				if err != nil || f < rg.Min || f > rg.Max {
+					t.Errorf("%s: the value %s is out of range", c.name, kv[i].Value.CustomizedValue())
+				}
+			}
+		}
+	}
+}
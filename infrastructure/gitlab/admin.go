@@ -15,6 +15,7 @@ var (
 	obsHelper *obsService
 
 	endpoint        string
+	webEndpoint     string
 	defaultBranch   string
 	graphqlEndpoint string
 
@@ -40,6 +41,7 @@ func Init(cfg *Config) error {
 
 	admin = &administrator{v}
 	endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	webEndpoint = strings.TrimSuffix(endpoint, "/api/v4")
 	maxFileCount = cfg.MaxFileCount
 	defaultBranch = cfg.DefaultBranch
 	graphqlEndpoint = strings.TrimSuffix(cfg.GraphqlEndpoint, "/")
@@ -0,0 +1,182 @@
+package gitlab
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/opensourceways/community-robot-lib/utils"
+
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/platform"
+)
+
+const (
+	lfsMediaType = "application/vnd.git-lfs+json"
+
+	// lfsUploadTimeout is the time to upload a LFS object,
+	// the object may be as large as the output of training.
+	lfsUploadTimeout = 10 * time.Minute
+)
+
+var lfsCli = &http.Client{Timeout: lfsUploadTimeout}
+
+type lfsObject struct {
+	Oid  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type lfsBatchRequest struct {
+	Operation string      `json:"operation"`
+	Transfers []string    `json:"transfers"`
+	Objects   []lfsObject `json:"objects"`
+}
+
+type lfsAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header"`
+}
+
+type lfsBatchResponse struct {
+	Objects []struct {
+		lfsObject
+
+		Actions map[string]lfsAction `json:"actions"`
+		Error   *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"objects"`
+}
+
+// UploadLFSFile uploads the content to the LFS storage of repo by the batch api of
+// git lfs. The content is saved to a temporary file at first, because the oid of
+// the LFS object is the sha256 of the whole content.
+func (impl *repoFile) UploadLFSFile(
+	u *platform.UserInfo, repo domain.ResourceName, r io.Reader,
+) (string, error) {
+	f, err := os.CreateTemp("", "lfs-")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	h := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return "", err
+	}
+
+	obj := lfsObject{
+		Oid:  hex.EncodeToString(h.Sum(nil)),
+		Size: size,
+	}
+
+	action, err := impl.lfsUploadAction(u, repo, &obj)
+	if err != nil {
+		return "", err
+	}
+
+	// the object has been uploaded if there is no upload action
+	if action != nil {
+		if _, err = f.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+
+		if err = impl.lfsUpload(action, f, size); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf(
+		"version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n",
+		obj.Oid, obj.Size,
+	), nil
+}
+
+func (impl *repoFile) lfsUploadAction(
+	u *platform.UserInfo, repo domain.ResourceName, obj *lfsObject,
+) (*lfsAction, error) {
+	v, err := utils.JsonMarshal(&lfsBatchRequest{
+		Operation: "upload",
+		Transfers: []string{"basic"},
+		Objects:   []lfsObject{*obj},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf(
+		"%s/%s/%s.git/info/lfs/objects/batch",
+		webEndpoint, u.User.Account(), repo.ResourceName(),
+	)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(v))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(u.User.Account(), u.Token)
+	req.Header.Add("Accept", lfsMediaType)
+	req.Header.Add("Content-Type", lfsMediaType)
+
+	resp := lfsBatchResponse{}
+	if _, err = impl.cli.ForwardTo(req, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Objects) == 0 {
+		return nil, errors.New("no lfs object in the response")
+	}
+
+	item := &resp.Objects[0]
+	if item.Error != nil {
+		return nil, fmt.Errorf(
+			"upload lfs object failed, code:%d, msg:%s",
+			item.Error.Code, item.Error.Message,
+		)
+	}
+
+	if action, ok := item.Actions["upload"]; ok {
+		return &action, nil
+	}
+
+	return nil, nil
+}
+
+func (impl *repoFile) lfsUpload(action *lfsAction, r io.Reader, size int64) error {
+	req, err := http.NewRequest(http.MethodPut, action.Href, r)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Add("Content-Type", "application/octet-stream")
+	for k, v := range action.Header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := lfsCli.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("upload lfs object failed, status code:%d", resp.StatusCode)
+	}
+
+	return nil
+}
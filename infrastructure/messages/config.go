@@ -14,4 +14,5 @@ type Topics struct {
 	RelatedResource string `json:"related_resource" required:"true"`
 	Cloud           string `json:"cloud"            required:"true"`
 	Async           string `json:"async"            required:"true"`
+	RepoFile        string `json:"repo_file"        required:"true"`
 }
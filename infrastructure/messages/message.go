@@ -56,6 +56,12 @@ type msgExtendInference struct {
 	Expiry int64 `json:"expiry"`
 }
 
+type msgRepoFile struct {
+	Resource resourceObject `json:"resource"`
+	Path     string         `json:"path"`
+	Content  string         `json:"content"`
+}
+
 type msgEvaluate struct {
 	Type         string `json:"type"`
 	OBSPath      string `json:"path"`
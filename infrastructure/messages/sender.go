@@ -73,6 +73,17 @@ func (s *sender) CreateEvaluate(info *message.EvaluateInfo) error {
 	return s.send(s.topics.Evaluate, &v)
 }
 
+// RepoFile
+func (s *sender) SendRepoFileCommitted(e *message.RepoFileCommitted) error {
+	v := msgRepoFile{
+		Path:    e.Path,
+		Content: e.Content,
+	}
+	toMsgResourceObject(&e.Resource, &v.Resource)
+
+	return s.send(s.topics.RepoFile, &v)
+}
+
 // Competition
 func (s *sender) CalcScore(info *message.SubmissionInfo) error {
 	v := msgSubmission{
@@ -27,6 +27,7 @@ const (
 	handlerNameCreateFinetune     = "create_finetune"
 	handlerNameCreateEvaluate     = "create_evaluate"
 	handlerNameCreateInference    = "create_inference"
+	handlerNameCheckRepoFile      = "check_repo_file"
 )
 
 func Subscribe(
@@ -78,6 +79,11 @@ func Subscribe(
 		return
 	}
 
+	// repo file
+	if err = r.registerHandlerForRepoFile(handler); err != nil {
+		return
+	}
+
 	// register end
 	<-ctx.Done()
 
@@ -331,6 +337,30 @@ func (r *register) registerHandlerForCloud(handler interface{}) error {
 	return r.subscribe(r.topics.Cloud, handlerNameCreateCloud, f)
 }
 
+func (r *register) registerHandlerForRepoFile(handler interface{}) error {
+	h, ok := handler.(message.RepoFileHandler)
+	if !ok {
+		return nil
+	}
+
+	return r.subscribe(r.topics.RepoFile, handlerNameCheckRepoFile, func(b []byte, hd map[string]string) (err error) {
+		body := msgRepoFile{}
+		if err = json.Unmarshal(b, &body); err != nil {
+			return
+		}
+
+		v := message.RepoFileCommitted{
+			Path:    body.Path,
+			Content: body.Content,
+		}
+		if err = body.Resource.toResourceObject(&v.Resource); err != nil {
+			return
+		}
+
+		return h.HandleEventRepoFileCommitted(&v)
+	})
+}
+
 func (r *register) subscribe(
 	topicName string, handlerName string,
 	handler func(b []byte, m map[string]string) (err error),
@@ -14,6 +14,8 @@ const (
 	projectId    = "project_id"
 	trainingId   = "training_id"
 	input        = "input"
+	scheduleId   = "schedule_id"
+	reason       = "reason"
 )
 
 func NewTrainingMessageAdapter(cfg *TrainingConfig, p commsg.Publisher) *trainingMessageAdapter {
@@ -49,6 +51,33 @@ func (impl *trainingMessageAdapter) SendTrainingCreated(v *domain.TrainingCreate
 	return impl.publisher.Publish(cfg.Topic, &msg, nil)
 }
 
+func (impl *trainingMessageAdapter) SendTrainingScheduleRunFailed(
+	v *domain.TrainingScheduleRunFailedEvent,
+) error {
+	cfg := &impl.cfg.TrainingScheduleRunFailed
+
+	msg := commsg.MsgNormal{
+		Type: cfg.Name,
+		User: v.Account.Account(),
+		Desc: fmt.Sprintf(
+			"the run of training schedule failed, schedule id: %s, reason: %s",
+			v.ScheduleId, v.Reason,
+		),
+		Details: map[string]string{
+			projectOwner: v.Account.Account(),
+			projectId:    v.ProjectId,
+			trainingId:   v.TrainingId,
+			scheduleId:   v.ScheduleId,
+			reason:       v.Reason,
+		},
+		CreatedAt: utils.Now(),
+	}
+
+	return impl.publisher.Publish(cfg.Topic, &msg, nil)
+}
+
 type TrainingConfig struct {
 	TrainingCreated commsg.TopicConfig `json:"training_created" required:"true"`
+
+	TrainingScheduleRunFailed commsg.TopicConfig `json:"training_schedule_run_failed" required:"true"`
 }
@@ -78,6 +78,10 @@ func (col dataset) Delete(do *repositories.ResourceIndexDO) error {
 	return deleteResource(col.collectionName, do)
 }
 
+func (col dataset) SetReviewStatus(do *repositories.ResourceIndexDO, status string) error {
+	return updateResourceReviewStatus(col.collectionName, do, status)
+}
+
 func (col dataset) UpdateProperty(do *repositories.DatasetPropertyDO) error {
 	p := &DatasetPropertyItem{
 		FL:       do.FL,
@@ -259,6 +263,7 @@ func (col dataset) toDatasetDO(owner string, item *datasetItem, do *repositories
 		Version:       item.Version,
 		LikeCount:     item.LikeCount,
 		DownloadCount: item.DownloadCount,
+		ReviewStatus:  item.ReviewStatus,
 
 		RelatedModels:   toResourceIndexDO(item.RelatedModels),
 		RelatedProjects: toResourceIndexDO(item.RelatedProjects),
@@ -53,6 +53,7 @@ const (
 	fieldTeams          = "teams"
 	fieldRepos          = "repos"
 	fieldOrder          = "order"
+	fieldReviewStatus   = "review_status"
 	fieldEnabled        = "enabled"
 	fieldCompetitors    = "competitors"
 	fieldSubmissions    = "submissions"
@@ -61,6 +62,15 @@ const (
 	fieldPictures       = "pictures"
 	fieldChoices        = "choices"
 	fieldCompletions    = "completions"
+	fieldRunning        = "running"
+	fieldQueued         = "queued"
+	fieldTrials         = "trials"
+	fieldPoints         = "points"
+	fieldRuns           = "runs"
+	fieldDone           = "done"
+	fieldPaused         = "paused"
+	fieldNextRunAt      = "next_run_at"
+	fieldNotices        = "notices"
 )
 
 type dProject struct {
@@ -90,6 +100,9 @@ type projectItem struct {
 	LikeCount     int `bson:"like_count"        json:"-"`
 	ForkCount     int `bson:"fork_count"        json:"-"`
 	DownloadCount int `bson:"download_count"    json:"-"`
+
+	// ReviewStatus is only set by the moderation, don't marshal it either.
+	ReviewStatus string `bson:"review_status" json:"-"`
 }
 
 type ProjectPropertyItem struct {
@@ -141,6 +154,9 @@ type modelItem struct {
 	Version       int `bson:"version"           json:"-"`
 	LikeCount     int `bson:"like_count"        json:"-"`
 	DownloadCount int `bson:"download_count"    json:"-"`
+
+	// ReviewStatus is only set by the moderation, don't marshal it either.
+	ReviewStatus string `bson:"review_status" json:"-"`
 }
 
 type ModelPropertyItem struct {
@@ -187,6 +203,9 @@ type datasetItem struct {
 	Version       int `bson:"version"               json:"-"`
 	LikeCount     int `bson:"like_count"            json:"-"`
 	DownloadCount int `bson:"download_count"        json:"-"`
+
+	// ReviewStatus is only set by the moderation, don't marshal it either.
+	ReviewStatus string `bson:"review_status" json:"-"`
 }
 
 type DatasetPropertyItem struct {
@@ -311,6 +330,7 @@ type trainingItem struct {
 	Env             []dKeyValue `bson:"env"           json:"env"`
 	Hyperparameters []dKeyValue `bson:"parameters"    json:"parameters"`
 	CreatedAt       int64       `bson:"created_at"    json:"created_at"`
+	Commit          string      `bson:"commit"        json:"commit"`
 	Job             dJobInfo    `bson:"job"           json:"-"`
 	JobDetail       dJobDetail  `bson:"detail"        json:"-"`
 }
@@ -559,3 +579,94 @@ type dFinetuneJobDetail struct {
 	Error    string `bson:"error"      json:"error,omitempty"`
 	Status   string `bson:"status"     json:"status,omitempty"`
 }
+
+type dTrainingQueue struct {
+	Owner   string            `bson:"owner"   json:"owner"`
+	Running []dQueuedTraining `bson:"running" json:"running"`
+	Queued  []dQueuedTraining `bson:"queued"  json:"queued"`
+	Version int               `bson:"version" json:"-"`
+}
+
+type dQueuedTraining struct {
+	ProjectId  string `bson:"pid"        json:"pid"`
+	TrainingId string `bson:"tid"        json:"tid"`
+	CreatedAt  int64  `bson:"created_at" json:"created_at,omitempty"`
+}
+
+type dTrainingSweep struct {
+	Id         string            `bson:"id"          json:"id"`
+	Owner      string            `bson:"owner"       json:"owner"`
+	ProjectId  string            `bson:"pid"         json:"pid"`
+	Name       string            `bson:"name"        json:"name"`
+	Strategy   string            `bson:"strategy"    json:"strategy"`
+	Metric     string            `bson:"metric"      json:"metric"`
+	Goal       string            `bson:"goal"        json:"goal"`
+	MaxTrials  int               `bson:"max_trials"  json:"max_trials"`
+	Parameters []dSweepParameter `bson:"parameters"  json:"parameters"`
+	Trials     []dSweepTrial     `bson:"trials"      json:"trials"`
+	CreatedAt  int64             `bson:"created_at"  json:"created_at"`
+	Version    int               `bson:"version"     json:"-"`
+}
+
+type dSweepParameter struct {
+	Key    string   `bson:"key"     json:"key"`
+	Values []string `bson:"values"  json:"values"`
+	Min    float64  `bson:"min"     json:"min"`
+	Max    float64  `bson:"max"     json:"max"`
+	Step   float64  `bson:"step"    json:"step"`
+}
+
+type dSweepTrial struct {
+	TrainingId      string      `bson:"tid"          json:"tid"`
+	Hyperparameters []dKeyValue `bson:"parameters"   json:"parameters"`
+}
+
+type dTrainingSchedule struct {
+	Id         string         `bson:"id"           json:"id"`
+	Owner      string         `bson:"owner"        json:"owner"`
+	ProjectId  string         `bson:"pid"          json:"pid"`
+	TrainingId string         `bson:"tid"          json:"tid"`
+	Spec       string         `bson:"spec"         json:"spec"`
+	Timezone   string         `bson:"timezone"     json:"timezone"`
+	Paused     bool           `bson:"paused"       json:"paused"`
+	NextRunAt  int64          `bson:"next_run_at"  json:"next_run_at"`
+	Runs       []dScheduleRun `bson:"runs"         json:"runs"`
+	CreatedAt  int64          `bson:"created_at"   json:"created_at"`
+	Version    int            `bson:"version"      json:"-"`
+}
+
+type dScheduleRun struct {
+	TrainingId string `bson:"tid"     json:"tid"`
+	RunAt      int64  `bson:"run_at"  json:"run_at"`
+	Status     string `bson:"status"  json:"status"`
+	Error      string `bson:"error"   json:"error"`
+	Done       bool   `bson:"done"    json:"done"`
+	Failed     bool   `bson:"failed"  json:"failed"`
+}
+
+type dTrainingScheduleNotices struct {
+	Owner   string                    `bson:"owner"    json:"owner"`
+	Notices []dTrainingScheduleNotice `bson:"notices"  json:"notices"`
+}
+
+type dTrainingScheduleNotice struct {
+	ScheduleId string `bson:"sid"         json:"sid"`
+	ProjectId  string `bson:"pid"         json:"pid"`
+	TrainingId string `bson:"tid"         json:"tid"`
+	Reason     string `bson:"reason"      json:"reason"`
+	CreatedAt  int64  `bson:"created_at"  json:"created_at"`
+}
+
+type dTrainingMetric struct {
+	Owner      string         `bson:"owner"   json:"owner"`
+	ProjectId  string         `bson:"pid"     json:"pid"`
+	TrainingId string         `bson:"tid"     json:"tid"`
+	Name       string         `bson:"name"    json:"name"`
+	Points     []dMetricPoint `bson:"points"  json:"points"`
+}
+
+type dMetricPoint struct {
+	Step       int     `bson:"step"        json:"step"`
+	Value      float64 `bson:"value"       json:"value"`
+	ReportedAt int64   `bson:"reported_at" json:"reported_at"`
+}
@@ -78,6 +78,10 @@ func (col model) Delete(do *repositories.ResourceIndexDO) error {
 	return deleteResource(col.collectionName, do)
 }
 
+func (col model) SetReviewStatus(do *repositories.ResourceIndexDO, status string) error {
+	return updateResourceReviewStatus(col.collectionName, do, status)
+}
+
 func (col model) UpdateProperty(do *repositories.ModelPropertyDO) error {
 	p := &ModelPropertyItem{
 		FL:       do.FL,
@@ -260,6 +264,7 @@ func (col model) toModelDO(owner string, item *modelItem, do *repositories.Model
 		Version:       item.Version,
 		LikeCount:     item.LikeCount,
 		DownloadCount: item.DownloadCount,
+		ReviewStatus:  item.ReviewStatus,
 
 		RelatedDatasets: toResourceIndexDO(item.RelatedDatasets),
 		RelatedProjects: toResourceIndexDO(item.RelatedProjects),
@@ -79,6 +79,10 @@ func (col project) Delete(do *repositories.ResourceIndexDO) error {
 	return deleteResource(col.collectionName, do)
 }
 
+func (col project) SetReviewStatus(do *repositories.ResourceIndexDO, status string) error {
+	return updateResourceReviewStatus(col.collectionName, do, status)
+}
+
 func (col project) UpdateProperty(do *repositories.ProjectPropertyDO) error {
 	p := &ProjectPropertyItem{
 		Level:    do.Level,
@@ -261,6 +265,7 @@ func (col project) toProjectDO(owner string, item *projectItem, do *repositories
 		LikeCount:     item.LikeCount,
 		ForkCount:     item.ForkCount,
 		DownloadCount: item.DownloadCount,
+		ReviewStatus:  item.ReviewStatus,
 
 		RelatedModels:   toResourceIndexDO(item.RelatedModels),
 		RelatedDatasets: toResourceIndexDO(item.RelatedDatasets),
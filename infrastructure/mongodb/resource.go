@@ -116,6 +116,29 @@ func updateResourceStatisticNum(
 	return err
 }
 
+func updateResourceReviewStatus(
+	collection string, r *repositories.ResourceIndexDO, status string,
+) error {
+	f := func(ctx context.Context) error {
+		_, err := cli.modifyArrayElemWithoutVersion(
+			ctx, collection, fieldItems,
+			resourceOwnerFilter(r.Owner), resourceIdFilter(r.Id),
+			bson.M{fieldReviewStatus: status}, mongoCmdSet,
+		)
+
+		return err
+	}
+
+	err := withContext(f)
+	if err != nil {
+		if isDocNotExists(err) {
+			err = repositories.NewErrorDataNotExists(err)
+		}
+	}
+
+	return err
+}
+
 func getResourceById(collection, owner, rid string, result interface{}) error {
 	f := func(ctx context.Context) error {
 		return cli.getArrayElem(
@@ -258,6 +281,12 @@ func listResourceWithoutSort(
 					))
 				}
 
+				if do.ExcludedReviewStatus != "" {
+					conds = append(conds, neCondForArrayElem(
+						fieldReviewStatus, do.ExcludedReviewStatus,
+					))
+				}
+
 				if do.Name != "" {
 					conds = append(conds, matchCondForArrayElem(
 						fieldName, do.Name,
@@ -303,6 +332,12 @@ func listGlobalResourceWithoutSort(
 			"cond": func() bson.M {
 				conds := bson.A{}
 
+				if do.ExcludedReviewStatus != "" {
+					conds = append(conds, neCondForArrayElem(
+						fieldReviewStatus, do.ExcludedReviewStatus,
+					))
+				}
+
 				if do.Level != 0 {
 					conds = append(conds, eqCondForArrayElem(
 						fieldLevel, do.Level,
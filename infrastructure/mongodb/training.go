@@ -127,6 +127,41 @@ func (col training) List(user, projectId string) ([]repositories.TrainingSummary
 	return r, v.Version, nil
 }
 
+func (col training) ListStatus(user string) ([]repositories.TrainingStatusDO, error) {
+	var v []dTraining
+
+	f := func(ctx context.Context) error {
+		return cli.getDocs(
+			ctx, col.collectionName,
+			bson.M{fieldOwner: user},
+			bson.M{
+				fieldPId:                     1,
+				subfieldOfItems(fieldId):     1,
+				subfieldOfItems(fieldDetail): 1,
+			}, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	var r []repositories.TrainingStatusDO
+
+	for i := range v {
+		for j := range v[i].Items {
+			item := &v[i].Items[j]
+
+			r = append(r, repositories.TrainingStatusDO{
+				ProjectId:  v[i].ProjectId,
+				TrainingId: item.Id,
+				Status:     item.JobDetail.Status,
+			})
+		}
+	}
+
+	return r, nil
+}
+
 func (col training) Delete(info *repositories.TrainingIndexDO) error {
 	f := func(ctx context.Context) error {
 		return cli.pullArrayElem(
@@ -17,6 +17,7 @@ func (col training) toTrainingDoc(do *repositories.UserTrainingDO) (bson.M, erro
 		CodeDir:         cfg.CodeDir,
 		BootFile:        cfg.BootFile,
 		CreatedAt:       do.CreatedAt,
+		Commit:          do.Commit,
 		Inputs:          col.toInputDoc(cfg.Inputs),
 		EnableAim:       do.EnableAim,
 		EnableOutput:    do.EnableOutput,
@@ -74,6 +75,7 @@ func (col training) toTrainingDetailDO(doc *dTraining, do *repositories.Training
 	item := &doc.Items[0]
 
 	do.CreatedAt = item.CreatedAt
+	do.Commit = item.Commit
 	col.toTrainingJobInfoDO(&item.Job, &do.Job)
 	col.toTrainingJobDetailDO(&item.JobDetail, &do.JobDetail)
 	col.toTrainingConfigDO(doc, &do.TrainingConfigDO)
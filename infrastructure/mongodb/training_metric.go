@@ -0,0 +1,142 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/xihe-server/infrastructure/repositories"
+)
+
+func NewTrainingMetricMapper(name string) repositories.TrainingMetricMapper {
+	return trainingMetric{name}
+}
+
+func trainingMetricDocFilter(do *repositories.TrainingMetricDO) bson.M {
+	return bson.M{
+		fieldOwner: do.Owner,
+		fieldPId:   do.ProjectId,
+		fieldTId:   do.TrainingId,
+		fieldName:  do.Name,
+	}
+}
+
+type trainingMetric struct {
+	collectionName string
+}
+
+func (col trainingMetric) newDoc(do *repositories.TrainingMetricDO) error {
+	doc := bson.M{
+		fieldOwner:  do.Owner,
+		fieldPId:    do.ProjectId,
+		fieldTId:    do.TrainingId,
+		fieldName:   do.Name,
+		fieldPoints: bson.A{},
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := cli.newDocIfNotExist(
+			ctx, col.collectionName, trainingMetricDocFilter(do), doc,
+		)
+
+		return err
+	}
+
+	if err := withContext(f); err != nil && isDBError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (col trainingMetric) Append(do *repositories.TrainingMetricDO, keep int) error {
+	err := col.append(do, keep)
+	if err == nil || !isDocNotExists(err) {
+		return err
+	}
+
+	// the first points of the metric
+	if err = col.newDoc(do); err != nil {
+		return err
+	}
+
+	return col.append(do, keep)
+}
+
+func (col trainingMetric) append(do *repositories.TrainingMetricDO, keep int) error {
+	points := make(bson.A, len(do.Points))
+	for i := range do.Points {
+		p := &do.Points[i]
+
+		doc, err := genDoc(dMetricPoint{
+			Step:       p.Step,
+			Value:      p.Value,
+			ReportedAt: p.ReportedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		points[i] = doc
+	}
+
+	f := func(ctx context.Context) error {
+		return cli.pushElemsToLimitedArray(
+			ctx, col.collectionName, fieldPoints, keep,
+			trainingMetricDocFilter(do), points,
+		)
+	}
+
+	return withContext(f)
+}
+
+func (col trainingMetric) List(owner, projectId string, trainingIds, names []string) (
+	[]repositories.TrainingMetricDO, error,
+) {
+	filter := bson.M{
+		fieldOwner: owner,
+		fieldPId:   projectId,
+		fieldTId:   bson.M{"$in": trainingIds},
+	}
+
+	if len(names) > 0 {
+		filter[fieldName] = bson.M{"$in": names}
+	}
+
+	var v []dTrainingMetric
+
+	f := func(ctx context.Context) error {
+		return cli.getDocs(ctx, col.collectionName, filter, nil, &v)
+	}
+
+	if err := withContext(f); err != nil || len(v) == 0 {
+		return nil, err
+	}
+
+	r := make([]repositories.TrainingMetricDO, len(v))
+	for i := range v {
+		col.toTrainingMetricDO(&v[i], &r[i])
+	}
+
+	return r, nil
+}
+
+func (col trainingMetric) toTrainingMetricDO(doc *dTrainingMetric, do *repositories.TrainingMetricDO) {
+	*do = repositories.TrainingMetricDO{
+		Owner:      doc.Owner,
+		ProjectId:  doc.ProjectId,
+		TrainingId: doc.TrainingId,
+		Name:       doc.Name,
+		Points:     make([]repositories.MetricPointDO, len(doc.Points)),
+	}
+
+	for i := range doc.Points {
+		p := &doc.Points[i]
+
+		do.Points[i] = repositories.MetricPointDO{
+			Step:       p.Step,
+			Value:      p.Value,
+			ReportedAt: p.ReportedAt,
+		}
+	}
+}
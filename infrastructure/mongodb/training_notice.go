@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/xihe-server/infrastructure/repositories"
+)
+
+func NewTrainingNoticeMapper(name string) repositories.TrainingNoticeMapper {
+	return trainingNotice{name}
+}
+
+type trainingNotice struct {
+	collectionName string
+}
+
+func (col trainingNotice) newDoc(owner string) error {
+	doc := bson.M{
+		fieldOwner:   owner,
+		fieldNotices: bson.A{},
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := cli.newDocIfNotExist(
+			ctx, col.collectionName, bson.M{fieldOwner: owner}, doc,
+		)
+
+		return err
+	}
+
+	if err := withContext(f); err != nil && isDBError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (col trainingNotice) Add(owner string, do *repositories.TrainingScheduleNoticeDO, keep int) error {
+	err := col.add(owner, do, keep)
+	if err == nil || !isDocNotExists(err) {
+		return err
+	}
+
+	// the first notice of the user
+	if err = col.newDoc(owner); err != nil {
+		return err
+	}
+
+	return col.add(owner, do, keep)
+}
+
+func (col trainingNotice) add(owner string, do *repositories.TrainingScheduleNoticeDO, keep int) error {
+	doc, err := genDoc(dTrainingScheduleNotice{
+		ScheduleId: do.ScheduleId,
+		ProjectId:  do.ProjectId,
+		TrainingId: do.TrainingId,
+		Reason:     do.Reason,
+		CreatedAt:  do.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		return cli.pushElemsToLimitedArray(
+			ctx, col.collectionName, fieldNotices, keep,
+			bson.M{fieldOwner: owner}, bson.A{doc},
+		)
+	}
+
+	return withContext(f)
+}
+
+func (col trainingNotice) List(owner string) ([]repositories.TrainingScheduleNoticeDO, error) {
+	var v dTrainingScheduleNotices
+
+	f := func(ctx context.Context) error {
+		return cli.getDoc(ctx, col.collectionName, bson.M{fieldOwner: owner}, nil, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		if isDocNotExists(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	r := make([]repositories.TrainingScheduleNoticeDO, len(v.Notices))
+	for i := range v.Notices {
+		item := &v.Notices[i]
+
+		r[i] = repositories.TrainingScheduleNoticeDO{
+			ScheduleId: item.ScheduleId,
+			ProjectId:  item.ProjectId,
+			TrainingId: item.TrainingId,
+			Reason:     item.Reason,
+			CreatedAt:  item.CreatedAt,
+		}
+	}
+
+	return r, nil
+}
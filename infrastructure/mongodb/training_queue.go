@@ -0,0 +1,127 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/xihe-server/infrastructure/repositories"
+)
+
+func NewTrainingQueueMapper(name string) repositories.TrainingQueueMapper {
+	return trainingQueue{name}
+}
+
+type trainingQueue struct {
+	collectionName string
+}
+
+func (col trainingQueue) newDoc(owner string) error {
+	doc := bson.M{
+		fieldOwner:   owner,
+		fieldRunning: bson.A{},
+		fieldQueued:  bson.A{},
+		fieldVersion: 0,
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := cli.newDocIfNotExist(
+			ctx, col.collectionName, resourceOwnerFilter(owner), doc,
+		)
+
+		return err
+	}
+
+	if err := withContext(f); err != nil && isDBError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (col trainingQueue) Get(owner string) (do repositories.TrainingQueueDO, err error) {
+	var v dTrainingQueue
+
+	f := func(ctx context.Context) error {
+		return cli.getDoc(
+			ctx, col.collectionName, resourceOwnerFilter(owner), nil, &v,
+		)
+	}
+
+	if err = withContext(f); err != nil {
+		if !isDocNotExists(err) {
+			return
+		}
+
+		// the queue is saved with version 0 later
+		if err = col.newDoc(owner); err != nil {
+			return
+		}
+	}
+
+	col.toTrainingQueueDO(&v, &do)
+	do.Owner = owner
+
+	return
+}
+
+func (col trainingQueue) Update(do *repositories.TrainingQueueDO) error {
+	v := bson.M{
+		fieldRunning: col.toQueuedTrainingDocs(do.Running),
+		fieldQueued:  col.toQueuedTrainingDocs(do.Queued),
+	}
+
+	f := func(ctx context.Context) error {
+		return cli.updateDoc(
+			ctx, col.collectionName, resourceOwnerFilter(do.Owner),
+			v, mongoCmdSet, do.Version,
+		)
+	}
+
+	if err := withContext(f); err != nil {
+		if isDocNotExists(err) {
+			return repositories.NewErrorConcurrentUpdating(err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (col trainingQueue) toQueuedTrainingDocs(v []repositories.QueuedTrainingDO) bson.A {
+	r := make(bson.A, len(v))
+
+	for i := range v {
+		r[i] = bson.M{
+			fieldPId:       v[i].ProjectId,
+			fieldTId:       v[i].TrainingId,
+			fieldCreatedAt: v[i].CreatedAt,
+		}
+	}
+
+	return r
+}
+
+func (col trainingQueue) toTrainingQueueDO(doc *dTrainingQueue, do *repositories.TrainingQueueDO) {
+	f := func(v []dQueuedTraining) []repositories.QueuedTrainingDO {
+		r := make([]repositories.QueuedTrainingDO, len(v))
+
+		for i := range v {
+			r[i] = repositories.QueuedTrainingDO{
+				ProjectId:  v[i].ProjectId,
+				TrainingId: v[i].TrainingId,
+				CreatedAt:  v[i].CreatedAt,
+			}
+		}
+
+		return r
+	}
+
+	*do = repositories.TrainingQueueDO{
+		Owner:   doc.Owner,
+		Running: f(doc.Running),
+		Queued:  f(doc.Queued),
+		Version: doc.Version,
+	}
+}
@@ -0,0 +1,199 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/xihe-server/infrastructure/repositories"
+)
+
+func NewTrainingScheduleMapper(name string) repositories.TrainingScheduleMapper {
+	return trainingSchedule{name}
+}
+
+func trainingScheduleDocFilter(owner, scheduleId string) bson.M {
+	return bson.M{
+		fieldOwner: owner,
+		fieldId:    scheduleId,
+	}
+}
+
+type trainingSchedule struct {
+	collectionName string
+}
+
+func (col trainingSchedule) Insert(do *repositories.TrainingScheduleDO) (string, error) {
+	do.Id = newId()
+
+	doc, err := genDoc(col.toTrainingScheduleDoc(do))
+	if err != nil {
+		return "", err
+	}
+	doc[fieldVersion] = 0
+
+	f := func(ctx context.Context) error {
+		_, err := cli.newDocIfNotExist(
+			ctx, col.collectionName, resourceIdFilter(do.Id), doc,
+		)
+
+		return err
+	}
+
+	if err = withContext(f); err != nil {
+		if isDocExists(err) {
+			err = repositories.NewErrorDuplicateCreating(err)
+		}
+
+		return "", err
+	}
+
+	return do.Id, nil
+}
+
+func (col trainingSchedule) Get(owner, scheduleId string) (
+	do repositories.TrainingScheduleDO, err error,
+) {
+	var v dTrainingSchedule
+
+	f := func(ctx context.Context) error {
+		return cli.getDoc(
+			ctx, col.collectionName,
+			trainingScheduleDocFilter(owner, scheduleId), nil, &v,
+		)
+	}
+
+	if err = withContext(f); err != nil {
+		if isDocNotExists(err) {
+			err = repositories.NewErrorDataNotExists(err)
+		}
+
+		return
+	}
+
+	col.toTrainingScheduleDO(&v, &do)
+
+	return
+}
+
+func (col trainingSchedule) List(owner, projectId string) ([]repositories.TrainingScheduleDO, error) {
+	return col.find(trainingDocFilter(owner, projectId))
+}
+
+func (col trainingSchedule) Delete(owner, scheduleId string) error {
+	f := func(ctx context.Context) error {
+		return cli.deleteDoc(
+			ctx, col.collectionName,
+			trainingScheduleDocFilter(owner, scheduleId),
+		)
+	}
+
+	if err := withContext(f); err != nil {
+		if isDocNotExists(err) {
+			return repositories.NewErrorDataNotExists(err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (col trainingSchedule) FindToHandle(now int64) ([]repositories.TrainingScheduleDO, error) {
+	return col.find(bson.M{
+		"$or": bson.A{
+			bson.M{
+				fieldPaused:    false,
+				fieldNextRunAt: bson.M{"$gt": 0, "$lte": now},
+			},
+			bson.M{
+				fieldRuns: bson.M{"$elemMatch": bson.M{fieldDone: false}},
+			},
+		},
+	})
+}
+
+func (col trainingSchedule) find(filter bson.M) ([]repositories.TrainingScheduleDO, error) {
+	var v []dTrainingSchedule
+
+	f := func(ctx context.Context) error {
+		return cli.getDocs(ctx, col.collectionName, filter, nil, &v)
+	}
+
+	if err := withContext(f); err != nil || len(v) == 0 {
+		return nil, err
+	}
+
+	r := make([]repositories.TrainingScheduleDO, len(v))
+	for i := range v {
+		col.toTrainingScheduleDO(&v[i], &r[i])
+	}
+
+	return r, nil
+}
+
+func (col trainingSchedule) Update(do *repositories.TrainingScheduleDO) error {
+	doc, err := genDoc(col.toTrainingScheduleDoc(do))
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		return cli.updateDoc(
+			ctx, col.collectionName,
+			trainingScheduleDocFilter(do.Owner, do.Id),
+			doc, mongoCmdSet, do.Version,
+		)
+	}
+
+	if err := withContext(f); err != nil {
+		if isDocNotExists(err) {
+			return repositories.NewErrorConcurrentUpdating(err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (col trainingSchedule) toTrainingScheduleDoc(do *repositories.TrainingScheduleDO) dTrainingSchedule {
+	doc := dTrainingSchedule{
+		Id:         do.Id,
+		Owner:      do.Owner,
+		ProjectId:  do.ProjectId,
+		TrainingId: do.TrainingId,
+		Spec:       do.Spec,
+		Timezone:   do.Timezone,
+		Paused:     do.Paused,
+		NextRunAt:  do.NextRunAt,
+		Runs:       make([]dScheduleRun, len(do.Runs)),
+		CreatedAt:  do.CreatedAt,
+	}
+
+	for i := range do.Runs {
+		doc.Runs[i] = dScheduleRun(do.Runs[i])
+	}
+
+	return doc
+}
+
+func (col trainingSchedule) toTrainingScheduleDO(doc *dTrainingSchedule, do *repositories.TrainingScheduleDO) {
+	*do = repositories.TrainingScheduleDO{
+		Id:         doc.Id,
+		Owner:      doc.Owner,
+		ProjectId:  doc.ProjectId,
+		TrainingId: doc.TrainingId,
+		Spec:       doc.Spec,
+		Timezone:   doc.Timezone,
+		Paused:     doc.Paused,
+		NextRunAt:  doc.NextRunAt,
+		Runs:       make([]repositories.ScheduleRunDO, len(doc.Runs)),
+		CreatedAt:  doc.CreatedAt,
+		Version:    doc.Version,
+	}
+
+	for i := range doc.Runs {
+		do.Runs[i] = repositories.ScheduleRunDO(doc.Runs[i])
+	}
+}
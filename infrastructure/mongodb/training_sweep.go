@@ -0,0 +1,192 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/xihe-server/infrastructure/repositories"
+)
+
+func NewTrainingSweepMapper(name string) repositories.TrainingSweepMapper {
+	return trainingSweep{name}
+}
+
+func trainingSweepDocFilter(owner, sweepId string) bson.M {
+	return bson.M{
+		fieldOwner: owner,
+		fieldId:    sweepId,
+	}
+}
+
+type trainingSweep struct {
+	collectionName string
+}
+
+func (col trainingSweep) Insert(do *repositories.TrainingSweepDO) (string, error) {
+	do.Id = newId()
+
+	doc, err := genDoc(col.toTrainingSweepDoc(do))
+	if err != nil {
+		return "", err
+	}
+	doc[fieldVersion] = 0
+
+	f := func(ctx context.Context) error {
+		_, err := cli.newDocIfNotExist(
+			ctx, col.collectionName, resourceIdFilter(do.Id), doc,
+		)
+
+		return err
+	}
+
+	if err = withContext(f); err != nil {
+		if isDocExists(err) {
+			err = repositories.NewErrorDuplicateCreating(err)
+		}
+
+		return "", err
+	}
+
+	return do.Id, nil
+}
+
+func (col trainingSweep) Get(owner, sweepId string) (
+	do repositories.TrainingSweepDO, err error,
+) {
+	var v dTrainingSweep
+
+	f := func(ctx context.Context) error {
+		return cli.getDoc(
+			ctx, col.collectionName,
+			trainingSweepDocFilter(owner, sweepId), nil, &v,
+		)
+	}
+
+	if err = withContext(f); err != nil {
+		if isDocNotExists(err) {
+			err = repositories.NewErrorDataNotExists(err)
+		}
+
+		return
+	}
+
+	col.toTrainingSweepDO(&v, &do)
+
+	return
+}
+
+func (col trainingSweep) List(owner, projectId string) ([]repositories.TrainingSweepDO, error) {
+	var v []dTrainingSweep
+
+	f := func(ctx context.Context) error {
+		return cli.getDocs(
+			ctx, col.collectionName,
+			trainingDocFilter(owner, projectId), nil, &v,
+		)
+	}
+
+	if err := withContext(f); err != nil || len(v) == 0 {
+		return nil, err
+	}
+
+	r := make([]repositories.TrainingSweepDO, len(v))
+	for i := range v {
+		col.toTrainingSweepDO(&v[i], &r[i])
+	}
+
+	return r, nil
+}
+
+func (col trainingSweep) toTrainingSweepDoc(do *repositories.TrainingSweepDO) dTrainingSweep {
+	doc := dTrainingSweep{
+		Id:         do.Id,
+		Owner:      do.Owner,
+		ProjectId:  do.ProjectId,
+		Name:       do.Name,
+		Strategy:   do.Strategy,
+		Metric:     do.Metric,
+		Goal:       do.Goal,
+		MaxTrials:  do.MaxTrials,
+		Parameters: make([]dSweepParameter, len(do.Parameters)),
+		Trials:     make([]dSweepTrial, len(do.Trials)),
+		CreatedAt:  do.CreatedAt,
+	}
+
+	for i := range do.Parameters {
+		p := &do.Parameters[i]
+
+		doc.Parameters[i] = dSweepParameter{
+			Key:    p.Key,
+			Values: p.Values,
+			Min:    p.Min,
+			Max:    p.Max,
+			Step:   p.Step,
+		}
+	}
+
+	for i := range do.Trials {
+		t := &do.Trials[i]
+
+		kv := make([]dKeyValue, len(t.Hyperparameters))
+		for j := range t.Hyperparameters {
+			kv[j] = dKeyValue{
+				Key:   t.Hyperparameters[j].Key,
+				Value: t.Hyperparameters[j].Value,
+			}
+		}
+
+		doc.Trials[i] = dSweepTrial{
+			TrainingId:      t.TrainingId,
+			Hyperparameters: kv,
+		}
+	}
+
+	return doc
+}
+
+func (col trainingSweep) toTrainingSweepDO(doc *dTrainingSweep, do *repositories.TrainingSweepDO) {
+	*do = repositories.TrainingSweepDO{
+		Id:         doc.Id,
+		Owner:      doc.Owner,
+		ProjectId:  doc.ProjectId,
+		Name:       doc.Name,
+		Strategy:   doc.Strategy,
+		Metric:     doc.Metric,
+		Goal:       doc.Goal,
+		MaxTrials:  doc.MaxTrials,
+		Parameters: make([]repositories.SweepParameterDO, len(doc.Parameters)),
+		Trials:     make([]repositories.SweepTrialDO, len(doc.Trials)),
+		CreatedAt:  doc.CreatedAt,
+		Version:    doc.Version,
+	}
+
+	for i := range doc.Parameters {
+		p := &doc.Parameters[i]
+
+		do.Parameters[i] = repositories.SweepParameterDO{
+			Key:    p.Key,
+			Values: p.Values,
+			Min:    p.Min,
+			Max:    p.Max,
+			Step:   p.Step,
+		}
+	}
+
+	for i := range doc.Trials {
+		t := &doc.Trials[i]
+
+		kv := make([]repositories.KeyValueDO, len(t.Hyperparameters))
+		for j := range t.Hyperparameters {
+			kv[j] = repositories.KeyValueDO{
+				Key:   t.Hyperparameters[j].Key,
+				Value: t.Hyperparameters[j].Value,
+			}
+		}
+
+		do.Trials[i] = repositories.SweepTrialDO{
+			TrainingId:      t.TrainingId,
+			Hyperparameters: kv,
+		}
+	}
+}
@@ -238,6 +238,21 @@ func (cli *client) getDocs(
 	return cursor.All(ctx, result)
 }
 
+func (cli *client) deleteDoc(
+	ctx context.Context, collection string, filterOfDoc bson.M,
+) error {
+	r, err := cli.collection(collection).DeleteOne(ctx, filterOfDoc)
+	if err != nil {
+		return dbError{err}
+	}
+
+	if r.DeletedCount == 0 {
+		return errDocNotExists
+	}
+
+	return nil
+}
+
 func (cli *client) addToSimpleArray(
 	ctx context.Context, collection, array string,
 	filterOfDoc, value interface{},
@@ -324,6 +339,30 @@ func (cli *client) pushElemToLimitedArray(
 	return nil
 }
 
+// pushElemsToLimitedArray appends the values and keeps the last keep elements of array
+func (cli *client) pushElemsToLimitedArray(
+	ctx context.Context,
+	collection, array string, keep int,
+	filterOfDoc bson.M, values bson.A,
+) error {
+	r, err := cli.collection(collection).UpdateOne(
+		ctx, filterOfDoc,
+		bson.M{mongoCmdPush: bson.M{array: bson.M{
+			"$each":  values,
+			"$slice": -keep,
+		}}},
+	)
+	if err != nil {
+		return dbError{err}
+	}
+
+	if r.MatchedCount == 0 {
+		return errDocNotExists
+	}
+
+	return nil
+}
+
 func (cli *client) pushElemToLimitedArrayWithVersion(
 	ctx context.Context,
 	collection, array string, keep int,
@@ -726,6 +765,10 @@ func eqCondForArrayElem(key string, value interface{}) bson.M {
 	return bson.M{"$eq": bson.A{condFieldOfArrayElem(key), value}}
 }
 
+func neCondForArrayElem(key string, value interface{}) bson.M {
+	return bson.M{"$ne": bson.A{condFieldOfArrayElem(key), value}}
+}
+
 func inCondForArrayElem(key string, value interface{}) bson.M {
 	return bson.M{"$in": bson.A{condFieldOfArrayElem(key), value}}
 }
@@ -12,6 +12,7 @@ import (
 type DatasetMapper interface {
 	Insert(DatasetDO) (string, error)
 	Delete(*ResourceIndexDO) error
+	SetReviewStatus(*ResourceIndexDO, string) error
 	Get(string, string) (DatasetDO, error)
 	GetByName(string, string) (DatasetDO, error)
 	GetSummaryByName(string, string) (ResourceSummaryDO, error)
@@ -79,6 +80,16 @@ func (impl dataset) Delete(index *domain.ResourceIndex) (err error) {
 	return
 }
 
+func (impl dataset) SetReviewStatus(index *domain.ResourceIndex, status string) (err error) {
+	do := toResourceIndexDO(index)
+
+	if err = impl.mapper.SetReviewStatus(&do, status); err != nil {
+		err = convertError(err)
+	}
+
+	return
+}
+
 func (impl dataset) Get(owner domain.Account, identity string) (r domain.Dataset, err error) {
 	v, err := impl.mapper.Get(owner.Account(), identity)
 	if err != nil {
@@ -219,6 +230,7 @@ type DatasetDO struct {
 	Version       int
 	LikeCount     int
 	DownloadCount int
+	ReviewStatus  string
 
 	RelatedModels   []ResourceIndexDO
 	RelatedProjects []ResourceIndexDO
@@ -267,6 +279,7 @@ func (do *DatasetDO) toDataset(r *domain.Dataset) (err error) {
 	r.UpdatedAt = do.UpdatedAt
 	r.LikeCount = do.LikeCount
 	r.DownloadCount = do.DownloadCount
+	r.ReviewStatus = do.ReviewStatus
 
 	return
 }
@@ -16,6 +16,8 @@ func searchOptionToListDO(
 		}
 	}
 
+	do.ExcludedReviewStatus = domain.ReviewStatusHidden
+
 	return
 }
 
@@ -30,6 +32,7 @@ func toGlobalResourceListDO(
 	opt *repository.GlobalResourceListOption,
 ) (do GlobalResourceListDO) {
 	do.ResourceListDO = toResourceListDO(&opt.ResourceListOption)
+	do.ExcludedReviewStatus = domain.ReviewStatusHidden
 
 	if opt.Level != nil {
 		do.Level = opt.Level.Int()
@@ -12,6 +12,7 @@ import (
 type ModelMapper interface {
 	Insert(ModelDO) (string, error)
 	Delete(*ResourceIndexDO) error
+	SetReviewStatus(*ResourceIndexDO, string) error
 	Get(string, string) (ModelDO, error)
 	GetByName(string, string) (ModelDO, error)
 	GetSummaryByName(string, string) (ResourceSummaryDO, error)
@@ -79,6 +80,16 @@ func (impl model) Delete(index *domain.ResourceIndex) (err error) {
 	return
 }
 
+func (impl model) SetReviewStatus(index *domain.ResourceIndex, status string) (err error) {
+	do := toResourceIndexDO(index)
+
+	if err = impl.mapper.SetReviewStatus(&do, status); err != nil {
+		err = convertError(err)
+	}
+
+	return
+}
+
 func (impl model) Get(owner domain.Account, identity string) (r domain.Model, err error) {
 	v, err := impl.mapper.Get(owner.Account(), identity)
 	if err != nil {
@@ -221,6 +232,7 @@ type ModelDO struct {
 	Version       int
 	LikeCount     int
 	DownloadCount int
+	ReviewStatus  string
 
 	RelatedDatasets []ResourceIndexDO
 	RelatedProjects []ResourceIndexDO
@@ -269,6 +281,7 @@ func (do *ModelDO) toModel(r *domain.Model) (err error) {
 	r.UpdatedAt = do.UpdatedAt
 	r.LikeCount = do.LikeCount
 	r.DownloadCount = do.DownloadCount
+	r.ReviewStatus = do.ReviewStatus
 
 	return
 }
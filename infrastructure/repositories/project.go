@@ -10,6 +10,7 @@ import (
 type ProjectMapper interface {
 	Insert(ProjectDO) (string, error)
 	Delete(*ResourceIndexDO) error
+	SetReviewStatus(*ResourceIndexDO, string) error
 	Get(string, string) (ProjectDO, error)
 	GetByName(string, string) (ProjectDO, error)
 	GetSummary(string, string) (ProjectResourceSummaryDO, error)
@@ -78,6 +79,16 @@ func (impl project) Delete(index *domain.ResourceIndex) (err error) {
 	return
 }
 
+func (impl project) SetReviewStatus(index *domain.ResourceIndex, status string) (err error) {
+	do := toResourceIndexDO(index)
+
+	if err = impl.mapper.SetReviewStatus(&do, status); err != nil {
+		err = convertError(err)
+	}
+
+	return
+}
+
 func (impl project) Get(owner domain.Account, identity string) (r domain.Project, err error) {
 	v, err := impl.mapper.Get(owner.Account(), identity)
 	if err != nil {
@@ -204,6 +215,7 @@ type ProjectDO struct {
 	LikeCount     int
 	ForkCount     int
 	DownloadCount int
+	ReviewStatus  string
 
 	RelatedModels   []ResourceIndexDO
 	RelatedDatasets []ResourceIndexDO
@@ -266,6 +278,7 @@ func (do *ProjectDO) toProject(r *domain.Project) (err error) {
 	r.LikeCount = do.LikeCount
 	r.ForkCount = do.ForkCount
 	r.DownloadCount = do.DownloadCount
+	r.ReviewStatus = do.ReviewStatus
 
 	return
 }
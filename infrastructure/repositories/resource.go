@@ -10,6 +10,9 @@ type ResourceListDO struct {
 	RepoType     []string
 	PageNum      int
 	CountPerPage int
+
+	// ExcludedReviewStatus filters out the resources in this review status
+	ExcludedReviewStatus string
 }
 
 func toResourceListDO(r *repository.ResourceListOption) ResourceListDO {
@@ -23,6 +26,9 @@ func toResourceListDO(r *repository.ResourceListOption) ResourceListDO {
 		for i := range r.RepoType {
 			do.RepoType = append(do.RepoType, r.RepoType[i].RepoType())
 		}
+
+		// it is listed for the visitor who can't see the hidden resources
+		do.ExcludedReviewStatus = domain.ReviewStatusHidden
 	}
 
 	return do
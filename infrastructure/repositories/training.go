@@ -13,6 +13,7 @@ type TrainingMapper interface {
 	Get(*TrainingIndexDO) (TrainingDetailDO, error)
 	GetTrainingConfig(*TrainingIndexDO) (TrainingConfigDO, error)
 	List(user, projectId string) ([]TrainingSummaryDO, int, error)
+	ListStatus(user string) ([]TrainingStatusDO, error)
 	UpdateJobInfo(*TrainingIndexDO, *TrainingJobInfoDO) error
 	GetJobInfo(*TrainingIndexDO) (TrainingJobInfoDO, error)
 	UpdateJobDetail(*TrainingIndexDO, *TrainingJobDetailDO) error
@@ -100,6 +101,29 @@ func (impl training) List(user domain.Account, projectId string) (
 	return
 }
 
+func (impl training) ListStatus(user domain.Account) ([]repository.TrainingStatus, error) {
+	v, err := impl.mapper.ListStatus(user.Account())
+	if err != nil || len(v) == 0 {
+		return nil, convertError(err)
+	}
+
+	r := make([]repository.TrainingStatus, len(v))
+	for i := range v {
+		r[i] = repository.TrainingStatus{
+			TrainingIndex: domain.TrainingIndex{
+				Project: domain.ResourceIndex{
+					Owner: user,
+					Id:    v[i].ProjectId,
+				},
+				TrainingId: v[i].TrainingId,
+			},
+			Status: v[i].Status,
+		}
+	}
+
+	return r, nil
+}
+
 func (impl training) SaveJob(info *domain.TrainingIndex, job *domain.JobInfo) error {
 	do := impl.toTrainingIndexDO(info)
 
@@ -10,6 +10,7 @@ type UserTrainingDO struct {
 	TrainingConfigDO
 
 	CreatedAt int64
+	Commit    string
 }
 
 type TrainingConfigDO struct {
@@ -180,6 +181,7 @@ func (impl training) toUserTrainingDO(ut *domain.UserTraining) UserTrainingDO {
 		Owner:     ut.Owner.Account(),
 		ProjectId: ut.ProjectId,
 		CreatedAt: ut.CreatedAt,
+		Commit:    ut.Commit,
 
 		TrainingConfigDO: TrainingConfigDO{
 			Name:          t.Name.TrainingName(),
@@ -282,6 +284,12 @@ func (do *TrainingSummaryDO) toTrainingSummary(t *domain.TrainingSummary) (
 	return
 }
 
+type TrainingStatusDO struct {
+	ProjectId  string
+	TrainingId string
+	Status     string
+}
+
 type TrainingIndexDO struct {
 	User       string
 	ProjectId  string
@@ -305,6 +313,7 @@ type TrainingDetailDO struct {
 	Job       TrainingJobInfoDO
 	JobDetail TrainingJobDetailDO
 	CreatedAt int64
+	Commit    string
 }
 
 func (do *TrainingDetailDO) toUserTraining(
@@ -317,6 +326,7 @@ func (do *TrainingDetailDO) toUserTraining(
 	ut.Job = do.Job
 	ut.JobDetail = do.JobDetail
 	ut.CreatedAt = do.CreatedAt
+	ut.Commit = do.Commit
 
 	ut.Id = index.TrainingId
 	ut.Owner = index.Project.Owner
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+)
+
+type TrainingMetricMapper interface {
+	Append(do *TrainingMetricDO, keep int) error
+	List(owner, projectId string, trainingIds, names []string) ([]TrainingMetricDO, error)
+}
+
+func NewTrainingMetricRepository(mapper TrainingMetricMapper) repository.TrainingMetric {
+	return trainingMetric{mapper}
+}
+
+type trainingMetric struct {
+	mapper TrainingMetricMapper
+}
+
+func (impl trainingMetric) Append(m *domain.TrainingMetric) error {
+	do := impl.toTrainingMetricDO(m)
+
+	return convertError(impl.mapper.Append(&do, domain.DomainConfig.MaxTrainingMetricPoints))
+}
+
+func (impl trainingMetric) List(
+	project *domain.ResourceIndex, trainingIds, names []string,
+) ([]domain.TrainingMetric, error) {
+	v, err := impl.mapper.List(project.Owner.Account(), project.Id, trainingIds, names)
+	if err != nil || len(v) == 0 {
+		return nil, convertError(err)
+	}
+
+	r := make([]domain.TrainingMetric, len(v))
+	for i := range v {
+		if err := v[i].toTrainingMetric(project, &r[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (impl trainingMetric) toTrainingMetricDO(m *domain.TrainingMetric) TrainingMetricDO {
+	do := TrainingMetricDO{
+		Owner:      m.Project.Owner.Account(),
+		ProjectId:  m.Project.Id,
+		TrainingId: m.TrainingId,
+		Name:       m.Name.CustomizedKey(),
+		Points:     make([]MetricPointDO, len(m.Points)),
+	}
+
+	for i := range m.Points {
+		p := &m.Points[i]
+
+		do.Points[i] = MetricPointDO{
+			Step:       p.Step,
+			Value:      p.Value,
+			ReportedAt: p.ReportedAt,
+		}
+	}
+
+	return do
+}
+
+type TrainingMetricDO struct {
+	Owner      string
+	ProjectId  string
+	TrainingId string
+	Name       string
+	Points     []MetricPointDO
+}
+
+func (do *TrainingMetricDO) toTrainingMetric(
+	project *domain.ResourceIndex, m *domain.TrainingMetric,
+) (err error) {
+	m.TrainingIndex = domain.TrainingIndex{
+		Project:    *project,
+		TrainingId: do.TrainingId,
+	}
+
+	if m.Name, err = domain.NewCustomizedKey(do.Name); err != nil {
+		return
+	}
+
+	m.Points = make([]domain.MetricPoint, len(do.Points))
+	for i := range do.Points {
+		p := &do.Points[i]
+
+		m.Points[i] = domain.MetricPoint{
+			Step:       p.Step,
+			Value:      p.Value,
+			ReportedAt: p.ReportedAt,
+		}
+	}
+
+	return
+}
+
+type MetricPointDO struct {
+	Step       int
+	Value      float64
+	ReportedAt int64
+}
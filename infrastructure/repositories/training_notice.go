@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+)
+
+type TrainingNoticeMapper interface {
+	Add(owner string, do *TrainingScheduleNoticeDO, keep int) error
+	List(owner string) ([]TrainingScheduleNoticeDO, error)
+}
+
+func NewTrainingNoticeRepository(mapper TrainingNoticeMapper) repository.TrainingScheduleNotice {
+	return trainingNotice{mapper}
+}
+
+type trainingNotice struct {
+	mapper TrainingNoticeMapper
+}
+
+func (impl trainingNotice) Add(owner domain.Account, v *domain.TrainingScheduleNotice) error {
+	do := TrainingScheduleNoticeDO{
+		ScheduleId: v.ScheduleId,
+		ProjectId:  v.ProjectId,
+		TrainingId: v.TrainingId,
+		Reason:     v.Reason,
+		CreatedAt:  v.CreatedAt,
+	}
+
+	err := impl.mapper.Add(
+		owner.Account(), &do, domain.DomainConfig.MaxTrainingScheduleNotices,
+	)
+
+	return convertError(err)
+}
+
+func (impl trainingNotice) List(owner domain.Account) ([]domain.TrainingScheduleNotice, error) {
+	v, err := impl.mapper.List(owner.Account())
+	if err != nil || len(v) == 0 {
+		return nil, convertError(err)
+	}
+
+	r := make([]domain.TrainingScheduleNotice, len(v))
+	for i := range v {
+		item := &v[i]
+
+		r[i] = domain.TrainingScheduleNotice{
+			ScheduleId: item.ScheduleId,
+			ProjectId:  item.ProjectId,
+			TrainingId: item.TrainingId,
+			Reason:     item.Reason,
+			CreatedAt:  item.CreatedAt,
+		}
+	}
+
+	return r, nil
+}
+
+type TrainingScheduleNoticeDO struct {
+	ScheduleId string
+	ProjectId  string
+	TrainingId string
+	Reason     string
+	CreatedAt  int64
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+)
+
+type TrainingQueueMapper interface {
+	Get(owner string) (TrainingQueueDO, error)
+	Update(*TrainingQueueDO) error
+}
+
+func NewTrainingQueueRepository(mapper TrainingQueueMapper) repository.TrainingQueue {
+	return trainingQueue{mapper}
+}
+
+type trainingQueue struct {
+	mapper TrainingQueueMapper
+}
+
+func (impl trainingQueue) Get(owner domain.Account) (q domain.TrainingQueue, err error) {
+	v, err := impl.mapper.Get(owner.Account())
+	if err != nil {
+		err = convertError(err)
+
+		return
+	}
+
+	v.toTrainingQueue(owner, &q)
+
+	return
+}
+
+func (impl trainingQueue) Save(q *domain.TrainingQueue) error {
+	do := impl.toTrainingQueueDO(q)
+
+	return convertError(impl.mapper.Update(&do))
+}
+
+func (impl trainingQueue) toTrainingQueueDO(q *domain.TrainingQueue) TrainingQueueDO {
+	do := TrainingQueueDO{
+		Owner:   q.Owner.Account(),
+		Running: make([]QueuedTrainingDO, len(q.Running)),
+		Queued:  make([]QueuedTrainingDO, len(q.Queued)),
+		Version: q.Version,
+	}
+
+	for i := range q.Running {
+		do.Running[i] = QueuedTrainingDO{
+			ProjectId:  q.Running[i].Project.Id,
+			TrainingId: q.Running[i].TrainingId,
+		}
+	}
+
+	for i := range q.Queued {
+		item := &q.Queued[i]
+
+		do.Queued[i] = QueuedTrainingDO{
+			ProjectId:  item.Project.Id,
+			TrainingId: item.TrainingId,
+			CreatedAt:  item.CreatedAt,
+		}
+	}
+
+	return do
+}
+
+type TrainingQueueDO struct {
+	Owner   string
+	Running []QueuedTrainingDO
+	Queued  []QueuedTrainingDO
+	Version int
+}
+
+func (do *TrainingQueueDO) toTrainingQueue(owner domain.Account, q *domain.TrainingQueue) {
+	*q = domain.TrainingQueue{
+		Owner:   owner,
+		Version: do.Version,
+	}
+
+	if n := len(do.Running); n > 0 {
+		q.Running = make([]domain.TrainingIndex, n)
+
+		for i := range do.Running {
+			q.Running[i] = do.Running[i].toTrainingIndex(owner)
+		}
+	}
+
+	if n := len(do.Queued); n > 0 {
+		q.Queued = make([]domain.QueuedTraining, n)
+
+		for i := range do.Queued {
+			q.Queued[i] = domain.QueuedTraining{
+				TrainingIndex: do.Queued[i].toTrainingIndex(owner),
+				CreatedAt:     do.Queued[i].CreatedAt,
+			}
+		}
+	}
+}
+
+type QueuedTrainingDO struct {
+	ProjectId  string
+	TrainingId string
+	CreatedAt  int64
+}
+
+func (do *QueuedTrainingDO) toTrainingIndex(owner domain.Account) domain.TrainingIndex {
+	return domain.TrainingIndex{
+		Project: domain.ResourceIndex{
+			Owner: owner,
+			Id:    do.ProjectId,
+		},
+		TrainingId: do.TrainingId,
+	}
+}
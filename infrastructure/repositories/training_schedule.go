@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+)
+
+type TrainingScheduleMapper interface {
+	Insert(*TrainingScheduleDO) (string, error)
+	Get(owner, scheduleId string) (TrainingScheduleDO, error)
+	List(owner, projectId string) ([]TrainingScheduleDO, error)
+	Delete(owner, scheduleId string) error
+	FindToHandle(now int64) ([]TrainingScheduleDO, error)
+	Update(*TrainingScheduleDO) error
+}
+
+func NewTrainingScheduleRepository(mapper TrainingScheduleMapper) repository.TrainingSchedule {
+	return trainingSchedule{mapper}
+}
+
+type trainingSchedule struct {
+	mapper TrainingScheduleMapper
+}
+
+func (impl trainingSchedule) Save(s *domain.TrainingSchedule) (string, error) {
+	if s.Id != "" {
+		return "", errors.New("must be a new schedule")
+	}
+
+	do := impl.toTrainingScheduleDO(s)
+
+	v, err := impl.mapper.Insert(&do)
+	if err != nil {
+		return "", convertError(err)
+	}
+
+	return v, nil
+}
+
+func (impl trainingSchedule) Get(owner domain.Account, scheduleId string) (
+	s domain.TrainingSchedule, err error,
+) {
+	v, err := impl.mapper.Get(owner.Account(), scheduleId)
+	if err != nil {
+		err = convertError(err)
+	} else {
+		err = v.toTrainingSchedule(&s)
+	}
+
+	return
+}
+
+func (impl trainingSchedule) List(owner domain.Account, projectId string) (
+	[]domain.TrainingSchedule, error,
+) {
+	v, err := impl.mapper.List(owner.Account(), projectId)
+	if err != nil || len(v) == 0 {
+		return nil, convertError(err)
+	}
+
+	return impl.toTrainingSchedules(v)
+}
+
+func (impl trainingSchedule) Delete(owner domain.Account, scheduleId string) error {
+	return convertError(impl.mapper.Delete(owner.Account(), scheduleId))
+}
+
+func (impl trainingSchedule) FindToHandle(now int64) ([]domain.TrainingSchedule, error) {
+	v, err := impl.mapper.FindToHandle(now)
+	if err != nil || len(v) == 0 {
+		return nil, convertError(err)
+	}
+
+	return impl.toTrainingSchedules(v)
+}
+
+func (impl trainingSchedule) Update(s *domain.TrainingSchedule) error {
+	do := impl.toTrainingScheduleDO(s)
+
+	return convertError(impl.mapper.Update(&do))
+}
+
+func (impl trainingSchedule) toTrainingSchedules(v []TrainingScheduleDO) (
+	[]domain.TrainingSchedule, error,
+) {
+	r := make([]domain.TrainingSchedule, len(v))
+	for i := range v {
+		if err := v[i].toTrainingSchedule(&r[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (impl trainingSchedule) toTrainingScheduleDO(s *domain.TrainingSchedule) TrainingScheduleDO {
+	do := TrainingScheduleDO{
+		Id:         s.Id,
+		Owner:      s.Owner.Account(),
+		ProjectId:  s.ProjectId,
+		TrainingId: s.TrainingId,
+		Spec:       s.Spec.CronSpec(),
+		Timezone:   s.Timezone.Timezone(),
+		Paused:     s.Paused,
+		NextRunAt:  s.NextRunAt,
+		Runs:       make([]ScheduleRunDO, len(s.Runs)),
+		CreatedAt:  s.CreatedAt,
+		Version:    s.Version,
+	}
+
+	for i := range s.Runs {
+		do.Runs[i] = ScheduleRunDO(s.Runs[i])
+	}
+
+	return do
+}
+
+type TrainingScheduleDO struct {
+	Id         string
+	Owner      string
+	ProjectId  string
+	TrainingId string
+	Spec       string
+	Timezone   string
+	Paused     bool
+	NextRunAt  int64
+	Runs       []ScheduleRunDO
+	CreatedAt  int64
+	Version    int
+}
+
+func (do *TrainingScheduleDO) toTrainingSchedule(s *domain.TrainingSchedule) (err error) {
+	s.Id = do.Id
+	s.ProjectId = do.ProjectId
+	s.TrainingId = do.TrainingId
+	s.Paused = do.Paused
+	s.NextRunAt = do.NextRunAt
+	s.CreatedAt = do.CreatedAt
+	s.Version = do.Version
+
+	if s.Owner, err = domain.NewAccount(do.Owner); err != nil {
+		return
+	}
+
+	if s.Spec, err = domain.NewCronSpec(do.Spec); err != nil {
+		return
+	}
+
+	if s.Timezone, err = domain.NewTimezone(do.Timezone); err != nil {
+		return
+	}
+
+	s.Runs = make([]domain.ScheduleRun, len(do.Runs))
+	for i := range do.Runs {
+		s.Runs[i] = domain.ScheduleRun(do.Runs[i])
+	}
+
+	return
+}
+
+type ScheduleRunDO struct {
+	TrainingId string
+	RunAt      int64
+	Status     string
+	Error      string
+	Done       bool
+	Failed     bool
+}
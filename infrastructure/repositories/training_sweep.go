@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/domain/repository"
+)
+
+type TrainingSweepMapper interface {
+	Insert(*TrainingSweepDO) (string, error)
+	Get(owner, sweepId string) (TrainingSweepDO, error)
+	List(owner, projectId string) ([]TrainingSweepDO, error)
+}
+
+func NewTrainingSweepRepository(mapper TrainingSweepMapper) repository.TrainingSweep {
+	return trainingSweep{mapper}
+}
+
+type trainingSweep struct {
+	mapper TrainingSweepMapper
+}
+
+func (impl trainingSweep) Save(s *domain.TrainingSweep) (string, error) {
+	if s.Id != "" {
+		return "", errors.New("must be a new sweep")
+	}
+
+	do := impl.toTrainingSweepDO(s)
+
+	v, err := impl.mapper.Insert(&do)
+	if err != nil {
+		return "", convertError(err)
+	}
+
+	return v, nil
+}
+
+func (impl trainingSweep) Get(owner domain.Account, sweepId string) (
+	s domain.TrainingSweep, err error,
+) {
+	v, err := impl.mapper.Get(owner.Account(), sweepId)
+	if err != nil {
+		err = convertError(err)
+	} else {
+		err = v.toTrainingSweep(&s)
+	}
+
+	return
+}
+
+func (impl trainingSweep) List(owner domain.Account, projectId string) (
+	[]domain.TrainingSweep, error,
+) {
+	v, err := impl.mapper.List(owner.Account(), projectId)
+	if err != nil || len(v) == 0 {
+		return nil, convertError(err)
+	}
+
+	r := make([]domain.TrainingSweep, len(v))
+	for i := range v {
+		if err := v[i].toTrainingSweep(&r[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (impl trainingSweep) toTrainingSweepDO(s *domain.TrainingSweep) TrainingSweepDO {
+	do := TrainingSweepDO{
+		Id:         s.Id,
+		Owner:      s.Owner.Account(),
+		ProjectId:  s.ProjectId,
+		Name:       s.Name.TrainingName(),
+		Strategy:   s.Strategy.SweepStrategy(),
+		Metric:     s.Metric.Name.CustomizedKey(),
+		Goal:       s.Metric.Goal.SweepGoal(),
+		MaxTrials:  s.MaxTrials,
+		Parameters: make([]SweepParameterDO, len(s.Parameters)),
+		Trials:     make([]SweepTrialDO, len(s.Trials)),
+		CreatedAt:  s.CreatedAt,
+		Version:    s.Version,
+	}
+
+	for i := range s.Parameters {
+		p := &s.Parameters[i]
+
+		values := make([]string, len(p.Values))
+		for j := range p.Values {
+			values[j] = p.Values[j].CustomizedValue()
+		}
+
+		do.Parameters[i] = SweepParameterDO{
+			Key:    p.Key.CustomizedKey(),
+			Values: values,
+			Min:    p.Range.Min,
+			Max:    p.Range.Max,
+			Step:   p.Range.Step,
+		}
+	}
+
+	for i := range s.Trials {
+		t := &s.Trials[i]
+
+		do.Trials[i] = SweepTrialDO{
+			TrainingId:      t.TrainingId,
+			Hyperparameters: training{}.toKeyValueDOs(t.Hyperparameters),
+		}
+	}
+
+	return do
+}
+
+type TrainingSweepDO struct {
+	Id         string
+	Owner      string
+	ProjectId  string
+	Name       string
+	Strategy   string
+	Metric     string
+	Goal       string
+	MaxTrials  int
+	Parameters []SweepParameterDO
+	Trials     []SweepTrialDO
+	CreatedAt  int64
+	Version    int
+}
+
+func (do *TrainingSweepDO) toTrainingSweep(s *domain.TrainingSweep) (err error) {
+	s.Id = do.Id
+	s.ProjectId = do.ProjectId
+	s.MaxTrials = do.MaxTrials
+	s.CreatedAt = do.CreatedAt
+	s.Version = do.Version
+
+	if s.Owner, err = domain.NewAccount(do.Owner); err != nil {
+		return
+	}
+
+	if s.Name, err = domain.NewTrainingName(do.Name); err != nil {
+		return
+	}
+
+	if s.Strategy, err = domain.NewSweepStrategy(do.Strategy); err != nil {
+		return
+	}
+
+	if s.Metric.Name, err = domain.NewCustomizedKey(do.Metric); err != nil {
+		return
+	}
+
+	if s.Metric.Goal, err = domain.NewSweepGoal(do.Goal); err != nil {
+		return
+	}
+
+	s.Parameters = make([]domain.SweepParameter, len(do.Parameters))
+	for i := range do.Parameters {
+		if err = do.Parameters[i].toSweepParameter(&s.Parameters[i]); err != nil {
+			return
+		}
+	}
+
+	s.Trials = make([]domain.SweepTrial, len(do.Trials))
+	for i := range do.Trials {
+		t := &do.Trials[i]
+
+		s.Trials[i] = domain.SweepTrial{
+			TrainingId: t.TrainingId,
+		}
+
+		v := TrainingConfigDO{}
+		if s.Trials[i].Hyperparameters, err = v.toKeyValues(t.Hyperparameters); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+type SweepParameterDO struct {
+	Key    string
+	Values []string
+	Min    float64
+	Max    float64
+	Step   float64
+}
+
+func (do *SweepParameterDO) toSweepParameter(p *domain.SweepParameter) (err error) {
+	if p.Key, err = domain.NewCustomizedKey(do.Key); err != nil {
+		return
+	}
+
+	if n := len(do.Values); n > 0 {
+		p.Values = make([]domain.CustomizedValue, n)
+
+		for i := range do.Values {
+			if p.Values[i], err = domain.NewCustomizedValue(do.Values[i]); err != nil {
+				return
+			}
+		}
+	}
+
+	p.Range = domain.SweepRange{
+		Min:  do.Min,
+		Max:  do.Max,
+		Step: do.Step,
+	}
+
+	return
+}
+
+type SweepTrialDO struct {
+	TrainingId      string
+	Hyperparameters []KeyValueDO
+}
@@ -2,4 +2,16 @@ package trainingimpl
 
 type Config struct {
 	JobDoneStatus []string `json:"job_done_status"  required:"true"`
+
+	// JobFailedStatus is the subset of JobDoneStatus which means the job is failed
+	JobFailedStatus []string `json:"job_failed_status"`
+
+	// DownloadTimeout is the seconds to download a file of job, such as the output
+	DownloadTimeout int `json:"download_timeout"`
+}
+
+func (cfg *Config) SetDefault() {
+	if cfg.DownloadTimeout <= 0 {
+		cfg.DownloadTimeout = 600
+	}
 }
@@ -1,7 +1,11 @@
 package trainingimpl
 
 import (
+	"fmt"
+	"io"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/opensourceways/xihe-training-center/sdk"
 	"github.com/sirupsen/logrus"
@@ -14,17 +18,32 @@ import (
 func NewTraining(cfg *Config) training.Training {
 	return &trainingImpl{
 		doneStatus: sets.NewString(cfg.JobDoneStatus...),
+
+		failedStatus: sets.NewString(cfg.JobFailedStatus...),
+
+		// the client with timeout, so the download can't hang forever
+		downloadCli: &http.Client{
+			Timeout: time.Duration(cfg.DownloadTimeout) * time.Second,
+		},
 	}
 }
 
 type trainingImpl struct {
 	doneStatus sets.String
+
+	failedStatus sets.String
+
+	downloadCli *http.Client
 }
 
 func (impl *trainingImpl) IsJobDone(status string) bool {
 	return impl.doneStatus.Has(status)
 }
 
+func (impl *trainingImpl) IsJobFailed(status string) bool {
+	return impl.failedStatus.Has(status)
+}
+
 func (impl *trainingImpl) CreateJob(endpoint string, info *domain.TrainingIndex, t *domain.TrainingConfig) (
 	job domain.JobInfo, err error,
 ) {
@@ -106,6 +125,33 @@ func (impl *trainingImpl) GetFileDownloadURL(endpoint, file string) (string, err
 	return v.URL, nil
 }
 
+func (impl *trainingImpl) DownloadFile(
+	endpoint, file string, handle func(io.Reader, int64) error,
+) error {
+	url, err := impl.GetFileDownloadURL(endpoint, file)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := impl.downloadCli.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download file failed, status code:%d", resp.StatusCode)
+	}
+
+	return handle(resp.Body, resp.ContentLength)
+}
+
 func (impl *trainingImpl) toCompute(c *domain.Compute) sdk.Compute {
 	return sdk.Compute{
 		Type:    c.Type.ComputeType(),
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	abuseapp "github.com/opensourceways/xihe-server/abuse/app"
+	abusedomain "github.com/opensourceways/xihe-server/abuse/domain"
+	"github.com/opensourceways/xihe-server/domain"
+)
+
+type abuseController struct {
+	service abuseapp.ReviewService
+}
+
+func (ctl abuseController) addRouter(rg *gin.RouterGroup) {
+	rg.GET("/v1/abuse/queue", ctl.ListPendingItems)
+	rg.GET("/v1/abuse/queue/:type/:owner/:id", ctl.ListReports)
+	rg.PUT("/v1/abuse/queue/:type/:owner/:id", ctl.Review)
+	rg.GET("/v1/abuse/audit", ctl.ListAudits)
+}
+
+// ListPendingItems lists the reported items which are waiting for review
+func (ctl abuseController) ListPendingItems(ctx *gin.Context) {
+	cmd := abuseapp.PendingItemListCmd{}
+
+	var err error
+
+	if v := ctx.Query("type"); v != "" {
+		if cmd.Type, err = abusedomain.NewItemType(v); err != nil {
+			sendBadRequest(ctx, err)
+
+			return
+		}
+	}
+
+	if cmd.PageNum, err = intQuery(ctx, "page_num", 1); err != nil {
+		sendBadRequest(ctx, err)
+
+		return
+	}
+
+	if cmd.CountPerPage, err = intQuery(ctx, "count_per_page", 20); err != nil {
+		sendBadRequest(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.service.ListPendingItems(&cmd); err != nil {
+		sendError(ctx, err)
+	} else {
+		sendResp(ctx, v)
+	}
+}
+
+// ListReports lists the pending reports of the item
+func (ctl abuseController) ListReports(ctx *gin.Context) {
+	item, err := ctl.item(ctx)
+	if err != nil {
+		sendBadRequest(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.service.ListReports(&item); err != nil {
+		sendError(ctx, err)
+	} else {
+		sendResp(ctx, v)
+	}
+}
+
+type abuseReviewRequest struct {
+	Action   string `json:"action"`
+	Operator string `json:"operator"`
+	Comment  string `json:"comment"`
+}
+
+// Review hides, deletes or restores the item, or dismisses the reports of it
+func (ctl abuseController) Review(ctx *gin.Context) {
+	req := abuseReviewRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		sendBadRequest(ctx, errors.New("invalid body"))
+
+		return
+	}
+
+	cmd := abuseapp.ReviewCmd{Operator: req.Operator}
+
+	f := func() (err error) {
+		if cmd.Item, err = ctl.item(ctx); err != nil {
+			return
+		}
+
+		if cmd.Action, err = abusedomain.NewReviewAction(req.Action); err != nil {
+			return
+		}
+
+		if cmd.Comment, err = abusedomain.NewReviewComment(req.Comment); err != nil {
+			return
+		}
+
+		return cmd.Validate()
+	}
+
+	if err := f(); err != nil {
+		sendBadRequest(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.service.Review(&cmd); err != nil {
+		if code != "" {
+			sendBadRequest(ctx, err)
+		} else {
+			sendError(ctx, err)
+		}
+	} else {
+		sendResp(ctx, "success")
+	}
+}
+
+// ListAudits lists the records of reviews, the latest is in the front
+func (ctl abuseController) ListAudits(ctx *gin.Context) {
+	cmd := abuseapp.AuditListCmd{}
+
+	var err error
+
+	if cmd.PageNum, err = intQuery(ctx, "page_num", 1); err != nil {
+		sendBadRequest(ctx, err)
+
+		return
+	}
+
+	if cmd.CountPerPage, err = intQuery(ctx, "count_per_page", 20); err != nil {
+		sendBadRequest(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.service.ListAudits(&cmd); err != nil {
+		sendError(ctx, err)
+	} else {
+		sendResp(ctx, v)
+	}
+}
+
+func (ctl abuseController) item(ctx *gin.Context) (item abusedomain.Item, err error) {
+	if item.Type, err = abusedomain.NewItemType(ctx.Param("type")); err != nil {
+		return
+	}
+
+	if item.Owner, err = domain.NewAccount(ctx.Param("owner")); err != nil {
+		return
+	}
+
+	item.Id = ctx.Param("id")
+
+	return
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	asyncapp "github.com/opensourceways/xihe-server/async-server/app"
+	asyncdomain "github.com/opensourceways/xihe-server/async-server/domain"
+	commonrepo "github.com/opensourceways/xihe-server/common/domain/repository"
+)
+
+type asyncTaskController struct {
+	service asyncapp.TaskAdminService
+}
+
+func (ctl asyncTaskController) addRouter(rg *gin.RouterGroup) {
+	rg.GET("/v1/async/task/dead", ctl.ListDeadTasks)
+	rg.PUT("/v1/async/task/dead/:id", ctl.RequeueDeadTask)
+}
+
+// ListDeadTasks lists the tasks which have exhausted the attempts
+func (ctl asyncTaskController) ListDeadTasks(ctx *gin.Context) {
+	cmd := asyncapp.DeadTaskListCmd{}
+
+	if v := ctx.Query("task_type"); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			if _, err := asyncdomain.NewTaskType(t); err != nil {
+				sendBadRequest(ctx, errors.New("invalid task_type"))
+
+				return
+			}
+
+			cmd.TaskType = append(cmd.TaskType, t)
+		}
+	}
+
+	var err error
+
+	if cmd.PageNum, err = intQuery(ctx, "page_num", 1); err != nil {
+		sendBadRequest(ctx, err)
+
+		return
+	}
+
+	if cmd.CountPerPage, err = intQuery(ctx, "count_per_page", 20); err != nil {
+		sendBadRequest(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.service.ListDeadTasks(&cmd); err != nil {
+		sendError(ctx, err)
+	} else {
+		sendResp(ctx, v)
+	}
+}
+
+// RequeueDeadTask puts the dead task back to the queue
+func (ctl asyncTaskController) RequeueDeadTask(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		sendBadRequest(ctx, errors.New("invalid id"))
+
+		return
+	}
+
+	if err := ctl.service.RequeueDeadTask(id); err != nil {
+		if commonrepo.IsErrorConcurrentUpdating(err) || commonrepo.IsErrorResourceNotExists(err) {
+			sendBadRequest(ctx, errors.New("the task is not dead"))
+		} else {
+			sendError(ctx, err)
+		}
+	} else {
+		sendResp(ctx, "success")
+	}
+}
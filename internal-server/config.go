@@ -1,7 +1,9 @@
 package main
 
 import (
+	abusemsg "github.com/opensourceways/xihe-server/abuse/infrastructure/messageadapter"
 	common "github.com/opensourceways/xihe-server/common/config"
+	"github.com/opensourceways/xihe-server/common/infrastructure/kafka"
 	"github.com/opensourceways/xihe-server/config"
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/utils"
@@ -21,6 +23,10 @@ type configuration struct {
 	Mongodb    config.Mongodb          `json:"mongodb"      required:"true"`
 	Postgresql config.PostgresqlConfig `json:"postgresql"   required:"true"`
 	Domain     domain.Config           `json:"domain"       required:"true"`
+	HTTP       httpConfig              `json:"http"         required:"true"`
+	MQ         kafka.Config            `json:"mq"           required:"true"`
+	Abuse      abusemsg.Config         `json:"abuse"        required:"true"`
+	Training   config.TrainingConfig   `json:"training"     required:"true"`
 }
 
 func (cfg *configuration) ConfigItems() []interface{} {
@@ -28,6 +34,10 @@ func (cfg *configuration) ConfigItems() []interface{} {
 		&cfg.Mongodb,
 		&cfg.Domain,
 		&cfg.Postgresql.DB,
+		&cfg.HTTP,
+		&cfg.MQ,
+		&cfg.Abuse,
+		&cfg.Training,
 	}
 }
 
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const headerInternalToken = "INTERNAL-TOKEN"
+
+type httpConfig struct {
+	Port   int          `json:"port"`
+	Tokens tokensConfig `json:"tokens"  required:"true"`
+}
+
+func (cfg *httpConfig) SetDefault() {
+	if cfg.Port <= 0 {
+		cfg.Port = 8890
+	}
+}
+
+// tokensConfig is the tokens of the consumers, each one can only access its own apis.
+type tokensConfig struct {
+	AsyncTask string `json:"async_task"  required:"true"`
+	Abuse     string `json:"abuse"       required:"true"`
+}
+
+// httpRouter is the apis which are accessed by the token checked by check
+type httpRouter struct {
+	check gin.HandlerFunc
+	add   func(*gin.RouterGroup)
+}
+
+// startHTTPServer serves the internal apis used by the operators and the training platform
+func startHTTPServer(cfg *httpConfig, routers ...httpRouter) {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	for _, item := range routers {
+		rg := r.Group("/internal")
+		rg.Use(item.check)
+
+		item.add(rg)
+	}
+
+	go func() {
+		if err := r.Run(fmt.Sprintf(":%d", cfg.Port)); err != nil {
+			logrus.Errorf("start http server failed, err:%s", err.Error())
+		}
+	}()
+}
+
+func checkInternalToken(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !isValidToken(ctx, token) {
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// isValidToken aborts the request if its token is not the expected one
+func isValidToken(ctx *gin.Context, token string) bool {
+	v := ctx.GetHeader(headerInternalToken)
+
+	if subtle.ConstantTimeCompare([]byte(v), []byte(token)) != 1 {
+		ctx.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			respData{Code: "invalid_token", Msg: "invalid internal token"},
+		)
+
+		return false
+	}
+
+	return true
+}
+
+type respData struct {
+	Code string      `json:"code"`
+	Msg  string      `json:"msg"`
+	Data interface{} `json:"data"`
+}
+
+func sendResp(ctx *gin.Context, data interface{}) {
+	ctx.JSON(http.StatusOK, respData{Data: data})
+}
+
+func sendBadRequest(ctx *gin.Context, err error) {
+	ctx.JSON(http.StatusBadRequest, respData{Code: "bad_request_param", Msg: err.Error()})
+}
+
+func sendError(ctx *gin.Context, err error) {
+	ctx.JSON(http.StatusInternalServerError, respData{Code: "system_error", Msg: err.Error()})
+}
+
+func intQuery(ctx *gin.Context, key string, dft int) (int, error) {
+	v := ctx.Query(key)
+	if v == "" {
+		return dft, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil || i <= 0 {
+		return 0, errors.New("invalid " + key)
+	}
+
+	return i, nil
+}
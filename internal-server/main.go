@@ -16,17 +16,27 @@ import (
 	"github.com/opensourceways/xihe-grpc-protocol/grpc/training"
 	"github.com/sirupsen/logrus"
 
+	abuseapp "github.com/opensourceways/xihe-server/abuse/app"
+	abuseitem "github.com/opensourceways/xihe-server/abuse/infrastructure/itemimpl"
+	abusemsg "github.com/opensourceways/xihe-server/abuse/infrastructure/messageadapter"
+	abuserepo "github.com/opensourceways/xihe-server/abuse/infrastructure/repositoryimpl"
 	"github.com/opensourceways/xihe-server/app"
+	asyncapp "github.com/opensourceways/xihe-server/async-server/app"
+	asyncrepo "github.com/opensourceways/xihe-server/async-server/infrastructure/repositoryimpl"
+	bigmodelrepo "github.com/opensourceways/xihe-server/bigmodel/infrastructure/repositoryimpl"
 	cloudapp "github.com/opensourceways/xihe-server/cloud/app"
 	clouddomain "github.com/opensourceways/xihe-server/cloud/domain"
 	cloudrepo "github.com/opensourceways/xihe-server/cloud/infrastructure/repositoryimpl"
+	"github.com/opensourceways/xihe-server/common/infrastructure/kafka"
 	"github.com/opensourceways/xihe-server/common/infrastructure/pgsql"
 	competitionapp "github.com/opensourceways/xihe-server/competition/app"
 	competitiondomain "github.com/opensourceways/xihe-server/competition/domain"
 	competitionrepo "github.com/opensourceways/xihe-server/competition/infrastructure/repositoryimpl"
 	"github.com/opensourceways/xihe-server/domain"
+	"github.com/opensourceways/xihe-server/infrastructure/messages"
 	"github.com/opensourceways/xihe-server/infrastructure/mongodb"
 	"github.com/opensourceways/xihe-server/infrastructure/repositories"
+	"github.com/opensourceways/xihe-server/infrastructure/trainingimpl"
 )
 
 type options struct {
@@ -101,15 +111,40 @@ func main() {
 
 	defer mongodb.Close()
 
+	// mq
+	if err := kafka.Init(&cfg.MQ, log, nil); err != nil {
+		log.Fatalf("initialize mq failed, err:%v", err)
+	}
+
+	defer kafka.Exit()
+
 	collections := &cfg.Mongodb.Collections
 
 	// training
+	trainingRepo := repositories.NewTrainingRepository(
+		mongodb.NewTrainingMapper(collections.Training),
+	)
+
+	trainingAdapter := trainingimpl.NewTraining(&cfg.Training.Config)
+
+	trainingMetric := app.NewTrainingMetricService(
+		trainingAdapter,
+		trainingRepo,
+		repositories.NewTrainingMetricRepository(
+			mongodb.NewTrainingMetricMapper(collections.TrainingMetric),
+		),
+	)
+
 	train := app.NewTrainingService(
-		nil,
-		repositories.NewTrainingRepository(
-			mongodb.NewTrainingMapper(collections.Training),
+		trainingAdapter,
+		trainingRepo,
+		repositories.NewTrainingQueueRepository(
+			mongodb.NewTrainingQueueMapper(collections.TrainingQueue),
+		),
+		messages.NewTrainingMessageAdapter(
+			&cfg.Training.Message, kafka.PublisherAdapter(),
 		),
-		nil, 0,
+		nil, nil, nil, 0,
 	)
 
 	// finetune
@@ -145,14 +180,46 @@ func main() {
 		),
 	)
 
+	// async task
+	asyncTaskAdmin := asyncTaskController{
+		asyncapp.NewTaskAdminService(
+			asyncrepo.NewAsyncTaskRepo(&cfg.Postgresql.Async),
+		),
+	}
+
+	// abuse
+	abuseAdmin := abuseController{
+		abuseapp.NewReviewService(
+			abuseitem.NewItemImpl(
+				bigmodelrepo.NewWuKongPictureRepo(mongodb.NewCollection(collections.WuKongPicture)),
+				bigmodelrepo.NewGalleryPicture(mongodb.NewCollection(collections.GalleryPicture)),
+				repositories.NewProjectRepository(mongodb.NewProjectMapper(collections.Project)),
+				repositories.NewModelRepository(mongodb.NewModelMapper(collections.Model)),
+				repositories.NewDatasetRepository(mongodb.NewDatasetMapper(collections.Dataset)),
+			),
+			abuserepo.NewReportRepo(mongodb.NewCollection(collections.AbuseReport)),
+			abuserepo.NewAuditRepo(mongodb.NewCollection(collections.AbuseAudit)),
+			abusemsg.MessageAdapter(&cfg.Abuse, kafka.PublisherAdapter()),
+		),
+	}
+
 	// cfg
 	cfg.initDomainConfig()
 
+	// http
+	tokens := &cfg.HTTP.Tokens
+
+	startHTTPServer(
+		&cfg.HTTP,
+		httpRouter{checkInternalToken(tokens.AsyncTask), asyncTaskAdmin.addRouter},
+		httpRouter{checkInternalToken(tokens.Abuse), abuseAdmin.addRouter},
+	)
+
 	// server
 	s := server.NewServer()
 
 	s.RegisterFinetuneServer(finetuneServer{finetuneService})
-	s.RegisterTrainingServer(trainingServer{train})
+	s.RegisterTrainingServer(trainingServer{train, trainingMetric})
 	s.RegisterEvaluateServer(evaluateServer{evaluateService})
 	s.RegisterInferenceServer(inferenceServer{inferenceService})
 	s.RegisterCloudServer(cloudServer{cloudService})
@@ -165,22 +232,27 @@ func main() {
 
 type trainingServer struct {
 	service app.TrainingService
+	metric  app.TrainingMetricService
 }
 
+// SetTrainingInfo updates the job detail of training. The step-wise metrics are
+// carried by the log of training, so they are ingested once the log is reported.
 func (t trainingServer) SetTrainingInfo(index *training.TrainingIndex, v *training.TrainingInfo) error {
 	u, err := domain.NewAccount(index.User)
 	if err != nil {
 		return nil
 	}
 
-	return t.service.UpdateJobDetail(
-		&domain.TrainingIndex{
-			Project: domain.ResourceIndex{
-				Owner: u,
-				Id:    index.ProjectId,
-			},
-			TrainingId: index.Id,
+	info := domain.TrainingIndex{
+		Project: domain.ResourceIndex{
+			Owner: u,
+			Id:    index.ProjectId,
 		},
+		TrainingId: index.Id,
+	}
+
+	err = t.service.UpdateJobDetail(
+		&info,
 		&app.JobDetail{
 			Duration:   v.Duration,
 			Status:     v.Status,
@@ -189,6 +261,20 @@ func (t trainingServer) SetTrainingInfo(index *training.TrainingIndex, v *traini
 			OutputPath: v.OutputZipPath,
 		},
 	)
+	if err != nil || v.LogPath == "" {
+		return err
+	}
+
+	// the metrics are not necessary for the training, so the failure of
+	// ingesting them should not fail the callback.
+	if err := t.metric.IngestLog(&info, v.LogPath); err != nil {
+		logrus.Errorf(
+			"ingest metrics of training(%s) failed, err:%s",
+			info.TrainingId, err.Error(),
+		)
+	}
+
+	return nil
 }
 
 // finetune
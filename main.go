@@ -25,6 +25,7 @@ import (
 type options struct {
 	service     liboptions.ServiceOptions
 	enableDebug bool
+	metricsPort int
 }
 
 func (o *options) Validate() error {
@@ -41,6 +42,11 @@ func gatherOptions(fs *flag.FlagSet, args ...string) (options, error) {
 		"whether to enable debug model.",
 	)
 
+	fs.IntVar(
+		&o.metricsPort, "metrics_port", 0,
+		"port to serve the metrics at /debug/vars, it should not be exposed to public. Disabled if 0.",
+	)
+
 	err := fs.Parse(args)
 
 	return o, err
@@ -77,6 +83,9 @@ func main() {
 		logrus.Fatalf("config file delete failed, err:%s", err.Error())
 	}
 
+	// the domain config is needed by the initialization below
+	cfg.InitDomainConfig()
+
 	// bigmodel
 	if err := bigmodels.Init(&cfg.BigModel.Config); err != nil {
 		logrus.Fatalf("initialize big model failed, err:%s", err.Error())
@@ -137,9 +146,12 @@ func main() {
 	}
 
 	// cfg
-	cfg.InitDomainConfig()
 	cfg.InitAppConfig()
 
 	// run
+	if o.metricsPort > 0 {
+		server.StartMetricsServer(o.metricsPort, o.service.GracePeriod)
+	}
+
 	server.StartWebServer(o.service.Port, o.service.GracePeriod, cfg)
 }
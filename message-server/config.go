@@ -2,6 +2,7 @@ package main
 
 import (
 	asyncrepoimpl "github.com/opensourceways/xihe-server/async-server/infrastructure/repositoryimpl"
+	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/moderationimpl"
 	bigmodelmq "github.com/opensourceways/xihe-server/bigmodel/messagequeue"
 	"github.com/opensourceways/xihe-server/cloud/infrastructure/cloudimpl"
 	cloudrepoimpl "github.com/opensourceways/xihe-server/cloud/infrastructure/repositoryimpl"
@@ -12,6 +13,7 @@ import (
 	"github.com/opensourceways/xihe-server/domain"
 	"github.com/opensourceways/xihe-server/infrastructure/evaluateimpl"
 	"github.com/opensourceways/xihe-server/infrastructure/finetuneimpl"
+	"github.com/opensourceways/xihe-server/infrastructure/gitlab"
 	"github.com/opensourceways/xihe-server/infrastructure/inferenceimpl"
 	"github.com/opensourceways/xihe-server/infrastructure/messages"
 	"github.com/opensourceways/xihe-server/messagequeue"
@@ -35,16 +37,17 @@ type configuration struct {
 	MaxRetry         int    `json:"max_retry"`
 	FinetuneEndpoint string `json:"finetune_endpoint"  required:"true"`
 
-	Inference  inferenceimpl.Config        `json:"inference"    required:"true"`
-	Evaluate   evaluateConfig              `json:"evaluate"     required:"true"`
-	Cloud      cloudConfig                 `json:"cloud"        required:"true"`
-	Mongodb    config.Mongodb              `json:"mongodb"      required:"true"`
-	Postgresql PostgresqlConfig            `json:"postgresql"   required:"true"`
-	Domain     domain.Config               `json:"domain"       required:"true"`
-	MQ         kafka.Config                `json:"mq"           required:"true"`
-	MQTopics   mqTopics                    `json:"mq_topics"    required:"true"`
-	Points     pointsConfig                `json:"points"`
-	Training   messagequeue.TrainingConfig `json:"training"`
+	Inference  inferenceimpl.Config  `json:"inference"    required:"true"`
+	Evaluate   evaluateConfig        `json:"evaluate"     required:"true"`
+	Cloud      cloudConfig           `json:"cloud"        required:"true"`
+	Mongodb    config.Mongodb        `json:"mongodb"      required:"true"`
+	Postgresql PostgresqlConfig      `json:"postgresql"   required:"true"`
+	Domain     domain.Config         `json:"domain"       required:"true"`
+	MQ         kafka.Config          `json:"mq"           required:"true"`
+	MQTopics   mqTopics              `json:"mq_topics"    required:"true"`
+	Points     pointsConfig          `json:"points"`
+	Training   trainingConfig        `json:"training"     required:"true"`
+	Moderation moderationimpl.Config `json:"moderation"   required:"true"`
 }
 
 type PostgresqlConfig struct {
@@ -67,6 +70,9 @@ func (cfg *configuration) ConfigItems() []interface{} {
 		&cfg.MQTopics,
 		&cfg.Points.Domain,
 		&cfg.Points.Repo,
+		&cfg.Moderation,
+		&cfg.Training.Service,
+		&cfg.Training.Schedule,
 	}
 }
 
@@ -97,6 +103,40 @@ func (cfg *configuration) getFinetuneConfig() finetuneimpl.Config {
 	}
 }
 
+// training
+type trainingConfig struct {
+	messagequeue.TrainingConfig
+
+	Service  config.TrainingConfig  `json:"service"  required:"true"`
+	Schedule trainingScheduleConfig `json:"schedule" required:"true"`
+}
+
+type trainingScheduleConfig struct {
+	// Interval is the seconds between two checks of the due schedules
+	Interval int `json:"interval"`
+
+	// MaxTrainingRecordNum should be the same as the one of server,
+	// it limits the trainings which the schedules recreate.
+	MaxTrainingRecordNum int `json:"max_training_record_num"`
+
+	// Gitlab and EncryptionKeyForGitlabToken should be the same as the ones of server,
+	// the commit of project is recorded by the token of owner when recreating the training.
+	Gitlab                      gitlab.Config `json:"gitlab"                       required:"true"`
+	EncryptionKeyForGitlabToken string        `json:"encryption_key_gitlab_token"  required:"true"`
+}
+
+func (cfg *trainingScheduleConfig) SetDefault() {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 60
+	}
+
+	if cfg.MaxTrainingRecordNum <= 0 {
+		cfg.MaxTrainingRecordNum = 10
+	}
+
+	common.SetDefault(&cfg.Gitlab)
+}
+
 // evaluate
 type evaluateConfig struct {
 	SurvivalTime int `json:"survival_time"`
@@ -178,7 +218,8 @@ type mqTopics struct {
 	CourseApplied string `json:"course_applied" required:"true"`
 
 	// training
-	TrainingCreated string `json:"training_created" required:"true"`
+	TrainingCreated           string `json:"training_created"             required:"true"`
+	TrainingScheduleRunFailed string `json:"training_schedule_run_failed" required:"true"`
 
 	// resource
 	ModelCreated      string `json:"model_created"      required:"true"`
@@ -44,6 +44,8 @@ type handler struct {
 	inference app.InferenceMessageService
 	cloud     cloudapp.CloudMessageService
 	async     asyncapp.AsyncMessageService
+
+	moderation app.ResourceModerationMessageService
 }
 
 func (h *handler) HandleEventAddRelatedResource(info *message.RelatedResource) error {
@@ -267,6 +269,20 @@ func (h *handler) HandleEventCreateEvaluate(info *message.EvaluateInfo) error {
 	})
 }
 
+func (h *handler) HandleEventRepoFileCommitted(info *message.RepoFileCommitted) error {
+	return h.do(func(bool) error {
+		err := h.moderation.CheckRepoFile(info)
+		if err != nil {
+			h.log.Errorf(
+				"check repo file %s of %s failed, err:%s",
+				info.Path, info.Resource.String(), err.Error(),
+			)
+		}
+
+		return err
+	})
+}
+
 func (h *handler) HandleEventPodSubscribe(info *cloudtypes.PodInfo) error {
 	return h.do(func(bool) error {
 		if err := h.cloud.CreatePodInstance(info); err != nil {
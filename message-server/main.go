@@ -12,17 +12,25 @@ import (
 	liboptions "github.com/opensourceways/community-robot-lib/options"
 	"github.com/sirupsen/logrus"
 
+	abuseapp "github.com/opensourceways/xihe-server/abuse/app"
+	abuseitem "github.com/opensourceways/xihe-server/abuse/infrastructure/itemimpl"
+	abuserepo "github.com/opensourceways/xihe-server/abuse/infrastructure/repositoryimpl"
 	"github.com/opensourceways/xihe-server/app"
 	asyncapp "github.com/opensourceways/xihe-server/async-server/app"
 	asyncrepo "github.com/opensourceways/xihe-server/async-server/infrastructure/repositoryimpl"
+	bigmodelapp "github.com/opensourceways/xihe-server/bigmodel/app"
+	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/moderationimpl"
+	bigmodelrepo "github.com/opensourceways/xihe-server/bigmodel/infrastructure/repositoryimpl"
 	bigmodelmq "github.com/opensourceways/xihe-server/bigmodel/messagequeue"
 	cloudapp "github.com/opensourceways/xihe-server/cloud/app"
 	"github.com/opensourceways/xihe-server/cloud/infrastructure/cloudimpl"
 	cloudrepo "github.com/opensourceways/xihe-server/cloud/infrastructure/repositoryimpl"
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	"github.com/opensourceways/xihe-server/common/infrastructure/kafka"
 	"github.com/opensourceways/xihe-server/common/infrastructure/pgsql"
 	"github.com/opensourceways/xihe-server/infrastructure/evaluateimpl"
 	"github.com/opensourceways/xihe-server/infrastructure/finetuneimpl"
+	"github.com/opensourceways/xihe-server/infrastructure/gitlab"
 	"github.com/opensourceways/xihe-server/infrastructure/inferenceimpl"
 	"github.com/opensourceways/xihe-server/infrastructure/messages"
 	"github.com/opensourceways/xihe-server/infrastructure/mongodb"
@@ -35,6 +43,7 @@ import (
 	userapp "github.com/opensourceways/xihe-server/user/app"
 	userrepo "github.com/opensourceways/xihe-server/user/infrastructure/repositoryimpl"
 	usermq "github.com/opensourceways/xihe-server/user/messagequeue"
+	"github.com/opensourceways/xihe-server/utils"
 )
 
 type options struct {
@@ -114,6 +123,11 @@ func main() {
 	// cfg
 	cfg.initDomainConfig()
 
+	// gitlab
+	if err := gitlab.Init(&cfg.Training.Schedule.Gitlab); err != nil {
+		logrus.Fatalf("initialize gitlab failed, err:%s", err.Error())
+	}
+
 	// points
 	if err = pointsSubscribesMessage(cfg, &cfg.MQTopics); err != nil {
 		logrus.Errorf("points subscribes message failed, err:%s", err.Error())
@@ -129,7 +143,9 @@ func main() {
 	}
 
 	// training
-	if err = trainingSubscribesMessage(log, cfg); err != nil {
+	train := newTrainingService(cfg)
+
+	if err = trainingSubscribesMessage(cfg, train); err != nil {
 		logrus.Errorf("training subscribes message failed, err:%s", err.Error())
 
 		return
@@ -142,8 +158,42 @@ func main() {
 		return
 	}
 
+	// moderation
+	contentModeration, err := newContentModeration(cfg)
+	if err != nil {
+		logrus.Errorf("new content moderation failed, err:%s", err.Error())
+
+		return
+	}
+
+	// training schedule
+	scheduler, err := newTrainingScheduler(cfg, train)
+	if err != nil {
+		logrus.Errorf("new training scheduler failed, err:%s", err.Error())
+
+		return
+	}
+
 	// run
-	run(newHandler(cfg, log), log, &cfg.MQTopics)
+	run(
+		newHandler(cfg, log, contentModeration), log, &cfg.MQTopics,
+		scheduler, cfg.Training.Schedule.Interval,
+	)
+}
+
+func newContentModeration(cfg *configuration) (moderation.Moderation, error) {
+	s, err := moderationimpl.NewService(&cfg.Moderation)
+	if err != nil {
+		return nil, err
+	}
+
+	s.SetAuditor(moderationimpl.NewRepoAuditor(
+		bigmodelrepo.NewModerationAudit(
+			mongodb.NewCollection(cfg.Mongodb.Collections.ModerationAudit),
+		),
+	))
+
+	return moderationimpl.NewContentModeration(s), nil
 }
 
 func pointsSubscribesMessage(cfg *configuration, topics *mqTopics) error {
@@ -195,7 +245,7 @@ func userSubscribesMessage(cfg *configuration, topics *userConfig) error {
 			userrepo.NewUserRepo(
 				mongodb.NewCollection(collections.User),
 			),
-			nil, nil, nil, nil,
+			nil, nil, nil, nil, nil,
 		),
 		kafka.SubscriberAdapter(),
 		&topics.TopicConfig,
@@ -203,54 +253,145 @@ func userSubscribesMessage(cfg *configuration, topics *userConfig) error {
 }
 
 func bigmodelSubscribesMessage(cfg *configuration, topics *mqTopics) error {
-	return bigmodelmq.Subscribe(
+	err := bigmodelmq.Subscribe(
 		asyncapp.NewAsyncMessageService(
 			asyncrepo.NewAsyncTaskRepo(&cfg.Postgresql.asyncconf),
 		),
 		&topics.BigModelTopics,
 	)
+	if err != nil {
+		return err
+	}
+
+	collections := &cfg.Mongodb.Collections
+
+	if t := topics.BigModelTopics.ModerationBlocked; t != "" {
+		err = bigmodelmq.SubscribeModerationAudit(
+			bigmodelrepo.NewModerationAudit(
+				mongodb.NewCollection(collections.ModerationAudit),
+			),
+			t,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if t := topics.BigModelTopics.ApiCalled; t != "" {
+		// the limiter is not needed to record the calls
+		err = bigmodelmq.SubscribeApiCalled(
+			bigmodelapp.NewApiGatewayService(
+				bigmodelrepo.NewApiService(mongodb.NewCollection(collections.ApiApply)),
+				bigmodelrepo.NewApiUsage(mongodb.NewCollection(collections.ApiUsage)),
+				nil,
+			),
+			t,
+		)
+	}
+
+	return err
 }
 
-func trainingSubscribesMessage(log *logrus.Entry, cfg *configuration) error {
+func newTrainingService(cfg *configuration) app.TrainingService {
 	collections := &cfg.Mongodb.Collections
 
+	return app.NewTrainingService(
+		trainingimpl.NewTraining(&cfg.Training.Service.Config),
+		repositories.NewTrainingRepository(
+			mongodb.NewTrainingMapper(collections.Training),
+		),
+		repositories.NewTrainingQueueRepository(
+			mongodb.NewTrainingQueueMapper(collections.TrainingQueue),
+		),
+		messages.NewTrainingMessageAdapter(
+			&cfg.Training.Service.Message, kafka.PublisherAdapter(),
+		),
+		nil, gitlab.NewRepoFile(),
+		repositories.NewTrainingScheduleRepository(
+			mongodb.NewTrainingScheduleMapper(collections.TrainingSchedule),
+		),
+		cfg.Training.Schedule.MaxTrainingRecordNum,
+	)
+}
+
+func trainingSubscribesMessage(cfg *configuration, ts app.TrainingService) error {
+	topics := &cfg.MQTopics
+
 	return messagequeue.Subscribe(
-		cfg.Training, cfg.MQTopics.TrainingCreated,
-		app.NewTrainingService(
-			trainingimpl.NewTraining(&trainingimpl.Config{}),
-			repositories.NewTrainingRepository(
-				mongodb.NewTrainingMapper(collections.Training),
+		cfg.Training.TrainingConfig,
+		topics.TrainingCreated, topics.TrainingScheduleRunFailed,
+		ts,
+		app.NewTrainingNoticeService(
+			repositories.NewTrainingNoticeRepository(
+				mongodb.NewTrainingNoticeMapper(cfg.Mongodb.Collections.TrainingNotice),
 			),
-			nil, 0,
 		),
 		kafka.SubscriberAdapter(),
 	)
 }
 
-func newHandler(cfg *configuration, log *logrus.Entry) *handler {
+func newTrainingScheduler(cfg *configuration, ts app.TrainingService) (app.TrainingScheduler, error) {
+	collections := &cfg.Mongodb.Collections
+
+	encryption, err := utils.NewSymmetricEncryption(
+		cfg.Training.Schedule.EncryptionKeyForGitlabToken, "",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.NewTrainingScheduler(
+		ts,
+		trainingimpl.NewTraining(&cfg.Training.Service.Config),
+		repositories.NewTrainingRepository(
+			mongodb.NewTrainingMapper(collections.Training),
+		),
+		repositories.NewTrainingScheduleRepository(
+			mongodb.NewTrainingScheduleMapper(collections.TrainingSchedule),
+		),
+		messages.NewTrainingMessageAdapter(
+			&cfg.Training.Service.Message, kafka.PublisherAdapter(),
+		),
+		userrepo.NewUserRepo(mongodb.NewCollection(collections.User)),
+		encryption,
+	), nil
+}
+
+func newHandler(cfg *configuration, log *logrus.Entry, m moderation.Moderation) *handler {
 	collections := &cfg.Mongodb.Collections
 
 	userRepo := userrepo.NewUserRepo(mongodb.NewCollection(collections.User))
 
+	projectRepo := repositories.NewProjectRepository(
+		mongodb.NewProjectMapper(collections.Project),
+	)
+
+	datasetRepo := repositories.NewDatasetRepository(
+		mongodb.NewDatasetMapper(collections.Dataset),
+	)
+
+	modelRepo := repositories.NewModelRepository(
+		mongodb.NewModelMapper(collections.Model),
+	)
+
 	h := &handler{
 		log:      log,
 		maxRetry: cfg.MaxRetry,
 
-		project: app.NewProjectMessageService(
-			repositories.NewProjectRepository(
-				mongodb.NewProjectMapper(collections.Project),
-			),
-		),
-
-		dataset: app.NewDatasetMessageService(
-			repositories.NewDatasetRepository(
-				mongodb.NewDatasetMapper(collections.Dataset),
-			),
-		),
-
-		model: app.NewModelMessageService(
-			repositories.NewModelRepository(
-				mongodb.NewModelMapper(collections.Model),
+		project: app.NewProjectMessageService(projectRepo),
+		dataset: app.NewDatasetMessageService(datasetRepo),
+		model:   app.NewModelMessageService(modelRepo),
+
+		moderation: app.NewResourceModerationMessageService(
+			m,
+			abuseapp.NewModerationService(
+				abuseitem.NewItemImpl(
+					bigmodelrepo.NewWuKongPictureRepo(mongodb.NewCollection(collections.WuKongPicture)),
+					bigmodelrepo.NewGalleryPicture(mongodb.NewCollection(collections.GalleryPicture)),
+					projectRepo, modelRepo, datasetRepo,
+				),
+				abuserepo.NewReportRepo(mongodb.NewCollection(collections.AbuseReport)),
+				abuserepo.NewAuditRepo(mongodb.NewCollection(collections.AbuseAudit)),
 			),
 		),
 
@@ -292,7 +433,10 @@ func newHandler(cfg *configuration, log *logrus.Entry) *handler {
 	return h
 }
 
-func run(h *handler, log *logrus.Entry, topics *mqTopics) {
+func run(
+	h *handler, log *logrus.Entry, topics *mqTopics,
+	scheduler app.TrainingScheduler, interval int,
+) {
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
 
@@ -326,6 +470,13 @@ func run(h *handler, log *logrus.Entry, topics *mqTopics) {
 		}
 	}(ctx)
 
+	wg.Add(1)
+	go func(ctx context.Context) {
+		defer wg.Done()
+
+		runTrainingScheduler(ctx, scheduler, interval, log)
+	}(ctx)
+
 	err := messages.Subscribe(ctx, h, log, &topics.Topics, kafka.SubscriberAdapter())
 	if err != nil {
 		log.Errorf("subscribe failed, err:%v", err)
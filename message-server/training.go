@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/xihe-server/app"
+	"github.com/opensourceways/xihe-server/utils"
+)
+
+// runTrainingScheduler checks the training schedules every interval seconds until ctx is done.
+func runTrainingScheduler(
+	ctx context.Context, s app.TrainingScheduler, interval int, log *logrus.Entry,
+) {
+	t := time.NewTicker(time.Duration(interval) * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("training scheduler exits")
+
+			return
+
+		case <-t.C:
+			if err := s.Schedule(utils.Now()); err != nil {
+				log.Errorf("schedule trainings failed, err:%s", err.Error())
+			}
+		}
+	}
+}
@@ -16,16 +16,18 @@ import (
 const (
 	retryNum = 3
 
-	handleNameTrainingCreated = "training_created"
+	handleNameTrainingCreated           = "training_created"
+	handleNameTrainingScheduleRunFailed = "training_schedule_run_failed"
 )
 
 func Subscribe(
 	cfg TrainingConfig,
-	tcTopic string,
+	tcTopic, srfTopic string,
 	s app.TrainingService,
+	notice app.TrainingNoticeService,
 	subscriber message.Subscriber,
 ) (err error) {
-	c := &consumer{cfg: cfg, s: s}
+	c := &consumer{cfg: cfg, s: s, notice: notice}
 
 	// training created
 	err = subscriber.SubscribeWithStrategyOfRetry(
@@ -33,13 +35,24 @@ func Subscribe(
 		c.handleEventTrainingCreated,
 		[]string{tcTopic}, retryNum,
 	)
+	if err != nil {
+		return
+	}
+
+	// training schedule run failed
+	err = subscriber.SubscribeWithStrategyOfRetry(
+		handleNameTrainingScheduleRunFailed,
+		c.handleEventTrainingScheduleRunFailed,
+		[]string{srfTopic}, retryNum,
+	)
 
 	return
 }
 
 type consumer struct {
-	cfg TrainingConfig
-	s   app.TrainingService
+	cfg    TrainingConfig
+	s      app.TrainingService
+	notice app.TrainingNoticeService
 }
 
 func (c *consumer) handleEventTrainingCreated(body []byte, h map[string]string) (err error) {
@@ -65,6 +78,32 @@ func (c *consumer) handleEventTrainingCreated(body []byte, h map[string]string)
 	return c.createJob(&v)
 }
 
+func (c *consumer) handleEventTrainingScheduleRunFailed(body []byte, h map[string]string) (err error) {
+	b := message.MsgNormal{}
+	if err = json.Unmarshal(body, &b); err != nil {
+		return
+	}
+
+	if b.Details["project_id"] == "" || b.Details["schedule_id"] == "" {
+		err = errors.New("invalid message of training schedule")
+
+		return
+	}
+
+	v := domain.TrainingScheduleRunFailedEvent{
+		ScheduleId: b.Details["schedule_id"],
+		ProjectId:  b.Details["project_id"],
+		TrainingId: b.Details["training_id"],
+		Reason:     b.Details["reason"],
+	}
+
+	if v.Account, err = domain.NewAccount(b.Details["project_owner"]); err != nil {
+		return
+	}
+
+	return c.notice.Add(&v, b.CreatedAt)
+}
+
 func (c *consumer) createJob(info *domain.TrainingIndex) error {
 	// wait for the sync of model and dataset
 	time.Sleep(10 * time.Second)
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"expvar"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +13,9 @@ import (
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	abuseapp "github.com/opensourceways/xihe-server/abuse/app"
+	abuseitem "github.com/opensourceways/xihe-server/abuse/infrastructure/itemimpl"
+	abuserepo "github.com/opensourceways/xihe-server/abuse/infrastructure/repositoryimpl"
 	"github.com/opensourceways/xihe-server/app"
 	asyncapp "github.com/opensourceways/xihe-server/async-server/app"
 	asyncrepoimpl "github.com/opensourceways/xihe-server/async-server/infrastructure/repositoryimpl"
@@ -18,11 +23,16 @@ import (
 	bigmodelasynccli "github.com/opensourceways/xihe-server/bigmodel/infrastructure/asynccli"
 	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/bigmodels"
 	bigmodelmsg "github.com/opensourceways/xihe-server/bigmodel/infrastructure/messageadapter"
+	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/moderationimpl"
+	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/quotaimpl"
 	bigmodelrepo "github.com/opensourceways/xihe-server/bigmodel/infrastructure/repositoryimpl"
+	"github.com/opensourceways/xihe-server/bigmodel/infrastructure/taskeventhub"
+	bigmodelmq "github.com/opensourceways/xihe-server/bigmodel/messagequeue"
 	cloudapp "github.com/opensourceways/xihe-server/cloud/app"
 	cloudmsg "github.com/opensourceways/xihe-server/cloud/infrastructure/messageadapter"
 	cloudrepo "github.com/opensourceways/xihe-server/cloud/infrastructure/repositoryimpl"
 	"github.com/opensourceways/xihe-server/common/infrastructure/kafka"
+	"github.com/opensourceways/xihe-server/common/infrastructure/redis"
 	competitionapp "github.com/opensourceways/xihe-server/competition/app"
 	competitionmsg "github.com/opensourceways/xihe-server/competition/infrastructure/messageadapter"
 	competitionrepo "github.com/opensourceways/xihe-server/competition/infrastructure/repositoryimpl"
@@ -74,6 +84,20 @@ func StartWebServer(port int, timeout time.Duration, cfg *config.Config) {
 	interrupts.ListenAndServe(srv, timeout)
 }
 
+// StartMetricsServer serves the metrics on the port which is not exposed to public,
+// it doesn't block.
+func StartMetricsServer(port int, timeout time.Duration) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+
+	interrupts.ListenAndServe(srv, timeout)
+}
+
 // setRouter init router
 func setRouter(engine *gin.Engine, cfg *config.Config) error {
 	docs.SwaggerInfo.BasePath = "/api"
@@ -165,6 +189,9 @@ func setRouter(engine *gin.Engine, cfg *config.Config) error {
 	challengeHelper := challengeimpl.NewChallenge(&cfg.Challenge)
 	likeAdapter := messages.NewLikeMessageAdapter(cfg.MQTopics.Like, &cfg.Like, publisher)
 
+	bigmodels.SetModerationAuditor(bigmodelmsg.NewModerationAuditor(&cfg.BigModel.Message, publisher))
+	contentModeration := moderationimpl.NewContentModeration(bigmodels.ModerationService())
+
 	// sender
 	sender := messages.NewMessageSender(&cfg.MQTopics, publisher)
 	// resource producer
@@ -181,13 +208,14 @@ func setRouter(engine *gin.Engine, cfg *config.Config) error {
 	)
 
 	asyncAppService := asyncapp.NewTaskService(asyncrepoimpl.NewAsyncTaskRepo(&cfg.Postgresql.Async))
+	asyncBatchService := asyncapp.NewBatchService(asyncrepoimpl.NewAsyncBatchRepo(&cfg.Postgresql.Async))
 
 	competitionAppService := competitionapp.NewCompetitionService(
 		competitionrepo.NewCompetitionRepo(mongodb.NewCollection(collections.Competition)),
 		competitionrepo.NewWorkRepo(mongodb.NewCollection(collections.CompetitionWork)),
 		competitionrepo.NewPlayerRepo(mongodb.NewCollection(collections.CompetitionPlayer)),
 		competitionmsg.MessageAdapter(&cfg.Competition.Message, publisher), uploader,
-		competitionusercli.NewUserCli(userRegService),
+		competitionusercli.NewUserCli(userRegService), contentModeration,
 	)
 
 	courseAppService := courseapp.NewCourseService(
@@ -206,6 +234,8 @@ func setRouter(engine *gin.Engine, cfg *config.Config) error {
 		cloudmsg.NewPublisher(&cfg.Cloud, publisher),
 	)
 
+	chatSessionRepo := bigmodelrepo.NewChatSession(mongodb.NewCollection(collections.ChatSession))
+
 	bigmodelAppService := bigmodelapp.NewBigModelService(
 		bigmodel, user,
 		bigmodelrepo.NewLuoJiaRepo(mongodb.NewCollection(collections.LuoJia)),
@@ -216,13 +246,69 @@ func setRouter(engine *gin.Engine, cfg *config.Config) error {
 		bigmodelrepo.NewApiService(mongodb.NewCollection(collections.ApiApply)),
 		bigmodelrepo.NewApiInfo(mongodb.NewCollection(collections.ApiInfo)),
 		userRegService,
+		chatSessionRepo,
+	)
+
+	chatSessionService := bigmodelapp.NewChatSessionService(bigmodel, chatSessionRepo)
+
+	galleryRepo := bigmodelrepo.NewGalleryPicture(mongodb.NewCollection(collections.GalleryPicture))
+
+	abuseService := abuseapp.NewReportService(
+		abuseitem.NewItemImpl(
+			bigmodelrepo.NewWuKongPictureRepo(mongodb.NewCollection(collections.WuKongPicture)),
+			galleryRepo, proj, model, dataset,
+		),
+		abuserepo.NewReportRepo(mongodb.NewCollection(collections.AbuseReport)),
 	)
 
-	projectService := app.NewProjectService(user, proj, model, dataset, activity, nil, resProducer)
+	promptService := bigmodelapp.NewPromptService(
+		bigmodelrepo.NewPromptTemplate(mongodb.NewCollection(collections.PromptTemplate)),
+		bigmodelAppService,
+		bigmodel,
+	)
 
-	modelService := app.NewModelService(user, model, proj, dataset, activity, nil, resProducer)
+	galleryService := bigmodelapp.NewGalleryService(bigmodel, galleryRepo, abuseService)
 
-	datasetService := app.NewDatasetService(user, dataset, proj, model, activity, nil, resProducer)
+	arenaService := bigmodelapp.NewArenaService(
+		bigmodel,
+		bigmodelrepo.NewArenaBattle(mongodb.NewCollection(collections.ArenaBattle)),
+		bigmodelrepo.NewArenaRating(mongodb.NewCollection(collections.ArenaRating)),
+		bigmodelmsg.NewMessageAdapter(&cfg.BigModel.Message, publisher),
+	)
+
+	apiKeyService := bigmodelapp.NewApiKeyService(
+		bigmodelrepo.NewApiService(mongodb.NewCollection(collections.ApiApply)),
+		bigmodelrepo.NewApiKey(mongodb.NewCollection(collections.ApiKey)),
+	)
+
+	apiGatewayService := bigmodelapp.NewApiGatewayService(
+		bigmodelrepo.NewApiService(mongodb.NewCollection(collections.ApiApply)),
+		bigmodelrepo.NewApiUsage(mongodb.NewCollection(collections.ApiUsage)),
+		quotaimpl.NewLimiter(redis.DB()),
+	)
+
+	batchService := bigmodelapp.NewBatchService(
+		asyncBatchService,
+		bigmodelrepo.NewApiService(mongodb.NewCollection(collections.ApiApply)),
+		apiGatewayService,
+	)
+
+	asyncTaskStatusService, err := newAsyncTaskStatusService(cfg, asyncAppService)
+	if err != nil {
+		return err
+	}
+
+	projectService := app.NewProjectService(
+		user, proj, model, dataset, activity, nil, resProducer, contentModeration,
+	)
+
+	modelService := app.NewModelService(
+		user, model, proj, dataset, activity, nil, resProducer, contentModeration,
+	)
+
+	datasetService := app.NewDatasetService(
+		user, dataset, proj, model, activity, nil, resProducer, contentModeration,
+	)
 
 	v1 := engine.Group(docs.SwaggerInfo.BasePath)
 
@@ -233,23 +319,23 @@ func setRouter(engine *gin.Engine, cfg *config.Config) error {
 
 	userAppService := userapp.NewUserService(
 		user, gitlabUser, usermsg.MessageAdapter(&cfg.User.Message, publisher),
-		pointsAppService, controller.EncryptHelperToken(),
+		pointsAppService, controller.EncryptHelperToken(), contentModeration,
 	)
 
 	{
 		controller.AddRouterForProjectController(
 			v1, user, proj, model, dataset, activity, tags, like, resProducer,
-			newPlatformRepository,
+			contentModeration, newPlatformRepository,
 		)
 
 		controller.AddRouterForModelController(
 			v1, user, model, proj, dataset, activity, tags, like, resProducer,
-			newPlatformRepository,
+			contentModeration, newPlatformRepository,
 		)
 
 		controller.AddRouterForDatasetController(
 			v1, user, dataset, model, proj, activity, tags, like, resProducer,
-			newPlatformRepository,
+			contentModeration, newPlatformRepository,
 		)
 
 		controller.AddRouterForUserController(
@@ -274,14 +360,48 @@ func setRouter(engine *gin.Engine, cfg *config.Config) error {
 		)
 
 		controller.AddRouterForBigModelController(
-			v1, bigmodelAppService, userRegService,
+			v1, bigmodelAppService, userRegService, asyncTaskStatusService,
+			apiGatewayService, apiKeyService, chatSessionService,
+		)
+
+		controller.AddRouterForPromptController(
+			v1, promptService,
+		)
+
+		controller.AddRouterForGalleryController(
+			v1, galleryService,
+		)
+
+		controller.AddRouterForArenaController(
+			v1, arenaService,
+		)
+
+		controller.AddRouterForBatchController(
+			v1, batchService,
 		)
 
 		controller.AddRouterForTrainingController(
-			v1, trainingAdapter, training, model, proj, dataset,
+			v1, trainingAdapter, training,
+			repositories.NewTrainingQueueRepository(
+				mongodb.NewTrainingQueueMapper(collections.TrainingQueue),
+			),
+			repositories.NewTrainingSweepRepository(
+				mongodb.NewTrainingSweepMapper(collections.TrainingSweep),
+			),
+			repositories.NewTrainingMetricRepository(
+				mongodb.NewTrainingMetricMapper(collections.TrainingMetric),
+			),
+			repositories.NewTrainingScheduleRepository(
+				mongodb.NewTrainingScheduleMapper(collections.TrainingSchedule),
+			),
+			repositories.NewTrainingNoticeRepository(
+				mongodb.NewTrainingNoticeMapper(collections.TrainingNotice),
+			),
+			model, proj, dataset,
 			messages.NewTrainingMessageAdapter(
 				&cfg.Training.Message, publisher,
 			),
+			modelService, gitlabRepo, newPlatformRepository,
 		)
 
 		controller.AddRouterForFinetuneController(
@@ -289,7 +409,7 @@ func setRouter(engine *gin.Engine, cfg *config.Config) error {
 		)
 
 		controller.AddRouterForRepoFileController(
-			v1, gitlabRepo, model, proj, dataset, repoAdapter, userAppService,
+			v1, gitlabRepo, model, proj, dataset, repoAdapter, sender, userAppService,
 		)
 
 		controller.AddRouterForInferenceController(
@@ -308,6 +428,10 @@ func setRouter(engine *gin.Engine, cfg *config.Config) error {
 			v1, competition, aiquestion, challengeHelper,
 		)
 
+		controller.AddRouterForAbuseController(
+			v1, abuseService,
+		)
+
 		controller.AddRouterForCourseController(
 			v1, courseAppService, userRegService, proj, user,
 		)
@@ -361,6 +485,29 @@ func addRouterForUserPointsController(g *gin.RouterGroup, cfg *config.Config) (
 	return pointsAppService, nil
 }
 
+// newAsyncTaskStatusService subscribes the status changes of async tasks for the watchers
+func newAsyncTaskStatusService(cfg *config.Config, s asyncapp.TaskService) (
+	bigmodelapp.AsyncTaskStatusService, error,
+) {
+	instance, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	hub := taskeventhub.NewTaskEventHub()
+
+	err = bigmodelmq.SubscribeTaskStatus(
+		hub, instance, cfg.BigModel.Message.AsyncTaskTopics(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bigmodelapp.NewAsyncTaskStatusService(
+		bigmodelasynccli.NewAsyncCli(s), hub,
+	), nil
+}
+
 func logRequest() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		startTime := time.Now()
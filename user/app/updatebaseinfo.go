@@ -1,8 +1,17 @@
 package app
 
-import "github.com/opensourceways/xihe-server/user/domain"
+import (
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
+	"github.com/opensourceways/xihe-server/user/domain"
+)
 
 func (s userService) UpdateBasicInfo(account domain.Account, cmd UpdateUserBasicInfoCmd) error {
+	if cmd.Bio != nil && s.moderation != nil {
+		if err := s.moderation.CheckText(moderation.SceneUser, cmd.Bio.Bio()); err != nil {
+			return err
+		}
+	}
+
 	user, err := s.repo.GetByAccount(account)
 	if err != nil {
 		return err
@@ -3,6 +3,7 @@ package app
 import (
 	"encoding/hex"
 
+	"github.com/opensourceways/xihe-server/common/domain/moderation"
 	platform "github.com/opensourceways/xihe-server/domain/platform"
 	typerepo "github.com/opensourceways/xihe-server/domain/repository"
 	"github.com/opensourceways/xihe-server/user/domain"
@@ -43,6 +44,7 @@ func NewUserService(
 	sender message.MessageProducer,
 	points pointsPort.Points,
 	encryption utils.SymmetricEncryption,
+	moderation moderation.Moderation,
 ) UserService {
 	return userService{
 		ps:         ps,
@@ -50,6 +52,7 @@ func NewUserService(
 		sender:     sender,
 		points:     points,
 		encryption: encryption,
+		moderation: moderation,
 	}
 }
 
@@ -59,6 +62,7 @@ type userService struct {
 	sender     message.MessageProducer
 	points     pointsPort.Points
 	encryption utils.SymmetricEncryption
+	moderation moderation.Moderation
 }
 
 func (s userService) Create(cmd *UserCreateCmd) (dto UserDTO, err error) {
@@ -75,7 +79,7 @@ func (s userService) Create(cmd *UserCreateCmd) (dto UserDTO, err error) {
 	s.toUserDTO(&u, &dto)
 
 	_ = s.sender.AddOperateLogForNewUser(u.Account)
-	
+
 	_ = s.sender.SendUserSignedUpEvent(&domain.UserSignedUpEvent{
 		Account: cmd.Account,
 	})